@@ -0,0 +1,196 @@
+// Package client provides a Go client for the asynqmon HTTP API.
+//
+// The client is useful when Redis is not directly reachable, but an asynqmon
+// server is. All request and response types mirror the JSON returned by the
+// asynqmon API server.
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Options are used to configure Client.
+type Options struct {
+	// BaseURL is the URL of the asynqmon application, including its root path
+	// (e.g. "http://localhost:8080/monitoring").
+	//
+	// This field is required.
+	BaseURL string
+
+	// HTTPClient is used to send requests to the API server.
+	//
+	// This field is optional. Default is http.DefaultClient.
+	HTTPClient *http.Client
+
+	// Header specifies additional headers to send with every request
+	// (e.g. "Authorization" header for an authenticating proxy).
+	//
+	// This field is optional.
+	Header http.Header
+
+	// Username and Password are used for HTTP basic authentication if Username is set.
+	//
+	// These fields are optional.
+	Username string
+	Password string
+
+	// BearerToken is sent in the "Authorization" header if set.
+	//
+	// This field is optional.
+	BearerToken string
+}
+
+// Client is a client for the asynqmon HTTP API.
+// It is safe for concurrent use by multiple goroutines.
+type Client struct {
+	baseURL    string // the value should not have the trailing slash
+	apiURL     string // the value should not have the trailing slash
+	httpClient *http.Client
+	header     http.Header
+	username   string
+	password   string
+	token      string
+}
+
+// New creates a Client with the given options.
+func New(opts Options) *Client {
+	if opts.BaseURL == "" {
+		panic("client.New: BaseURL field is required")
+	}
+	if _, err := url.Parse(opts.BaseURL); err != nil {
+		panic(fmt.Sprintf("client.New: invalid BaseURL: %v", err))
+	}
+	httpClient := opts.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	return &Client{
+		baseURL:    baseURL,
+		apiURL:     baseURL + "/api",
+		httpClient: httpClient,
+		header:     opts.Header.Clone(),
+		username:   opts.Username,
+		password:   opts.Password,
+		token:      opts.BearerToken,
+	}
+}
+
+// ListOptions specifies pagination for list methods.
+// Zero values use the server defaults.
+type ListOptions struct {
+	// Number of items in a page.
+	PageSize int
+	// Page number starting from 1.
+	Page int
+}
+
+func (opts *ListOptions) values() url.Values {
+	v := url.Values{}
+	if opts == nil {
+		return v
+	}
+	if opts.PageSize > 0 {
+		v.Set("size", strconv.Itoa(opts.PageSize))
+	}
+	if opts.Page > 0 {
+		v.Set("page", strconv.Itoa(opts.Page))
+	}
+	return v
+}
+
+// get sends a GET request to the API path and decodes the JSON response into out.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out interface{}) error {
+	return c.do(ctx, http.MethodGet, path, query, nil, out)
+}
+
+// post sends a POST request with the JSON encoded body (if non-nil) and decodes the JSON response into out (if non-nil).
+func (c *Client) post(ctx context.Context, path string, body, out interface{}) error {
+	return c.do(ctx, http.MethodPost, path, nil, body, out)
+}
+
+// put sends a PUT request with the JSON encoded body and decodes the JSON response into out (if non-nil).
+func (c *Client) put(ctx context.Context, path string, body, out interface{}) error {
+	return c.do(ctx, http.MethodPut, path, nil, body, out)
+}
+
+// delete sends a DELETE request and decodes the JSON response into out (if non-nil).
+func (c *Client) delete(ctx context.Context, path string, out interface{}) error {
+	return c.do(ctx, http.MethodDelete, path, nil, nil, out)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, query url.Values, body, out interface{}) error {
+	return c.doURL(ctx, method, c.apiURL+path, query, body, out)
+}
+
+// doURL sends a request to the URL u, which may be outside of the API path (e.g. health check endpoints).
+func (c *Client) doURL(ctx context.Context, method, u string, query url.Values, body, out interface{}) error {
+	if token := confirmationTokenFromContext(ctx); token != "" {
+		q := make(url.Values, len(query)+1)
+		for k, vs := range query {
+			q[k] = vs
+		}
+		q.Set("confirmation_token", token)
+		query = q
+	}
+	if len(query) > 0 {
+		u += "?" + query.Encode()
+	}
+	var r io.Reader
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("asynqmon client: could not encode request body: %v", err)
+		}
+		r = bytes.NewReader(b)
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, r)
+	if err != nil {
+		return err
+	}
+	for k, vs := range c.header {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.username != "" {
+		req.SetBasicAuth(c.username, c.password)
+	}
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return newError(resp)
+	}
+	if out == nil || resp.StatusCode == http.StatusNoContent {
+		io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("asynqmon client: could not decode response from %s %s: %v", method, u, err)
+	}
+	return nil
+}
+
+// escape escapes a path segment such as a queue name or a task ID.
+func escape(s string) string {
+	return url.PathEscape(s)
+}
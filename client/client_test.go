@@ -0,0 +1,107 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestClientRequest(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got, want := r.Header.Get("Authorization"), "Bearer secret"; got != want {
+			t.Errorf("Authorization header = %q, want %q", got, want)
+		}
+		if got, want := r.Header.Get("X-Team"), "infra"; got != want {
+			t.Errorf("X-Team header = %q, want %q", got, want)
+		}
+		if r.Method != "GET" || r.URL.Path != "/monitoring/api/queues/default/archived_tasks" {
+			t.Errorf("got request %s %s", r.Method, r.URL.Path)
+		}
+		if got, want := r.URL.RawQuery, "page=2&size=10"; got != want {
+			t.Errorf("query = %q, want %q", got, want)
+		}
+		fmt.Fprint(w, `{"tasks":[{"id":"abc","type":"email:send","queue":"default","error_message":"oops"}],"stats":{"queue":"default","archived":1}}`)
+	}))
+	defer srv.Close()
+
+	c := New(Options{
+		BaseURL:     srv.URL + "/monitoring/",
+		BearerToken: "secret",
+		Header:      http.Header{"X-Team": []string{"infra"}},
+	})
+	got, err := c.ListArchivedTasks(context.Background(), "default", &ListOptions{PageSize: 10, Page: 2})
+	if err != nil {
+		t.Fatalf("ListArchivedTasks returned error: %v", err)
+	}
+	want := &ListArchivedTasksResponse{
+		Tasks: []*ArchivedTask{
+			{BaseTask: BaseTask{ID: "abc", Type: "email:send", Queue: "default", LastError: "oops"}},
+		},
+		Stats: &Queue{Queue: "default", Archived: 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("ListArchivedTasks returned %+v, want %+v; (-want,+got)\n%s", got, want, diff)
+	}
+}
+
+func TestClientErrors(t *testing.T) {
+	tests := []struct {
+		desc       string
+		status     int
+		body       string
+		wantTarget error
+		wantCode   string
+	}{
+		{
+			desc:       "not found",
+			status:     http.StatusNotFound,
+			body:       `{"code":"queue_not_found","message":"queue not found","queue":"default"}`,
+			wantTarget: ErrNotFound,
+			wantCode:   "queue_not_found",
+		},
+		{
+			desc:       "read-only",
+			status:     http.StatusMethodNotAllowed,
+			body:       `{"code":"read_only","message":"API Server is running in read-only mode: POST request is not allowed"}`,
+			wantTarget: ErrReadOnly,
+			wantCode:   "read_only",
+		},
+		{
+			desc:       "confirmation required",
+			status:     http.StatusPreconditionRequired,
+			body:       `{"code":"confirmation_required","message":"POST /api/queues/default:pause requires a confirmation token"}`,
+			wantTarget: ErrConfirmationRequired,
+			wantCode:   "confirmation_required",
+		},
+		{
+			desc:       "plain text body",
+			status:     http.StatusNotFound,
+			body:       "404 page not found",
+			wantTarget: ErrNotFound,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, tc.body, tc.status)
+			}))
+			defer srv.Close()
+
+			c := New(Options{BaseURL: srv.URL})
+			err := c.PauseQueue(context.Background(), "default")
+			if !errors.Is(err, tc.wantTarget) {
+				t.Errorf("PauseQueue returned %v, want error matching %v", err, tc.wantTarget)
+			}
+			var apiErr *Error
+			if !errors.As(err, &apiErr) || apiErr.StatusCode != tc.status || apiErr.Code != tc.wantCode {
+				t.Errorf("PauseQueue returned %v, want *Error with status %d and code %q", err, tc.status, tc.wantCode)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+type confirmationTokenKey struct{}
+
+// WithConfirmationToken returns a context which makes the requests of the client carry the
+// confirmation token returned by a dry run (e.g. PreviewDeleteAllTasks).
+//
+// Use it when the server requires confirmation of destructive requests:
+//
+//	p, err := c.PreviewDeleteAllTasks(ctx, "critical", client.TaskStateArchived)
+//	// check p.Affected
+//	n, err := c.DeleteAllTasks(client.WithConfirmationToken(ctx, p.ConfirmationToken), "critical", client.TaskStateArchived)
+func WithConfirmationToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, confirmationTokenKey{}, token)
+}
+
+func confirmationTokenFromContext(ctx context.Context) string {
+	token, _ := ctx.Value(confirmationTokenKey{}).(string)
+	return token
+}
+
+// dryRun sends the DELETE request to path as a dry run.
+func (c *Client) dryRun(ctx context.Context, path string, query url.Values) (*DryRunResult, error) {
+	if query == nil {
+		query = make(url.Values)
+	}
+	query.Set("dry_run", "true")
+	var resp DryRunResult
+	if err := c.do(ctx, http.MethodDelete, path, query, nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// PreviewDeleteQueue returns the number of tasks in the queue and a token to confirm DeleteQueue.
+func (c *Client) PreviewDeleteQueue(ctx context.Context, qname string) (*DryRunResult, error) {
+	return c.dryRun(ctx, "/queues/"+escape(qname), nil)
+}
+
+// PreviewDeleteAllTasks returns the number of tasks DeleteAllTasks would delete and a token to confirm it.
+func (c *Client) PreviewDeleteAllTasks(ctx context.Context, qname string, state TaskState) (*DryRunResult, error) {
+	return c.dryRun(ctx, tasksPath(qname, state)+":delete_all", nil)
+}
+
+// PreviewDeleteAllAggregatingTasks returns the number of tasks DeleteAllAggregatingTasks would delete
+// and a token to confirm it.
+func (c *Client) PreviewDeleteAllAggregatingTasks(ctx context.Context, qname, group string) (*DryRunResult, error) {
+	return c.dryRun(ctx, aggregatingTasksPath(qname, group)+":delete_all", nil)
+}
+
+// PreviewDeleteErrorCluster returns the number of tasks DeleteErrorCluster would delete and a token to confirm it.
+func (c *Client) PreviewDeleteErrorCluster(ctx context.Context, qname string, state TaskState, clusterID string, opts *ErrorClusterOptions) (*DryRunResult, error) {
+	return c.dryRun(ctx, errorClustersPath(qname, state)+"/"+escape(clusterID), opts.values())
+}
@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - Client methods for error cluster related endpoints
+// ****************************************************************************
+
+// errorClustersPath returns the API path for the error clusters of tasks in the given state.
+func errorClustersPath(qname string, state TaskState) string {
+	return tasksPath(qname, state) + "/error_clusters"
+}
+
+func (opts *ErrorClusterOptions) values() url.Values {
+	q := make(url.Values)
+	if opts != nil && opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	return q
+}
+
+// ListErrorClusters groups the tasks in the given state (TaskStateRetry or TaskStateArchived)
+// by normalized error message.
+func (c *Client) ListErrorClusters(ctx context.Context, qname string, state TaskState, opts *ErrorClusterOptions) (*ListErrorClustersResponse, error) {
+	var resp ListErrorClustersResponse
+	if err := c.get(ctx, errorClustersPath(qname, state), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// RunErrorCluster moves the tasks in the error cluster to pending state.
+func (c *Client) RunErrorCluster(ctx context.Context, qname string, state TaskState, clusterID string, opts *ErrorClusterOptions) (*BatchRunResponse, error) {
+	var resp BatchRunResponse
+	path := errorClustersPath(qname, state) + "/" + escape(clusterID) + ":run"
+	if err := c.do(ctx, http.MethodPost, path, opts.values(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ArchiveErrorCluster moves the tasks in the error cluster of retry tasks to archived state.
+func (c *Client) ArchiveErrorCluster(ctx context.Context, qname, clusterID string, opts *ErrorClusterOptions) (*BatchArchiveResponse, error) {
+	var resp BatchArchiveResponse
+	path := errorClustersPath(qname, TaskStateRetry) + "/" + escape(clusterID) + ":archive"
+	if err := c.do(ctx, http.MethodPost, path, opts.values(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteErrorCluster deletes the tasks in the error cluster.
+func (c *Client) DeleteErrorCluster(ctx context.Context, qname string, state TaskState, clusterID string, opts *ErrorClusterOptions) (*BatchDeleteResponse, error) {
+	var resp BatchDeleteResponse
+	path := errorClustersPath(qname, state) + "/" + escape(clusterID)
+	if err := c.do(ctx, http.MethodDelete, path, opts.values(), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
@@ -0,0 +1,79 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+var (
+	// ErrNotFound indicates that the requested resource (e.g. queue or task) was not found.
+	ErrNotFound = errors.New("asynqmon: not found")
+
+	// ErrReadOnly indicates that the request was rejected because the server is running in read-only mode.
+	ErrReadOnly = errors.New("asynqmon: server is running in read-only mode")
+
+	// ErrConfirmationRequired indicates that the server requires a confirmation token for the request.
+	// See WithConfirmationToken.
+	ErrConfirmationRequired = errors.New("asynqmon: confirmation token required")
+)
+
+// Error is returned when the API server responds with a non-successful status code.
+//
+// Use errors.Is with ErrNotFound, ErrReadOnly or ErrConfirmationRequired to check for those specific conditions.
+type Error struct {
+	// StatusCode is the HTTP status code of the response.
+	StatusCode int `json:"-"`
+
+	// Code is the machine-readable error code returned by the server (e.g. "queue_not_found").
+	// Empty if the response body was not a JSON error response (e.g. the error came from a proxy).
+	Code string `json:"code"`
+
+	// Message is the error message returned by the server.
+	Message string `json:"message"`
+
+	// Queue and TaskID are the queue name and the task ID the request was made against, if any.
+	Queue  string `json:"queue"`
+	TaskID string `json:"task_id"`
+}
+
+func (e *Error) Error() string {
+	if e.Code != "" {
+		return fmt.Sprintf("asynqmon: %s (status %d, code %s)", e.Message, e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("asynqmon: %s (status %d)", e.Message, e.StatusCode)
+}
+
+// Is reports whether the error matches the target.
+func (e *Error) Is(target error) bool {
+	switch target {
+	case ErrNotFound:
+		return e.StatusCode == http.StatusNotFound
+	case ErrConfirmationRequired:
+		return e.Code == "confirmation_required"
+	case ErrReadOnly:
+		return e.Code == "read_only" ||
+			(e.Code == "" && e.StatusCode == http.StatusMethodNotAllowed && strings.Contains(e.Message, "read-only mode"))
+	}
+	return false
+}
+
+// Maximum number of bytes to read from an error response body.
+const maxErrorBodySize = 64 << 10
+
+func newError(resp *http.Response) *Error {
+	b, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	var e Error
+	if err := json.Unmarshal(b, &e); err != nil || e.Code == "" {
+		// Not a JSON error response; use the body as the message.
+		e = Error{Message: strings.TrimSpace(string(b))}
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	e.StatusCode = resp.StatusCode
+	return &e
+}
@@ -0,0 +1,12 @@
+package client
+
+import (
+	"context"
+	"net/http"
+)
+
+// Ready reports whether the asynqmon server is ready to serve requests,
+// that is, whether it can connect to Redis. It returns nil if the server is ready.
+func (c *Client) Ready(ctx context.Context) error {
+	return c.doURL(ctx, http.MethodGet, c.baseURL+"/readyz", nil, nil, nil)
+}
@@ -0,0 +1,30 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// GetMetrics returns the time series data of queue metrics.
+// The server needs to be configured with a Prometheus address.
+func (c *Client) GetMetrics(ctx context.Context, opts *MetricsOptions) (*Metrics, error) {
+	v := url.Values{}
+	if opts != nil {
+		if opts.Duration > 0 {
+			v.Set("duration", strconv.Itoa(int(opts.Duration.Seconds())))
+		}
+		if !opts.EndTime.IsZero() {
+			v.Set("endtime", strconv.FormatInt(opts.EndTime.Unix(), 10))
+		}
+		if len(opts.Queues) > 0 {
+			v.Set("queues", strings.Join(opts.Queues, ","))
+		}
+	}
+	var resp Metrics
+	if err := c.get(ctx, "/metrics", v, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
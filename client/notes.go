@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+type noteRequest struct {
+	Text   string `json:"text"`
+	Author string `json:"author,omitempty"`
+}
+
+// ListTaskNotes returns the notes left on the task, oldest first.
+func (c *Client) ListTaskNotes(ctx context.Context, qname, taskID string) ([]*Note, error) {
+	return c.listNotes(ctx, "/queues/"+escape(qname)+"/tasks/"+escape(taskID)+"/notes")
+}
+
+// AddTaskNote leaves a note on the task. The author is used if the server doesn't know
+// the authenticated user.
+func (c *Client) AddTaskNote(ctx context.Context, qname, taskID, text, author string) (*Note, error) {
+	return c.addNote(ctx, "/queues/"+escape(qname)+"/tasks/"+escape(taskID)+"/notes", text, author)
+}
+
+// DeleteTaskNote deletes a note of the task.
+func (c *Client) DeleteTaskNote(ctx context.Context, qname, taskID, noteID string) error {
+	return c.delete(ctx, "/queues/"+escape(qname)+"/tasks/"+escape(taskID)+"/notes/"+escape(noteID), nil)
+}
+
+// ListQueueNotes returns the notes left on the queue, oldest first.
+func (c *Client) ListQueueNotes(ctx context.Context, qname string) ([]*Note, error) {
+	return c.listNotes(ctx, "/queues/"+escape(qname)+"/notes")
+}
+
+// AddQueueNote leaves a note on the queue. The author is used if the server doesn't know
+// the authenticated user.
+func (c *Client) AddQueueNote(ctx context.Context, qname, text, author string) (*Note, error) {
+	return c.addNote(ctx, "/queues/"+escape(qname)+"/notes", text, author)
+}
+
+// DeleteQueueNote deletes a note of the queue.
+func (c *Client) DeleteQueueNote(ctx context.Context, qname, noteID string) error {
+	return c.delete(ctx, "/queues/"+escape(qname)+"/notes/"+escape(noteID), nil)
+}
+
+// ListErrorClusterNotes returns the notes left on the error cluster, oldest first.
+// Notes of an error cluster are shared by all queues.
+func (c *Client) ListErrorClusterNotes(ctx context.Context, clusterID string) ([]*Note, error) {
+	return c.listNotes(ctx, "/error_clusters/"+escape(clusterID)+"/notes")
+}
+
+// AddErrorClusterNote leaves a note on the error cluster. The author is used if the server
+// doesn't know the authenticated user.
+func (c *Client) AddErrorClusterNote(ctx context.Context, clusterID, text, author string) (*Note, error) {
+	return c.addNote(ctx, "/error_clusters/"+escape(clusterID)+"/notes", text, author)
+}
+
+// DeleteErrorClusterNote deletes a note of the error cluster.
+func (c *Client) DeleteErrorClusterNote(ctx context.Context, clusterID, noteID string) error {
+	return c.delete(ctx, "/error_clusters/"+escape(clusterID)+"/notes/"+escape(noteID), nil)
+}
+
+// ListAnnotations returns the annotations overlapping the time range, sorted by start time.
+func (c *Client) ListAnnotations(ctx context.Context, opts *AnnotationOptions) ([]*Annotation, error) {
+	v := url.Values{}
+	if opts != nil {
+		if opts.Duration > 0 {
+			v.Set("duration", strconv.Itoa(int(opts.Duration.Seconds())))
+		}
+		if !opts.EndTime.IsZero() {
+			v.Set("endtime", strconv.FormatInt(opts.EndTime.Unix(), 10))
+		}
+		if len(opts.Queues) > 0 {
+			v.Set("queues", strings.Join(opts.Queues, ","))
+		}
+	}
+	var resp struct {
+		Annotations []*Annotation `json:"annotations"`
+	}
+	if err := c.get(ctx, "/annotations", v, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Annotations, nil
+}
+
+// CreateAnnotation creates an annotation.
+func (c *Client) CreateAnnotation(ctx context.Context, req *CreateAnnotationRequest) (*Annotation, error) {
+	var resp Annotation
+	if err := c.post(ctx, "/annotations", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteAnnotation deletes an annotation.
+func (c *Client) DeleteAnnotation(ctx context.Context, id string) error {
+	return c.delete(ctx, "/annotations/"+escape(id), nil)
+}
+
+func (c *Client) listNotes(ctx context.Context, path string) ([]*Note, error) {
+	var resp struct {
+		Notes []*Note `json:"notes"`
+	}
+	if err := c.get(ctx, path, nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Notes, nil
+}
+
+func (c *Client) addNote(ctx context.Context, path, text, author string) (*Note, error) {
+	var resp Note
+	if err := c.post(ctx, path, &noteRequest{Text: text, Author: author}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
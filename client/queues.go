@@ -0,0 +1,153 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - Client methods for queue related endpoints
+// ****************************************************************************
+
+// ListQueues returns snapshots of all queues.
+func (c *Client) ListQueues(ctx context.Context) ([]*Queue, error) {
+	var resp struct {
+		Queues []*Queue `json:"queues"`
+	}
+	if err := c.get(ctx, "/queues", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Queues, nil
+}
+
+// GetQueue returns the current snapshot and the recent daily stats of the queue.
+// If opts is nil, the stats of the last 10 days are returned.
+func (c *Client) GetQueue(ctx context.Context, qname string, opts *HistoryOptions) (*QueueDetail, error) {
+	var resp QueueDetail
+	if err := c.get(ctx, "/queues/"+escape(qname), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteQueue deletes the queue. The queue must be empty.
+func (c *Client) DeleteQueue(ctx context.Context, qname string) error {
+	return c.delete(ctx, "/queues/"+escape(qname), nil)
+}
+
+// PauseQueue pauses task processing on the queue.
+func (c *Client) PauseQueue(ctx context.Context, qname string) error {
+	return c.post(ctx, "/queues/"+escape(qname)+":pause", nil, nil)
+}
+
+// ResumeQueue resumes task processing on the queue.
+func (c *Client) ResumeQueue(ctx context.Context, qname string) error {
+	return c.post(ctx, "/queues/"+escape(qname)+":resume", nil, nil)
+}
+
+// ListQueueStats returns the daily stats of all queues keyed by queue name.
+// If opts is nil, the stats of the last 90 days are returned.
+func (c *Client) ListQueueStats(ctx context.Context, opts *HistoryOptions) (map[string][]*DailyStats, error) {
+	var resp struct {
+		Stats map[string][]*DailyStats `json:"stats"`
+	}
+	if err := c.get(ctx, "/queue_stats", opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Stats, nil
+}
+
+// ListGroups returns the groups in the queue along with the queue snapshot.
+func (c *Client) ListGroups(ctx context.Context, qname string) (*ListGroupsResponse, error) {
+	var resp ListGroupsResponse
+	if err := c.get(ctx, "/queues/"+escape(qname)+"/groups", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListGroupDetails returns the groups in the queue, oldest first, with the age and task types
+// of each group and a histogram of group sizes.
+func (c *Client) ListGroupDetails(ctx context.Context, qname string, opts *GroupDetailsOptions) (*ListGroupDetailsResponse, error) {
+	q := url.Values{}
+	if opts != nil && opts.Sample > 0 {
+		q.Set("sample", strconv.Itoa(opts.Sample))
+	}
+	var resp ListGroupDetailsResponse
+	if err := c.get(ctx, "/queues/"+escape(qname)+"/group_details", q, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// FlushGroups moves all aggregating tasks of the selected groups to pending state
+// without waiting for the groups to be aggregated, and reports the outcome for each group.
+func (c *Client) FlushGroups(ctx context.Context, qname string, req *FlushGroupsRequest) (*FlushGroupsResponse, error) {
+	var resp FlushGroupsResponse
+	if err := c.post(ctx, "/queues/"+escape(qname)+"/groups:flush", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListSLOs returns the compliance and error budget burn rates of the queue SLOs,
+// or of the SLOs of the given queue if qname is not empty.
+func (c *Client) ListSLOs(ctx context.Context, qname string) (*ListSLOsResponse, error) {
+	q := make(url.Values)
+	if qname != "" {
+		q.Set("queue", qname)
+	}
+	var resp ListSLOsResponse
+	if err := c.get(ctx, "/slos", q, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetQueueCoverage returns the servers consuming each queue and the worker capacity each queue gets,
+// with warnings about queues with pending tasks and no workers.
+func (c *Client) GetQueueCoverage(ctx context.Context) (*QueueCoverageReport, error) {
+	var resp QueueCoverageReport
+	if err := c.get(ctx, "/queue_coverage", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetQueueRanking returns the top queues by growth, latency, failure ratio or memory usage,
+// with the change of their stats and the trend of the ranking metric over a recent period.
+func (c *Client) GetQueueRanking(ctx context.Context, opts *QueueRankingOptions) (*QueueRanking, error) {
+	q := url.Values{}
+	if opts != nil {
+		if opts.SortBy != "" {
+			q.Set("sort_by", opts.SortBy)
+		}
+		if opts.Limit > 0 {
+			q.Set("limit", strconv.Itoa(opts.Limit))
+		}
+		if opts.Duration > 0 {
+			q.Set("duration", strconv.Itoa(int(opts.Duration.Seconds())))
+		}
+	}
+	var resp QueueRanking
+	if err := c.get(ctx, "/queue_ranking", q, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (opts *HistoryOptions) values() url.Values {
+	v := url.Values{}
+	if opts == nil {
+		return v
+	}
+	if opts.Days > 0 {
+		v.Set("days", strconv.Itoa(opts.Days))
+	}
+	if opts.GroupBy != "" {
+		v.Set("group_by", opts.GroupBy)
+	}
+	return v
+}
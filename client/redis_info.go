@@ -0,0 +1,97 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// GetRedisInfo returns information about the redis server (or cluster) asynqmon is connected to.
+func (c *Client) GetRedisInfo(ctx context.Context) (*RedisInfo, error) {
+	var resp RedisInfo
+	if err := c.get(ctx, "/redis_info", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetRedisInfoHistory returns the samples of redis INFO fields taken by the server.
+func (c *Client) GetRedisInfoHistory(ctx context.Context, opts *RedisInfoHistoryOptions) (*RedisInfoHistory, error) {
+	q := url.Values{}
+	if opts != nil {
+		if opts.Duration > 0 {
+			q.Set("duration", strconv.Itoa(int(opts.Duration.Seconds())))
+		}
+		if opts.Node != "" {
+			q.Set("node", opts.Node)
+		}
+	}
+	var resp RedisInfoHistory
+	if err := c.get(ctx, "/redis_info_history", q, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetRedisMemory returns the memory used by the redis keys of the queues,
+// broken down by key type, task state and task type.
+func (c *Client) GetRedisMemory(ctx context.Context, opts *RedisMemoryOptions) (*RedisMemory, error) {
+	q := url.Values{}
+	if opts != nil {
+		if opts.Queue != "" {
+			q.Set("queue", opts.Queue)
+		}
+		if opts.SampleSize > 0 {
+			q.Set("sample_size", strconv.Itoa(opts.SampleSize))
+		}
+		if opts.Top > 0 {
+			q.Set("top", strconv.Itoa(opts.Top))
+		}
+	}
+	var resp RedisMemory
+	if err := c.get(ctx, "/redis_memory", q, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetRedisSlowLog returns up to count latest slow log entries of each redis server.
+// If count is zero, the server default is used.
+func (c *Client) GetRedisSlowLog(ctx context.Context, count int) (*RedisSlowLog, error) {
+	q := url.Values{}
+	if count > 0 {
+		q.Set("count", strconv.Itoa(count))
+	}
+	var resp RedisSlowLog
+	if err := c.get(ctx, "/redis_slowlog", q, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetRedisLatency returns the latency events of each redis server.
+func (c *Client) GetRedisLatency(ctx context.Context) (*RedisLatency, error) {
+	var resp RedisLatency
+	if err := c.get(ctx, "/redis_latency", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetRedisClients returns the clients connected to each redis server.
+func (c *Client) GetRedisClients(ctx context.Context) (*RedisClients, error) {
+	var resp RedisClients
+	if err := c.get(ctx, "/redis_clients", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetRedisCommandStats returns the command stats of each redis server.
+func (c *Client) GetRedisCommandStats(ctx context.Context) (*RedisCommandStats, error) {
+	var resp RedisCommandStats
+	if err := c.get(ctx, "/redis_commandstats", nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
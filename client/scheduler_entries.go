@@ -0,0 +1,25 @@
+package client
+
+import "context"
+
+// ListSchedulerEntries returns the periodic tasks registered by the running schedulers.
+func (c *Client) ListSchedulerEntries(ctx context.Context) ([]*SchedulerEntry, error) {
+	var resp struct {
+		Entries []*SchedulerEntry `json:"entries"`
+	}
+	if err := c.get(ctx, "/scheduler_entries", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Entries, nil
+}
+
+// ListSchedulerEnqueueEvents returns a page of enqueue events of the scheduler entry.
+func (c *Client) ListSchedulerEnqueueEvents(ctx context.Context, entryID string, opts *ListOptions) ([]*SchedulerEnqueueEvent, error) {
+	var resp struct {
+		Events []*SchedulerEnqueueEvent `json:"events"`
+	}
+	if err := c.get(ctx, "/scheduler_entries/"+escape(entryID)+"/enqueue_events", opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return resp.Events, nil
+}
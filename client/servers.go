@@ -0,0 +1,78 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// ListServers returns the asynq servers currently running.
+func (c *Client) ListServers(ctx context.Context) ([]*ServerInfo, error) {
+	var resp struct {
+		Servers []*ServerInfo `json:"servers"`
+	}
+	if err := c.get(ctx, "/servers", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Servers, nil
+}
+
+func (opts *WorkersOptions) values() url.Values {
+	q := make(url.Values)
+	if opts == nil {
+		return q
+	}
+	if opts.Queue != "" {
+		q.Set("queue", opts.Queue)
+	}
+	if opts.LongRunningThreshold > 0 {
+		q.Set("long_running", strconv.Itoa(int(opts.LongRunningThreshold.Seconds())))
+	}
+	return q
+}
+
+// ListWorkers returns the workers of the servers joined with the active tasks,
+// sorted with orphaned tasks first and then by elapsed time.
+func (c *Client) ListWorkers(ctx context.Context, opts *WorkersOptions) (*ListWorkersResponse, error) {
+	var resp ListWorkersResponse
+	if err := c.get(ctx, "/workers", opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelFlaggedWorkers sends the cancelation signal to the tasks of the workers past their
+// deadline, and of the long-running workers only if opts.LongRunningThreshold is set.
+// Orphaned tasks are reported instead. If taskIDs is non-empty, only the flagged tasks
+// with the given IDs are canceled.
+func (c *Client) CancelFlaggedWorkers(ctx context.Context, opts *WorkersOptions, taskIDs []string) (*BatchCancelResponse, error) {
+	var body interface{}
+	if len(taskIDs) > 0 {
+		body = &BatchRequest{TaskIDs: taskIDs}
+	}
+	var resp BatchCancelResponse
+	if err := c.do(ctx, http.MethodPost, "/workers:cancel_flagged", opts.values(), body, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetServerHistory returns the servers seen by the server tracker, their appearances
+// and disappearances, and the fleet size over time.
+func (c *Client) GetServerHistory(ctx context.Context, opts *ServerHistoryOptions) (*ServerHistory, error) {
+	q := url.Values{}
+	if opts != nil {
+		if opts.Duration > 0 {
+			q.Set("duration", strconv.Itoa(int(opts.Duration.Seconds())))
+		}
+		if opts.StuckAfter > 0 {
+			q.Set("stuck_after", strconv.Itoa(int(opts.StuckAfter.Seconds())))
+		}
+	}
+	var resp ServerHistory
+	if err := c.get(ctx, "/server_history", q, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
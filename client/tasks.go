@@ -0,0 +1,309 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strconv"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - Client methods for task related endpoints
+// ****************************************************************************
+
+// TaskState specifies the state of tasks an operation applies to.
+type TaskState string
+
+const (
+	TaskStateActive      TaskState = "active"
+	TaskStatePending     TaskState = "pending"
+	TaskStateAggregating TaskState = "aggregating"
+	TaskStateScheduled   TaskState = "scheduled"
+	TaskStateRetry       TaskState = "retry"
+	TaskStateArchived    TaskState = "archived"
+	TaskStateCompleted   TaskState = "completed"
+)
+
+// tasksPath returns the API path for the collection of tasks in the given state.
+func tasksPath(qname string, state TaskState) string {
+	return fmt.Sprintf("/queues/%s/%s_tasks", escape(qname), state)
+}
+
+// aggregatingTasksPath returns the API path for the collection of aggregating tasks in the given group.
+func aggregatingTasksPath(qname, group string) string {
+	return fmt.Sprintf("/queues/%s/groups/%s/aggregating_tasks", escape(qname), escape(group))
+}
+
+// GetTask returns information about the task.
+func (c *Client) GetTask(ctx context.Context, qname, taskID string) (*TaskInfo, error) {
+	var resp TaskInfo
+	if err := c.get(ctx, fmt.Sprintf("/queues/%s/tasks/%s", escape(qname), escape(taskID)), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// GetTaskWithTimeline returns information about the task along with its lifecycle events
+// in chronological order.
+func (c *Client) GetTaskWithTimeline(ctx context.Context, qname, taskID string) (*TaskInfo, error) {
+	var resp TaskInfo
+	path := fmt.Sprintf("/queues/%s/tasks/%s", escape(qname), escape(taskID))
+	if err := c.get(ctx, path, url.Values{"timeline": {"true"}}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListActiveTasks returns a page of active tasks in the queue.
+func (c *Client) ListActiveTasks(ctx context.Context, qname string, opts *ListOptions) (*ListActiveTasksResponse, error) {
+	var resp ListActiveTasksResponse
+	if err := c.get(ctx, tasksPath(qname, TaskStateActive), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListPendingTasks returns a page of pending tasks in the queue.
+func (c *Client) ListPendingTasks(ctx context.Context, qname string, opts *ListOptions) (*ListPendingTasksResponse, error) {
+	var resp ListPendingTasksResponse
+	if err := c.get(ctx, tasksPath(qname, TaskStatePending), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListAggregatingTasks returns a page of aggregating tasks in the group.
+func (c *Client) ListAggregatingTasks(ctx context.Context, qname, group string, opts *ListOptions) (*ListAggregatingTasksResponse, error) {
+	var resp ListAggregatingTasksResponse
+	if err := c.get(ctx, aggregatingTasksPath(qname, group), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListScheduledTasks returns a page of scheduled tasks in the queue.
+func (c *Client) ListScheduledTasks(ctx context.Context, qname string, opts *ListOptions) (*ListScheduledTasksResponse, error) {
+	var resp ListScheduledTasksResponse
+	if err := c.get(ctx, tasksPath(qname, TaskStateScheduled), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListRetryTasks returns a page of retry tasks in the queue.
+func (c *Client) ListRetryTasks(ctx context.Context, qname string, opts *ListOptions) (*ListRetryTasksResponse, error) {
+	var resp ListRetryTasksResponse
+	if err := c.get(ctx, tasksPath(qname, TaskStateRetry), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListArchivedTasks returns a page of archived tasks in the queue.
+func (c *Client) ListArchivedTasks(ctx context.Context, qname string, opts *ListOptions) (*ListArchivedTasksResponse, error) {
+	var resp ListArchivedTasksResponse
+	if err := c.get(ctx, tasksPath(qname, TaskStateArchived), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// ListCompletedTasks returns a page of completed tasks in the queue.
+func (c *Client) ListCompletedTasks(ctx context.Context, qname string, opts *ListOptions) (*ListCompletedTasksResponse, error) {
+	var resp ListCompletedTasksResponse
+	if err := c.get(ctx, tasksPath(qname, TaskStateCompleted), opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (opts *CompletedTaskStatsOptions) values() url.Values {
+	q := make(url.Values)
+	if opts == nil {
+		return q
+	}
+	if opts.Window > 0 {
+		q.Set("duration", strconv.Itoa(int(opts.Window.Seconds())))
+	}
+	if opts.Limit > 0 {
+		q.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	return q
+}
+
+// GetCompletedTaskStats returns the completion throughput, retention and result size
+// of the completed tasks in the queue, in total and per task type.
+func (c *Client) GetCompletedTaskStats(ctx context.Context, qname string, opts *CompletedTaskStatsOptions) (*CompletedTaskStats, error) {
+	var resp CompletedTaskStats
+	if err := c.get(ctx, "/queues/"+escape(qname)+"/completed_task_stats", opts.values(), &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CancelActiveTask sends a cancelation signal to the active task.
+func (c *Client) CancelActiveTask(ctx context.Context, qname, taskID string) error {
+	return c.post(ctx, tasksPath(qname, TaskStateActive)+"/"+escape(taskID)+":cancel", nil, nil)
+}
+
+// CancelAllActiveTasks sends a cancelation signal to all active tasks in the queue.
+func (c *Client) CancelAllActiveTasks(ctx context.Context, qname string) error {
+	return c.post(ctx, tasksPath(qname, TaskStateActive)+":cancel_all", nil, nil)
+}
+
+// BatchCancelActiveTasks sends a cancelation signal to the active tasks with the given IDs.
+func (c *Client) BatchCancelActiveTasks(ctx context.Context, qname string, taskIDs []string) (*BatchCancelResponse, error) {
+	var resp BatchCancelResponse
+	if err := c.post(ctx, tasksPath(qname, TaskStateActive)+":batch_cancel", &BatchRequest{TaskIDs: taskIDs}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteTask deletes the task in the given state.
+// Use DeleteAggregatingTask for tasks in aggregating state.
+func (c *Client) DeleteTask(ctx context.Context, qname string, state TaskState, taskID string) error {
+	return c.delete(ctx, tasksPath(qname, state)+"/"+escape(taskID), nil)
+}
+
+// DeleteAllTasks deletes all tasks in the given state and returns the number of tasks deleted.
+// Use DeleteAllAggregatingTasks for tasks in aggregating state.
+func (c *Client) DeleteAllTasks(ctx context.Context, qname string, state TaskState) (int, error) {
+	var resp DeleteAllResponse
+	if err := c.delete(ctx, tasksPath(qname, state)+":delete_all", &resp); err != nil {
+		return 0, err
+	}
+	return resp.Deleted, nil
+}
+
+// BatchDeleteTasks deletes the tasks with the given IDs in the given state.
+// Use BatchDeleteAggregatingTasks for tasks in aggregating state.
+func (c *Client) BatchDeleteTasks(ctx context.Context, qname string, state TaskState, taskIDs []string) (*BatchDeleteResponse, error) {
+	return c.batchDelete(ctx, tasksPath(qname, state), taskIDs)
+}
+
+// RunTask moves the task in the given state to pending state.
+// Use RunAggregatingTask for tasks in aggregating state.
+func (c *Client) RunTask(ctx context.Context, qname string, state TaskState, taskID string) error {
+	return c.post(ctx, tasksPath(qname, state)+"/"+escape(taskID)+":run", nil, nil)
+}
+
+// RunAllTasks moves all tasks in the given state to pending state and returns the number of tasks moved.
+// Use RunAllAggregatingTasks for tasks in aggregating state.
+func (c *Client) RunAllTasks(ctx context.Context, qname string, state TaskState) (int, error) {
+	return c.runAll(ctx, tasksPath(qname, state))
+}
+
+// BatchRunTasks moves the tasks with the given IDs in the given state to pending state.
+// Use BatchRunAggregatingTasks for tasks in aggregating state.
+func (c *Client) BatchRunTasks(ctx context.Context, qname string, state TaskState, taskIDs []string) (*BatchRunResponse, error) {
+	return c.batchRun(ctx, tasksPath(qname, state), taskIDs)
+}
+
+// ArchiveTask moves the task in the given state to archived state.
+// Use ArchiveAggregatingTask for tasks in aggregating state.
+func (c *Client) ArchiveTask(ctx context.Context, qname string, state TaskState, taskID string) error {
+	return c.post(ctx, tasksPath(qname, state)+"/"+escape(taskID)+":archive", nil, nil)
+}
+
+// ArchiveAllTasks moves all tasks in the given state to archived state and returns the number of tasks moved.
+// Use ArchiveAllAggregatingTasks for tasks in aggregating state.
+func (c *Client) ArchiveAllTasks(ctx context.Context, qname string, state TaskState) (int, error) {
+	return c.archiveAll(ctx, tasksPath(qname, state))
+}
+
+// BatchArchiveTasks moves the tasks with the given IDs in the given state to archived state.
+// Use BatchArchiveAggregatingTasks for tasks in aggregating state.
+func (c *Client) BatchArchiveTasks(ctx context.Context, qname string, state TaskState, taskIDs []string) (*BatchArchiveResponse, error) {
+	return c.batchArchive(ctx, tasksPath(qname, state), taskIDs)
+}
+
+// DeleteAggregatingTask deletes the aggregating task in the group.
+func (c *Client) DeleteAggregatingTask(ctx context.Context, qname, group, taskID string) error {
+	return c.delete(ctx, aggregatingTasksPath(qname, group)+"/"+escape(taskID), nil)
+}
+
+// DeleteAllAggregatingTasks deletes all aggregating tasks in the group and returns the number of tasks deleted.
+func (c *Client) DeleteAllAggregatingTasks(ctx context.Context, qname, group string) (int, error) {
+	var resp DeleteAllResponse
+	if err := c.delete(ctx, aggregatingTasksPath(qname, group)+":delete_all", &resp); err != nil {
+		return 0, err
+	}
+	return resp.Deleted, nil
+}
+
+// BatchDeleteAggregatingTasks deletes the aggregating tasks with the given IDs in the group.
+func (c *Client) BatchDeleteAggregatingTasks(ctx context.Context, qname, group string, taskIDs []string) (*BatchDeleteResponse, error) {
+	return c.batchDelete(ctx, aggregatingTasksPath(qname, group), taskIDs)
+}
+
+// RunAggregatingTask moves the aggregating task in the group to pending state.
+func (c *Client) RunAggregatingTask(ctx context.Context, qname, group, taskID string) error {
+	return c.post(ctx, aggregatingTasksPath(qname, group)+"/"+escape(taskID)+":run", nil, nil)
+}
+
+// RunAllAggregatingTasks moves all aggregating tasks in the group to pending state and returns the number of tasks moved.
+func (c *Client) RunAllAggregatingTasks(ctx context.Context, qname, group string) (int, error) {
+	return c.runAll(ctx, aggregatingTasksPath(qname, group))
+}
+
+// BatchRunAggregatingTasks moves the aggregating tasks with the given IDs in the group to pending state.
+func (c *Client) BatchRunAggregatingTasks(ctx context.Context, qname, group string, taskIDs []string) (*BatchRunResponse, error) {
+	return c.batchRun(ctx, aggregatingTasksPath(qname, group), taskIDs)
+}
+
+// ArchiveAggregatingTask moves the aggregating task in the group to archived state.
+func (c *Client) ArchiveAggregatingTask(ctx context.Context, qname, group, taskID string) error {
+	return c.post(ctx, aggregatingTasksPath(qname, group)+"/"+escape(taskID)+":archive", nil, nil)
+}
+
+// ArchiveAllAggregatingTasks moves all aggregating tasks in the group to archived state and returns the number of tasks moved.
+func (c *Client) ArchiveAllAggregatingTasks(ctx context.Context, qname, group string) (int, error) {
+	return c.archiveAll(ctx, aggregatingTasksPath(qname, group))
+}
+
+// BatchArchiveAggregatingTasks moves the aggregating tasks with the given IDs in the group to archived state.
+func (c *Client) BatchArchiveAggregatingTasks(ctx context.Context, qname, group string, taskIDs []string) (*BatchArchiveResponse, error) {
+	return c.batchArchive(ctx, aggregatingTasksPath(qname, group), taskIDs)
+}
+
+func (c *Client) runAll(ctx context.Context, path string) (int, error) {
+	var resp RunAllResponse
+	if err := c.post(ctx, path+":run_all", nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Scheduled, nil
+}
+
+func (c *Client) archiveAll(ctx context.Context, path string) (int, error) {
+	var resp ArchiveAllResponse
+	if err := c.post(ctx, path+":archive_all", nil, &resp); err != nil {
+		return 0, err
+	}
+	return resp.Archived, nil
+}
+
+func (c *Client) batchDelete(ctx context.Context, path string, taskIDs []string) (*BatchDeleteResponse, error) {
+	var resp BatchDeleteResponse
+	if err := c.post(ctx, path+":batch_delete", &BatchRequest{TaskIDs: taskIDs}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) batchRun(ctx context.Context, path string, taskIDs []string) (*BatchRunResponse, error) {
+	var resp BatchRunResponse
+	if err := c.post(ctx, path+":batch_run", &BatchRequest{TaskIDs: taskIDs}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+func (c *Client) batchArchive(ctx context.Context, path string, taskIDs []string) (*BatchArchiveResponse, error) {
+	var resp BatchArchiveResponse
+	if err := c.post(ctx, path+":batch_archive", &BatchRequest{TaskIDs: taskIDs}, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
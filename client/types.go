@@ -0,0 +1,1185 @@
+package client
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - request and response types of the asynqmon API
+// ****************************************************************************
+
+// Queue is a snapshot of a queue state.
+type Queue struct {
+	// Name of the queue.
+	Queue string `json:"queue"`
+	// Total number of bytes the queue and its tasks require to be stored in redis.
+	MemoryUsage int64 `json:"memory_usage_bytes"`
+	// Total number of tasks in the queue.
+	Size int `json:"size"`
+	// Totoal number of groups in the queue.
+	Groups int `json:"groups"`
+	// Latency of the queue in milliseconds.
+	LatencyMillisec int64 `json:"latency_msec"`
+	// Latency duration string for display purpose.
+	DisplayLatency string `json:"display_latency"`
+
+	// Number of tasks in each state.
+	Active      int `json:"active"`
+	Pending     int `json:"pending"`
+	Aggregating int `json:"aggregating"`
+	Scheduled   int `json:"scheduled"`
+	Retry       int `json:"retry"`
+	Archived    int `json:"archived"`
+	Completed   int `json:"completed"`
+
+	// Total number of tasks processed during the given date.
+	// The number includes both succeeded and failed tasks.
+	Processed int `json:"processed"`
+	// Breakdown of processed tasks.
+	Succeeded int `json:"succeeded"`
+	Failed    int `json:"failed"`
+	// Paused indicates whether the queue is paused.
+	Paused bool `json:"paused"`
+	// Time when this snapshot was taken.
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// QueueCoverageReport is the response of GetQueueCoverage.
+type QueueCoverageReport struct {
+	Queues           []*QueueCoverage `json:"queues"`
+	TotalConcurrency int              `json:"total_concurrency"`
+	// Queues no server is listening on.
+	OrphanedQueues []string `json:"orphaned_queues"`
+	// Queues servers listen on which do not exist in redis.
+	UnknownQueues []string                `json:"unknown_queues"`
+	Warnings      []*QueueCoverageWarning `json:"warnings"`
+}
+
+// QueueRanking is the response of GetQueueRanking.
+type QueueRanking struct {
+	// Source of the history of the queue stats: "prometheus", "samples"
+	// or "snapshot" if no history is available.
+	Source          string  `json:"source"`
+	SortBy          string  `json:"sort_by"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	// Total number of queues.
+	Total  int                  `json:"total"`
+	Queues []*QueueRankingEntry `json:"queues"`
+}
+
+// QueueRankingEntry is the change of the stats of a queue over the ranking period.
+type QueueRankingEntry struct {
+	Queue               string  `json:"queue"`
+	Size                int     `json:"size"`
+	SizeDelta           int     `json:"size_delta"`
+	LatencySeconds      float64 `json:"latency_seconds"`
+	LatencyDeltaSeconds float64 `json:"latency_delta_seconds"`
+	MemoryUsage         int64   `json:"memory_usage_bytes"`
+	MemoryDelta         int64   `json:"memory_delta_bytes"`
+	Processed           int     `json:"processed"`
+	Failed              int     `json:"failed"`
+	// Nil if no task was processed over the period.
+	FailureRate     *float64 `json:"failure_rate"`
+	CoverageSeconds float64  `json:"coverage_seconds"`
+	// Values of the ranking metric over the period.
+	Trend []*RankingTrendPoint `json:"trend"`
+}
+
+// RankingTrendPoint is a value of the ranking metric at a point in time.
+type RankingTrendPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// Metrics to rank queues by.
+const (
+	RankByGrowth      = "growth"
+	RankByLatency     = "latency"
+	RankByFailureRate = "failure_rate"
+	RankByMemory      = "memory"
+)
+
+// QueueRankingOptions specifies the queues returned by GetQueueRanking.
+type QueueRankingOptions struct {
+	// SortBy is the metric to rank queues by: RankByGrowth (default), RankByLatency,
+	// RankByFailureRate or RankByMemory.
+	SortBy string
+	// Limit is the number of queues to return (default 10).
+	Limit int
+	// Duration of the period over which changes are computed (default 1h).
+	Duration time.Duration
+}
+
+// QueueCoverage describes the servers consuming a queue and the worker capacity the queue gets.
+// WeightedConcurrency is the number of workers the queue is expected to get, and Share is its
+// fraction of the total concurrency of the servers.
+type QueueCoverage struct {
+	Queue                 string   `json:"queue"`
+	Paused                bool     `json:"paused"`
+	Pending               int      `json:"pending"`
+	ServerIDs             []string `json:"server_ids"`
+	Concurrency           int      `json:"concurrency"`
+	WeightedConcurrency   float64  `json:"weighted_concurrency"`
+	Share                 float64  `json:"share"`
+	StrictPriorityServers int      `json:"strict_priority_servers"`
+	Orphaned              bool     `json:"orphaned"`
+}
+
+// QueueCoverageWarning describes a queue with pending tasks and no workers.
+type QueueCoverageWarning struct {
+	Queue   string `json:"queue"`
+	Message string `json:"message"`
+}
+
+// ListSLOsResponse is the response of ListSLOs.
+type ListSLOsResponse struct {
+	// Source of the data used to evaluate the SLOs: "prometheus" or "samples".
+	Source string       `json:"source"`
+	SLOs   []*SLOStatus `json:"slos"`
+}
+
+// SLOStatus is the compliance of a queue SLO over its window.
+// Compliance, ErrorBudgetRemaining and BurnRate are nil if there is no data.
+type SLOStatus struct {
+	Name      string `json:"name"`
+	Queue     string `json:"queue"`
+	Kind      string `json:"kind"` // "latency" or "failure_ratio"
+	Objective string `json:"objective"`
+	// Fraction of good time or good tasks required by the SLO.
+	Target        float64 `json:"target"`
+	WindowSeconds float64 `json:"window_seconds"`
+	// Time span covered by the data, shorter than the window if asynqmon has not
+	// sampled the queue for the whole window.
+	CoverageSeconds      float64        `json:"coverage_seconds"`
+	Compliance           *float64       `json:"compliance"`
+	Met                  bool           `json:"met"`
+	ErrorBudgetRemaining *float64       `json:"error_budget_remaining"`
+	BurnRates            []*SLOBurnRate `json:"burn_rates"`
+	Error                string         `json:"error,omitempty"`
+}
+
+// SLOBurnRate is the rate at which the error budget was consumed over a recent period.
+// A burn rate of 1 consumes exactly the whole budget over the window of the SLO.
+type SLOBurnRate struct {
+	PeriodSeconds float64  `json:"period_seconds"`
+	BurnRate      *float64 `json:"burn_rate"`
+}
+
+// DailyStats holds aggregate data for a given day.
+type DailyStats struct {
+	Queue     string `json:"queue"`
+	Processed int    `json:"processed"`
+	Succeeded int    `json:"succeeded"`
+	Failed    int    `json:"failed"`
+	// Date in YYYY-MM-DD format.
+	// When aggregated by week or month, this is the first day of the period.
+	Date string `json:"date"`
+	// Number of days included in the aggregated stats.
+	// Set only when aggregated by week or month.
+	Days int `json:"days,omitempty"`
+}
+
+// Periods to aggregate daily stats by.
+const (
+	GroupByDay   = "day"
+	GroupByWeek  = "week"
+	GroupByMonth = "month"
+)
+
+// HistoryOptions specifies the daily stats returned by GetQueue and ListQueueStats.
+type HistoryOptions struct {
+	// Number of days to return, counting today (max 90).
+	Days int
+	// GroupBy aggregates the stats by GroupByDay (default), GroupByWeek or GroupByMonth.
+	GroupBy string
+}
+
+// QueueDetail is the response of GetQueue.
+type QueueDetail struct {
+	Current *Queue        `json:"current"`
+	History []*DailyStats `json:"history"`
+	// Notes left on the queue, oldest first.
+	Notes []*Note `json:"notes"`
+}
+
+// TaskInfo describes a task returned by GetTask.
+type TaskInfo struct {
+	ID       string `json:"id"`
+	Queue    string `json:"queue"`
+	Type     string `json:"type"`
+	Payload  string `json:"payload"`
+	State    string `json:"state"`
+	MaxRetry int    `json:"max_retry"`
+	Retried  int    `json:"retried"`
+	LastErr  string `json:"error_message"`
+	// LastFailedAt is in RFC3339 format, or empty string if the task has no failures.
+	LastFailedAt string `json:"last_failed_at"`
+	// Timeout is the number of seconds the task can be processed by Handler before being retried.
+	Timeout int `json:"timeout_seconds"`
+	// Deadline is in RFC3339 format, or empty string if not set.
+	Deadline string `json:"deadline"`
+	// NextProcessAt is in RFC3339 format, or empty string if not applicable.
+	NextProcessAt string `json:"next_process_at"`
+	// CompletedAt is in RFC3339 format, or empty string if not applicable.
+	CompletedAt string `json:"completed_at"`
+	Result      string `json:"result"`
+	// TTL is the number of seconds the task has left to be retained in the queue.
+	TTL int64 `json:"ttl_seconds"`
+	// Timeline is set only by GetTaskWithTimeline.
+	Timeline []*TaskEvent `json:"timeline,omitempty"`
+	// Notes left on the task, oldest first.
+	Notes []*Note `json:"notes"`
+	// ErrorClusterID is the ID of the error cluster of the last error, and ErrorClusterNotes
+	// the notes left on the cluster. Set only if the task has failed.
+	ErrorClusterID    string  `json:"error_cluster_id,omitempty"`
+	ErrorClusterNotes []*Note `json:"error_cluster_notes,omitempty"`
+}
+
+// TaskEvent is an event in the lifecycle of a task.
+type TaskEvent struct {
+	Time time.Time `json:"time"`
+	// Type is one of "action", "scheduler_enqueue", "state_change", "failed" or "completed".
+	Type string `json:"type"`
+	// Action is the operator action (e.g. "run", "batch_archive") for events of type "action".
+	Action    string `json:"action,omitempty"`
+	FromState string `json:"from_state,omitempty"`
+	ToState   string `json:"to_state,omitempty"`
+	User      string `json:"user,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// BaseTask holds the fields common to all tasks in list responses.
+type BaseTask struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Payload   string `json:"payload"`
+	Queue     string `json:"queue"`
+	MaxRetry  int    `json:"max_retry"`
+	Retried   int    `json:"retried"`
+	LastError string `json:"error_message"`
+}
+
+// ActiveTask is a task in active state.
+type ActiveTask struct {
+	BaseTask
+	// Started is in RFC3339 format, or "-" if the data is not available yet.
+	Started string `json:"start_time"`
+	// Deadline is in RFC3339 format, or "-" if the data is not available yet.
+	Deadline string `json:"deadline"`
+	// IsOrphaned indicates whether the task is left in active state with no worker processing it.
+	IsOrphaned bool `json:"is_orphaned"`
+}
+
+// PendingTask is a task in pending state.
+type PendingTask struct {
+	BaseTask
+}
+
+// AggregatingTask is a task in aggregating state.
+type AggregatingTask struct {
+	BaseTask
+	Group string `json:"group"`
+}
+
+// ScheduledTask is a task in scheduled state.
+type ScheduledTask struct {
+	BaseTask
+	NextProcessAt time.Time `json:"next_process_at"`
+}
+
+// RetryTask is a task in retry state.
+type RetryTask struct {
+	BaseTask
+	NextProcessAt time.Time `json:"next_process_at"`
+}
+
+// ArchivedTask is a task in archived state.
+type ArchivedTask struct {
+	BaseTask
+	LastFailedAt time.Time `json:"last_failed_at"`
+}
+
+// CompletedTask is a task in completed state.
+type CompletedTask struct {
+	BaseTask
+	CompletedAt time.Time `json:"completed_at"`
+	Result      string    `json:"result"`
+	// Number of seconds left for retention.
+	TTL int64 `json:"ttl_seconds"`
+}
+
+// ListActiveTasksResponse is the response of ListActiveTasks.
+type ListActiveTasksResponse struct {
+	Tasks []*ActiveTask `json:"tasks"`
+	Stats *Queue        `json:"stats"`
+}
+
+// ListPendingTasksResponse is the response of ListPendingTasks.
+type ListPendingTasksResponse struct {
+	Tasks []*PendingTask `json:"tasks"`
+	Stats *Queue         `json:"stats"`
+}
+
+// ListAggregatingTasksResponse is the response of ListAggregatingTasks.
+type ListAggregatingTasksResponse struct {
+	Tasks  []*AggregatingTask `json:"tasks"`
+	Stats  *Queue             `json:"stats"`
+	Groups []*GroupInfo       `json:"groups"`
+}
+
+// ListScheduledTasksResponse is the response of ListScheduledTasks.
+type ListScheduledTasksResponse struct {
+	Tasks []*ScheduledTask `json:"tasks"`
+	Stats *Queue           `json:"stats"`
+}
+
+// ListRetryTasksResponse is the response of ListRetryTasks.
+type ListRetryTasksResponse struct {
+	Tasks []*RetryTask `json:"tasks"`
+	Stats *Queue       `json:"stats"`
+}
+
+// ListArchivedTasksResponse is the response of ListArchivedTasks.
+type ListArchivedTasksResponse struct {
+	Tasks []*ArchivedTask `json:"tasks"`
+	Stats *Queue          `json:"stats"`
+}
+
+// ListCompletedTasksResponse is the response of ListCompletedTasks.
+type ListCompletedTasksResponse struct {
+	Tasks []*CompletedTask `json:"tasks"`
+	Stats *Queue           `json:"stats"`
+}
+
+// CompletedTaskStatsOptions specifies the window and the number of tasks scanned
+// to compute completed task stats.
+type CompletedTaskStatsOptions struct {
+	// Window of the completion throughput (default 1h).
+	Window time.Duration
+	// Limit is the maximum number of tasks to scan (default 10000).
+	Limit int
+}
+
+// CompletedTaskStats is the response of GetCompletedTaskStats.
+type CompletedTaskStats struct {
+	Queue string `json:"queue"`
+	// Start of the window and the duration of each bucket of Completions.
+	WindowStart   string  `json:"window_start"`
+	BucketSeconds float64 `json:"bucket_seconds"`
+	// Number of completed tasks in the queue, and the number of tasks scanned.
+	Total   int `json:"total"`
+	Scanned int `json:"scanned"`
+	CompletedStats
+	// Stats per task type, sorted by number of tasks in descending order.
+	TaskTypes []*CompletedTaskTypeStats `json:"task_types"`
+}
+
+// CompletedTaskTypeStats is the stats of the completed tasks of a task type.
+type CompletedTaskTypeStats struct {
+	TaskType string `json:"task_type"`
+	CompletedStats
+}
+
+// CompletedStats is the analytics of a set of completed tasks.
+type CompletedStats struct {
+	Count               int     `json:"count"`
+	CompletedInWindow   int     `json:"completed_in_window"`
+	ThroughputPerMinute float64 `json:"throughput_per_minute"`
+	// Number of tasks completed in each bucket of the window.
+	Completions []int `json:"completions"`
+	// Distributions of the retention period and of the time left before deletion.
+	Retention  []*DurationBucket `json:"retention"`
+	TTL        []*DurationBucket `json:"ttl"`
+	ResultSize ResultSizeStats   `json:"result_size"`
+}
+
+// DurationBucket counts the tasks with a duration in [MinSeconds, MaxSeconds).
+// MaxSeconds is zero if the bucket has no upper bound.
+type DurationBucket struct {
+	MinSeconds int64 `json:"min_seconds"`
+	MaxSeconds int64 `json:"max_seconds"`
+	Count      int   `json:"count"`
+}
+
+// ResultSizeStats describes the size of the results of tasks in bytes.
+type ResultSizeStats struct {
+	Min   int     `json:"min"`
+	Max   int     `json:"max"`
+	Avg   float64 `json:"avg"`
+	P50   int     `json:"p50"`
+	P95   int     `json:"p95"`
+	Total int64   `json:"total"`
+	// Number of tasks without result.
+	Empty int `json:"empty"`
+}
+
+// ErrorClusterOptions specifies the number of tasks scanned to compute error clusters.
+type ErrorClusterOptions struct {
+	// Limit is the maximum number of tasks to scan (default 10000).
+	Limit int
+}
+
+// ListErrorClustersResponse is the response of ListErrorClusters.
+type ListErrorClustersResponse struct {
+	// Clusters sorted by number of tasks in descending order.
+	Clusters []*ErrorCluster `json:"clusters"`
+	// Total is the number of tasks in the state, and Scanned the number of tasks scanned.
+	Total   int `json:"total"`
+	Scanned int `json:"scanned"`
+}
+
+// ErrorCluster is a group of tasks whose error messages are the same after masking
+// numbers, UUIDs and quoted values.
+type ErrorCluster struct {
+	ID             string   `json:"id"`
+	Pattern        string   `json:"pattern"`
+	ExampleMessage string   `json:"example_message"`
+	Count          int      `json:"count"`
+	ExampleTaskIDs []string `json:"example_task_ids"`
+	// FirstFailedAt and LastFailedAt are in RFC3339 format.
+	FirstFailedAt string           `json:"first_failed_at"`
+	LastFailedAt  string           `json:"last_failed_at"`
+	TaskTypes     []*TaskTypeCount `json:"task_types"`
+	// Notes left on the cluster, oldest first.
+	Notes []*Note `json:"notes"`
+}
+
+// TaskTypeCount is the number of tasks of a task type.
+type TaskTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// BatchRequest is the request body used for all batch operations.
+type BatchRequest struct {
+	TaskIDs []string `json:"task_ids"`
+}
+
+// BatchCancelResponse is the response of BatchCancelActiveTasks.
+type BatchCancelResponse struct {
+	CanceledIDs []string `json:"canceled_ids"`
+	ErrorIDs    []string `json:"error_ids"`
+}
+
+// BatchDeleteResponse is the response of batch delete operations.
+type BatchDeleteResponse struct {
+	// Task ids that were successfully deleted.
+	DeletedIDs []string `json:"deleted_ids"`
+	// Task ids that were not deleted.
+	FailedIDs []string `json:"failed_ids"`
+}
+
+// BatchRunResponse is the response of batch run operations.
+type BatchRunResponse struct {
+	// Task ids that were successfully moved to the pending state.
+	PendingIDs []string `json:"pending_ids"`
+	// Task ids that were not able to move to the pending state.
+	ErrorIDs []string `json:"error_ids"`
+}
+
+// BatchArchiveResponse is the response of batch archive operations.
+type BatchArchiveResponse struct {
+	// Task ids that were successfully moved to the archived state.
+	ArchivedIDs []string `json:"archived_ids"`
+	// Task ids that were not able to move to the archived state.
+	ErrorIDs []string `json:"error_ids"`
+}
+
+// DeleteAllResponse is the response of delete-all operations.
+type DeleteAllResponse struct {
+	// Number of tasks deleted.
+	Deleted int `json:"deleted"`
+}
+
+// RunAllResponse is the response of run-all operations.
+type RunAllResponse struct {
+	// Number of tasks scheduled to run.
+	Scheduled int `json:"scheduled"`
+}
+
+// ArchiveAllResponse is the response of archive-all operations.
+type ArchiveAllResponse struct {
+	// Number of tasks archived.
+	Archived int `json:"archived"`
+}
+
+// GroupInfo describes a group of aggregating tasks.
+type GroupInfo struct {
+	Group string `json:"group"`
+	Size  int    `json:"size"`
+}
+
+// ListGroupsResponse is the response of ListGroups.
+type ListGroupsResponse struct {
+	Queue  *Queue       `json:"stats"`
+	Groups []*GroupInfo `json:"groups"`
+}
+
+// ListGroupDetailsResponse is the response of ListGroupDetails.
+type ListGroupDetailsResponse struct {
+	Groups        []*GroupDetail     `json:"groups"`
+	SizeHistogram []*GroupSizeBucket `json:"size_histogram"`
+}
+
+// GroupDetail describes the tasks waiting in a group to be aggregated.
+// Times are in RFC3339 format, and TaskTypes counts the types of the first Scanned tasks.
+type GroupDetail struct {
+	Group          string           `json:"group"`
+	Size           int              `json:"size"`
+	OldestTaskTime string           `json:"oldest_task_time"`
+	NewestTaskTime string           `json:"newest_task_time"`
+	AgeSeconds     float64          `json:"age_seconds"`
+	TaskTypes      []*TaskTypeCount `json:"task_types"`
+	Scanned        int              `json:"scanned"`
+}
+
+// GroupSizeBucket is the number of groups with a size in [Min, Max].
+// Max is 0 for the last bucket, which has no upper bound.
+type GroupSizeBucket struct {
+	Min    int `json:"min"`
+	Max    int `json:"max"`
+	Groups int `json:"groups"`
+}
+
+// GroupDetailsOptions specifies the number of tasks scanned per group by ListGroupDetails.
+type GroupDetailsOptions struct {
+	// Sample is the number of tasks scanned per group to count task types (default 1000).
+	Sample int
+}
+
+// FlushGroupsRequest selects the groups to flush, either by name or by age.
+type FlushGroupsRequest struct {
+	Groups []string `json:"groups,omitempty"`
+	// Flush the groups whose oldest task was added more than the given number of seconds ago.
+	OlderThanSeconds int `json:"older_than_seconds,omitempty"`
+}
+
+// FlushGroupsResponse is the response of FlushGroups.
+type FlushGroupsResponse struct {
+	Results []*GroupFlushResult `json:"results"`
+	// Total number of tasks moved to pending state.
+	Run int `json:"run"`
+}
+
+// GroupFlushResult is the outcome of flushing a group.
+type GroupFlushResult struct {
+	Group string `json:"group"`
+	Run   int    `json:"run"`
+	Error string `json:"error"`
+}
+
+// ServerInfo describes a running asynq server.
+type ServerInfo struct {
+	ID             string         `json:"id"`
+	Host           string         `json:"host"`
+	PID            int            `json:"pid"`
+	Concurrency    int            `json:"concurrency"`
+	Queues         map[string]int `json:"queue_priorities"`
+	StrictPriority bool           `json:"strict_priority_enabled"`
+	Started        string         `json:"start_time"`
+	Status         string         `json:"status"`
+	ActiveWorkers  []*WorkerInfo  `json:"active_workers"`
+}
+
+// WorkerInfo describes a worker processing a task.
+type WorkerInfo struct {
+	TaskID      string `json:"task_id"`
+	Queue       string `json:"queue"`
+	TaskType    string `json:"task_type"`
+	TaskPayload string `json:"task_payload"`
+	Started     string `json:"start_time"`
+}
+
+// ServerHistory is the response of GetServerHistory.
+// Enabled is false if the server does not track asynq servers.
+type ServerHistory struct {
+	Enabled                  bool                  `json:"enabled"`
+	IntervalSeconds          float64               `json:"interval_seconds"`
+	RetentionSeconds         float64               `json:"retention_seconds"`
+	StuckStoppedAfterSeconds float64               `json:"stuck_stopped_after_seconds"`
+	Servers                  []*ServerHistoryEntry `json:"servers"`
+	Events                   []*ServerFleetEvent   `json:"events"`
+	Samples                  []*FleetSample        `json:"samples"`
+	// Number of flagged servers.
+	StuckStopped   int    `json:"stuck_stopped"`
+	StaleHeartbeat int    `json:"stale_heartbeat"`
+	LastError      string `json:"last_error"`
+}
+
+// ServerHistoryEntry is a server seen by the server tracker.
+// DisappearedAt is nil if the server is alive.
+type ServerHistoryEntry struct {
+	ID                 string         `json:"id"`
+	Host               string         `json:"host"`
+	PID                int            `json:"pid"`
+	Concurrency        int            `json:"concurrency"`
+	Queues             map[string]int `json:"queue_priorities"`
+	StrictPriority     bool           `json:"strict_priority_enabled"`
+	Started            time.Time      `json:"start_time"`
+	Status             string         `json:"status"`
+	StatusSince        time.Time      `json:"status_since"`
+	FirstSeen          time.Time      `json:"first_seen"`
+	LastSeen           time.Time      `json:"last_seen"`
+	HeartbeatExpiresAt time.Time      `json:"heartbeat_expires_at"`
+	StaleHeartbeat     bool           `json:"stale_heartbeat"`
+	StuckStopped       bool           `json:"stuck_stopped"`
+	DisappearedAt      *time.Time     `json:"disappeared_at"`
+	// "shutdown", "heartbeat_expired" or "unknown".
+	DisappearReason string `json:"disappear_reason"`
+}
+
+// ServerFleetEvent is an appearance ("appeared") or a disappearance ("disappeared") of a server.
+type ServerFleetEvent struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	ServerID string    `json:"server_id"`
+	Host     string    `json:"host"`
+	PID      int       `json:"pid"`
+	Reason   string    `json:"reason"`
+}
+
+// FleetSample is the size of the fleet at a point in time.
+type FleetSample struct {
+	Time          time.Time `json:"time"`
+	Servers       int       `json:"servers"`
+	Concurrency   int       `json:"concurrency"`
+	ActiveWorkers int       `json:"active_workers"`
+}
+
+// ServerHistoryOptions specifies the history returned by GetServerHistory.
+type ServerHistoryOptions struct {
+	// Duration of the history to return. Default is the retention period of the server.
+	Duration time.Duration
+	// Duration after which a server with "stopped" status is flagged as stuck (default 5m).
+	StuckAfter time.Duration
+}
+
+// WorkersOptions specifies the workers to list.
+type WorkersOptions struct {
+	// Queue limits the workers to the queue. All queues if empty.
+	Queue string
+	// LongRunningThreshold is the elapsed time above which a worker is flagged
+	// as long-running (default 10m).
+	LongRunningThreshold time.Duration
+}
+
+// ListWorkersResponse is the response of ListWorkers.
+type ListWorkersResponse struct {
+	Workers                     []*WorkerEntry `json:"workers"`
+	LongRunningThresholdSeconds float64        `json:"long_running_threshold_seconds"`
+	// Number of flagged workers.
+	PastDeadline int `json:"past_deadline"`
+	LongRunning  int `json:"long_running"`
+	Orphaned     int `json:"orphaned"`
+}
+
+// WorkerEntry is a worker processing an active task, or an active task without a worker.
+type WorkerEntry struct {
+	TaskID      string `json:"task_id"`
+	Queue       string `json:"queue"`
+	TaskType    string `json:"task_type"`
+	TaskPayload string `json:"task_payload"`
+	// Server running the worker; empty if no worker is found.
+	ServerID string `json:"server_id"`
+	Host     string `json:"host"`
+	PID      int    `json:"pid"`
+	// Started and Deadline are in RFC3339 format, or empty if no worker is found.
+	Started        string  `json:"start_time"`
+	Deadline       string  `json:"deadline"`
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+	PastDeadline   bool    `json:"past_deadline"`
+	LongRunning    bool    `json:"long_running"`
+	IsOrphaned     bool    `json:"is_orphaned"`
+}
+
+// SchedulerEntry describes a periodic task registered by a scheduler.
+type SchedulerEntry struct {
+	ID            string   `json:"id"`
+	Spec          string   `json:"spec"`
+	TaskType      string   `json:"task_type"`
+	TaskPayload   string   `json:"task_payload"`
+	Opts          []string `json:"options"`
+	NextEnqueueAt string   `json:"next_enqueue_at"`
+	// Empty if there were no previous enqueue events.
+	PrevEnqueueAt string `json:"prev_enqueue_at,omitempty"`
+}
+
+// SchedulerEnqueueEvent describes an event of a scheduler enqueueing a task.
+type SchedulerEnqueueEvent struct {
+	TaskID     string `json:"task_id"`
+	EnqueuedAt string `json:"enqueued_at"`
+}
+
+// RedisInfo is the response of GetRedisInfo.
+type RedisInfo struct {
+	Addr    string            `json:"address"`
+	Info    map[string]string `json:"info"`
+	RawInfo string            `json:"raw_info"`
+	Cluster bool              `json:"cluster"`
+
+	// Following fields are only set when connected to redis cluster.
+	RawClusterNodes string             `json:"raw_cluster_nodes"`
+	QueueLocations  []*QueueLocation   `json:"queue_locations"`
+	Nodes           []*ClusterNodeInfo `json:"nodes"`
+	Warnings        []string           `json:"warnings"`
+
+	// Following field is only set when connected to redis through sentinels.
+	Sentinel *SentinelInfo `json:"sentinel"`
+}
+
+// QueueLocation describes the location of a queue in redis cluster.
+type QueueLocation struct {
+	Queue   string   `json:"queue"`
+	KeySlot int64    `json:"keyslot"`
+	Nodes   []string `json:"nodes"`
+}
+
+// ClusterNodeInfo describes a master or replica node in redis cluster.
+type ClusterNodeInfo struct {
+	ID                string       `json:"id"`
+	Addr              string       `json:"address"`
+	Role              string       `json:"role"`
+	MasterID          string       `json:"master_id"`
+	Flags             []string     `json:"flags"`
+	Connected         bool         `json:"connected"`
+	Slots             []*SlotRange `json:"slots"`
+	SlotCount         int          `json:"slot_count"`
+	Queues            []string     `json:"queues"`
+	UsedMemory        int64        `json:"used_memory"`
+	UsedMemoryHuman   string       `json:"used_memory_human"`
+	MaxMemory         int64        `json:"maxmemory"`
+	OpsPerSec         int64        `json:"ops_per_sec"`
+	ConnectedClients  int64        `json:"connected_clients"`
+	ReplicationOffset int64        `json:"replication_offset"`
+	ReplicationLag    int64        `json:"replication_lag"`
+}
+
+// SlotRange is a range of cluster key slots (both inclusive).
+type SlotRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// SentinelInfo is the view of a sentinel on the master asynqmon is connected to.
+// Error is set if no sentinel could be queried.
+type SentinelInfo struct {
+	SentinelAddr       string              `json:"sentinel_address"`
+	MasterName         string              `json:"master_name"`
+	Master             *SentinelInstance   `json:"master"`
+	Replicas           []*SentinelInstance `json:"replicas"`
+	Sentinels          []*SentinelInstance `json:"sentinels"`
+	Quorum             int                 `json:"quorum"`
+	QuorumOK           bool                `json:"quorum_ok"`
+	QuorumStatus       string              `json:"quorum_status"`
+	FailoverInProgress bool                `json:"failover_in_progress"`
+	FailoverState      string              `json:"failover_state"`
+	Error              string              `json:"error"`
+}
+
+// SentinelInstance is a master, replica or sentinel as seen by a sentinel.
+type SentinelInstance struct {
+	Addr              string   `json:"address"`
+	RunID             string   `json:"run_id"`
+	Flags             []string `json:"flags"`
+	Down              bool     `json:"down"`
+	LastOKPingReplyMs int64    `json:"last_ok_ping_reply_ms"`
+	MasterLinkStatus  string   `json:"master_link_status"`
+	ReplicationOffset int64    `json:"replication_offset"`
+}
+
+// RedisInfoHistory is the response of GetRedisInfoHistory.
+// Enabled is false if the server does not sample redis INFO.
+type RedisInfoHistory struct {
+	Enabled          bool               `json:"enabled"`
+	IntervalSeconds  float64            `json:"interval_seconds"`
+	RetentionSeconds float64            `json:"retention_seconds"`
+	Nodes            []string           `json:"nodes"`
+	Samples          []*RedisInfoSample `json:"samples"`
+	LastError        string             `json:"last_error"`
+}
+
+// RedisInfoSample is a sample of redis INFO fields.
+// KeyspaceHitRatio is nil if there were no keyspace lookups since the previous sample.
+type RedisInfoSample struct {
+	Time             time.Time `json:"time"`
+	UsedMemory       int64     `json:"used_memory"`
+	ConnectedClients int64     `json:"connected_clients"`
+	OpsPerSec        int64     `json:"ops_per_sec"`
+	EvictedKeys      int64     `json:"evicted_keys"`
+	KeyspaceHits     int64     `json:"keyspace_hits"`
+	KeyspaceMisses   int64     `json:"keyspace_misses"`
+	KeyspaceHitRatio *float64  `json:"keyspace_hit_ratio"`
+}
+
+// RedisInfoHistoryOptions specifies the samples returned by GetRedisInfoHistory.
+type RedisInfoHistoryOptions struct {
+	// Duration of the history to return. Default is the retention period of the server.
+	Duration time.Duration
+	// Node address to return the samples of. Samples are summed over all nodes if empty.
+	Node string
+}
+
+// RedisMemoryOptions specifies the queue and the sample size used by GetRedisMemory.
+// Zero values use the server defaults.
+type RedisMemoryOptions struct {
+	// Queue to analyze. All queues are analyzed if empty.
+	Queue string
+	// Maximum number of keys to scan per queue.
+	SampleSize int
+	// Number of largest payloads to return.
+	Top int
+}
+
+// RedisMemory is the response of GetRedisMemory.
+type RedisMemory struct {
+	SampleSize      int                    `json:"sample_size"`
+	Queues          []*QueueMemoryUsage    `json:"queues"`
+	ByState         []*StateMemoryUsage    `json:"by_state"`
+	ByTaskType      []*TaskTypeMemoryUsage `json:"by_task_type"`
+	LargestPayloads []*TaskPayloadSize     `json:"largest_payloads"`
+}
+
+// QueueMemoryUsage describes the memory used by the redis keys of a queue.
+// If Complete is false, the numbers are based on a sample of the keys.
+type QueueMemoryUsage struct {
+	Queue             string                 `json:"queue"`
+	ScannedKeys       int                    `json:"scanned_keys"`
+	Complete          bool                   `json:"complete"`
+	MemoryBytes       int64                  `json:"memory_bytes"`
+	ApproxMemoryBytes int64                  `json:"approx_memory_bytes"`
+	ByKeyType         []*KeyTypeMemoryUsage  `json:"by_key_type"`
+	ByState           []*StateMemoryUsage    `json:"by_state"`
+	ByTaskType        []*TaskTypeMemoryUsage `json:"by_task_type"`
+}
+
+// KeyTypeMemoryUsage describes the memory used by a kind of redis key (e.g. "task", "archived", "unique").
+type KeyTypeMemoryUsage struct {
+	KeyType     string `json:"key_type"`
+	Keys        int    `json:"keys"`
+	MemoryBytes int64  `json:"memory_bytes"`
+}
+
+// StateMemoryUsage describes the memory used by the tasks in a state.
+// EstimatedMemoryBytes is extrapolated from the sampled tasks to all tasks in the state.
+type StateMemoryUsage struct {
+	State                string `json:"state"`
+	SampledTasks         int    `json:"sampled_tasks"`
+	MemoryBytes          int64  `json:"memory_bytes"`
+	Tasks                int    `json:"tasks"`
+	EstimatedMemoryBytes int64  `json:"estimated_memory_bytes"`
+}
+
+// TaskTypeMemoryUsage describes the memory used by the sampled tasks of a task type.
+type TaskTypeMemoryUsage struct {
+	TaskType     string `json:"task_type"`
+	SampledTasks int    `json:"sampled_tasks"`
+	MemoryBytes  int64  `json:"memory_bytes"`
+	PayloadBytes int64  `json:"payload_bytes"`
+}
+
+// TaskPayloadSize describes the payload size of a task.
+type TaskPayloadSize struct {
+	Queue        string `json:"queue"`
+	TaskID       string `json:"task_id"`
+	TaskType     string `json:"task_type"`
+	State        string `json:"state"`
+	PayloadBytes int    `json:"payload_bytes"`
+	MemoryBytes  int64  `json:"memory_bytes"`
+}
+
+// RedisNode is a redis server. Role is "master" or "replica" in cluster mode, and empty otherwise.
+type RedisNode struct {
+	Addr string `json:"address"`
+	Role string `json:"role,omitempty"`
+}
+
+// RedisSlowLog is the response of GetRedisSlowLog.
+type RedisSlowLog struct {
+	Nodes []*RedisSlowLogNode `json:"nodes"`
+}
+
+// RedisSlowLogNode holds the slow log entries of a redis server, latest first.
+type RedisSlowLogNode struct {
+	RedisNode
+	Entries []*SlowLogEntry `json:"entries"`
+}
+
+// SlowLogEntry is an entry of the redis slow log.
+type SlowLogEntry struct {
+	ID             int64     `json:"id"`
+	Time           time.Time `json:"time"`
+	DurationMicros int64     `json:"duration_us"`
+	Args           []string  `json:"args"`
+	ClientAddr     string    `json:"client_addr"`
+	ClientName     string    `json:"client_name"`
+}
+
+// RedisLatency is the response of GetRedisLatency.
+type RedisLatency struct {
+	Nodes []*RedisLatencyNode `json:"nodes"`
+}
+
+// RedisLatencyNode holds the latency events of a redis server.
+type RedisLatencyNode struct {
+	RedisNode
+	Events []*LatencyEvent `json:"events"`
+}
+
+// LatencyEvent is a latency event reported by the redis latency monitor.
+type LatencyEvent struct {
+	Event        string           `json:"event"`
+	Time         time.Time        `json:"time"`
+	LatencyMs    int64            `json:"latency_ms"`
+	MaxLatencyMs int64            `json:"max_latency_ms"`
+	History      []*LatencySample `json:"history"`
+}
+
+// LatencySample is a latency spike of a latency event.
+type LatencySample struct {
+	Time      time.Time `json:"time"`
+	LatencyMs int64     `json:"latency_ms"`
+}
+
+// RedisClients is the response of GetRedisClients.
+type RedisClients struct {
+	Nodes []*RedisClientsNode `json:"nodes"`
+}
+
+// RedisClientsNode holds the clients connected to a redis server grouped by name and by host.
+type RedisClientsNode struct {
+	RedisNode
+	TotalClients int                 `json:"total_clients"`
+	ByName       []*RedisClientGroup `json:"by_name"`
+	ByHost       []*RedisClientGroup `json:"by_host"`
+}
+
+// RedisClientGroup is a group of connected clients with the same name or host.
+type RedisClientGroup struct {
+	Key            string         `json:"key"`
+	Clients        int            `json:"clients"`
+	Blocked        int            `json:"blocked"`
+	MaxAgeSeconds  int64          `json:"max_age_seconds"`
+	MaxIdleSeconds int64          `json:"max_idle_seconds"`
+	LastCommands   map[string]int `json:"last_commands"`
+}
+
+// RedisCommandStats is the response of GetRedisCommandStats.
+type RedisCommandStats struct {
+	Nodes []*RedisCommandStatsNode `json:"nodes"`
+}
+
+// RedisCommandStatsNode holds the command stats of a redis server.
+type RedisCommandStatsNode struct {
+	RedisNode
+	Commands []*RedisCommandStat `json:"commands"`
+}
+
+// RedisCommandStat is the stats of a redis command reported by INFO commandstats.
+type RedisCommandStat struct {
+	Command       string  `json:"command"`
+	Calls         int64   `json:"calls"`
+	Usec          int64   `json:"usec"`
+	UsecPerCall   float64 `json:"usec_per_call"`
+	RejectedCalls int64   `json:"rejected_calls"`
+	FailedCalls   int64   `json:"failed_calls"`
+}
+
+// MetricsOptions specifies the time range and queues used by GetMetrics.
+// Zero values use the server defaults.
+type MetricsOptions struct {
+	// Duration to scan for metrics.
+	Duration time.Duration
+	// End time of the range.
+	EndTime time.Time
+	// Queues to get metrics for. Empty list indicates all queues.
+	Queues []string
+}
+
+// Metrics is the response of GetMetrics.
+// Each field holds the raw Prometheus range query response.
+type Metrics struct {
+	QueueSize            json.RawMessage `json:"queue_size"`
+	QueueLatency         json.RawMessage `json:"queue_latency_seconds"`
+	QueueMemUsgApprox    json.RawMessage `json:"queue_memory_usage_approx_bytes"`
+	ProcessedPerSecond   json.RawMessage `json:"tasks_processed_per_second"`
+	FailedPerSecond      json.RawMessage `json:"tasks_failed_per_second"`
+	ErrorRate            json.RawMessage `json:"error_rate"`
+	PendingTasksByQueue  json.RawMessage `json:"pending_tasks_by_queue"`
+	RetryTasksByQueue    json.RawMessage `json:"retry_tasks_by_queue"`
+	ArchivedTasksByQueue json.RawMessage `json:"archived_tasks_by_queue"`
+}
+
+// Webhook is a webhook configured on the server. The secret is not returned.
+type Webhook struct {
+	URL string `json:"url"`
+	// Whether the payloads are signed with a secret.
+	Signed          bool                   `json:"signed"`
+	Events          []string               `json:"events"`
+	CountThresholds []*QueueCountThreshold `json:"count_thresholds"`
+}
+
+// QueueCountThreshold is the minimum increase of the number of tasks in a state of a queue
+// (all queues if Queue is empty) which triggers a "queue.count_changed" event.
+type QueueCountThreshold struct {
+	Queue       string `json:"queue,omitempty"`
+	State       string `json:"state"`
+	MinIncrease int    `json:"min_increase"`
+}
+
+// WebhookDeliveries is the response of ListWebhookDeliveries.
+type WebhookDeliveries struct {
+	// Total number of deliveries with the status in the delivery log.
+	Total      int                `json:"total"`
+	Deliveries []*WebhookDelivery `json:"deliveries"`
+}
+
+// WebhookDelivery is the delivery of an event to a webhook.
+type WebhookDelivery struct {
+	ID       string            `json:"id"`
+	URL      string            `json:"url"`
+	Event    *WebhookEvent     `json:"event"`
+	Status   string            `json:"status"` // "pending", "delivered", "failed" or "dropped"
+	Attempts []*WebhookAttempt `json:"attempts"`
+}
+
+// WebhookEvent is the payload sent to webhooks.
+type WebhookEvent struct {
+	ID    string    `json:"id"`
+	Type  string    `json:"type"`
+	Time  time.Time `json:"time"`
+	Queue string    `json:"queue,omitempty"`
+	// Set for "action" events.
+	Action *ActionEvent `json:"action,omitempty"`
+	// Set for "queue.count_changed" events.
+	Changes []*QueueCountChange `json:"changes,omitempty"`
+}
+
+// ActionEvent describes an API request which changed something.
+type ActionEvent struct {
+	Method    string            `json:"method"`
+	Route     string            `json:"route"`
+	Path      string            `json:"path"`
+	Vars      map[string]string `json:"vars,omitempty"`
+	Status    int               `json:"status"`
+	User      string            `json:"user,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+// QueueCountChange is the change of the number of tasks in a state between two samples of a queue.
+type QueueCountChange struct {
+	State string `json:"state"`
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Delta int    `json:"delta"`
+}
+
+// WebhookAttempt is an attempt to deliver an event.
+type WebhookAttempt struct {
+	Time time.Time `json:"time"`
+	// Zero if no response was received.
+	StatusCode int     `json:"status_code,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// WebhookDeliveryOptions specifies the deliveries returned by ListWebhookDeliveries.
+type WebhookDeliveryOptions struct {
+	// Status of the deliveries to return. Empty returns all deliveries.
+	Status string
+	// Limit is the number of deliveries to return (default 100).
+	Limit int
+}
+
+// Note is a comment left by an operator on a task, a queue or an error cluster.
+type Note struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Annotation marks a time range (e.g. an incident or a deploy) on the metrics charts.
+type Annotation struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	StartTime time.Time `json:"start_time"`
+	// Equal to StartTime for an annotation of a point in time.
+	EndTime time.Time `json:"end_time"`
+	// Queues the annotation applies to. Empty for all queues.
+	Queues    []string  `json:"queues"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// AnnotationOptions specifies the annotations returned by ListAnnotations.
+// Zero values use the server defaults.
+type AnnotationOptions struct {
+	// Duration of the time range (default 1h).
+	Duration time.Duration
+	// End time of the time range (default now).
+	EndTime time.Time
+	// Queues the annotations apply to. Empty list indicates all queues.
+	Queues []string
+}
+
+// CreateAnnotationRequest is the annotation to create with CreateAnnotation.
+type CreateAnnotationRequest struct {
+	Text string `json:"text"`
+	// Author of the annotation, used if the server doesn't know the authenticated user.
+	Author    string    `json:"author,omitempty"`
+	StartTime time.Time `json:"start_time"`
+	// EndTime defaults to StartTime if nil.
+	EndTime *time.Time `json:"end_time,omitempty"`
+	// Queues the annotation applies to. Empty for all queues.
+	Queues []string `json:"queues,omitempty"`
+}
+
+// Visibility of a saved view.
+const (
+	// Only the owner can see the view. Requires the server to know the authenticated user.
+	ViewVisibilityPrivate = "private"
+	// Everyone can see the view, only the owner can update or delete it.
+	ViewVisibilityTeam = "team"
+)
+
+// SavedView is a named preset of a task list, stored on the server.
+type SavedView struct {
+	ID    string    `json:"id"`
+	Name  string    `json:"name"`
+	Queue string    `json:"queue"`
+	State TaskState `json:"state"`
+	// Filter parameters of the task list (e.g. {"type": "payments:*"}).
+	Filters  map[string]string `json:"filters"`
+	Sort     string            `json:"sort"`
+	PageSize int               `json:"page_size"`
+	// Owner is the user who created the view, empty if the server doesn't know the authenticated user.
+	Owner      string    `json:"owner"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ViewRequest is the view to create with CreateView or the new fields of a view updated with UpdateView.
+type ViewRequest struct {
+	Name     string            `json:"name"`
+	Queue    string            `json:"queue"`
+	State    TaskState         `json:"state"`
+	Filters  map[string]string `json:"filters,omitempty"`
+	Sort     string            `json:"sort,omitempty"`
+	PageSize int               `json:"page_size,omitempty"`
+	// Visibility defaults to ViewVisibilityPrivate if the user is authenticated, and to
+	// ViewVisibilityTeam otherwise.
+	Visibility string `json:"visibility,omitempty"`
+}
+
+// DryRunResult is the preview of a destructive request.
+type DryRunResult struct {
+	// Number of tasks the request would affect at the time of the dry run.
+	Affected int `json:"affected"`
+	// Token to pass with WithConfirmationToken to execute the request. It can be used once.
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"context"
+	"net/url"
+)
+
+// ListViews returns the saved views visible to the user, sorted by name: the team views and the user's own
+// private views. If qname is non-empty, only the views of the queue are returned.
+func (c *Client) ListViews(ctx context.Context, qname string) ([]*SavedView, error) {
+	var query url.Values
+	if qname != "" {
+		query = url.Values{"queue": {qname}}
+	}
+	var resp struct {
+		Views []*SavedView `json:"views"`
+	}
+	if err := c.get(ctx, "/views", query, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Views, nil
+}
+
+// GetView returns the saved view with the ID.
+func (c *Client) GetView(ctx context.Context, id string) (*SavedView, error) {
+	var resp SavedView
+	if err := c.get(ctx, "/views/"+escape(id), nil, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// CreateView saves a view owned by the user.
+func (c *Client) CreateView(ctx context.Context, req *ViewRequest) (*SavedView, error) {
+	var resp SavedView
+	if err := c.post(ctx, "/views", req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// UpdateView replaces the fields of the saved view. Only the owner of a view can update it.
+func (c *Client) UpdateView(ctx context.Context, id string, req *ViewRequest) (*SavedView, error) {
+	var resp SavedView
+	if err := c.put(ctx, "/views/"+escape(id), req, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
+
+// DeleteView deletes the saved view. Only the owner of a view can delete it.
+func (c *Client) DeleteView(ctx context.Context, id string) error {
+	return c.delete(ctx, "/views/"+escape(id), nil)
+}
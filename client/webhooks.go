@@ -0,0 +1,36 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// ListWebhooks returns the webhooks configured on the server.
+func (c *Client) ListWebhooks(ctx context.Context) ([]*Webhook, error) {
+	var resp struct {
+		Webhooks []*Webhook `json:"webhooks"`
+	}
+	if err := c.get(ctx, "/webhooks", nil, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Webhooks, nil
+}
+
+// ListWebhookDeliveries returns the recent deliveries of events to webhooks, most recent first.
+func (c *Client) ListWebhookDeliveries(ctx context.Context, opts *WebhookDeliveryOptions) (*WebhookDeliveries, error) {
+	q := url.Values{}
+	if opts != nil {
+		if opts.Status != "" {
+			q.Set("status", opts.Status)
+		}
+		if opts.Limit > 0 {
+			q.Set("limit", strconv.Itoa(opts.Limit))
+		}
+	}
+	var resp WebhookDeliveries
+	if err := c.get(ctx, "/webhook_deliveries", q, &resp); err != nil {
+		return nil, err
+	}
+	return &resp, nil
+}
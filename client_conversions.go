@@ -0,0 +1,52 @@
+package asynqmon
+
+import (
+	"github.com/hibiken/asynq"
+	"github.com/hibiken/asynqmon/client"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - conversion functions from asynq types to the types of the client package,
+//     for programs reading redis directly (e.g. the asynqmon command line tool)
+//     to output the same values as the API
+// ****************************************************************************
+
+// ClientQueue converts info to the queue returned by the API.
+func ClientQueue(info *asynq.QueueInfo) *client.Queue {
+	q := client.Queue(*toQueueStateSnapshot(info))
+	return &q
+}
+
+// ClientDailyStats converts the daily stats to the stats returned by the API.
+func ClientDailyStats(in []*asynq.DailyStats) []*client.DailyStats {
+	out := make([]*client.DailyStats, len(in))
+	for i, s := range in {
+		st := client.DailyStats(*toDailyStats(s))
+		out[i] = &st
+	}
+	return out
+}
+
+// ClientTaskInfo converts info to the task returned by the API, formatting the payload and result
+// with the given formatters. The timeline and notes are not set.
+func ClientTaskInfo(info *asynq.TaskInfo, pf PayloadFormatter, rf ResultFormatter) *client.TaskInfo {
+	t := toTaskInfo(info, pf, rf)
+	return &client.TaskInfo{
+		ID:            t.ID,
+		Queue:         t.Queue,
+		Type:          t.Type,
+		Payload:       t.Payload,
+		State:         t.State,
+		MaxRetry:      t.MaxRetry,
+		Retried:       t.Retried,
+		LastErr:       t.LastErr,
+		LastFailedAt:  t.LastFailedAt,
+		Timeout:       t.Timeout,
+		Deadline:      t.Deadline,
+		NextProcessAt: t.NextProcessAt,
+		CompletedAt:   t.CompletedAt,
+		Result:        t.Result,
+		TTL:           t.TTL,
+	}
+}
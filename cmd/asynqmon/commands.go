@@ -0,0 +1,578 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/hibiken/asynqmon"
+	"github.com/hibiken/asynqmon/client"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - subcommands of the asynqmon program used for scripting operations
+// ****************************************************************************
+
+// commandUsage is printed when the program is invoked with an unknown subcommand.
+const commandUsage = `Usage: asynqmon [flags] <command> [command flags]
+
+Commands:
+  queues              list all queues
+  tasks list          list tasks in a queue (requires --queue and --state)
+  tasks run-all       run all tasks in a queue with the given state
+  tasks archive-all   archive all tasks in a queue with the given state
+  tasks delete-all    delete all tasks in a queue with the given state
+  export              export queues and their tasks as JSON
+  stats               show daily processed/failed stats of queues
+
+Redis connection flags (e.g. --redis-addr) must be given before the command.
+Run 'asynqmon <command> -h' to see the command flags.
+Without a command, asynqmon starts the web UI server.`
+
+// commandEnv holds the values shared by all subcommands.
+type commandEnv struct {
+	cfg       *Config
+	inspector *asynq.Inspector
+	out       io.Writer
+}
+
+// formatters returns the payload and result formatters of the configuration,
+// the same ones the web UI uses.
+func (env *commandEnv) formatters() (asynqmon.PayloadFormatter, asynqmon.ResultFormatter) {
+	live := newLiveConfig(env.cfg)
+	return asynqmon.PayloadFormatterFunc(payloadFormatterFunc(live)), asynqmon.ResultFormatterFunc(resultFormatterFunc(live))
+}
+
+// commandFunc runs a subcommand with the given (command specific) arguments.
+type commandFunc func(env *commandEnv, args []string) error
+
+var commands = map[string]commandFunc{
+	"queues":            runQueuesCommand,
+	"tasks list":        runTasksListCommand,
+	"tasks run-all":     runTasksRunAllCommand,
+	"tasks archive-all": runTasksArchiveAllCommand,
+	"tasks delete-all":  runTasksDeleteAllCommand,
+	"export":            runExportCommand,
+	"stats":             runStatsCommand,
+}
+
+// lookupCommand returns the subcommand specified by args and the remaining arguments.
+func lookupCommand(args []string) (commandFunc, []string, error) {
+	if len(args) == 0 {
+		return nil, nil, fmt.Errorf("no command specified\n\n%s", commandUsage)
+	}
+	name, rest := args[0], args[1:]
+	if name == "tasks" {
+		if len(rest) == 0 {
+			return nil, nil, fmt.Errorf("tasks requires a subcommand\n\n%s", commandUsage)
+		}
+		name, rest = name+" "+rest[0], rest[1:]
+	}
+	cmd, ok := commands[name]
+	if !ok {
+		return nil, nil, fmt.Errorf("unknown command %q\n\n%s", name, commandUsage)
+	}
+	return cmd, rest, nil
+}
+
+// runCommand runs the subcommand specified by the positional arguments in cfg.
+func runCommand(cfg *Config, out io.Writer) error {
+	cmd, args, err := lookupCommand(cfg.Args)
+	if err != nil {
+		return err
+	}
+	redisConnOpt, err := makeRedisConnOpt(cfg)
+	if err != nil {
+		return err
+	}
+	inspector := asynq.NewInspector(redisConnOpt)
+	defer inspector.Close()
+	return cmd(&commandEnv{cfg: cfg, inspector: inspector, out: out}, args)
+}
+
+// Supported output formats.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// newCommandFlagSet returns a flag set for the subcommand.
+// Parse errors and usage are written to the returned buffer so that the caller can include them in the error.
+func newCommandFlagSet(name string) (*flag.FlagSet, *bytes.Buffer) {
+	flags := flag.NewFlagSet(name, flag.ContinueOnError)
+	var buf bytes.Buffer
+	flags.SetOutput(&buf)
+	return flags, &buf
+}
+
+// parseCommandFlags parses args and validates the output format if the flag is defined.
+func parseCommandFlags(flags *flag.FlagSet, buf *bytes.Buffer, args []string) error {
+	if err := flags.Parse(args); err != nil {
+		return fmt.Errorf("%v\n%s", err, buf.String())
+	}
+	if flags.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.Args(), " "))
+	}
+	if f := flags.Lookup("output"); f != nil {
+		if v := f.Value.String(); v != outputTable && v != outputJSON {
+			return fmt.Errorf("invalid output format %q: must be one of %q or %q", v, outputTable, outputJSON)
+		}
+	}
+	return nil
+}
+
+// writeOutput writes v as indented JSON if format is "json", otherwise it writes a table
+// using the given function.
+func writeOutput(w io.Writer, format string, v interface{}, table func(tw *tabwriter.Writer)) error {
+	if format == outputJSON {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	}
+	tw := tabwriter.NewWriter(w, 0, 8, 2, ' ', 0)
+	table(tw)
+	return tw.Flush()
+}
+
+// splitList splits a comma separated list, ignoring empty elements.
+func splitList(s string) []string {
+	var out []string
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			out = append(out, v)
+		}
+	}
+	return out
+}
+
+// queueNames returns the queue names given in the comma separated list, or all queues if the list is empty.
+func queueNames(inspector *asynq.Inspector, list string) ([]string, error) {
+	if qnames := splitList(list); len(qnames) > 0 {
+		return qnames, nil
+	}
+	qnames, err := inspector.Queues()
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(qnames)
+	return qnames, nil
+}
+
+func runQueuesCommand(env *commandEnv, args []string) error {
+	flags, buf := newCommandFlagSet("queues")
+	output := flags.String("output", outputTable, "output format (table or json)")
+	if err := parseCommandFlags(flags, buf, args); err != nil {
+		return err
+	}
+	qnames, err := queueNames(env.inspector, "")
+	if err != nil {
+		return err
+	}
+	queues := make([]*client.Queue, 0, len(qnames))
+	for _, qname := range qnames {
+		info, err := env.inspector.GetQueueInfo(qname)
+		if err != nil {
+			return err
+		}
+		queues = append(queues, asynqmon.ClientQueue(info))
+	}
+	return writeOutput(env.out, *output, queues, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "QUEUE\tSTATE\tSIZE\tACTIVE\tPENDING\tAGGREGATING\tSCHEDULED\tRETRY\tARCHIVED\tCOMPLETED\tLATENCY\tMEMORY")
+		for _, q := range queues {
+			state := "run"
+			if q.Paused {
+				state = "paused"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%s\t%d\n",
+				q.Queue, state, q.Size, q.Active, q.Pending, q.Aggregating, q.Scheduled,
+				q.Retry, q.Archived, q.Completed, q.DisplayLatency, q.MemoryUsage)
+		}
+	})
+}
+
+// tasksListOptions holds the flag values of the "tasks list" command.
+type tasksListOptions struct {
+	queue    string
+	state    string
+	group    string
+	taskType string
+	pageSize int
+	page     int
+	all      bool
+	output   string
+}
+
+func parseTasksListFlags(args []string) (*tasksListOptions, error) {
+	flags, buf := newCommandFlagSet("tasks list")
+	var opts tasksListOptions
+	flags.StringVar(&opts.queue, "queue", "", "name of the queue (required)")
+	flags.StringVar(&opts.state, "state", "", "state of the tasks: active, pending, aggregating, scheduled, retry, archived or completed (required)")
+	flags.StringVar(&opts.group, "group", "", "name of the group (required for aggregating state)")
+	flags.StringVar(&opts.taskType, "type", "", "only list tasks of this type; tasks are filtered before pagination, which reads all tasks of the state")
+	flags.IntVar(&opts.pageSize, "size", 20, "number of tasks in a page")
+	flags.IntVar(&opts.page, "page", 1, "page number (starting from 1)")
+	flags.BoolVar(&opts.all, "all", false, "list tasks in all pages")
+	flags.StringVar(&opts.output, "output", outputTable, "output format (table or json)")
+	if err := parseCommandFlags(flags, buf, args); err != nil {
+		return nil, err
+	}
+	if opts.queue == "" || opts.state == "" {
+		return nil, fmt.Errorf("--queue and --state are required")
+	}
+	if _, err := listTasksFunc(opts.state, opts.group); err != nil {
+		return nil, err
+	}
+	if opts.pageSize <= 0 || opts.page <= 0 {
+		return nil, fmt.Errorf("--size and --page must be positive")
+	}
+	return &opts, nil
+}
+
+// listTasksFunc returns a function to list tasks of the given state.
+func listTasksFunc(state, group string) (func(i *asynq.Inspector, qname string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error), error) {
+	switch state {
+	case "active":
+		return (*asynq.Inspector).ListActiveTasks, nil
+	case "pending":
+		return (*asynq.Inspector).ListPendingTasks, nil
+	case "scheduled":
+		return (*asynq.Inspector).ListScheduledTasks, nil
+	case "retry":
+		return (*asynq.Inspector).ListRetryTasks, nil
+	case "archived":
+		return (*asynq.Inspector).ListArchivedTasks, nil
+	case "completed":
+		return (*asynq.Inspector).ListCompletedTasks, nil
+	case "aggregating":
+		if group == "" {
+			return nil, fmt.Errorf("--group is required for aggregating state")
+		}
+		return func(i *asynq.Inspector, qname string, opts ...asynq.ListOption) ([]*asynq.TaskInfo, error) {
+			return i.ListAggregatingTasks(qname, group, opts...)
+		}, nil
+	}
+	return nil, fmt.Errorf("unsupported task state %q", state)
+}
+
+// listAllTasks lists tasks in all pages.
+func listAllTasks(inspector *asynq.Inspector, qname string, list func(*asynq.Inspector, string, ...asynq.ListOption) ([]*asynq.TaskInfo, error)) ([]*asynq.TaskInfo, error) {
+	const batchSize = 100
+	var all []*asynq.TaskInfo
+	for page := 1; ; page++ {
+		tasks, err := list(inspector, qname, asynq.PageSize(batchSize), asynq.Page(page))
+		if err != nil {
+			return nil, err
+		}
+		all = append(all, tasks...)
+		if len(tasks) < batchSize {
+			return all, nil
+		}
+	}
+}
+
+// filterTasksByType returns the tasks of the given type.
+func filterTasksByType(tasks []*asynq.TaskInfo, taskType string) []*asynq.TaskInfo {
+	var out []*asynq.TaskInfo
+	for _, t := range tasks {
+		if t.Type == taskType {
+			out = append(out, t)
+		}
+	}
+	return out
+}
+
+// pageOf returns the tasks in the page of the given size and number (starting from 1).
+func pageOf(tasks []*asynq.TaskInfo, size, page int) []*asynq.TaskInfo {
+	start := (page - 1) * size
+	if start >= len(tasks) {
+		return nil
+	}
+	end := start + size
+	if end > len(tasks) {
+		end = len(tasks)
+	}
+	return tasks[start:end]
+}
+
+func runTasksListCommand(env *commandEnv, args []string) error {
+	opts, err := parseTasksListFlags(args)
+	if err != nil {
+		return err
+	}
+	list, _ := listTasksFunc(opts.state, opts.group) // flags are validated
+	var tasks []*asynq.TaskInfo
+	switch {
+	case opts.taskType != "":
+		// Filter all tasks so that pages are full.
+		tasks, err = listAllTasks(env.inspector, opts.queue, list)
+		tasks = filterTasksByType(tasks, opts.taskType)
+		if !opts.all {
+			tasks = pageOf(tasks, opts.pageSize, opts.page)
+		}
+	case opts.all:
+		tasks, err = listAllTasks(env.inspector, opts.queue, list)
+	default:
+		tasks, err = list(env.inspector, opts.queue, asynq.PageSize(opts.pageSize), asynq.Page(opts.page))
+	}
+	if err != nil {
+		return err
+	}
+	pf, rf := env.formatters()
+	out := make([]*client.TaskInfo, 0, len(tasks))
+	for _, t := range tasks {
+		out = append(out, asynqmon.ClientTaskInfo(t, pf, rf))
+	}
+	return writeOutput(env.out, opts.output, out, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "ID\tTYPE\tSTATE\tRETRIED\tNEXT PROCESS AT\tLAST FAILED AT\tERROR\tPAYLOAD")
+		for _, t := range out {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%d/%d\t%s\t%s\t%s\t%s\n",
+				t.ID, t.Type, t.State, t.Retried, t.MaxRetry, orDash(t.NextProcessAt),
+				orDash(t.LastFailedAt), orDash(oneLine(t.LastErr)), oneLine(t.Payload))
+		}
+	})
+}
+
+// bulkTaskOptions holds the flag values of the "tasks run-all", "tasks archive-all" and "tasks delete-all" commands.
+type bulkTaskOptions struct {
+	queue  string
+	state  string
+	group  string
+	output string
+}
+
+func parseBulkTaskFlags(name string, args []string) (*bulkTaskOptions, error) {
+	flags, buf := newCommandFlagSet(name)
+	var opts bulkTaskOptions
+	flags.StringVar(&opts.queue, "queue", "", "name of the queue (required)")
+	flags.StringVar(&opts.state, "state", "", "state of the tasks (required)")
+	flags.StringVar(&opts.group, "group", "", "name of the group (required for aggregating state)")
+	flags.StringVar(&opts.output, "output", outputTable, "output format (table or json)")
+	if err := parseCommandFlags(flags, buf, args); err != nil {
+		return nil, err
+	}
+	if opts.queue == "" || opts.state == "" {
+		return nil, fmt.Errorf("--queue and --state are required")
+	}
+	if opts.state == "aggregating" && opts.group == "" {
+		return nil, fmt.Errorf("--group is required for aggregating state")
+	}
+	return &opts, nil
+}
+
+// bulkTaskResult is the output of the bulk task commands.
+type bulkTaskResult struct {
+	Queue string `json:"queue"`
+	State string `json:"state"`
+	// Number of tasks affected by the operation.
+	Count int `json:"count"`
+}
+
+func writeBulkTaskResult(env *commandEnv, opts *bulkTaskOptions, verb string, n int) error {
+	res := &bulkTaskResult{Queue: opts.queue, State: opts.state, Count: n}
+	return writeOutput(env.out, opts.output, res, func(tw *tabwriter.Writer) {
+		fmt.Fprintf(tw, "%s %d %s tasks in queue %q\n", verb, n, opts.state, opts.queue)
+	})
+}
+
+func runTasksRunAllCommand(env *commandEnv, args []string) error {
+	opts, err := parseBulkTaskFlags("tasks run-all", args)
+	if err != nil {
+		return err
+	}
+	var n int
+	switch opts.state {
+	case "scheduled":
+		n, err = env.inspector.RunAllScheduledTasks(opts.queue)
+	case "retry":
+		n, err = env.inspector.RunAllRetryTasks(opts.queue)
+	case "archived":
+		n, err = env.inspector.RunAllArchivedTasks(opts.queue)
+	case "aggregating":
+		n, err = env.inspector.RunAllAggregatingTasks(opts.queue, opts.group)
+	default:
+		return fmt.Errorf("cannot run tasks in %q state", opts.state)
+	}
+	if err != nil {
+		return err
+	}
+	return writeBulkTaskResult(env, opts, "Scheduled", n)
+}
+
+func runTasksArchiveAllCommand(env *commandEnv, args []string) error {
+	opts, err := parseBulkTaskFlags("tasks archive-all", args)
+	if err != nil {
+		return err
+	}
+	var n int
+	switch opts.state {
+	case "pending":
+		n, err = env.inspector.ArchiveAllPendingTasks(opts.queue)
+	case "scheduled":
+		n, err = env.inspector.ArchiveAllScheduledTasks(opts.queue)
+	case "retry":
+		n, err = env.inspector.ArchiveAllRetryTasks(opts.queue)
+	case "aggregating":
+		n, err = env.inspector.ArchiveAllAggregatingTasks(opts.queue, opts.group)
+	default:
+		return fmt.Errorf("cannot archive tasks in %q state", opts.state)
+	}
+	if err != nil {
+		return err
+	}
+	return writeBulkTaskResult(env, opts, "Archived", n)
+}
+
+func runTasksDeleteAllCommand(env *commandEnv, args []string) error {
+	opts, err := parseBulkTaskFlags("tasks delete-all", args)
+	if err != nil {
+		return err
+	}
+	var n int
+	switch opts.state {
+	case "pending":
+		n, err = env.inspector.DeleteAllPendingTasks(opts.queue)
+	case "scheduled":
+		n, err = env.inspector.DeleteAllScheduledTasks(opts.queue)
+	case "retry":
+		n, err = env.inspector.DeleteAllRetryTasks(opts.queue)
+	case "archived":
+		n, err = env.inspector.DeleteAllArchivedTasks(opts.queue)
+	case "completed":
+		n, err = env.inspector.DeleteAllCompletedTasks(opts.queue)
+	case "aggregating":
+		n, err = env.inspector.DeleteAllAggregatingTasks(opts.queue, opts.group)
+	default:
+		return fmt.Errorf("cannot delete tasks in %q state", opts.state)
+	}
+	if err != nil {
+		return err
+	}
+	return writeBulkTaskResult(env, opts, "Deleted", n)
+}
+
+// exportedQueue is the output of the export command for a single queue.
+type exportedQueue struct {
+	Queue *client.Queue        `json:"queue"`
+	Tasks []*exportedTask      `json:"tasks"`
+	Stats []*client.DailyStats `json:"history"`
+}
+
+// exportedTask is a task in the export command output.
+// Unlike the API, the export includes the untruncated payload and the raw payload bytes.
+type exportedTask struct {
+	*client.TaskInfo
+	Group      string `json:"group,omitempty"`
+	RawPayload []byte `json:"raw_payload"`
+}
+
+// exportStates lists the task states included in the export by default.
+var exportStates = []string{"active", "pending", "scheduled", "retry", "archived", "completed"}
+
+func runExportCommand(env *commandEnv, args []string) error {
+	flags, buf := newCommandFlagSet("export")
+	queues := flags.String("queue", "", "comma separated list of queues to export (default all queues)")
+	states := flags.String("state", strings.Join(exportStates, ","), "comma separated list of task states to export")
+	days := flags.Int("days", 30, "number of days of daily stats to export")
+	if err := parseCommandFlags(flags, buf, args); err != nil {
+		return err
+	}
+	for _, s := range splitList(*states) {
+		if s == "aggregating" {
+			return fmt.Errorf("exporting aggregating tasks is not supported")
+		}
+		if _, err := listTasksFunc(s, ""); err != nil {
+			return err
+		}
+	}
+	qnames, err := queueNames(env.inspector, *queues)
+	if err != nil {
+		return err
+	}
+
+	pf, rf := env.formatters()
+	res := struct {
+		ExportedAt time.Time        `json:"exported_at"`
+		Queues     []*exportedQueue `json:"queues"`
+	}{ExportedAt: time.Now().UTC()}
+	for _, qname := range qnames {
+		info, err := env.inspector.GetQueueInfo(qname)
+		if err != nil {
+			return err
+		}
+		stats, err := env.inspector.History(qname, *days)
+		if err != nil {
+			return err
+		}
+		q := &exportedQueue{Queue: asynqmon.ClientQueue(info), Tasks: make([]*exportedTask, 0), Stats: asynqmon.ClientDailyStats(stats)}
+		for _, s := range splitList(*states) {
+			list, _ := listTasksFunc(s, "") // states are validated
+			tasks, err := listAllTasks(env.inspector, qname, list)
+			if err != nil {
+				return err
+			}
+			for _, t := range tasks {
+				task := &exportedTask{TaskInfo: asynqmon.ClientTaskInfo(t, pf, rf), Group: t.Group, RawPayload: t.Payload}
+				if f := env.cfg.formatterFor(t.Type); f != nil && f.Redact {
+					task.RawPayload = nil
+				}
+				q.Tasks = append(q.Tasks, task)
+			}
+		}
+		res.Queues = append(res.Queues, q)
+	}
+	return writeOutput(env.out, outputJSON, res, nil)
+}
+
+func runStatsCommand(env *commandEnv, args []string) error {
+	flags, buf := newCommandFlagSet("stats")
+	queues := flags.String("queue", "", "comma separated list of queues (default all queues)")
+	days := flags.Int("days", 7, "number of days to show stats for")
+	output := flags.String("output", outputTable, "output format (table or json)")
+	if err := parseCommandFlags(flags, buf, args); err != nil {
+		return err
+	}
+	if *days <= 0 {
+		return fmt.Errorf("--days must be positive")
+	}
+	qnames, err := queueNames(env.inspector, *queues)
+	if err != nil {
+		return err
+	}
+	stats := make(map[string][]*client.DailyStats)
+	for _, qname := range qnames {
+		s, err := env.inspector.History(qname, *days)
+		if err != nil {
+			return err
+		}
+		stats[qname] = asynqmon.ClientDailyStats(s)
+	}
+	return writeOutput(env.out, *output, stats, func(tw *tabwriter.Writer) {
+		fmt.Fprintln(tw, "QUEUE\tDATE\tPROCESSED\tSUCCEEDED\tFAILED")
+		for _, qname := range qnames {
+			for _, s := range stats[qname] {
+				fmt.Fprintf(tw, "%s\t%s\t%d\t%d\t%d\n", qname, s.Date, s.Processed, s.Succeeded, s.Failed)
+			}
+		}
+	})
+}
+
+// orDash returns "-" if s is empty so that table cells are never blank.
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
+
+// oneLine replaces newlines and tabs in s so that it fits in a table cell.
+func oneLine(s string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ", "\t", " ").Replace(s)
+}
@@ -0,0 +1,531 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"os/signal"
+	"path"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/hibiken/asynqmon"
+	"gopkg.in/yaml.v3"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - loading of the config file given by the --config flag
+//   - sections of the config file which map onto asynqmon.Options
+//   - hot-reloading of the config while the web UI server is running
+// ****************************************************************************
+
+// reloadableFlags are the flags whose values are applied without restarting
+// the program when the config file changes.
+var reloadableFlags = map[string]bool{
+	"read-only":          true,
+	"max-payload-length": true,
+	"max-result-length":  true,
+}
+
+// How often the config file is checked for changes.
+const configPollInterval = 5 * time.Second
+
+// loadConfigFile reads the config file at path, sets the flags in the file and
+// returns the sections of the file which have no flag equivalent.
+//
+// The top-level keys in the file are the flag names (e.g. redis-addr), or the names
+// of the sections (see configSections). Flags explicitly set on the command line take
+// precedence over the values in the file.
+func loadConfigFile(flags *flag.FlagSet, path string) (*configSections, error) {
+	values, err := readConfigFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("config file %s: %v", path, err)
+	}
+	setOnCommandLine := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) {
+		setOnCommandLine[f.Name] = true
+	})
+	// Sort the keys so that errors are reported deterministically.
+	keys := make([]string, 0, len(values))
+	for k := range values {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	var sections configSections
+	sv := reflect.ValueOf(&sections).Elem()
+	for _, key := range keys {
+		// A section shares its name with a flag (e.g. slos) if the flag accepts a list
+		// in a single string. The value is decoded as the section if it is a list.
+		if f, ok := configField(sv, key); ok {
+			if _, isList := configList(values[key]); isList || flags.Lookup(key) == nil {
+				if err := decodeConfigValue(key, values[key], f); err != nil {
+					return nil, fmt.Errorf("config file %s: %v", path, err)
+				}
+				continue
+			}
+		}
+		name := strings.ReplaceAll(key, "_", "-")
+		if flags.Lookup(name) == nil || name == "config" {
+			return nil, fmt.Errorf("config file %s: unknown field %q", path, key)
+		}
+		v, err := configValueString(values[key])
+		if err != nil {
+			return nil, fmt.Errorf("config file %s: invalid value for field %q: %v", path, key, err)
+		}
+		if setOnCommandLine[name] {
+			continue
+		}
+		if err := flags.Set(name, v); err != nil {
+			return nil, fmt.Errorf("config file %s: invalid value for field %q: %v", path, key, err)
+		}
+	}
+	if err := sections.validate(); err != nil {
+		return nil, fmt.Errorf("config file %s: %v", path, err)
+	}
+	return &sections, nil
+}
+
+// readConfigFile decodes the config file at path.
+// The format is determined by the file extension.
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	values := make(map[string]interface{})
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("unsupported file extension %q: must be .yaml, .yml or .toml", ext)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// configValueString returns the string representation of a value in the
+// config file as accepted by flag.Value.Set.
+// Lists are joined with commas (e.g. redis-cluster-nodes).
+func configValueString(v interface{}) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case map[string]interface{}:
+		return "", fmt.Errorf("must not be a table")
+	case []interface{}:
+		elems := make([]string, len(v))
+		for i, e := range v {
+			s, err := configValueString(e)
+			if err != nil {
+				return "", err
+			}
+			elems[i] = s
+		}
+		return strings.Join(elems, ","), nil
+	}
+	return fmt.Sprint(v), nil
+}
+
+// configSections are the sections of the config file which have no flag equivalent.
+// Keys in the sections accept "-" and "_" interchangeably (e.g. max-payload-length).
+type configSections struct {
+	// SLOs of asynqmon.Options, in addition to the SLOs of the slos flag.
+	SLOs []sloConfig `config:"slos"`
+	// Webhooks of asynqmon.Options, in addition to the webhook of the webhook-urls flag.
+	Webhooks []webhookConfig `config:"webhooks"`
+	// Formatters customize the payloads and results shown for some task types.
+	// The first formatter matching the task type is used.
+	Formatters []formatterConfig `config:"formatters"`
+}
+
+// sloConfig is an asynqmon.SLO in the config file.
+type sloConfig struct {
+	Name             string         `config:"name"`
+	Queue            string         `config:"queue"`
+	LatencyThreshold configDuration `config:"latency_threshold"`
+	Target           configRatio    `config:"target"`
+	MaxFailureRatio  configRatio    `config:"max_failure_ratio"`
+	Window           configDuration `config:"window"`
+}
+
+// webhookConfig is an asynqmon.Webhook in the config file.
+type webhookConfig struct {
+	URL             string                 `config:"url"`
+	Secret          string                 `config:"secret"`
+	Events          []string               `config:"events"`
+	CountThresholds []countThresholdConfig `config:"count_thresholds"`
+}
+
+// countThresholdConfig is an asynqmon.QueueCountThreshold in the config file.
+type countThresholdConfig struct {
+	Queue       string `config:"queue"`
+	State       string `config:"state"`
+	MinIncrease int    `config:"min_increase"`
+}
+
+// formatterConfig customizes how the payloads and results of the tasks of some types are shown.
+type formatterConfig struct {
+	// Type is a pattern of task types as accepted by path.Match (e.g. "payments:*").
+	Type string `config:"type"`
+	// Redact hides the payloads and results.
+	Redact bool `config:"redact"`
+	// Override the max-payload-length and max-result-length flags if set.
+	MaxPayloadLength *int `config:"max_payload_length"`
+	MaxResultLength  *int `config:"max_result_length"`
+}
+
+// redactedText replaces the payloads and results hidden by a formatter.
+const redactedText = "(redacted)"
+
+// validate returns an error naming the path of the first invalid field.
+func (s *configSections) validate() error {
+	for i, slo := range s.SLOs {
+		p := fmt.Sprintf("slos[%d]", i)
+		switch {
+		case slo.Queue == "":
+			return fmt.Errorf("%s.queue: required", p)
+		case slo.LatencyThreshold > 0 && slo.MaxFailureRatio > 0:
+			return fmt.Errorf("%s: only one of latency_threshold and max_failure_ratio can be set", p)
+		case slo.LatencyThreshold > 0 && slo.Target == 0:
+			return fmt.Errorf("%s.target: required with latency_threshold", p)
+		case slo.LatencyThreshold == 0 && slo.MaxFailureRatio == 0:
+			return fmt.Errorf("%s: one of latency_threshold and max_failure_ratio is required", p)
+		}
+	}
+	for i, wh := range s.Webhooks {
+		p := fmt.Sprintf("webhooks[%d]", i)
+		if u, err := url.Parse(wh.URL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s.url: %q is not an http or https URL", p, wh.URL)
+		}
+		for j, e := range wh.Events {
+			switch e {
+			case asynqmon.WebhookEventAction, asynqmon.WebhookEventQueuePaused, asynqmon.WebhookEventQueueResumed, asynqmon.WebhookEventQueueCountChanged:
+			default:
+				return fmt.Errorf("%s.events[%d]: unknown event %q", p, j, e)
+			}
+		}
+		for j, t := range wh.CountThresholds {
+			switch t.State {
+			case "active", "pending", "aggregating", "scheduled", "retry", "archived", "completed":
+			default:
+				return fmt.Errorf("%s.count_thresholds[%d].state: unknown task state %q", p, j, t.State)
+			}
+			if t.MinIncrease <= 0 {
+				return fmt.Errorf("%s.count_thresholds[%d].min_increase: must be positive", p, j)
+			}
+		}
+	}
+	for i, f := range s.Formatters {
+		p := fmt.Sprintf("formatters[%d]", i)
+		if _, err := path.Match(f.Type, ""); err != nil || f.Type == "" {
+			return fmt.Errorf("%s.type: invalid pattern %q", p, f.Type)
+		}
+		if f.MaxPayloadLength != nil && *f.MaxPayloadLength < 0 {
+			return fmt.Errorf("%s.max_payload_length: must not be negative", p)
+		}
+		if f.MaxResultLength != nil && *f.MaxResultLength < 0 {
+			return fmt.Errorf("%s.max_result_length: must not be negative", p)
+		}
+	}
+	return nil
+}
+
+// asynqmonSLOs returns the SLOs of the section. The section must be valid.
+func (s *configSections) asynqmonSLOs() []asynqmon.SLO {
+	var slos []asynqmon.SLO
+	for _, c := range s.SLOs {
+		slos = append(slos, asynqmon.SLO{
+			Name:             c.Name,
+			Queue:            c.Queue,
+			LatencyThreshold: time.Duration(c.LatencyThreshold),
+			Target:           float64(c.Target),
+			MaxFailureRatio:  float64(c.MaxFailureRatio),
+			Window:           time.Duration(c.Window),
+		})
+	}
+	return slos
+}
+
+// asynqmonWebhooks returns the webhooks of the section. The section must be valid.
+func (s *configSections) asynqmonWebhooks() []asynqmon.Webhook {
+	var webhooks []asynqmon.Webhook
+	for _, c := range s.Webhooks {
+		wh := asynqmon.Webhook{URL: c.URL, Secret: c.Secret, Events: c.Events}
+		for _, t := range c.CountThresholds {
+			wh.CountThresholds = append(wh.CountThresholds, asynqmon.QueueCountThreshold{Queue: t.Queue, State: t.State, MinIncrease: t.MinIncrease})
+		}
+		webhooks = append(webhooks, wh)
+	}
+	return webhooks
+}
+
+// formatterFor returns the first formatter matching the task type, or nil if none matches.
+func (cfg *Config) formatterFor(taskType string) *formatterConfig {
+	for i, f := range cfg.Formatters {
+		if ok, _ := path.Match(f.Type, taskType); ok {
+			return &cfg.Formatters[i]
+		}
+	}
+	return nil
+}
+
+// configDuration is a duration in the config file, either a string accepted by
+// time.ParseDuration or a number of days followed by "d" (e.g. 7d).
+type configDuration time.Duration
+
+func (d *configDuration) unmarshalConfig(v interface{}) error {
+	s, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("must be a duration string (e.g. 30s or 7d)")
+	}
+	dur, err := parseWindow(s)
+	if err != nil {
+		return fmt.Errorf("invalid duration %q", s)
+	}
+	*d = configDuration(dur)
+	return nil
+}
+
+// configRatio is a ratio in the config file, either a number between 0 and 1
+// exclusive or a percentage string (e.g. "95%").
+type configRatio float64
+
+func (r *configRatio) unmarshalConfig(v interface{}) error {
+	if s, ok := v.(string); ok {
+		f, err := parsePercent(s)
+		if err != nil {
+			return err
+		}
+		*r = configRatio(f)
+		return nil
+	}
+	f, ok := configNumber(v)
+	if !ok || f <= 0 || f >= 1 {
+		return fmt.Errorf("must be a number between 0 and 1 exclusive, or a percentage (e.g. \"95%%\")")
+	}
+	*r = configRatio(f)
+	return nil
+}
+
+// configUnmarshaler is implemented by the types of the config sections decoded from
+// values which are not tables, lists, strings, booleans or integers.
+type configUnmarshaler interface {
+	unmarshalConfig(v interface{}) error
+}
+
+// decodeConfigValue decodes v, a value decoded from the config file, into rv.
+// Keys of tables are matched with the config tags of the struct fields.
+// Errors name the path of the invalid value (e.g. webhooks[0].url).
+func decodeConfigValue(path string, v interface{}, rv reflect.Value) error {
+	if u, ok := rv.Addr().Interface().(configUnmarshaler); ok {
+		if err := u.unmarshalConfig(v); err != nil {
+			return fmt.Errorf("%s: %v", path, err)
+		}
+		return nil
+	}
+	switch rv.Kind() {
+	case reflect.Pointer:
+		rv.Set(reflect.New(rv.Type().Elem()))
+		return decodeConfigValue(path, v, rv.Elem())
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: must be a table", path)
+		}
+		keys := make([]string, 0, len(m))
+		for k := range m {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			f, ok := configField(rv, k)
+			if !ok {
+				return fmt.Errorf("%s.%s: unknown field", path, k)
+			}
+			if err := decodeConfigValue(path+"."+k, m[k], f); err != nil {
+				return err
+			}
+		}
+	case reflect.Slice:
+		list, ok := configList(v)
+		if !ok {
+			return fmt.Errorf("%s: must be a list", path)
+		}
+		s := reflect.MakeSlice(rv.Type(), len(list), len(list))
+		for i, e := range list {
+			if err := decodeConfigValue(fmt.Sprintf("%s[%d]", path, i), e, s.Index(i)); err != nil {
+				return err
+			}
+		}
+		rv.Set(s)
+	case reflect.String:
+		s, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: must be a string", path)
+		}
+		rv.SetString(s)
+	case reflect.Bool:
+		b, ok := v.(bool)
+		if !ok {
+			return fmt.Errorf("%s: must be a boolean", path)
+		}
+		rv.SetBool(b)
+	case reflect.Int:
+		f, ok := configNumber(v)
+		if !ok || f != float64(int(f)) {
+			return fmt.Errorf("%s: must be an integer", path)
+		}
+		rv.SetInt(int64(f))
+	default:
+		panic(fmt.Sprintf("decodeConfigValue: unsupported type %v", rv.Type()))
+	}
+	return nil
+}
+
+// configField returns the field of the struct rv with the config tag matching key.
+func configField(rv reflect.Value, key string) (reflect.Value, bool) {
+	key = strings.ReplaceAll(key, "-", "_")
+	for i := 0; i < rv.NumField(); i++ {
+		if rv.Type().Field(i).Tag.Get("config") == key {
+			return rv.Field(i), true
+		}
+	}
+	return reflect.Value{}, false
+}
+
+// configList returns v as a list. TOML arrays of tables are decoded as a slice of maps.
+func configList(v interface{}) ([]interface{}, bool) {
+	switch v := v.(type) {
+	case []interface{}:
+		return v, true
+	case []map[string]interface{}:
+		list := make([]interface{}, len(v))
+		for i, m := range v {
+			list[i] = m
+		}
+		return list, true
+	}
+	return nil, false
+}
+
+// configNumber returns v as a float64 if it is a number.
+func configNumber(v interface{}) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float64:
+		return v, true
+	}
+	return 0, false
+}
+
+// liveConfig holds the current Config of the running web UI server.
+// It is safe for concurrent use.
+type liveConfig struct {
+	v atomic.Value // *Config
+}
+
+func newLiveConfig(cfg *Config) *liveConfig {
+	var c liveConfig
+	c.Store(cfg)
+	return &c
+}
+
+// Load returns the current Config. The returned value must not be modified.
+func (c *liveConfig) Load() *Config {
+	return c.v.Load().(*Config)
+}
+
+func (c *liveConfig) Store(cfg *Config) {
+	c.v.Store(cfg)
+}
+
+// reloadConfig parses the command-line flags and the config file again and
+// applies the reloadable settings to the running server.
+// Changes to other settings are logged and require a restart.
+func reloadConfig(live *liveConfig, h *asynqmon.HTTPHandler) error {
+	cfg, _, err := parseFlags(os.Args[0], os.Args[1:])
+	if err != nil {
+		return err
+	}
+	cur := live.Load()
+	next := *cur
+	next.ReadOnly = cfg.ReadOnly
+	next.MaxPayloadLength = cfg.MaxPayloadLength
+	next.MaxResultLength = cfg.MaxResultLength
+	next.Formatters = cfg.Formatters
+	for _, name := range changedFlags(cur, cfg) {
+		if reloadableFlags[name] {
+			log.Printf("config: applied new value for %s", name)
+		} else {
+			log.Printf("config: %s changed; restart asynqmon to apply the new value", name)
+		}
+	}
+	if !reflect.DeepEqual(cur.Formatters, cfg.Formatters) {
+		log.Printf("config: applied new value for formatters")
+	}
+	if !reflect.DeepEqual(cur.ConfigSLOs, cfg.ConfigSLOs) || !reflect.DeepEqual(cur.ConfigWebhooks, cfg.ConfigWebhooks) {
+		log.Printf("config: slos or webhooks section changed; restart asynqmon to apply the new value")
+	}
+	live.Store(&next)
+	h.SetReadOnly(next.ReadOnly)
+	return nil
+}
+
+// changedFlags returns the names of the flags whose values differ between a and b.
+func changedFlags(a, b *Config) []string {
+	// newFlagSet sets the fields to the default values, so copy the configs after creating the flag sets.
+	var ca, cb Config
+	aflags := newFlagSet("", &ca)
+	bflags := newFlagSet("", &cb)
+	ca, cb = *a, *b
+	var names []string
+	aflags.VisitAll(func(f *flag.Flag) {
+		if f.Value.String() != bflags.Lookup(f.Name).Value.String() {
+			names = append(names, f.Name)
+		}
+	})
+	return names
+}
+
+// watchConfigFile calls reload when the program receives SIGHUP or
+// when the config file at path is modified. It never returns.
+func watchConfigFile(path string, reload func()) {
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGHUP)
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	last, _ := os.Stat(path)
+	for {
+		select {
+		case <-sigs:
+			reload()
+		case <-ticker.C:
+			fi, err := os.Stat(path)
+			if err != nil {
+				continue
+			}
+			if last == nil || !fi.ModTime().Equal(last.ModTime()) || fi.Size() != last.Size() {
+				last = fi
+				reload()
+			}
+		}
+	}
+}
@@ -6,18 +6,15 @@ import (
 	"flag"
 	"fmt"
 	"log"
-	"net/http"
+	"log/slog"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/hibiken/asynq"
-	"github.com/hibiken/asynq/x/metrics"
 	"github.com/hibiken/asynqmon"
-	"github.com/prometheus/client_golang/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/rs/cors"
 )
 
 // Config holds configurations for the program provided via the command line.
@@ -25,6 +22,19 @@ type Config struct {
 	// Server port
 	Port int
 
+	// Web UI server configs
+	RootPath        string
+	TLSCertFile     string
+	TLSKeyFile      string
+	TLSClientCAFile string
+	ShutdownTimeout time.Duration
+
+	// Logging and tracing configs
+	LogFormat      string
+	LogLevel       string
+	LogRequests    bool
+	TracesExporter string
+
 	// Redis connection options
 	RedisAddr         string
 	RedisDB           int
@@ -35,14 +45,50 @@ type Config struct {
 	RedisClusterNodes string
 
 	// UI related configs
-	ReadOnly         bool
-	MaxPayloadLength int
-	MaxResultLength  int
+	ReadOnly            bool
+	RequireConfirmation bool
+	MaxPayloadLength    int
+	MaxResultLength     int
+
+	// Authentication related configs
+	TrustIdentityHeaders bool
 
 	// Prometheus related configs
 	EnableMetricsExporter bool
 	PrometheusServerAddr  string
 
+	// Redis INFO sampling configs
+	RedisInfoSampleInterval time.Duration
+	RedisInfoRetention      time.Duration
+
+	// Server tracking configs
+	ServerSampleInterval   time.Duration
+	ServerHistoryRetention time.Duration
+
+	// Task state sampling configs
+	TaskStateSampleInterval time.Duration
+
+	// Queue sampling configs
+	QueueSampleInterval  time.Duration
+	QueueSampleRetention time.Duration
+
+	// SLO configs
+	SLOs string
+
+	// Webhook configs
+	WebhookURLs            string
+	WebhookSecret          string
+	WebhookEvents          string
+	WebhookCountThresholds string
+
+	// Path to the config file (YAML or TOML)
+	ConfigFile string
+
+	// Settings which can only be set in the config file
+	ConfigSLOs     []asynqmon.SLO
+	ConfigWebhooks []asynqmon.Webhook
+	Formatters     []formatterConfig
+
 	// Args are the positional (non-flag) command line arguments
 	Args []string
 }
@@ -53,12 +99,38 @@ type Config struct {
 // output of the flag.Parse is returned in output.
 //
 // Reference: https://eli.thegreenplace.net/2020/testing-flag-parsing-in-go-programs/
+//
+// Values are taken from the command-line flags, the config file and the
+// environment variables, in that order of precedence.
 func parseFlags(progname string, args []string) (cfg *Config, output string, err error) {
-	flags := flag.NewFlagSet(progname, flag.ContinueOnError)
+	var conf Config
+	flags := newFlagSet(progname, &conf)
 	var buf bytes.Buffer
 	flags.SetOutput(&buf)
 
-	var conf Config
+	err = flags.Parse(args)
+	if err != nil {
+		return nil, buf.String(), err
+	}
+	if conf.ConfigFile != "" {
+		sections, err := loadConfigFile(flags, conf.ConfigFile)
+		if err != nil {
+			return nil, buf.String(), err
+		}
+		conf.ConfigSLOs = sections.asynqmonSLOs()
+		conf.ConfigWebhooks = sections.asynqmonWebhooks()
+		conf.Formatters = sections.Formatters
+	}
+	if err := conf.validate(); err != nil {
+		return nil, buf.String(), err
+	}
+	conf.Args = flags.Args()
+	return &conf, buf.String(), nil
+}
+
+// newFlagSet returns a flag set which stores the values of the flags in conf.
+func newFlagSet(progname string, conf *Config) *flag.FlagSet {
+	flags := flag.NewFlagSet(progname, flag.ContinueOnError)
 	flags.IntVar(&conf.Port, "port", getEnvOrDefaultInt("PORT", 8080), "port number to use for web ui server")
 	flags.StringVar(&conf.RedisAddr, "redis-addr", getEnvDefaultString("REDIS_ADDR", "127.0.0.1:6379"), "address of redis server to connect to")
 	flags.IntVar(&conf.RedisDB, "redis-db", getEnvOrDefaultInt("REDIS_DB", 0), "redis database number")
@@ -69,16 +141,248 @@ func parseFlags(progname string, args []string) (cfg *Config, output string, err
 	flags.StringVar(&conf.RedisClusterNodes, "redis-cluster-nodes", getEnvDefaultString("REDIS_CLUSTER_NODES", ""), "comma separated list of host:port addresses of cluster nodes")
 	flags.IntVar(&conf.MaxPayloadLength, "max-payload-length", getEnvOrDefaultInt("MAX_PAYLOAD_LENGTH", 200), "maximum number of utf8 characters printed in the payload cell in the Web UI")
 	flags.IntVar(&conf.MaxResultLength, "max-result-length", getEnvOrDefaultInt("MAX_RESULT_LENGTH", 200), "maximum number of utf8 characters printed in the result cell in the Web UI")
-	flags.BoolVar(&conf.EnableMetricsExporter, "enable-metrics-exporter", getEnvOrDefaultBool("ENABLE_METRICS_EXPORTER", false), "enable prometheus metrics exporter to expose queue metrics")
+	flags.BoolVar(&conf.EnableMetricsExporter, "enable-metrics-exporter", getEnvOrDefaultBool("ENABLE_METRICS_EXPORTER", false), "enable prometheus metrics exporter to expose queue metrics and metrics about asynqmon itself")
 	flags.StringVar(&conf.PrometheusServerAddr, "prometheus-addr", getEnvDefaultString("PROMETHEUS_ADDR", ""), "address of prometheus server to query time series")
 	flags.BoolVar(&conf.ReadOnly, "read-only", getEnvOrDefaultBool("READ_ONLY", false), "restrict to read-only mode")
+	flags.BoolVar(&conf.RequireConfirmation, "require-confirmation", getEnvOrDefaultBool("REQUIRE_CONFIRMATION", false), "require a confirmation token from a dry run for destructive API requests")
+	flags.BoolVar(&conf.TrustIdentityHeaders, "trust-identity-headers", getEnvOrDefaultBool("TRUST_IDENTITY_HEADERS", false), "trust the user in the X-Forwarded-User and X-Auth-Request-User headers (or basic auth) set by an authenticating proxy")
+	flags.StringVar(&conf.RootPath, "root-path", getEnvDefaultString("ROOT_PATH", ""), "URL path under which the web UI is served (e.g. /monitoring)")
+	flags.StringVar(&conf.TLSCertFile, "tls-cert", getEnvDefaultString("TLS_CERT_FILE", ""), "path to TLS certificate file to serve the web UI over HTTPS")
+	flags.StringVar(&conf.TLSKeyFile, "tls-key", getEnvDefaultString("TLS_KEY_FILE", ""), "path to TLS private key file to serve the web UI over HTTPS")
+	flags.StringVar(&conf.TLSClientCAFile, "tls-client-ca", getEnvDefaultString("TLS_CLIENT_CA_FILE", ""), "path to CA certificate file used to verify client certificates (enables mutual TLS)")
+	flags.DurationVar(&conf.ShutdownTimeout, "shutdown-timeout", getEnvOrDefaultDuration("SHUTDOWN_TIMEOUT", 30*time.Second), "maximum time to wait for active connections to finish on shutdown")
+	flags.StringVar(&conf.LogFormat, "log-format", getEnvDefaultString("LOG_FORMAT", "text"), "log format (text or json)")
+	flags.StringVar(&conf.LogLevel, "log-level", getEnvDefaultString("LOG_LEVEL", "info"), "minimum log level (debug, info, warn or error)")
+	flags.BoolVar(&conf.LogRequests, "log-requests", getEnvOrDefaultBool("LOG_REQUESTS", false), "log each API request")
+	flags.StringVar(&conf.TracesExporter, "otel-traces-exporter", getEnvDefaultString("OTEL_TRACES_EXPORTER", "none"), "exporter of OpenTelemetry trace spans (otlp, stdout or none)")
+	flags.DurationVar(&conf.RedisInfoSampleInterval, "redis-info-sample-interval", getEnvOrDefaultDuration("REDIS_INFO_SAMPLE_INTERVAL", time.Minute), "how often to sample redis INFO fields shown as history in the web UI (0 to disable)")
+	flags.DurationVar(&conf.RedisInfoRetention, "redis-info-retention", getEnvOrDefaultDuration("REDIS_INFO_RETENTION", 24*time.Hour), "how long to keep the samples of redis INFO fields")
+	flags.DurationVar(&conf.ServerSampleInterval, "server-sample-interval", getEnvOrDefaultDuration("SERVER_SAMPLE_INTERVAL", 30*time.Second), "how often to list asynq servers to record the fleet history shown in the web UI (0 to disable)")
+	flags.DurationVar(&conf.ServerHistoryRetention, "server-history-retention", getEnvOrDefaultDuration("SERVER_HISTORY_RETENTION", 24*time.Hour), "how long to keep the fleet history")
+	flags.DurationVar(&conf.TaskStateSampleInterval, "task-state-sample-interval", getEnvOrDefaultDuration("TASK_STATE_SAMPLE_INTERVAL", time.Minute), "how often to list tasks to record their state changes in the task timeline (0 to disable)")
+	flags.DurationVar(&conf.QueueSampleInterval, "queue-sample-interval", getEnvOrDefaultDuration("QUEUE_SAMPLE_INTERVAL", time.Minute), "how often to sample queue stats to rank queues and evaluate SLOs when prometheus-addr is not set, and to detect queue events for webhooks (0 to sample only if SLOs or webhooks are set)")
+	flags.DurationVar(&conf.QueueSampleRetention, "queue-sample-retention", getEnvOrDefaultDuration("QUEUE_SAMPLE_RETENTION", 24*time.Hour), "how long to keep the queue samples (at least the longest SLO window)")
+	flags.StringVar(&conf.SLOs, "slos", getEnvDefaultString("SLOS", ""), "comma separated list of queue SLOs (e.g. critical:latency<30s@95%/7d,default:failure_ratio<1%/7d)")
+	flags.StringVar(&conf.WebhookURLs, "webhook-urls", getEnvDefaultString("WEBHOOK_URLS", ""), "comma separated list of URLs to publish operator actions and queue events to")
+	flags.StringVar(&conf.WebhookSecret, "webhook-secret", getEnvDefaultString("WEBHOOK_SECRET", ""), "secret used to sign the webhook payloads with HMAC-SHA256")
+	flags.StringVar(&conf.WebhookEvents, "webhook-events", getEnvDefaultString("WEBHOOK_EVENTS", ""), "comma separated list of events to publish to webhooks (action, queue.paused, queue.resumed, queue.count_changed; default all)")
+	flags.StringVar(&conf.WebhookCountThresholds, "webhook-count-thresholds", getEnvDefaultString("WEBHOOK_COUNT_THRESHOLDS", ""), "comma separated list of task count increases which trigger a queue.count_changed event (e.g. critical:archived=10,retry=1000)")
+	flags.StringVar(&conf.ConfigFile, "config", getEnvDefaultString("CONFIG_FILE", ""), "path to YAML or TOML config file")
+	return flags
+}
 
-	err = flags.Parse(args)
+// validate returns an error naming the first invalid field in cfg.
+func (cfg *Config) validate() error {
+	if cfg.Port < 1 || cfg.Port > 65535 {
+		return fmt.Errorf("invalid value %d for port: must be between 1 and 65535", cfg.Port)
+	}
+	if cfg.RedisDB < 0 {
+		return fmt.Errorf("invalid value %d for redis-db: must not be negative", cfg.RedisDB)
+	}
+	if cfg.MaxPayloadLength < 0 {
+		return fmt.Errorf("invalid value %d for max-payload-length: must not be negative", cfg.MaxPayloadLength)
+	}
+	if cfg.MaxResultLength < 0 {
+		return fmt.Errorf("invalid value %d for max-result-length: must not be negative", cfg.MaxResultLength)
+	}
+	if cfg.RootPath != "" && !strings.HasPrefix(cfg.RootPath, "/") {
+		return fmt.Errorf("invalid value %q for root-path: must start with a slash", cfg.RootPath)
+	}
+	if (cfg.TLSCertFile == "") != (cfg.TLSKeyFile == "") {
+		return fmt.Errorf("tls-cert and tls-key must be specified together")
+	}
+	if cfg.TLSClientCAFile != "" && cfg.TLSCertFile == "" {
+		return fmt.Errorf("tls-client-ca requires tls-cert and tls-key")
+	}
+	switch cfg.LogFormat {
+	case "text", "json":
+	default:
+		return fmt.Errorf("invalid value %q for log-format: must be text or json", cfg.LogFormat)
+	}
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(cfg.LogLevel)); err != nil {
+		return fmt.Errorf("invalid value %q for log-level: must be debug, info, warn or error", cfg.LogLevel)
+	}
+	switch cfg.TracesExporter {
+	case "otlp", "stdout", "none":
+	default:
+		return fmt.Errorf("invalid value %q for otel-traces-exporter: must be otlp, stdout or none", cfg.TracesExporter)
+	}
+	if cfg.ShutdownTimeout < 0 {
+		return fmt.Errorf("invalid value %v for shutdown-timeout: must not be negative", cfg.ShutdownTimeout)
+	}
+	if cfg.RedisInfoSampleInterval < 0 {
+		return fmt.Errorf("invalid value %v for redis-info-sample-interval: must not be negative", cfg.RedisInfoSampleInterval)
+	}
+	if cfg.RedisInfoRetention <= 0 {
+		return fmt.Errorf("invalid value %v for redis-info-retention: must be positive", cfg.RedisInfoRetention)
+	}
+	if cfg.ServerSampleInterval < 0 {
+		return fmt.Errorf("invalid value %v for server-sample-interval: must not be negative", cfg.ServerSampleInterval)
+	}
+	if cfg.ServerHistoryRetention <= 0 {
+		return fmt.Errorf("invalid value %v for server-history-retention: must be positive", cfg.ServerHistoryRetention)
+	}
+	if cfg.TaskStateSampleInterval < 0 {
+		return fmt.Errorf("invalid value %v for task-state-sample-interval: must not be negative", cfg.TaskStateSampleInterval)
+	}
+	if cfg.QueueSampleInterval < 0 {
+		return fmt.Errorf("invalid value %v for queue-sample-interval: must not be negative", cfg.QueueSampleInterval)
+	}
+	if cfg.QueueSampleRetention <= 0 {
+		return fmt.Errorf("invalid value %v for queue-sample-retention: must be positive", cfg.QueueSampleRetention)
+	}
+	if _, err := parseSLOs(cfg.SLOs); err != nil {
+		return fmt.Errorf("invalid value %q for slos: %v", cfg.SLOs, err)
+	}
+	if _, err := parseCountThresholds(cfg.WebhookCountThresholds); err != nil {
+		return fmt.Errorf("invalid value %q for webhook-count-thresholds: %v", cfg.WebhookCountThresholds, err)
+	}
+	for _, u := range splitList(cfg.WebhookURLs) {
+		if parsed, err := url.Parse(u); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return fmt.Errorf("invalid value %q for webhook-urls: %q is not an http or https URL", cfg.WebhookURLs, u)
+		}
+	}
+	for _, e := range splitList(cfg.WebhookEvents) {
+		switch e {
+		case asynqmon.WebhookEventAction, asynqmon.WebhookEventQueuePaused, asynqmon.WebhookEventQueueResumed, asynqmon.WebhookEventQueueCountChanged:
+		default:
+			return fmt.Errorf("invalid value %q for webhook-events: unknown event %q", cfg.WebhookEvents, e)
+		}
+	}
+	return nil
+}
+
+// parseWebhooks returns the webhooks configured by the webhook flags.
+func parseWebhooks(cfg *Config) ([]asynqmon.Webhook, error) {
+	thresholds, err := parseCountThresholds(cfg.WebhookCountThresholds)
 	if err != nil {
-		return nil, buf.String(), err
+		return nil, err
 	}
-	conf.Args = flags.Args()
-	return &conf, buf.String(), nil
+	var webhooks []asynqmon.Webhook
+	for _, u := range splitList(cfg.WebhookURLs) {
+		webhooks = append(webhooks, asynqmon.Webhook{
+			URL:             u,
+			Secret:          cfg.WebhookSecret,
+			Events:          splitList(cfg.WebhookEvents),
+			CountThresholds: thresholds,
+		})
+	}
+	return webhooks, nil
+}
+
+// parseCountThresholds parses a comma separated list of count thresholds
+// of the form [<queue>:]<state>=<min increase>.
+func parseCountThresholds(s string) ([]asynqmon.QueueCountThreshold, error) {
+	var thresholds []asynqmon.QueueCountThreshold
+	for _, spec := range splitList(s) {
+		var t asynqmon.QueueCountThreshold
+		state, n, ok := strings.Cut(spec, "=")
+		if !ok {
+			return nil, fmt.Errorf("%q: missing minimum increase", spec)
+		}
+		if i := strings.LastIndex(state, ":"); i >= 0 {
+			t.Queue, state = state[:i], state[i+1:]
+		}
+		switch state {
+		case "active", "pending", "aggregating", "scheduled", "retry", "archived", "completed":
+			t.State = state
+		default:
+			return nil, fmt.Errorf("%q: unknown task state %q", spec, state)
+		}
+		min, err := strconv.Atoi(n)
+		if err != nil || min <= 0 {
+			return nil, fmt.Errorf("%q: minimum increase must be a positive integer", spec)
+		}
+		t.MinIncrease = min
+		thresholds = append(thresholds, t)
+	}
+	return thresholds, nil
+}
+
+// parseSLOs parses a comma separated list of SLOs. Each SLO is either
+//
+//	<queue>:latency<<duration>@<percent>%[/<window>]
+//	<queue>:failure_ratio<<percent>%[/<window>]
+//
+// where window is a duration, or a number of days followed by "d" (e.g. 7d).
+func parseSLOs(s string) ([]asynqmon.SLO, error) {
+	var slos []asynqmon.SLO
+	if strings.TrimSpace(s) == "" {
+		return slos, nil
+	}
+	for _, spec := range strings.Split(s, ",") {
+		spec = strings.TrimSpace(spec)
+		i := strings.LastIndex(spec, ":")
+		if i <= 0 {
+			return nil, fmt.Errorf("%q: missing queue name", spec)
+		}
+		slo := asynqmon.SLO{Queue: spec[:i]}
+		objective := spec[i+1:]
+		if j := strings.LastIndex(objective, "/"); j >= 0 {
+			window, err := parseWindow(objective[j+1:])
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", spec, err)
+			}
+			slo.Window = window
+			objective = objective[:j]
+		}
+		switch {
+		case strings.HasPrefix(objective, "latency<"):
+			threshold, target, ok := strings.Cut(strings.TrimPrefix(objective, "latency<"), "@")
+			if !ok {
+				return nil, fmt.Errorf("%q: missing target percentage of latency SLO", spec)
+			}
+			d, err := time.ParseDuration(threshold)
+			if err != nil || d <= 0 {
+				return nil, fmt.Errorf("%q: invalid latency threshold %q", spec, threshold)
+			}
+			pct, err := parsePercent(target)
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", spec, err)
+			}
+			slo.LatencyThreshold = d
+			slo.Target = pct
+		case strings.HasPrefix(objective, "failure_ratio<"):
+			pct, err := parsePercent(strings.TrimPrefix(objective, "failure_ratio<"))
+			if err != nil {
+				return nil, fmt.Errorf("%q: %v", spec, err)
+			}
+			slo.MaxFailureRatio = pct
+		default:
+			return nil, fmt.Errorf("%q: objective must start with latency< or failure_ratio<", spec)
+		}
+		slos = append(slos, slo)
+	}
+	return slos, nil
+}
+
+// parsePercent parses a percentage (e.g. 95%) into a fraction between 0 and 1 exclusive.
+func parsePercent(s string) (float64, error) {
+	if !strings.HasSuffix(s, "%") {
+		return 0, fmt.Errorf("invalid percentage %q: must end with %%", s)
+	}
+	f, err := strconv.ParseFloat(strings.TrimSuffix(s, "%"), 64)
+	if err != nil || f <= 0 || f >= 100 {
+		return 0, fmt.Errorf("invalid percentage %q: must be between 0%% and 100%% exclusive", s)
+	}
+	return f / 100, nil
+}
+
+// parseWindow parses a duration, or a number of days followed by "d".
+func parseWindow(s string) (time.Duration, error) {
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		n, err := strconv.Atoi(days)
+		if err != nil || n <= 0 {
+			return 0, fmt.Errorf("invalid window %q", s)
+		}
+		return time.Duration(n) * 24 * time.Hour, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid window %q", s)
+	}
+	return d, nil
 }
 
 func makeTLSConfig(cfg *Config) *tls.Config {
@@ -142,62 +446,50 @@ func main() {
 		os.Exit(1)
 	}
 
-	redisConnOpt, err := makeRedisConnOpt(cfg)
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	h := asynqmon.New(asynqmon.Options{
-		RedisConnOpt:      redisConnOpt,
-		PayloadFormatter:  asynqmon.PayloadFormatterFunc(payloadFormatterFunc(cfg)),
-		ResultFormatter:   asynqmon.ResultFormatterFunc(resultFormatterFunc(cfg)),
-		PrometheusAddress: cfg.PrometheusServerAddr,
-		ReadOnly:          cfg.ReadOnly,
-	})
-	defer h.Close()
-
-	c := cors.New(cors.Options{
-		AllowedMethods: []string{"GET", "POST", "DELETE"},
-	})
-	mux := http.NewServeMux()
-	mux.Handle("/", c.Handler(h))
-	if cfg.EnableMetricsExporter {
-		// Using NewPedanticRegistry here to test the implementation of Collectors and Metrics.
-		reg := prometheus.NewPedanticRegistry()
-
-		inspector := asynq.NewInspector(redisConnOpt)
-
-		reg.MustRegister(
-			metrics.NewQueueMetricsCollector(inspector),
-			// Add the standard process and go metrics to the registry
-			prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
-			prometheus.NewGoCollector(),
-		)
-		mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	if len(cfg.Args) > 0 {
+		if err := runCommand(cfg, os.Stdout); err != nil {
+			fmt.Fprintf(os.Stderr, "error: %v\n", err)
+			os.Exit(1)
+		}
+		return
 	}
 
-	srv := &http.Server{
-		Handler:      mux,
-		Addr:         fmt.Sprintf(":%d", cfg.Port),
-		WriteTimeout: 10 * time.Second,
-		ReadTimeout:  10 * time.Second,
+	if err := runServer(cfg); err != nil {
+		log.Fatal(err)
 	}
-
-	fmt.Printf("Asynq Monitoring WebUI server is listening on port %d\n", cfg.Port)
-	log.Fatal(srv.ListenAndServe())
 }
 
-func payloadFormatterFunc(cfg *Config) func(string, []byte) string {
+func payloadFormatterFunc(cfg *liveConfig) func(string, []byte) string {
 	return func(taskType string, payload []byte) string {
+		c := cfg.Load()
+		limit := c.MaxPayloadLength
+		if f := c.formatterFor(taskType); f != nil {
+			if f.Redact {
+				return redactedText
+			}
+			if f.MaxPayloadLength != nil {
+				limit = *f.MaxPayloadLength
+			}
+		}
 		payloadStr := asynqmon.DefaultPayloadFormatter.FormatPayload(taskType, payload)
-		return truncate(payloadStr, cfg.MaxPayloadLength)
+		return truncate(payloadStr, limit)
 	}
 }
 
-func resultFormatterFunc(cfg *Config) func(string, []byte) string {
+func resultFormatterFunc(cfg *liveConfig) func(string, []byte) string {
 	return func(taskType string, result []byte) string {
+		c := cfg.Load()
+		limit := c.MaxResultLength
+		if f := c.formatterFor(taskType); f != nil {
+			if f.Redact {
+				return redactedText
+			}
+			if f.MaxResultLength != nil {
+				limit = *f.MaxResultLength
+			}
+		}
 		resultStr := asynqmon.DefaultResultFormatter.FormatResult(taskType, result)
-		return truncate(resultStr, cfg.MaxResultLength)
+		return truncate(resultStr, limit)
 	}
 }
 
@@ -230,6 +522,14 @@ func getEnvOrDefaultInt(key string, def int) int {
 	return v
 }
 
+func getEnvOrDefaultDuration(key string, def time.Duration) time.Duration {
+	v, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return def
+	}
+	return v
+}
+
 func getEnvOrDefaultBool(key string, def bool) bool {
 	v, err := strconv.ParseBool(os.Getenv(key))
 	if err != nil {
@@ -1,13 +1,25 @@
 package main
 
 import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
 	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
 	"github.com/hibiken/asynq"
+	"github.com/hibiken/asynqmon"
 )
 
 func TestParseFlags(t *testing.T) {
@@ -33,6 +45,21 @@ func TestParseFlags(t *testing.T) {
 				EnableMetricsExporter: false,
 				PrometheusServerAddr:  "",
 				ReadOnly:              false,
+				ShutdownTimeout:       30 * time.Second,
+				LogFormat:             "text",
+				LogLevel:              "info",
+				TracesExporter:        "none",
+
+				RedisInfoSampleInterval: time.Minute,
+				RedisInfoRetention:      24 * time.Hour,
+
+				ServerSampleInterval:   30 * time.Second,
+				ServerHistoryRetention: 24 * time.Hour,
+
+				TaskStateSampleInterval: time.Minute,
+
+				QueueSampleInterval:  time.Minute,
+				QueueSampleRetention: 24 * time.Hour,
 
 				Args: []string{},
 			},
@@ -56,6 +83,408 @@ func TestParseFlags(t *testing.T) {
 
 }
 
+func TestParseFlagsWithConfigFile(t *testing.T) {
+	tests := []struct {
+		desc     string
+		filename string
+		content  string
+		args     []string
+		want     *Config
+	}{
+		{
+			desc:     "YAML file",
+			filename: "asynqmon.yaml",
+			content: `
+port: 9090
+redis-cluster-nodes: ["localhost:7000", "localhost:7001"]
+read-only: true
+max_payload_length: 50
+`,
+			want: &Config{
+				Port:              9090,
+				RedisAddr:         "127.0.0.1:6379",
+				RedisClusterNodes: "localhost:7000,localhost:7001",
+				ReadOnly:          true,
+				MaxPayloadLength:  50,
+				MaxResultLength:   200,
+			},
+		},
+		{
+			desc:     "TOML file",
+			filename: "asynqmon.toml",
+			content: `
+redis-addr = "localhost:6380"
+redis-db = 2
+max-result-length = 10
+`,
+			want: &Config{
+				Port:             8080,
+				RedisAddr:        "localhost:6380",
+				RedisDB:          2,
+				MaxPayloadLength: 200,
+				MaxResultLength:  10,
+			},
+		},
+		{
+			desc:     "Command line flags take precedence",
+			filename: "asynqmon.yaml",
+			content: `
+port: 9090
+redis-addr: localhost:6380
+`,
+			args: []string{"--port", "3000"},
+			want: &Config{
+				Port:             3000,
+				RedisAddr:        "localhost:6380",
+				MaxPayloadLength: 200,
+				MaxResultLength:  200,
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			args := append([]string{"--config", path}, tc.args...)
+			cfg, _, err := parseFlags("asynqmon", args)
+			if err != nil {
+				t.Fatalf("parseFlags returned error: %v", err)
+			}
+			tc.want.ConfigFile = path
+			tc.want.ShutdownTimeout = 30 * time.Second
+			tc.want.LogFormat = "text"
+			tc.want.LogLevel = "info"
+			tc.want.TracesExporter = "none"
+			tc.want.RedisInfoSampleInterval = time.Minute
+			tc.want.RedisInfoRetention = 24 * time.Hour
+			tc.want.ServerSampleInterval = 30 * time.Second
+			tc.want.ServerHistoryRetention = 24 * time.Hour
+			tc.want.TaskStateSampleInterval = time.Minute
+			tc.want.QueueSampleInterval = time.Minute
+			tc.want.QueueSampleRetention = 24 * time.Hour
+			tc.want.Args = []string{}
+			if diff := cmp.Diff(tc.want, cfg); diff != "" {
+				t.Errorf("parseFlag returned Config %v, want %v; (-want,+got)\n%s", cfg, tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestParseFlagsErrors(t *testing.T) {
+	tests := []struct {
+		desc     string
+		filename string
+		content  string
+		args     []string
+		wantErr  string // substring of the error message
+	}{
+		{
+			desc:     "Unknown field",
+			filename: "asynqmon.yaml",
+			content:  "redis-adr: localhost:6380\n",
+			wantErr:  `unknown field "redis-adr"`,
+		},
+		{
+			desc:     "Invalid value",
+			filename: "asynqmon.toml",
+			content:  "read-only = \"maybe\"\n",
+			wantErr:  `invalid value for field "read-only"`,
+		},
+		{
+			desc:     "Nested table",
+			filename: "asynqmon.yaml",
+			content:  "redis-addr:\n  host: localhost\n",
+			wantErr:  `invalid value for field "redis-addr"`,
+		},
+		{
+			desc:     "Unsupported extension",
+			filename: "asynqmon.json",
+			content:  "{}",
+			wantErr:  `unsupported file extension ".json"`,
+		},
+		{
+			desc:     "Invalid port in file",
+			filename: "asynqmon.yaml",
+			content:  "port: 70000\n",
+			wantErr:  "for port",
+		},
+		{
+			desc:     "Unknown field in section",
+			filename: "asynqmon.yaml",
+			content:  "webhooks:\n  - url: https://hooks.example.com\n    secrett: x\n",
+			wantErr:  "webhooks[0].secrett: unknown field",
+		},
+		{
+			desc:     "Invalid value type in section",
+			filename: "asynqmon.toml",
+			content:  "[[webhooks]]\nurl = \"https://hooks.example.com\"\n[[webhooks.count_thresholds]]\nstate = \"archived\"\nmin_increase = \"ten\"\n",
+			wantErr:  "webhooks[0].count_thresholds[0].min_increase: must be an integer",
+		},
+		{
+			desc:     "Invalid webhook URL in section",
+			filename: "asynqmon.yaml",
+			content:  "webhooks:\n  - url: hooks.example.com\n",
+			wantErr:  "webhooks[0].url:",
+		},
+		{
+			desc:     "Invalid SLO in section",
+			filename: "asynqmon.yaml",
+			content:  "slos:\n  - queue: critical\n    latency_threshold: 30s\n    target: 1.5\n",
+			wantErr:  "slos[0].target:",
+		},
+		{
+			desc:     "Invalid formatter pattern",
+			filename: "asynqmon.yaml",
+			content:  "formatters:\n  - type: \"payments:[\"\n",
+			wantErr:  "formatters[0].type:",
+		},
+		{
+			desc:    "TLS cert without key",
+			args:    []string{"--tls-cert", "cert.pem"},
+			wantErr: "tls-cert and tls-key",
+		},
+		{
+			desc:    "Root path without leading slash",
+			args:    []string{"--root-path", "monitoring"},
+			wantErr: "for root-path",
+		},
+		{
+			desc:    "Unknown log format",
+			args:    []string{"--log-format", "xml"},
+			wantErr: "for log-format",
+		},
+		{
+			desc:    "Unknown traces exporter",
+			args:    []string{"--otel-traces-exporter", "zipkin"},
+			wantErr: "for otel-traces-exporter",
+		},
+		{
+			desc:    "Invalid flag value",
+			args:    []string{"--max-payload-length", "-1"},
+			wantErr: "for max-payload-length",
+		},
+		{
+			desc:    "Invalid SLO",
+			args:    []string{"--slos", "critical:latency<30s"},
+			wantErr: "for slos",
+		},
+		{
+			desc:    "Invalid webhook URL",
+			args:    []string{"--webhook-urls", "hooks.example.com/asynq"},
+			wantErr: "for webhook-urls",
+		},
+		{
+			desc:    "Unknown webhook event",
+			args:    []string{"--webhook-events", "queue.deleted"},
+			wantErr: "for webhook-events",
+		},
+		{
+			desc:    "Invalid webhook count threshold",
+			args:    []string{"--webhook-count-thresholds", "critical:archived"},
+			wantErr: "for webhook-count-thresholds",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.desc, func(t *testing.T) {
+			args := tc.args
+			if tc.filename != "" {
+				path := filepath.Join(t.TempDir(), tc.filename)
+				if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+					t.Fatal(err)
+				}
+				args = append([]string{"--config", path}, args...)
+			}
+			_, _, err := parseFlags("asynqmon", args)
+			if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+				t.Errorf("parseFlags returned error %v, want error containing %q", err, tc.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigFileSections(t *testing.T) {
+	tests := []struct {
+		filename string
+		content  string
+	}{
+		{
+			filename: "asynqmon.yaml",
+			content: `
+slos: "low:failure_ratio<5%"
+webhooks:
+  - url: https://hooks.example.com/asynq
+    secret: s3cret
+    events: [queue.count_changed]
+    count-thresholds:
+      - {queue: critical, state: archived, min_increase: 10}
+formatters:
+  - type: "payments:*"
+    redact: true
+  - type: "email:*"
+    max_payload_length: 20
+`,
+		},
+		{
+			filename: "asynqmon.toml",
+			content: `
+slos = "low:failure_ratio<5%"
+
+[[webhooks]]
+url = "https://hooks.example.com/asynq"
+secret = "s3cret"
+events = ["queue.count_changed"]
+count_thresholds = [{queue = "critical", state = "archived", min_increase = 10}]
+
+[[formatters]]
+type = "payments:*"
+redact = true
+
+[[formatters]]
+type = "email:*"
+max-payload-length = 20
+`,
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.filename, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), tc.filename)
+			if err := os.WriteFile(path, []byte(tc.content), 0644); err != nil {
+				t.Fatal(err)
+			}
+			cfg, _, err := parseFlags("asynqmon", []string{"--config", path})
+			if err != nil {
+				t.Fatalf("parseFlags returned error: %v", err)
+			}
+			if cfg.SLOs != "low:failure_ratio<5%" {
+				t.Errorf("SLOs = %q, want the value of the slos flag", cfg.SLOs)
+			}
+			wantWebhooks := []asynqmon.Webhook{{
+				URL:             "https://hooks.example.com/asynq",
+				Secret:          "s3cret",
+				Events:          []string{asynqmon.WebhookEventQueueCountChanged},
+				CountThresholds: []asynqmon.QueueCountThreshold{{Queue: "critical", State: "archived", MinIncrease: 10}},
+			}}
+			if diff := cmp.Diff(wantWebhooks, cfg.ConfigWebhooks); diff != "" {
+				t.Errorf("ConfigWebhooks = %+v; (-want,+got)\n%s", cfg.ConfigWebhooks, diff)
+			}
+			live := newLiveConfig(cfg)
+			pf := payloadFormatterFunc(live)
+			if got := pf("payments:charge", []byte(`{"card":"4242"}`)); got != redactedText {
+				t.Errorf("payload of payments:charge = %q, want %q", got, redactedText)
+			}
+			if got, want := pf("email:send", []byte(strings.Repeat("x", 30))), strings.Repeat("x", 20)+"…"; got != want {
+				t.Errorf("payload of email:send = %q, want %q", got, want)
+			}
+			if got, want := pf("sms:send", []byte(strings.Repeat("x", 30))), strings.Repeat("x", 30); got != want {
+				t.Errorf("payload of sms:send = %q, want %q", got, want)
+			}
+			// Subcommands (e.g. tasks list and export) format tasks the same way.
+			cmdPF, cmdRF := (&commandEnv{cfg: cfg}).formatters()
+			if got, want := cmdPF.FormatPayload("email:send", []byte(strings.Repeat("x", 30))), strings.Repeat("x", 20)+"…"; got != want {
+				t.Errorf("command payload of email:send = %q, want %q", got, want)
+			}
+			if got := cmdRF.FormatResult("payments:charge", []byte(`{"ok":true}`)); got != redactedText {
+				t.Errorf("command result of payments:charge = %q, want %q", got, redactedText)
+			}
+		})
+	}
+
+	path := filepath.Join(t.TempDir(), "asynqmon.yaml")
+	content := `
+slos:
+  - queue: critical
+    latency_threshold: 30s
+    target: 95%
+    window: 7d
+  - name: default failures
+    queue: default
+    max_failure_ratio: 0.01
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, _, err := parseFlags("asynqmon", []string{"--config", path})
+	if err != nil {
+		t.Fatalf("parseFlags returned error: %v", err)
+	}
+	wantSLOs := []asynqmon.SLO{
+		{Queue: "critical", LatencyThreshold: 30 * time.Second, Target: 0.95, Window: 7 * 24 * time.Hour},
+		{Name: "default failures", Queue: "default", MaxFailureRatio: 0.01},
+	}
+	if diff := cmp.Diff(wantSLOs, cfg.ConfigSLOs, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("ConfigSLOs = %+v; (-want,+got)\n%s", cfg.ConfigSLOs, diff)
+	}
+}
+
+func TestParseSLOs(t *testing.T) {
+	got, err := parseSLOs("critical:latency<30s@95%/7d, default:failure_ratio<0.5%, low:latency<5m@99.9%/12h")
+	if err != nil {
+		t.Fatalf("parseSLOs returned error: %v", err)
+	}
+	want := []asynqmon.SLO{
+		{Queue: "critical", LatencyThreshold: 30 * time.Second, Target: 0.95, Window: 7 * 24 * time.Hour},
+		{Queue: "default", MaxFailureRatio: 0.005},
+		{Queue: "low", LatencyThreshold: 5 * time.Minute, Target: 0.999, Window: 12 * time.Hour},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("parseSLOs returned %+v; (-want,+got)\n%s", got, diff)
+	}
+
+	for _, s := range []string{
+		"latency<30s@95%",            // missing queue
+		"critical:latency<30s",       // missing target
+		"critical:latency<0s@95%",    // invalid threshold
+		"critical:latency<30s@100%",  // invalid target
+		"default:failure_ratio<1",    // missing percent sign
+		"default:failure_ratio<1%/x", // invalid window
+		"default:throughput>10",      // unknown objective
+	} {
+		if _, err := parseSLOs(s); err == nil {
+			t.Errorf("parseSLOs(%q) returned nil error, want error", s)
+		}
+	}
+}
+
+func TestParseWebhooks(t *testing.T) {
+	cfg := &Config{
+		WebhookURLs:            "https://a.example.com/hook, https://b.example.com/hook",
+		WebhookSecret:          "s3cret",
+		WebhookEvents:          "action,queue.count_changed",
+		WebhookCountThresholds: "critical:archived=10, retry=1000",
+	}
+	got, err := parseWebhooks(cfg)
+	if err != nil {
+		t.Fatalf("parseWebhooks returned error: %v", err)
+	}
+	thresholds := []asynqmon.QueueCountThreshold{
+		{Queue: "critical", State: "archived", MinIncrease: 10},
+		{State: "retry", MinIncrease: 1000},
+	}
+	events := []string{"action", "queue.count_changed"}
+	want := []asynqmon.Webhook{
+		{URL: "https://a.example.com/hook", Secret: "s3cret", Events: events, CountThresholds: thresholds},
+		{URL: "https://b.example.com/hook", Secret: "s3cret", Events: events, CountThresholds: thresholds},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("parseWebhooks returned %+v; (-want,+got)\n%s", got, diff)
+	}
+
+	for _, s := range []string{
+		"archived",              // missing minimum increase
+		"critical:deleted=10",   // unknown state
+		"critical:archived=0",   // non-positive increase
+		"critical:archived=ten", // not a number
+	} {
+		if _, err := parseCountThresholds(s); err == nil {
+			t.Errorf("parseCountThresholds(%q) returned nil error, want error", s)
+		}
+	}
+}
+
 func TestMakeRedisConnOpt(t *testing.T) {
 	var tests = []struct {
 		desc string
@@ -135,3 +564,177 @@ func TestMakeRedisConnOpt(t *testing.T) {
 		})
 	}
 }
+
+func TestParseTasksListFlags(t *testing.T) {
+	tests := []struct {
+		args    []string
+		want    *tasksListOptions
+		wantErr bool
+	}{
+		{
+			args: []string{"--queue", "critical", "--state", "archived", "--type", "email:send", "--output", "json"},
+			want: &tasksListOptions{
+				queue:    "critical",
+				state:    "archived",
+				taskType: "email:send",
+				pageSize: 20,
+				page:     1,
+				output:   "json",
+			},
+		},
+		{
+			args:    []string{"--queue", "critical"},
+			wantErr: true, // missing state
+		},
+		{
+			args:    []string{"--queue", "critical", "--state", "aggregating"},
+			wantErr: true, // missing group
+		},
+		{
+			args:    []string{"--queue", "critical", "--state", "archived", "--output", "yaml"},
+			wantErr: true, // unsupported output format
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(strings.Join(tc.args, " "), func(t *testing.T) {
+			got, err := parseTasksListFlags(tc.args)
+			if tc.wantErr {
+				if err == nil {
+					t.Errorf("parseTasksListFlags returned %+v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTasksListFlags returned error: %v", err)
+			}
+			if diff := cmp.Diff(tc.want, got, cmp.AllowUnexported(tasksListOptions{})); diff != "" {
+				t.Errorf("parseTasksListFlags returned %+v, want %+v; (-want,+got)\n%s", got, tc.want, diff)
+			}
+		})
+	}
+}
+
+func TestFilterTasksByTypeAndPage(t *testing.T) {
+	var tasks []*asynq.TaskInfo
+	for i := 0; i < 5; i++ {
+		tasks = append(tasks, &asynq.TaskInfo{ID: fmt.Sprintf("email%d", i), Type: "email:send"}, &asynq.TaskInfo{ID: fmt.Sprintf("sms%d", i), Type: "sms:send"})
+	}
+	filtered := filterTasksByType(tasks, "email:send")
+	tests := []struct {
+		size, page int
+		want       []string
+	}{
+		{2, 1, []string{"email0", "email1"}},
+		{2, 3, []string{"email4"}},
+		{2, 4, nil},
+		{10, 1, []string{"email0", "email1", "email2", "email3", "email4"}},
+	}
+	for _, tc := range tests {
+		var got []string
+		for _, t := range pageOf(filtered, tc.size, tc.page) {
+			got = append(got, t.ID)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("page %d of size %d = %v; (-want,+got)\n%s", tc.page, tc.size, got, diff)
+		}
+	}
+}
+
+func TestLookupCommand(t *testing.T) {
+	if _, rest, err := lookupCommand([]string{"tasks", "run-all", "--queue", "default"}); err != nil {
+		t.Errorf("lookupCommand returned error: %v", err)
+	} else if diff := cmp.Diff([]string{"--queue", "default"}, rest); diff != "" {
+		t.Errorf("lookupCommand returned args %v; (-want,+got)\n%s", rest, diff)
+	}
+	for _, args := range [][]string{{"tasks"}, {"tasks", "explode"}, {"unknown"}} {
+		if _, _, err := lookupCommand(args); err == nil {
+			t.Errorf("lookupCommand(%v) returned nil error, want error", args)
+		}
+	}
+}
+
+func TestCertReloader(t *testing.T) {
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	writeTestCert(t, certFile, keyFile, "first")
+
+	r, err := newCertReloader(certFile, keyFile)
+	if err != nil {
+		t.Fatalf("newCertReloader returned error: %v", err)
+	}
+	if got := leafCommonName(t, r); got != "first" {
+		t.Errorf("GetCertificate returned certificate for %q, want %q", got, "first")
+	}
+
+	writeTestCert(t, certFile, keyFile, "second")
+	// Make sure the modification time changes and the files are checked again.
+	later := time.Now().Add(time.Minute)
+	for _, f := range []string{certFile, keyFile} {
+		if err := os.Chtimes(f, later, later); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r.lastCheck = time.Time{}
+	if got := leafCommonName(t, r); got != "second" {
+		t.Errorf("GetCertificate returned certificate for %q after reload, want %q", got, "second")
+	}
+
+	// Invalid files should not replace the loaded certificate.
+	if err := os.WriteFile(certFile, []byte("invalid"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later = later.Add(time.Minute)
+	if err := os.Chtimes(certFile, later, later); err != nil {
+		t.Fatal(err)
+	}
+	r.lastCheck = time.Time{}
+	if got := leafCommonName(t, r); got != "second" {
+		t.Errorf("GetCertificate returned certificate for %q after failed reload, want %q", got, "second")
+	}
+}
+
+func leafCommonName(t *testing.T, r *certReloader) string {
+	t.Helper()
+	cert, err := r.GetCertificate(nil)
+	if err != nil {
+		t.Fatalf("GetCertificate returned error: %v", err)
+	}
+	leaf, err := x509.ParseCertificate(cert.Certificate[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	return leaf.Subject.CommonName
+}
+
+// writeTestCert writes a self-signed certificate for the common name and its private key.
+func writeTestCert(t *testing.T, certFile, keyFile, commonName string) {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	if err := os.WriteFile(certFile, certPEM, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, keyPEM, 0600); err != nil {
+		t.Fatal(err)
+	}
+}
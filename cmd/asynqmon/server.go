@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/hibiken/asynq/x/metrics"
+	"github.com/hibiken/asynqmon"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/rs/cors"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - web UI server started when the program is run without a subcommand
+//   - TLS configuration of the web UI server
+// ****************************************************************************
+
+// runServer starts the web UI server and blocks until the server fails or
+// the program receives SIGTERM or SIGINT, in which case the server is shut
+// down gracefully.
+func runServer(cfg *Config) error {
+	// Logs written with the log package are also written by the logger.
+	logger := newLogger(cfg)
+	slog.SetDefault(logger)
+
+	shutdownTracing, err := setupTracing(context.Background(), cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Failed to flush trace spans: %v", err)
+		}
+	}()
+
+	redisConnOpt, err := makeRedisConnOpt(cfg)
+	if err != nil {
+		return err
+	}
+	var tlsConfig *tls.Config
+	if cfg.TLSCertFile != "" {
+		tlsConfig, err = makeServerTLSConfig(cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	var reg *prometheus.Registry
+	if cfg.EnableMetricsExporter {
+		// Using NewPedanticRegistry here to test the implementation of Collectors and Metrics.
+		reg = prometheus.NewPedanticRegistry()
+	}
+
+	live := newLiveConfig(cfg)
+	opts := asynqmon.Options{
+		RootPath:          cfg.RootPath,
+		RedisConnOpt:      redisConnOpt,
+		PayloadFormatter:  asynqmon.PayloadFormatterFunc(payloadFormatterFunc(live)),
+		ResultFormatter:   asynqmon.ResultFormatterFunc(resultFormatterFunc(live)),
+		PrometheusAddress: cfg.PrometheusServerAddr,
+		ReadOnly:          cfg.ReadOnly,
+
+		RequireConfirmation:  cfg.RequireConfirmation,
+		TrustIdentityHeaders: cfg.TrustIdentityHeaders,
+
+		RedisInfoSampleInterval: cfg.RedisInfoSampleInterval,
+		RedisInfoRetention:      cfg.RedisInfoRetention,
+
+		ServerSampleInterval:   cfg.ServerSampleInterval,
+		ServerHistoryRetention: cfg.ServerHistoryRetention,
+
+		TaskStateSampleInterval: cfg.TaskStateSampleInterval,
+
+		QueueSampleInterval:  cfg.QueueSampleInterval,
+		QueueSampleRetention: cfg.QueueSampleRetention,
+	}
+	slos, err := parseSLOs(cfg.SLOs)
+	if err != nil {
+		return err
+	}
+	opts.SLOs = append(slos, cfg.ConfigSLOs...)
+	webhooks, err := parseWebhooks(cfg)
+	if err != nil {
+		return err
+	}
+	opts.Webhooks = append(webhooks, cfg.ConfigWebhooks...)
+	if reg != nil {
+		opts.MetricsRegisterer = reg
+	}
+	if cfg.LogRequests {
+		opts.Logger = logger
+	}
+	h := asynqmon.New(opts)
+	defer h.Close()
+
+	if cfg.ConfigFile != "" {
+		go watchConfigFile(cfg.ConfigFile, func() {
+			if err := reloadConfig(live, h); err != nil {
+				log.Printf("config: failed to reload %s: %v", cfg.ConfigFile, err)
+			}
+		})
+	}
+
+	c := cors.New(cors.Options{
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE"},
+	})
+	mux := http.NewServeMux()
+	mux.Handle(h.RootPath()+"/", c.Handler(h))
+	if reg != nil {
+		inspector := asynq.NewInspector(redisConnOpt)
+		defer inspector.Close()
+
+		reg.MustRegister(
+			metrics.NewQueueMetricsCollector(inspector),
+			// Add the standard process and go metrics to the registry
+			prometheus.NewProcessCollector(prometheus.ProcessCollectorOpts{}),
+			prometheus.NewGoCollector(),
+		)
+		mux.Handle("/metrics", promhttp.HandlerFor(reg, promhttp.HandlerOpts{}))
+	}
+
+	srv := &http.Server{
+		Handler:      mux,
+		Addr:         fmt.Sprintf(":%d", cfg.Port),
+		WriteTimeout: 10 * time.Second,
+		ReadTimeout:  10 * time.Second,
+		TLSConfig:    tlsConfig,
+	}
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, syscall.SIGTERM, os.Interrupt)
+	defer signal.Stop(sigs)
+
+	errCh := make(chan error, 1)
+	go func() {
+		if tlsConfig != nil {
+			// Certificates are provided by TLSConfig.GetCertificate.
+			errCh <- srv.ListenAndServeTLS("", "")
+		} else {
+			errCh <- srv.ListenAndServe()
+		}
+	}()
+	scheme := "http"
+	if tlsConfig != nil {
+		scheme = "https"
+	}
+	logger.Info("Asynq Monitoring WebUI server is listening", "port", cfg.Port, "scheme", scheme, "root_path", h.RootPath()+"/")
+
+	select {
+	case err := <-errCh:
+		return err
+	case sig := <-sigs:
+		log.Printf("Received %v, shutting down the server", sig)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		return fmt.Errorf("failed to shut down the server gracefully: %v", err)
+	}
+	if err := <-errCh; !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	log.Printf("Server stopped")
+	return nil
+}
+
+// makeServerTLSConfig returns the TLS config used by the web UI server.
+// The certificate is reloaded when the certificate or key file changes.
+func makeServerTLSConfig(cfg *Config) (*tls.Config, error) {
+	certs, err := newCertReloader(cfg.TLSCertFile, cfg.TLSKeyFile)
+	if err != nil {
+		return nil, err
+	}
+	tlsConfig := &tls.Config{
+		MinVersion:     tls.VersionTLS12,
+		GetCertificate: certs.GetCertificate,
+	}
+	if cfg.TLSClientCAFile != "" {
+		pem, err := os.ReadFile(cfg.TLSClientCAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read tls-client-ca: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in tls-client-ca file %s", cfg.TLSClientCAFile)
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	return tlsConfig, nil
+}
+
+// How often the certificate and key files are checked for changes.
+const certCheckInterval = 10 * time.Second
+
+// certReloader loads a TLS certificate from files and reloads it
+// when the files are modified.
+type certReloader struct {
+	certFile string
+	keyFile  string
+
+	mu          sync.Mutex
+	cert        *tls.Certificate
+	certModTime time.Time
+	keyModTime  time.Time
+	lastCheck   time.Time
+}
+
+func newCertReloader(certFile, keyFile string) (*certReloader, error) {
+	r := &certReloader{certFile: certFile, keyFile: keyFile}
+	if err := r.reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// reload loads the certificate from the files if they were modified since the last load.
+// r.mu must be held by the caller unless r is not shared yet.
+func (r *certReloader) reload() error {
+	r.lastCheck = time.Now()
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return fmt.Errorf("failed to read tls-cert: %v", err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to read tls-key: %v", err)
+	}
+	if r.cert != nil && certInfo.ModTime().Equal(r.certModTime) && keyInfo.ModTime().Equal(r.keyModTime) {
+		return nil
+	}
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+	r.cert = &cert
+	r.certModTime = certInfo.ModTime()
+	r.keyModTime = keyInfo.ModTime()
+	return nil
+}
+
+// GetCertificate returns the current certificate.
+// If reloading the certificate fails, the previously loaded certificate is used.
+func (r *certReloader) GetCertificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if time.Since(r.lastCheck) >= certCheckInterval {
+		if err := r.reload(); err != nil {
+			log.Printf("Failed to reload TLS certificate, using the previous certificate: %v", err)
+		}
+	}
+	return r.cert, nil
+}
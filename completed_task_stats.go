@@ -0,0 +1,270 @@
+package asynqmon
+
+import (
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - analytics of completed tasks (throughput, retention and result size)
+//   - http.Handler(s) for completed task stats endpoint
+// ****************************************************************************
+
+const (
+	// Default and maximum number of completed tasks scanned to compute the stats.
+	defaultCompletedStatsScanLimit = 10000
+	maxCompletedStatsScanLimit     = 100000
+
+	// Number of tasks listed per page while scanning completed tasks.
+	completedStatsScanPageSize = 1000
+
+	// Default window of the completion throughput.
+	defaultCompletedStatsWindow = time.Hour
+
+	// Number of buckets the window is divided into.
+	completedStatsBuckets = 60
+)
+
+// Upper bounds of the buckets of the retention and TTL distributions.
+// The last bucket has no upper bound.
+var completedRetentionBounds = []time.Duration{
+	time.Minute,
+	time.Hour,
+	24 * time.Hour,
+	7 * 24 * time.Hour,
+}
+
+// durationBucket counts the tasks with a duration in [MinSeconds, MaxSeconds).
+type durationBucket struct {
+	MinSeconds int64 `json:"min_seconds"`
+	// Zero if the bucket has no upper bound.
+	MaxSeconds int64 `json:"max_seconds"`
+	Count      int   `json:"count"`
+}
+
+func newDurationBuckets() []*durationBucket {
+	buckets := make([]*durationBucket, len(completedRetentionBounds)+1)
+	var min time.Duration
+	for i, max := range completedRetentionBounds {
+		buckets[i] = &durationBucket{MinSeconds: int64(min.Seconds()), MaxSeconds: int64(max.Seconds())}
+		min = max
+	}
+	buckets[len(buckets)-1] = &durationBucket{MinSeconds: int64(min.Seconds())}
+	return buckets
+}
+
+func addToDurationBuckets(buckets []*durationBucket, d time.Duration) {
+	for i, max := range completedRetentionBounds {
+		if d < max {
+			buckets[i].Count++
+			return
+		}
+	}
+	buckets[len(buckets)-1].Count++
+}
+
+// resultSizeStats describes the size of the results of tasks in bytes.
+type resultSizeStats struct {
+	Min   int     `json:"min"`
+	Max   int     `json:"max"`
+	Avg   float64 `json:"avg"`
+	P50   int     `json:"p50"`
+	P95   int     `json:"p95"`
+	Total int64   `json:"total"`
+	// Number of tasks without result.
+	Empty int `json:"empty"`
+}
+
+func computeResultSizeStats(sizes []int) resultSizeStats {
+	var s resultSizeStats
+	if len(sizes) == 0 {
+		return s
+	}
+	sort.Ints(sizes)
+	for _, n := range sizes {
+		s.Total += int64(n)
+		if n == 0 {
+			s.Empty++
+		}
+	}
+	s.Min = sizes[0]
+	s.Max = sizes[len(sizes)-1]
+	s.Avg = float64(s.Total) / float64(len(sizes))
+	s.P50 = sizes[(len(sizes)-1)*50/100]
+	s.P95 = sizes[(len(sizes)-1)*95/100]
+	return s
+}
+
+// completedStats is the analytics of a set of completed tasks.
+type completedStats struct {
+	// Number of completed tasks scanned.
+	Count int `json:"count"`
+	// Number of tasks completed in the window, and the number per minute.
+	CompletedInWindow   int     `json:"completed_in_window"`
+	ThroughputPerMinute float64 `json:"throughput_per_minute"`
+	// Number of tasks completed in each bucket of the window.
+	Completions []int `json:"completions"`
+	// Distribution of the retention period of the tasks.
+	Retention []*durationBucket `json:"retention"`
+	// Distribution of the time left before the tasks are deleted.
+	TTL        []*durationBucket `json:"ttl"`
+	ResultSize resultSizeStats   `json:"result_size"`
+
+	sizes []int
+}
+
+func newCompletedStats() *completedStats {
+	return &completedStats{
+		Completions: make([]int, completedStatsBuckets),
+		Retention:   newDurationBuckets(),
+		TTL:         newDurationBuckets(),
+	}
+}
+
+type completedTaskTypeStats struct {
+	TaskType string `json:"task_type"`
+	*completedStats
+}
+
+type completedTaskStatsResponse struct {
+	Queue string `json:"queue"`
+	// Start of the window and the duration of its buckets.
+	WindowStart   string  `json:"window_start"`
+	BucketSeconds float64 `json:"bucket_seconds"`
+	// Number of completed tasks in the queue, and the number of tasks scanned to compute the stats.
+	// Only the first `limit` tasks are scanned.
+	Total   int `json:"total"`
+	Scanned int `json:"scanned"`
+	// Stats of all scanned tasks.
+	*completedStats
+	// Stats per task type, sorted by number of tasks in descending order.
+	TaskTypes []*completedTaskTypeStats `json:"task_types"`
+}
+
+// computeCompletedTaskStats computes the stats of the completed tasks over the window ending at now.
+func computeCompletedTaskStats(tasks []*asynq.TaskInfo, now time.Time, window time.Duration) (*completedStats, []*completedTaskTypeStats) {
+	all := newCompletedStats()
+	byType := make(map[string]*completedStats)
+	start := now.Add(-window)
+	bucket := window / completedStatsBuckets
+	for _, t := range tasks {
+		ts, ok := byType[t.Type]
+		if !ok {
+			ts = newCompletedStats()
+			byType[t.Type] = ts
+		}
+		for _, s := range []*completedStats{all, ts} {
+			s.Count++
+			if !t.CompletedAt.Before(start) && !t.CompletedAt.After(now) {
+				i := int(t.CompletedAt.Sub(start) / bucket)
+				if i >= completedStatsBuckets {
+					i = completedStatsBuckets - 1
+				}
+				s.Completions[i]++
+				s.CompletedInWindow++
+			}
+			addToDurationBuckets(s.Retention, t.Retention)
+			addToDurationBuckets(s.TTL, t.CompletedAt.Add(t.Retention).Sub(now))
+			s.sizes = append(s.sizes, len(t.Result))
+		}
+	}
+	types := make([]*completedTaskTypeStats, 0, len(byType))
+	for typ, s := range byType {
+		types = append(types, &completedTaskTypeStats{TaskType: typ, completedStats: s})
+	}
+	sort.Slice(types, func(i, j int) bool {
+		if types[i].Count != types[j].Count {
+			return types[i].Count > types[j].Count
+		}
+		return types[i].TaskType < types[j].TaskType
+	})
+	all.finish(window)
+	for _, t := range types {
+		t.finish(window)
+	}
+	return all, types
+}
+
+// finish computes the throughput and result size stats once all tasks are added.
+func (s *completedStats) finish(window time.Duration) {
+	s.ThroughputPerMinute = float64(s.CompletedInWindow) / window.Minutes()
+	s.ResultSize = computeResultSizeStats(s.sizes)
+}
+
+// scanCompletedTasks lists up to limit completed tasks which expire last, most recently expiring first.
+// total is the number of completed tasks in the queue.
+//
+// Completed tasks are sorted by expiration time, so the tasks which expire last are the
+// most recently completed ones when the task types have the same retention.
+func scanCompletedTasks(r *http.Request, inspector *asynq.Inspector, qname string, total, limit int) ([]*asynq.TaskInfo, error) {
+	return scanLastPages(total, limit, completedStatsScanPageSize, func(page int) ([]*asynq.TaskInfo, error) {
+		span := startSpan(r.Context(), "asynq.Inspector/ListCompletedTasks")
+		list, err := inspector.ListCompletedTasks(qname, asynq.PageSize(completedStatsScanPageSize), asynq.Page(page))
+		endSpan(span, err)
+		return list, err
+	})
+}
+
+// scanLastPages lists up to limit of the total tasks, starting from the last page,
+// and returns them in reverse order.
+func scanLastPages(total, limit, pageSize int, listPage func(page int) ([]*asynq.TaskInfo, error)) ([]*asynq.TaskInfo, error) {
+	var tasks []*asynq.TaskInfo
+	for page := (total + pageSize - 1) / pageSize; page >= 1 && len(tasks) < limit; page-- {
+		list, err := listPage(page)
+		if err != nil {
+			return nil, err
+		}
+		for i := len(list) - 1; i >= 0; i-- {
+			tasks = append(tasks, list[i])
+		}
+	}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return tasks, nil
+}
+
+func newCompletedTaskStatsHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qname := mux.Vars(r)["qname"]
+		limit, err := intQueryParam(r, "limit", defaultCompletedStatsScanLimit, 1, maxCompletedStatsScanLimit)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		secs, err := intQueryParam(r, "duration", int(defaultCompletedStatsWindow.Seconds()), completedStatsBuckets, int((30 * 24 * time.Hour).Seconds()))
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		window := time.Duration(secs) * time.Second
+		span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
+		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		tasks, err := scanCompletedTasks(r, inspector, qname, qinfo.Completed, limit)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		now := time.Now()
+		all, types := computeCompletedTaskStats(tasks, now, window)
+		writeResponseJSON(w, completedTaskStatsResponse{
+			Queue:          qname,
+			WindowStart:    now.Add(-window).Format(time.RFC3339),
+			BucketSeconds:  (window / completedStatsBuckets).Seconds(),
+			Total:          qinfo.Completed,
+			Scanned:        len(tasks),
+			completedStats: all,
+			TaskTypes:      types,
+		})
+	}
+}
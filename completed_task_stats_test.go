@@ -0,0 +1,117 @@
+package asynqmon
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hibiken/asynq"
+)
+
+func TestComputeCompletedTaskStats(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	tasks := []*asynq.TaskInfo{
+		{Type: "email:send", CompletedAt: now.Add(-90 * time.Second), Retention: 24 * time.Hour, Result: []byte("ok")},
+		{Type: "email:send", CompletedAt: now.Add(-30 * time.Second), Retention: 24 * time.Hour},
+		{Type: "email:send", CompletedAt: now.Add(-2 * time.Hour), Retention: 24 * time.Hour, Result: []byte("0123456789")},
+		{Type: "image:resize", CompletedAt: now.Add(-5 * time.Minute), Retention: 10 * time.Minute, Result: []byte("abcd")},
+	}
+	all, types := computeCompletedTaskStats(tasks, now, time.Hour)
+
+	if all.Count != 4 || all.CompletedInWindow != 3 {
+		t.Errorf("Count, CompletedInWindow = %d, %d; want 4, 3", all.Count, all.CompletedInWindow)
+	}
+	if want := 3.0 / 60; all.ThroughputPerMinute != want {
+		t.Errorf("ThroughputPerMinute = %v, want %v", all.ThroughputPerMinute, want)
+	}
+	// Buckets are one minute long.
+	wantCompletions := make([]int, completedStatsBuckets)
+	wantCompletions[58] = 1
+	wantCompletions[59] = 1
+	wantCompletions[55] = 1
+	if diff := cmp.Diff(wantCompletions, all.Completions); diff != "" {
+		t.Errorf("Completions mismatch (-want,+got)\n%s", diff)
+	}
+
+	var gotRetention, gotTTL []int
+	for i := range all.Retention {
+		gotRetention = append(gotRetention, all.Retention[i].Count)
+		gotTTL = append(gotTTL, all.TTL[i].Count)
+	}
+	if diff := cmp.Diff([]int{0, 1, 0, 3, 0}, gotRetention); diff != "" {
+		t.Errorf("Retention mismatch (-want,+got)\n%s", diff)
+	}
+	if diff := cmp.Diff([]int{0, 1, 3, 0, 0}, gotTTL); diff != "" {
+		t.Errorf("TTL mismatch (-want,+got)\n%s", diff)
+	}
+
+	wantSize := resultSizeStats{Min: 0, Max: 10, Avg: 4, P50: 2, P95: 4, Total: 16, Empty: 1}
+	if diff := cmp.Diff(wantSize, all.ResultSize); diff != "" {
+		t.Errorf("ResultSize mismatch (-want,+got)\n%s", diff)
+	}
+
+	var gotTypes []string
+	for _, ts := range types {
+		gotTypes = append(gotTypes, ts.TaskType)
+	}
+	if diff := cmp.Diff([]string{"email:send", "image:resize"}, gotTypes); diff != "" {
+		t.Errorf("task types mismatch (-want,+got)\n%s", diff)
+	}
+	if types[0].Count != 3 || types[0].CompletedInWindow != 2 || types[0].ResultSize.Max != 10 {
+		t.Errorf("email:send stats = %+v", types[0].completedStats)
+	}
+}
+
+func TestToTaskInfoFormatsResultWithTaskType(t *testing.T) {
+	var gotType string
+	rf := ResultFormatterFunc(func(taskType string, result []byte) string {
+		gotType = taskType
+		return string(result)
+	})
+	toTaskInfo(&asynq.TaskInfo{Type: "email:send", State: asynq.TaskStateCompleted, Result: []byte("ok")}, DefaultPayloadFormatter, rf)
+	if gotType != "email:send" {
+		t.Errorf("FormatResult got task type %q, want %q", gotType, "email:send")
+	}
+}
+
+func TestScanLastPages(t *testing.T) {
+	var all []*asynq.TaskInfo
+	for i := 0; i < 25; i++ {
+		all = append(all, &asynq.TaskInfo{ID: fmt.Sprintf("t%d", i)})
+	}
+	listPage := func(page int) ([]*asynq.TaskInfo, error) {
+		start, end := (page-1)*10, page*10
+		if end > len(all) {
+			end = len(all)
+		}
+		return all[start:end], nil
+	}
+	tests := []struct {
+		limit int
+		want  []string
+	}{
+		{3, []string{"t24", "t23", "t22"}},
+		{12, []string{"t24", "t23", "t22", "t21", "t20", "t19", "t18", "t17", "t16", "t15", "t14", "t13"}},
+		{30, nil}, // all tasks
+	}
+	for _, tc := range tests {
+		tasks, err := scanLastPages(len(all), tc.limit, 10, listPage)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := tc.want
+		if want == nil {
+			for i := len(all) - 1; i >= 0; i-- {
+				want = append(want, all[i].ID)
+			}
+		}
+		var got []string
+		for _, task := range tasks {
+			got = append(got, task.ID)
+		}
+		if diff := cmp.Diff(want, got); diff != "" {
+			t.Errorf("scanLastPages(limit=%d) diff (-want, +got):\n%s", tc.limit, diff)
+		}
+	}
+}
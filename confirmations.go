@@ -0,0 +1,255 @@
+package asynqmon
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - dry-run previews of destructive endpoints
+//   - single-use confirmation tokens issued by the previews
+// ****************************************************************************
+
+// Time a confirmation token can be used after the preview which issued it.
+const confirmationTokenTTL = 5 * time.Minute
+
+// Number of tasks a confirmed request may affect above the number of the preview,
+// as a fraction of the number of the preview, with a minimum of minAffectedSlack tasks.
+// A request affecting more tasks is rejected so that the user previews it again.
+const (
+	affectedSlackRatio = 0.1
+	minAffectedSlack   = 10
+)
+
+// Query params of destructive endpoints.
+const (
+	// If true, the request is not executed and the number of affected tasks is returned with a confirmation token.
+	dryRunParam = "dry_run"
+	// Token returned by the dry run of the same request.
+	confirmationTokenParam = "confirmation_token"
+)
+
+func confirmationKey(token string) string {
+	return "asynqmon:confirmations:" + token
+}
+
+// confirmation is the request a confirmation token was issued for.
+type confirmation struct {
+	Method   string `json:"method"`
+	Path     string `json:"path"`
+	User     string `json:"user"`
+	Affected int    `json:"affected"`
+}
+
+// dryRunResponse is the response of the dry run of a destructive request.
+type dryRunResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Number of tasks the request would affect at the time of the dry run.
+	Affected          int       `json:"affected"`
+	ConfirmationToken string    `json:"confirmation_token"`
+	ExpiresAt         time.Time `json:"expires_at"`
+}
+
+// affectedCounter returns the number of tasks a destructive request would affect.
+// It writes an error response and returns false if the request would fail.
+type affectedCounter func(w http.ResponseWriter, r *http.Request) (int, bool)
+
+// confirmationGuard issues and checks the confirmation tokens of destructive endpoints.
+type confirmationGuard struct {
+	rc redis.UniversalClient
+	// If true, destructive requests are rejected without a confirmation token.
+	required bool
+}
+
+func newConfirmationGuard(rc redis.UniversalClient, required bool) *confirmationGuard {
+	return &confirmationGuard{rc: rc, required: required}
+}
+
+// isDryRun reports whether the request asks for a dry run.
+func isDryRun(r *http.Request) bool {
+	v, _ := strconv.ParseBool(r.URL.Query().Get(dryRunParam))
+	return v
+}
+
+// wrap returns a handler which answers dry runs of the request with a preview and a confirmation token,
+// and checks the confirmation token before calling h.
+// The token is required if the guard requires confirmation, and is checked if present otherwise.
+func (g *confirmationGuard) wrap(count affectedCounter, h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if isDryRun(r) {
+			g.preview(w, r, count)
+			return
+		}
+		token := r.URL.Query().Get(confirmationTokenParam)
+		if token == "" {
+			if g.required {
+				writeError(w, r, http.StatusPreconditionRequired, errCodeConfirmationRequired,
+					fmt.Sprintf("%s %s requires a confirmation token: send the request with %s=true first", r.Method, r.URL.Path, dryRunParam))
+				return
+			}
+			h(w, r)
+			return
+		}
+		c, err := g.consume(r, token)
+		if err != nil {
+			writeError(w, r, http.StatusPreconditionFailed, errCodeInvalidConfirmation, err.Error())
+			return
+		}
+		n, ok := count(w, r)
+		if !ok {
+			return
+		}
+		if exceedsPreview(c.Affected, n) {
+			writeError(w, r, http.StatusPreconditionFailed, errCodeOutdatedConfirmation,
+				fmt.Sprintf("%s %s would affect %d tasks, well above the %d tasks of the dry run: send the request with %s=true again",
+					r.Method, r.URL.Path, n, c.Affected, dryRunParam))
+			return
+		}
+		h(w, r)
+	}
+}
+
+// exceedsPreview reports whether the number of tasks a request would affect now
+// is well above the number of tasks of its preview.
+func exceedsPreview(previewed, current int) bool {
+	slack := int(float64(previewed) * affectedSlackRatio)
+	if slack < minAffectedSlack {
+		slack = minAffectedSlack
+	}
+	return current > previewed+slack
+}
+
+func (g *confirmationGuard) preview(w http.ResponseWriter, r *http.Request, count affectedCounter) {
+	n, ok := count(w, r)
+	if !ok {
+		return
+	}
+	c := confirmation{Method: r.Method, Path: r.URL.Path, User: requestUser(r), Affected: n}
+	data, err := json.Marshal(c)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	token := newRequestID()
+	if err := g.rc.Set(r.Context(), confirmationKey(token), data, confirmationTokenTTL).Err(); err != nil {
+		writeErrorResponse(w, r, err)
+		return
+	}
+	writeResponseJSON(w, dryRunResponse{
+		DryRun:            true,
+		Affected:          n,
+		ConfirmationToken: token,
+		ExpiresAt:         time.Now().Add(confirmationTokenTTL).UTC(),
+	})
+}
+
+// consume deletes the confirmation token and returns the request it was issued for.
+// It returns an error if the token doesn't exist, expired or was issued for another request.
+func (g *confirmationGuard) consume(r *http.Request, token string) (*confirmation, error) {
+	var get *redis.StringCmd
+	_, err := g.rc.TxPipelined(r.Context(), func(p redis.Pipeliner) error {
+		get = p.Get(r.Context(), confirmationKey(token))
+		p.Del(r.Context(), confirmationKey(token))
+		return nil
+	})
+	if err == redis.Nil {
+		return nil, fmt.Errorf("confirmation token is invalid, expired or already used")
+	}
+	if err != nil {
+		return nil, err
+	}
+	var c confirmation
+	if err := json.Unmarshal([]byte(get.Val()), &c); err != nil {
+		return nil, fmt.Errorf("could not decode confirmation %q: %v", get.Val(), err)
+	}
+	if c.Method != r.Method || c.Path != r.URL.Path {
+		return nil, fmt.Errorf("confirmation token was issued for %s %s", c.Method, c.Path)
+	}
+	if c.User != requestUser(r) {
+		return nil, fmt.Errorf("confirmation token was issued to another user")
+	}
+	return &c, nil
+}
+
+// countTasksInState returns a counter of the tasks of the queue in the given state.
+func countTasksInState(inspector *asynq.Inspector, state asynq.TaskState) affectedCounter {
+	return func(w http.ResponseWriter, r *http.Request) (int, bool) {
+		span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
+		info, err := inspector.GetQueueInfo(mux.Vars(r)["qname"])
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return 0, false
+		}
+		switch state {
+		case asynq.TaskStateActive:
+			return info.Active, true
+		case asynq.TaskStatePending:
+			return info.Pending, true
+		case asynq.TaskStateScheduled:
+			return info.Scheduled, true
+		case asynq.TaskStateRetry:
+			return info.Retry, true
+		case asynq.TaskStateArchived:
+			return info.Archived, true
+		case asynq.TaskStateCompleted:
+			return info.Completed, true
+		case asynq.TaskStateAggregating:
+			return info.Aggregating, true
+		}
+		return 0, true
+	}
+}
+
+// countQueueTasks returns a counter of all tasks of the queue.
+func countQueueTasks(inspector *asynq.Inspector) affectedCounter {
+	return func(w http.ResponseWriter, r *http.Request) (int, bool) {
+		span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
+		info, err := inspector.GetQueueInfo(mux.Vars(r)["qname"])
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return 0, false
+		}
+		return info.Size + info.Completed, true
+	}
+}
+
+// countGroupTasks returns a counter of the aggregating tasks of the group.
+func countGroupTasks(inspector *asynq.Inspector) affectedCounter {
+	return func(w http.ResponseWriter, r *http.Request) (int, bool) {
+		vars := mux.Vars(r)
+		span := startSpan(r.Context(), "asynq.Inspector/Groups")
+		groups, err := inspector.Groups(vars["qname"])
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return 0, false
+		}
+		for _, g := range groups {
+			if g.Group == vars["gname"] {
+				return g.Size, true
+			}
+		}
+		return 0, true
+	}
+}
+
+// countErrorClusterTasks returns a counter of the tasks of the error cluster.
+func countErrorClusterTasks(inspector *asynq.Inspector, state asynq.TaskState) affectedCounter {
+	return func(w http.ResponseWriter, r *http.Request) (int, bool) {
+		c := findErrorCluster(w, r, inspector, state)
+		if c == nil {
+			return 0, false
+		}
+		return len(c.taskIDs), true
+	}
+}
@@ -0,0 +1,79 @@
+package asynqmon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestConfirmationGuardWithoutToken(t *testing.T) {
+	// Requests without a token and not dry runs don't reach redis.
+	tests := []struct {
+		required   bool
+		wantStatus int
+		wantCalled bool
+	}{
+		{required: false, wantStatus: http.StatusNoContent, wantCalled: true},
+		{required: true, wantStatus: http.StatusPreconditionRequired, wantCalled: false},
+	}
+	for _, tc := range tests {
+		called := false
+		h := newConfirmationGuard(nil, tc.required).wrap(
+			func(w http.ResponseWriter, r *http.Request) (int, bool) {
+				t.Error("counter called for a request which is not a dry run")
+				return 0, false
+			},
+			func(w http.ResponseWriter, r *http.Request) {
+				called = true
+				w.WriteHeader(http.StatusNoContent)
+			},
+		)
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest("DELETE", "/api/queues/critical/archived_tasks:delete_all", nil))
+		if w.Code != tc.wantStatus || called != tc.wantCalled {
+			t.Errorf("required=%t: got status %d, handler called %t; want status %d, handler called %t",
+				tc.required, w.Code, called, tc.wantStatus, tc.wantCalled)
+		}
+	}
+}
+
+func TestIsDryRun(t *testing.T) {
+	tests := []struct {
+		query string
+		want  bool
+	}{
+		{"", false},
+		{"?dry_run=true", true},
+		{"?dry_run=1", true},
+		{"?dry_run=false", false},
+		{"?dry_run=yes", false},
+	}
+	for _, tc := range tests {
+		r := httptest.NewRequest("DELETE", "/api/queues/critical"+tc.query, nil)
+		if got := isDryRun(r); got != tc.want {
+			t.Errorf("isDryRun(%q) = %t, want %t", tc.query, got, tc.want)
+		}
+	}
+}
+
+func TestExceedsPreview(t *testing.T) {
+	tests := []struct {
+		previewed int
+		current   int
+		want      bool
+	}{
+		{0, 0, false},
+		{0, 10, false},
+		{0, 11, true},
+		{1000, 900, false},
+		{1000, 1100, false},
+		{1000, 1101, true},
+		{50, 60, false},
+		{50, 61, true},
+	}
+	for _, tc := range tests {
+		if got := exceedsPreview(tc.previewed, tc.current); got != tc.want {
+			t.Errorf("exceedsPreview(%d, %d) = %t, want %t", tc.previewed, tc.current, got, tc.want)
+		}
+	}
+}
@@ -139,7 +139,10 @@ type dailyStats struct {
 	Processed int    `json:"processed"`
 	Succeeded int    `json:"succeeded"`
 	Failed    int    `json:"failed"`
-	Date      string `json:"date"`
+	// Date in YYYY-MM-DD format. When aggregated by week or month, this is the first day of the period.
+	Date string `json:"date"`
+	// Number of days included in the aggregated stats. Set only when aggregated by week or month.
+	Days int `json:"days,omitempty"`
 }
 
 func toDailyStats(s *asynq.DailyStats) *dailyStats {
@@ -195,6 +198,15 @@ type taskInfo struct {
 	// TTL is the number of seconds the task has left to be retained in the queue.
 	// This is calculated by (CompletedAt + ResultTTL) - Now.
 	TTL int64 `json:"ttl_seconds"`
+	// Timeline is the lifecycle events of the task in chronological order.
+	// Set only if requested with the timeline query parameter.
+	Timeline []*taskEvent `json:"timeline,omitempty"`
+	// Notes left on the task, oldest first.
+	Notes []*note `json:"notes"`
+	// ErrorClusterID is the ID of the error cluster of the last error, and ErrorClusterNotes
+	// the notes left on the cluster. Set only if the task has failed.
+	ErrorClusterID    string  `json:"error_cluster_id,omitempty"`
+	ErrorClusterNotes []*note `json:"error_cluster_notes,omitempty"`
 }
 
 // taskTTL calculates TTL for the given task.
@@ -229,7 +241,7 @@ func toTaskInfo(info *asynq.TaskInfo, pf PayloadFormatter, rf ResultFormatter) *
 		Deadline:      formatTimeInRFC3339(info.Deadline),
 		NextProcessAt: formatTimeInRFC3339(info.NextProcessAt),
 		CompletedAt:   formatTimeInRFC3339(info.CompletedAt),
-		Result:        rf.FormatResult("", info.Result),
+		Result:        rf.FormatResult(info.Type, info.Result),
 		TTL:           int64(taskTTL(info).Seconds()),
 	}
 }
@@ -0,0 +1,359 @@
+package asynqmon
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - clustering of tasks by normalized error message
+//   - http.Handler(s) for error cluster related endpoints
+// ****************************************************************************
+
+const (
+	// Default and maximum number of tasks scanned to compute error clusters.
+	defaultErrorClusterScanLimit = 10000
+	maxErrorClusterScanLimit     = 100000
+
+	// Number of tasks listed per page while scanning tasks.
+	errorClusterScanPageSize = 1000
+
+	// Maximum number of example task IDs returned per cluster.
+	maxErrorClusterExamples = 5
+)
+
+// Patterns masked in error messages, in the order they are applied.
+var errorMessagePatterns = []struct {
+	re   *regexp.Regexp
+	mask string
+}{
+	{regexp.MustCompile(`[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12}`), "<uuid>"},
+	{regexp.MustCompile(`"(?:[^"\\]|\\.)*"`), `"<str>"`},
+	{regexp.MustCompile(`'(?:[^'\\]|\\.)*'`), `'<str>'`},
+	{regexp.MustCompile(`0x[0-9a-fA-F]+`), "<num>"},
+	{regexp.MustCompile(`[0-9]+(?:\.[0-9]+)?`), "<num>"},
+}
+
+// normalizeErrorMessage masks the parts of the error message which usually differ between tasks
+// failing for the same reason (UUIDs, quoted values and numbers).
+func normalizeErrorMessage(msg string) string {
+	for _, p := range errorMessagePatterns {
+		msg = p.re.ReplaceAllString(msg, p.mask)
+	}
+	return msg
+}
+
+// errorClusterID returns the ID of the cluster of the given normalized error message.
+func errorClusterID(pattern string) string {
+	sum := sha256.Sum256([]byte(pattern))
+	return hex.EncodeToString(sum[:8])
+}
+
+type errorCluster struct {
+	// ID identifies the cluster. It is derived from the pattern, so it is stable across requests.
+	ID string `json:"id"`
+	// Pattern is the normalized error message shared by the tasks in the cluster.
+	Pattern string `json:"pattern"`
+	// ExampleMessage is the error message of one of the tasks in the cluster.
+	ExampleMessage string `json:"example_message"`
+	// Count is the number of tasks in the cluster.
+	Count          int      `json:"count"`
+	ExampleTaskIDs []string `json:"example_task_ids"`
+	// Time range of last_failed_at of the tasks in RFC3339 format.
+	FirstFailedAt string `json:"first_failed_at"`
+	LastFailedAt  string `json:"last_failed_at"`
+	// TaskTypes is the number of tasks per task type, sorted by count in descending order.
+	TaskTypes []*taskTypeCount `json:"task_types"`
+	// Notes left on the cluster, oldest first.
+	Notes []*note `json:"notes"`
+
+	taskIDs                     []string
+	firstFailedAt, lastFailedAt time.Time
+	types                       map[string]int
+}
+
+type taskTypeCount struct {
+	Type  string `json:"type"`
+	Count int    `json:"count"`
+}
+
+// toTaskTypeCounts returns the counts by task type, sorted by count in descending order.
+func toTaskTypeCounts(types map[string]int) []*taskTypeCount {
+	res := make([]*taskTypeCount, 0, len(types))
+	for typ, n := range types {
+		res = append(res, &taskTypeCount{Type: typ, Count: n})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Count != res[j].Count {
+			return res[i].Count > res[j].Count
+		}
+		return res[i].Type < res[j].Type
+	})
+	return res
+}
+
+// clusterErrors groups the tasks by normalized error message.
+// The clusters are sorted by number of tasks in descending order.
+func clusterErrors(tasks []*asynq.TaskInfo) []*errorCluster {
+	m := make(map[string]*errorCluster)
+	var clusters []*errorCluster
+	for _, t := range tasks {
+		pattern := normalizeErrorMessage(t.LastErr)
+		c, ok := m[pattern]
+		if !ok {
+			c = &errorCluster{
+				ID:             errorClusterID(pattern),
+				Pattern:        pattern,
+				ExampleMessage: t.LastErr,
+				types:          make(map[string]int),
+			}
+			m[pattern] = c
+			clusters = append(clusters, c)
+		}
+		c.Count++
+		c.taskIDs = append(c.taskIDs, t.ID)
+		c.types[t.Type]++
+		if !t.LastFailedAt.IsZero() {
+			if c.firstFailedAt.IsZero() || t.LastFailedAt.Before(c.firstFailedAt) {
+				c.firstFailedAt = t.LastFailedAt
+			}
+			if t.LastFailedAt.After(c.lastFailedAt) {
+				c.lastFailedAt = t.LastFailedAt
+			}
+		}
+	}
+	for _, c := range clusters {
+		n := len(c.taskIDs)
+		if n > maxErrorClusterExamples {
+			n = maxErrorClusterExamples
+		}
+		c.ExampleTaskIDs = c.taskIDs[:n]
+		c.FirstFailedAt = formatTimeInRFC3339(c.firstFailedAt)
+		c.LastFailedAt = formatTimeInRFC3339(c.lastFailedAt)
+		c.TaskTypes = toTaskTypeCounts(c.types)
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return clusters[i].Count > clusters[j].Count })
+	return clusters
+}
+
+// scanErrorClusters lists up to limit tasks in the given state and groups them by error message.
+// It returns the clusters and the number of tasks scanned.
+func scanErrorClusters(r *http.Request, inspector *asynq.Inspector, qname string, state asynq.TaskState, limit int) ([]*errorCluster, int, error) {
+	var tasks []*asynq.TaskInfo
+	for page := 1; len(tasks) < limit; page++ {
+		var (
+			list []*asynq.TaskInfo
+			err  error
+		)
+		opts := []asynq.ListOption{asynq.PageSize(errorClusterScanPageSize), asynq.Page(page)}
+		switch state {
+		case asynq.TaskStateRetry:
+			span := startSpan(r.Context(), "asynq.Inspector/ListRetryTasks")
+			list, err = inspector.ListRetryTasks(qname, opts...)
+			endSpan(span, err)
+		case asynq.TaskStateArchived:
+			span := startSpan(r.Context(), "asynq.Inspector/ListArchivedTasks")
+			list, err = inspector.ListArchivedTasks(qname, opts...)
+			endSpan(span, err)
+		default:
+			return nil, 0, fmt.Errorf("cannot cluster errors of %s tasks", state)
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+		tasks = append(tasks, list...)
+		if len(list) < errorClusterScanPageSize {
+			break
+		}
+	}
+	if len(tasks) > limit {
+		tasks = tasks[:limit]
+	}
+	return clusterErrors(tasks), len(tasks), nil
+}
+
+type listErrorClustersResponse struct {
+	Clusters []*errorCluster `json:"clusters"`
+	// Number of tasks in the state, and the number of tasks scanned to compute the clusters.
+	// Only the first `limit` tasks are scanned.
+	Total   int `json:"total"`
+	Scanned int `json:"scanned"`
+}
+
+func newListErrorClustersHandlerFunc(inspector *asynq.Inspector, state asynq.TaskState, ns *noteStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qname := mux.Vars(r)["qname"]
+		limit, err := intQueryParam(r, "limit", defaultErrorClusterScanLimit, 1, maxErrorClusterScanLimit)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
+		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		clusters, scanned, err := scanErrorClusters(r, inspector, qname, state, limit)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		keys := make([]string, len(clusters))
+		for i, c := range clusters {
+			keys[i] = errorClusterNotesKey(c.ID)
+		}
+		notes, err := ns.list(r.Context(), keys...)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		for i, c := range clusters {
+			c.Notes = notes[i]
+		}
+		resp := listErrorClustersResponse{
+			// avoid null in the json response
+			Clusters: make([]*errorCluster, 0, len(clusters)),
+			Scanned:  scanned,
+		}
+		resp.Clusters = append(resp.Clusters, clusters...)
+		switch state {
+		case asynq.TaskStateRetry:
+			resp.Total = qinfo.Retry
+		case asynq.TaskStateArchived:
+			resp.Total = qinfo.Archived
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+// findErrorCluster scans the tasks in the given state and returns the cluster with the ID
+// in the route parameters. It writes an error response and returns nil if the cluster is not found.
+func findErrorCluster(w http.ResponseWriter, r *http.Request, inspector *asynq.Inspector, state asynq.TaskState) *errorCluster {
+	vars := mux.Vars(r)
+	qname, id := vars["qname"], vars["cluster_id"]
+	limit, err := intQueryParam(r, "limit", defaultErrorClusterScanLimit, 1, maxErrorClusterScanLimit)
+	if err != nil {
+		writeBadRequest(w, r, "%v", err)
+		return nil
+	}
+	clusters, _, err := scanErrorClusters(r, inspector, qname, state, limit)
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return nil
+	}
+	for _, c := range clusters {
+		if c.ID == id {
+			return c
+		}
+	}
+	writeError(w, r, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("error cluster %q not found in %s tasks", id, state))
+	return nil
+}
+
+func newRunErrorClusterHandlerFunc(inspector *asynq.Inspector, state asynq.TaskState, tl *taskTimeline, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c := findErrorCluster(w, r, inspector, state)
+		if c == nil {
+			return
+		}
+		qname := mux.Vars(r)["qname"]
+		resp := batchRunTasksResponse{
+			// avoid null in the json response
+			PendingIDs: make([]string, 0),
+			ErrorIDs:   make([]string, 0),
+		}
+		for _, taskid := range c.taskIDs {
+			span := startSpan(r.Context(), "asynq.Inspector/RunTask")
+			err := inspector.RunTask(qname, taskid)
+			endSpan(span, err)
+			if err != nil {
+				if logger != nil {
+					logger.Warn("Failed to run task in error cluster", slog.String("request_id", requestIDFromContext(r.Context())),
+						slog.String("queue", qname), slog.String("cluster_id", c.ID), slog.String("task_id", taskid),
+						slog.String("error", err.Error()))
+				}
+				resp.ErrorIDs = append(resp.ErrorIDs, taskid)
+			} else {
+				resp.PendingIDs = append(resp.PendingIDs, taskid)
+				tl.recordAction(r, qname, taskid, "run_error_cluster", "pending")
+			}
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+func newArchiveErrorClusterHandlerFunc(inspector *asynq.Inspector, state asynq.TaskState, tl *taskTimeline, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c := findErrorCluster(w, r, inspector, state)
+		if c == nil {
+			return
+		}
+		qname := mux.Vars(r)["qname"]
+		resp := batchArchiveTasksResponse{
+			// avoid null in the json response
+			ArchivedIDs: make([]string, 0),
+			ErrorIDs:    make([]string, 0),
+		}
+		for _, taskid := range c.taskIDs {
+			span := startSpan(r.Context(), "asynq.Inspector/ArchiveTask")
+			err := inspector.ArchiveTask(qname, taskid)
+			endSpan(span, err)
+			if err != nil {
+				if logger != nil {
+					logger.Warn("Failed to archive task in error cluster", slog.String("request_id", requestIDFromContext(r.Context())),
+						slog.String("queue", qname), slog.String("cluster_id", c.ID), slog.String("task_id", taskid),
+						slog.String("error", err.Error()))
+				}
+				resp.ErrorIDs = append(resp.ErrorIDs, taskid)
+			} else {
+				resp.ArchivedIDs = append(resp.ArchivedIDs, taskid)
+				tl.recordAction(r, qname, taskid, "archive_error_cluster", "archived")
+			}
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+func newDeleteErrorClusterHandlerFunc(inspector *asynq.Inspector, state asynq.TaskState, tl *taskTimeline, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		c := findErrorCluster(w, r, inspector, state)
+		if c == nil {
+			return
+		}
+		qname := mux.Vars(r)["qname"]
+		resp := batchDeleteTasksResponse{
+			// avoid null in the json response
+			DeletedIDs: make([]string, 0),
+			FailedIDs:  make([]string, 0),
+		}
+		for _, taskid := range c.taskIDs {
+			span := startSpan(r.Context(), "asynq.Inspector/DeleteTask")
+			err := inspector.DeleteTask(qname, taskid)
+			endSpan(span, err)
+			if err != nil {
+				if logger != nil {
+					logger.Warn("Failed to delete task in error cluster", slog.String("request_id", requestIDFromContext(r.Context())),
+						slog.String("queue", qname), slog.String("cluster_id", c.ID), slog.String("task_id", taskid),
+						slog.String("error", err.Error()))
+				}
+				resp.FailedIDs = append(resp.FailedIDs, taskid)
+			} else {
+				resp.DeletedIDs = append(resp.DeletedIDs, taskid)
+				tl.recordAction(r, qname, taskid, "delete_error_cluster", "")
+			}
+		}
+		writeResponseJSON(w, resp)
+	}
+}
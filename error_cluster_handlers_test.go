@@ -0,0 +1,79 @@
+package asynqmon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hibiken/asynq"
+)
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{
+			`user 1234 not found`,
+			`user <num> not found`,
+		},
+		{
+			`could not fetch order 5f0e7a5c-3d2b-4c1a-9e8f-0a1b2c3d4e5f: timeout after 2.5s`,
+			`could not fetch order <uuid>: timeout after <num>s`,
+		},
+		{
+			`invalid email "foo@example.com" for account 'acme-42'`,
+			`invalid email "<str>" for account '<str>'`,
+		},
+		{
+			`dial tcp 10.0.0.12:6379: connect: connection refused`,
+			`dial tcp <num>.<num>:<num>: connect: connection refused`,
+		},
+		{
+			`unexpected value 0xdeadbeef`,
+			`unexpected value <num>`,
+		},
+	}
+	for _, tc := range tests {
+		if got := normalizeErrorMessage(tc.msg); got != tc.want {
+			t.Errorf("normalizeErrorMessage(%q) = %q, want %q", tc.msg, got, tc.want)
+		}
+	}
+}
+
+func TestClusterErrors(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	tasks := []*asynq.TaskInfo{
+		{ID: "t1", Type: "email:send", LastErr: `user 1 not found`, LastFailedAt: now.Add(-3 * time.Hour)},
+		{ID: "t2", Type: "email:send", LastErr: `smtp: 421 service not available`, LastFailedAt: now.Add(-2 * time.Hour)},
+		{ID: "t3", Type: "email:welcome", LastErr: `user 2 not found`, LastFailedAt: now.Add(-1 * time.Hour)},
+		{ID: "t4", Type: "email:send", LastErr: `user 3 not found`, LastFailedAt: now.Add(-5 * time.Hour)},
+	}
+	want := []*errorCluster{
+		{
+			ID:             errorClusterID("user <num> not found"),
+			Pattern:        "user <num> not found",
+			ExampleMessage: "user 1 not found",
+			Count:          3,
+			ExampleTaskIDs: []string{"t1", "t3", "t4"},
+			FirstFailedAt:  now.Add(-5 * time.Hour).Format(time.RFC3339),
+			LastFailedAt:   now.Add(-1 * time.Hour).Format(time.RFC3339),
+			TaskTypes:      []*taskTypeCount{{"email:send", 2}, {"email:welcome", 1}},
+		},
+		{
+			ID:             errorClusterID("smtp: <num> service not available"),
+			Pattern:        "smtp: <num> service not available",
+			ExampleMessage: "smtp: 421 service not available",
+			Count:          1,
+			ExampleTaskIDs: []string{"t2"},
+			FirstFailedAt:  now.Add(-2 * time.Hour).Format(time.RFC3339),
+			LastFailedAt:   now.Add(-2 * time.Hour).Format(time.RFC3339),
+			TaskTypes:      []*taskTypeCount{{"email:send", 1}},
+		},
+	}
+	got := clusterErrors(tasks)
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreUnexported(errorCluster{})); diff != "" {
+		t.Errorf("clusterErrors diff (-want, +got):\n%s", diff)
+	}
+}
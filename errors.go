@@ -0,0 +1,130 @@
+package asynqmon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - error response type returned by all API endpoints
+//   - mapping from errors returned by asynq to HTTP status codes
+// ****************************************************************************
+
+// Machine-readable error codes used in error responses.
+const (
+	errCodeBadRequest           = "bad_request"
+	errCodeNotFound             = "not_found"
+	errCodeMethodNotAllowed     = "method_not_allowed"
+	errCodeQueueNotFound        = "queue_not_found"
+	errCodeTaskNotFound         = "task_not_found"
+	errCodeQueueNotEmpty        = "queue_not_empty"
+	errCodeFailedPrecondition   = "failed_precondition"
+	errCodeAlreadyExists        = "already_exists"
+	errCodeReadOnly             = "read_only"
+	errCodePermissionDenied     = "permission_denied"
+	errCodeConfirmationRequired = "confirmation_required"
+	errCodeInvalidConfirmation  = "invalid_confirmation_token"
+	errCodeOutdatedConfirmation = "outdated_confirmation_token"
+	errCodeUnavailable          = "unavailable"
+	errCodeInternal             = "internal"
+)
+
+// errorResponse is the JSON body of an error response.
+type errorResponse struct {
+	// Code is a machine-readable error code (e.g. "queue_not_found").
+	Code string `json:"code"`
+	// Message is a human-readable description of the error.
+	Message string `json:"message"`
+	// Queue is the name of the queue the request was made against, if any.
+	Queue string `json:"queue,omitempty"`
+	// TaskID is the ID of the task the request was made against, if any.
+	TaskID string `json:"task_id,omitempty"`
+}
+
+// writeErrorResponse writes an error response for err with the status code and error code
+// corresponding to the error.
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, err error) {
+	status, code := classifyError(err)
+	writeError(w, r, status, code, strings.TrimPrefix(err.Error(), "asynq: "))
+}
+
+// writeBadRequest writes an error response with status 400 and the formatted message.
+func writeBadRequest(w http.ResponseWriter, r *http.Request, format string, args ...interface{}) {
+	writeError(w, r, http.StatusBadRequest, errCodeBadRequest, fmt.Sprintf(format, args...))
+}
+
+// writeError writes an error response with the given status code, error code and message.
+// Queue name and task ID are taken from the route variables of r if present.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code, msg string) {
+	resp := errorResponse{Code: code, Message: msg}
+	if r != nil {
+		vars := mux.Vars(r)
+		resp.Queue = vars["qname"]
+		resp.TaskID = vars["task_id"]
+	}
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(resp)
+}
+
+// classifyError returns the HTTP status code and error code for err.
+func classifyError(err error) (status int, code string) {
+	switch {
+	case errors.Is(err, asynq.ErrQueueNotFound):
+		return http.StatusNotFound, errCodeQueueNotFound
+	case errors.Is(err, asynq.ErrTaskNotFound):
+		return http.StatusNotFound, errCodeTaskNotFound
+	case errors.Is(err, asynq.ErrQueueNotEmpty):
+		return http.StatusBadRequest, errCodeQueueNotEmpty
+	}
+	// Some Inspector methods return asynq's internal errors as is, or only their message
+	// (e.g. `asynq: NOT_FOUND: queue "default" does not exist`). Their types are in an internal
+	// package, so they are classified by the canonical error code at the start of the message.
+	canonical, detail := canonicalErrorCode(err)
+	switch canonical {
+	case "NOT_FOUND":
+		if strings.HasPrefix(detail, "cannot find task") {
+			return http.StatusNotFound, errCodeTaskNotFound
+		}
+		return http.StatusNotFound, errCodeQueueNotFound
+	case "FAILED_PRECONDITION":
+		return http.StatusBadRequest, errCodeFailedPrecondition
+	case "ALREADY_EXISTS":
+		return http.StatusConflict, errCodeAlreadyExists
+	}
+	// PauseQueue and UnpauseQueue return plain errors without a canonical error code.
+	if msg := err.Error(); strings.HasSuffix(msg, "is already paused") || strings.HasSuffix(msg, "is not paused") {
+		return http.StatusBadRequest, errCodeFailedPrecondition
+	}
+	return http.StatusInternalServerError, errCodeInternal
+}
+
+// canonicalErrorCode returns the canonical error code at the start of the message of an
+// asynq internal error (e.g. "NOT_FOUND") and the rest of the message.
+// It returns empty strings if the message doesn't start with a canonical error code.
+func canonicalErrorCode(err error) (code, detail string) {
+	msg := strings.TrimPrefix(err.Error(), "asynq: ")
+	code, detail, ok := strings.Cut(msg, ": ")
+	if !ok || code == "" || strings.Trim(code, "ABCDEFGHIJKLMNOPQRSTUVWXYZ_") != "" {
+		return "", ""
+	}
+	return code, detail
+}
+
+// notFoundAPIHandler is used for API requests which do not match any route.
+func notFoundAPIHandler(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("no API endpoint for %s %s", r.Method, r.URL.Path))
+}
+
+// methodNotAllowedAPIHandler is used for API requests which match a route but not its method.
+func methodNotAllowedAPIHandler(w http.ResponseWriter, r *http.Request) {
+	writeError(w, r, http.StatusMethodNotAllowed, errCodeMethodNotAllowed, fmt.Sprintf("method %s is not allowed for %s", r.Method, r.URL.Path))
+}
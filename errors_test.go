@@ -0,0 +1,66 @@
+package asynqmon
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+	"github.com/hibiken/asynq"
+)
+
+func TestClassifyError(t *testing.T) {
+	tests := []struct {
+		err        error
+		wantStatus int
+		wantCode   string
+	}{
+		{fmt.Errorf("asynq: %w", asynq.ErrQueueNotFound), http.StatusNotFound, errCodeQueueNotFound},
+		{fmt.Errorf("asynq: %w", asynq.ErrTaskNotFound), http.StatusNotFound, errCodeTaskNotFound},
+		{fmt.Errorf("%w: queue=%q", asynq.ErrQueueNotEmpty, "default"), http.StatusBadRequest, errCodeQueueNotEmpty},
+		// Internal errors returned as is by asynq.
+		{errors.New(`NOT_FOUND: queue "default" does not exist`), http.StatusNotFound, errCodeQueueNotFound},
+		{errors.New(`NOT_FOUND: cannot find task with id=abc in queue "default"`), http.StatusNotFound, errCodeTaskNotFound},
+		{errors.New(`FAILED_PRECONDITION: cannot kill task in active state`), http.StatusBadRequest, errCodeFailedPrecondition},
+		{errors.New(`ALREADY_EXISTS: task already exists`), http.StatusConflict, errCodeAlreadyExists},
+		// Internal errors flattened into a message by asynq.
+		{errors.New(`asynq: NOT_FOUND: queue "default" does not exist`), http.StatusNotFound, errCodeQueueNotFound},
+		{errors.New(`asynq: NOT_FOUND: cannot find task with id=abc in queue "default"`), http.StatusNotFound, errCodeTaskNotFound},
+		{errors.New(`queue "default" is already paused`), http.StatusBadRequest, errCodeFailedPrecondition},
+		{errors.New(`queue "default" is not paused`), http.StatusBadRequest, errCodeFailedPrecondition},
+		{errors.New(`INTERNAL_ERROR: redis eval error: ERR NOT_FOUND`), http.StatusInternalServerError, errCodeInternal},
+		{errors.New("dial tcp 127.0.0.1:6379: connect: connection refused"), http.StatusInternalServerError, errCodeInternal},
+	}
+	for _, tc := range tests {
+		status, code := classifyError(tc.err)
+		if status != tc.wantStatus || code != tc.wantCode {
+			t.Errorf("classifyError(%q) = %d, %q, want %d, %q", tc.err, status, code, tc.wantStatus, tc.wantCode)
+		}
+	}
+}
+
+func TestWriteErrorResponse(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/queues/default/retry_tasks/abc:run", nil)
+	r = mux.SetURLVars(r, map[string]string{"qname": "default", "task_id": "abc"})
+	w := httptest.NewRecorder()
+	writeErrorResponse(w, r, fmt.Errorf("asynq: %w", asynq.ErrTaskNotFound))
+
+	if w.Code != http.StatusNotFound {
+		t.Errorf("status = %d, want %d", w.Code, http.StatusNotFound)
+	}
+	if got, want := w.Header().Get("Content-Type"), "application/json; charset=utf-8"; got != want {
+		t.Errorf("Content-Type = %q, want %q", got, want)
+	}
+	var got errorResponse
+	if err := json.NewDecoder(w.Body).Decode(&got); err != nil {
+		t.Fatalf("could not decode response body: %v", err)
+	}
+	want := errorResponse{Code: errCodeTaskNotFound, Message: "task not found", Queue: "default", TaskID: "abc"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("writeErrorResponse wrote %+v, want %+v; (-want,+got)\n%s", got, want, diff)
+	}
+}
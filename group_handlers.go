@@ -1,11 +1,16 @@
 package asynqmon
 
 import (
+	"context"
 	"encoding/json"
+	"log/slog"
 	"net/http"
+	"sort"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
 )
 
 type listGroupsResponse struct {
@@ -17,14 +22,18 @@ func newListGroupsHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
 
+		span := startSpan(r.Context(), "asynq.Inspector/Groups")
 		groups, err := inspector.Groups(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 
@@ -33,8 +42,249 @@ func newListGroupsHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 			Groups: toGroupInfos(groups),
 		}
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 	}
 }
+
+// Number of aggregating tasks listed per page to count the task types in a group.
+const groupTasksPageSize = 1000
+
+// Default number of aggregating tasks scanned per group to count the task types.
+const defaultGroupTaskSample = 1000
+
+// groupKey returns the redis key of the sorted set of the aggregating tasks in the group,
+// scored by the time the task was added to the group (see asynq base.GroupKey).
+func groupKey(qname, gname string) string {
+	return queueKeyPrefix(qname) + "g:" + gname
+}
+
+// groupDetail describes the tasks waiting in a group to be aggregated.
+type groupDetail struct {
+	Group string `json:"group"`
+	Size  int    `json:"size"`
+	// Times the oldest and newest tasks were added to the group, in RFC3339 format.
+	OldestTaskTime string `json:"oldest_task_time"`
+	NewestTaskTime string `json:"newest_task_time"`
+	// Seconds elapsed since the oldest task was added to the group.
+	AgeSeconds float64 `json:"age_seconds"`
+	// Task types of the first Scanned tasks in the group.
+	TaskTypes []*taskTypeCount `json:"task_types"`
+	Scanned   int              `json:"scanned"`
+
+	oldest time.Time
+}
+
+// groupSizeBucket is the number of groups with a size in [Min, Max].
+type groupSizeBucket struct {
+	Min int `json:"min"`
+	// Max is 0 for the last bucket, which has no upper bound.
+	Max    int `json:"max"`
+	Groups int `json:"groups"`
+}
+
+// groupSizeHistogram returns the number of groups by size, in buckets of powers of ten.
+func groupSizeHistogram(groups []*groupDetail) []*groupSizeBucket {
+	buckets := []*groupSizeBucket{{Min: 1, Max: 1}, {Min: 2, Max: 10}, {Min: 11, Max: 100}, {Min: 101, Max: 1000}, {Min: 1001}}
+	for _, g := range groups {
+		for _, b := range buckets {
+			if g.Size >= b.Min && (b.Max == 0 || g.Size <= b.Max) {
+				b.Groups++
+				break
+			}
+		}
+	}
+	return buckets
+}
+
+// groupTimeRange returns the times the oldest and newest tasks were added to the group.
+func groupTimeRange(ctx context.Context, rc redis.UniversalClient, qname, gname string) (oldest, newest time.Time, err error) {
+	key := groupKey(qname, gname)
+	var first, last *redis.ZSliceCmd
+	_, err = rc.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		first = pipe.ZRangeWithScores(ctx, key, 0, 0)
+		last = pipe.ZRangeWithScores(ctx, key, -1, -1)
+		return nil
+	})
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if zs := first.Val(); len(zs) > 0 {
+		oldest = time.Unix(int64(zs[0].Score), 0)
+	}
+	if zs := last.Val(); len(zs) > 0 {
+		newest = time.Unix(int64(zs[0].Score), 0)
+	}
+	return oldest, newest, nil
+}
+
+// groupDetails returns the details of the groups in the queue, oldest first.
+// Task types are counted over up to sample tasks per group.
+func groupDetails(r *http.Request, inspector *asynq.Inspector, rc redis.UniversalClient, qname string, sample int) ([]*groupDetail, error) {
+	span := startSpan(r.Context(), "asynq.Inspector/Groups")
+	groups, err := inspector.Groups(qname)
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	res := make([]*groupDetail, 0, len(groups))
+	for _, g := range groups {
+		oldest, newest, err := groupTimeRange(r.Context(), rc, qname, g.Group)
+		if err != nil {
+			return nil, err
+		}
+		d := &groupDetail{
+			Group:          g.Group,
+			Size:           g.Size,
+			OldestTaskTime: formatTimeInRFC3339(oldest),
+			NewestTaskTime: formatTimeInRFC3339(newest),
+			oldest:         oldest,
+		}
+		if !oldest.IsZero() {
+			d.AgeSeconds = now.Sub(oldest).Seconds()
+		}
+		types := make(map[string]int)
+		for page := 1; d.Scanned < sample; page++ {
+			span := startSpan(r.Context(), "asynq.Inspector/ListAggregatingTasks")
+			tasks, err := inspector.ListAggregatingTasks(qname, g.Group, asynq.PageSize(groupTasksPageSize), asynq.Page(page))
+			endSpan(span, err)
+			if err != nil {
+				return nil, err
+			}
+			for _, t := range tasks {
+				if d.Scanned == sample {
+					break
+				}
+				types[t.Type]++
+				d.Scanned++
+			}
+			if len(tasks) < groupTasksPageSize {
+				break
+			}
+		}
+		d.TaskTypes = toTaskTypeCounts(types)
+		res = append(res, d)
+	}
+	sort.SliceStable(res, func(i, j int) bool { return res[i].AgeSeconds > res[j].AgeSeconds })
+	return res, nil
+}
+
+type listGroupDetailsResponse struct {
+	Groups    []*groupDetail     `json:"groups"`
+	Histogram []*groupSizeBucket `json:"size_histogram"`
+}
+
+func newListGroupDetailsHandlerFunc(inspector *asynq.Inspector, rc redis.UniversalClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qname := mux.Vars(r)["qname"]
+		sample, err := intQueryParam(r, "sample", defaultGroupTaskSample, 0, 100000)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		groups, err := groupDetails(r, inspector, rc, qname, sample)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeResponseJSON(w, listGroupDetailsResponse{
+			Groups:    groups,
+			Histogram: groupSizeHistogram(groups),
+		})
+	}
+}
+
+type flushGroupsRequest struct {
+	// Names of the groups to flush.
+	Groups []string `json:"groups"`
+	// If set, flush the groups whose oldest task was added more than the given number of seconds ago.
+	OlderThanSeconds int `json:"older_than_seconds"`
+}
+
+// groupFlushResult is the outcome of flushing a group.
+type groupFlushResult struct {
+	Group string `json:"group"`
+	// Number of tasks moved to pending state.
+	Run   int    `json:"run"`
+	Error string `json:"error,omitempty"`
+}
+
+type flushGroupsResponse struct {
+	Results []*groupFlushResult `json:"results"`
+	// Total number of tasks moved to pending state.
+	Run int `json:"run"`
+}
+
+// groupsToFlush returns the names of the groups selected by the request.
+// Requested groups which do not exist are returned in notFound.
+func groupsToFlush(groups []*groupDetail, req *flushGroupsRequest, now time.Time) (names, notFound []string) {
+	if len(req.Groups) > 0 {
+		exists := make(map[string]bool)
+		for _, g := range groups {
+			exists[g.Group] = true
+		}
+		for _, name := range req.Groups {
+			if exists[name] {
+				names = append(names, name)
+			} else {
+				notFound = append(notFound, name)
+			}
+		}
+		return names, notFound
+	}
+	threshold := time.Duration(req.OlderThanSeconds) * time.Second
+	for _, g := range groups {
+		if !g.oldest.IsZero() && now.Sub(g.oldest) > threshold {
+			names = append(names, g.Group)
+		}
+	}
+	return names, nil
+}
+
+// newFlushGroupsHandlerFunc returns a handler which moves all aggregating tasks of the selected
+// groups to pending state without waiting for the groups to be aggregated.
+func newFlushGroupsHandlerFunc(inspector *asynq.Inspector, rc redis.UniversalClient, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qname := mux.Vars(r)["qname"]
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		var req flushGroupsRequest
+		if err := dec.Decode(&req); err != nil {
+			writeBadRequest(w, r, "invalid request body: %v", err)
+			return
+		}
+		if (len(req.Groups) > 0) == (req.OlderThanSeconds > 0) {
+			writeBadRequest(w, r, "exactly one of groups or older_than_seconds must be specified")
+			return
+		}
+		groups, err := groupDetails(r, inspector, rc, qname, 0)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		names, notFound := groupsToFlush(groups, &req, time.Now())
+		resp := flushGroupsResponse{Results: make([]*groupFlushResult, 0, len(names)+len(notFound))}
+		for _, gname := range names {
+			span := startSpan(r.Context(), "asynq.Inspector/RunAllAggregatingTasks")
+			n, err := inspector.RunAllAggregatingTasks(qname, gname)
+			endSpan(span, err)
+			res := &groupFlushResult{Group: gname, Run: n}
+			if err != nil {
+				if logger != nil {
+					logger.Warn("Failed to flush group", slog.String("request_id", requestIDFromContext(r.Context())),
+						slog.String("queue", qname), slog.String("group", gname), slog.String("error", err.Error()))
+				}
+				res.Error = err.Error()
+			}
+			resp.Run += n
+			resp.Results = append(resp.Results, res)
+		}
+		for _, gname := range notFound {
+			resp.Results = append(resp.Results, &groupFlushResult{Group: gname, Error: "group not found"})
+		}
+		writeResponseJSON(w, resp)
+	}
+}
@@ -0,0 +1,51 @@
+package asynqmon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestGroupSizeHistogram(t *testing.T) {
+	groups := []*groupDetail{{Size: 1}, {Size: 2}, {Size: 10}, {Size: 11}, {Size: 500}, {Size: 5000}}
+	got := groupSizeHistogram(groups)
+	want := []*groupSizeBucket{
+		{Min: 1, Max: 1, Groups: 1},
+		{Min: 2, Max: 10, Groups: 2},
+		{Min: 11, Max: 100, Groups: 1},
+		{Min: 101, Max: 1000, Groups: 1},
+		{Min: 1001, Groups: 1},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("groupSizeHistogram mismatch (-want,+got)\n%s", diff)
+	}
+}
+
+func TestGroupsToFlush(t *testing.T) {
+	now := time.Now()
+	groups := []*groupDetail{
+		{Group: "old", oldest: now.Add(-time.Hour)},
+		{Group: "recent", oldest: now.Add(-time.Minute)},
+		{Group: "empty"},
+	}
+	tests := []struct {
+		desc         string
+		req          flushGroupsRequest
+		wantNames    []string
+		wantNotFound []string
+	}{
+		{"by name", flushGroupsRequest{Groups: []string{"recent", "missing"}}, []string{"recent"}, []string{"missing"}},
+		{"older than 10m", flushGroupsRequest{OlderThanSeconds: 600}, []string{"old"}, nil},
+		{"older than 30s", flushGroupsRequest{OlderThanSeconds: 30}, []string{"old", "recent"}, nil},
+	}
+	for _, tc := range tests {
+		names, notFound := groupsToFlush(groups, &tc.req, now)
+		if diff := cmp.Diff(tc.wantNames, names); diff != "" {
+			t.Errorf("%s: names mismatch (-want,+got)\n%s", tc.desc, diff)
+		}
+		if diff := cmp.Diff(tc.wantNotFound, notFound); diff != "" {
+			t.Errorf("%s: notFound mismatch (-want,+got)\n%s", tc.desc, diff)
+		}
+	}
+}
@@ -3,12 +3,17 @@ package asynqmon
 import (
 	"embed"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strings"
+	"sync/atomic"
+	"time"
 
 	"github.com/gorilla/mux"
 	"github.com/hibiken/asynq"
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Options are used to configure HTTPHandler.
@@ -41,7 +46,107 @@ type Options struct {
 	PrometheusAddress string
 
 	// Set ReadOnly to true to restrict user to view-only mode.
+	//
+	// The mode can be changed later with HTTPHandler.SetReadOnly.
 	ReadOnly bool
+
+	// MetricsRegisterer is used to register prometheus metrics about asynqmon itself
+	// (API request latency and errors per route, redis command latency and number of bulk operations).
+	//
+	// This field is optional. If this field is not set, the metrics are not collected.
+	MetricsRegisterer prometheus.Registerer
+
+	// Logger is used to log API requests with the request ID, user, route template and latency,
+	// and failures that don't fail a request (e.g. recording the task timeline) or happen
+	// in the background (e.g. sampling).
+	//
+	// This field is optional. If this field is not set, nothing is logged.
+	Logger *slog.Logger
+
+	// TracerProvider is used to create trace spans for API requests,
+	// and child spans for inspector calls and redis commands.
+	//
+	// This field is optional. Default is the global TracerProvider (see otel.SetTracerProvider).
+	TracerProvider trace.TracerProvider
+
+	// RedisInfoSampleInterval specifies how often to sample redis INFO fields
+	// (e.g. used_memory, connected_clients) to show their history in the web UI.
+	// Samples are kept in memory and lost when the process exits.
+	//
+	// This field is optional. If this field is not set, redis INFO is not sampled.
+	RedisInfoSampleInterval time.Duration
+
+	// RedisInfoRetention specifies how long the samples of redis INFO fields are kept.
+	//
+	// This field is optional. Default is 24 hours.
+	RedisInfoRetention time.Duration
+
+	// ServerSampleInterval specifies how often to list the asynq servers to record
+	// their appearances and disappearances, and the fleet size over time.
+	// History is kept in memory and lost when the process exits.
+	//
+	// This field is optional. If this field is not set, servers are not tracked.
+	ServerSampleInterval time.Duration
+
+	// ServerHistoryRetention specifies how long the server history is kept.
+	//
+	// This field is optional. Default is 24 hours.
+	ServerHistoryRetention time.Duration
+
+	// TaskStateSampleInterval specifies how often to list the tasks of all queues to record
+	// their state changes in the task timeline. The first 100 tasks in each state of a queue
+	// (and in each group for aggregating tasks) are listed in each sample.
+	// Nothing is sampled in read-only mode.
+	//
+	// This field is optional. If this field is not set, state changes are not recorded.
+	TaskStateSampleInterval time.Duration
+
+	// SLOs specifies the service level objectives of queues, shown with their
+	// compliance and error budget burn rate in the web UI.
+	// SLOs are evaluated from Prometheus if PrometheusAddress is set,
+	// or from samples of the queue stats kept in memory otherwise.
+	//
+	// This field is optional.
+	SLOs []SLO
+
+	// QueueSampleInterval specifies how often to sample the stats of all queues
+	// (size, latency, memory usage and processed counts) when PrometheusAddress is not set.
+	// The samples are used to rank queues by growth and failure ratio, to evaluate SLOs,
+	// and to detect the queue events of Webhooks (sampled even if PrometheusAddress is set).
+	// Samples are kept in memory and lost when the process exits.
+	//
+	// This field is optional. If this field is not set, queues are sampled every minute
+	// if SLOs or Webhooks are set, and not sampled otherwise.
+	QueueSampleInterval time.Duration
+
+	// QueueSampleRetention specifies how long the queue samples are kept.
+	// The samples are kept for at least the longest window of the SLOs.
+	//
+	// This field is optional. Default is 24 hours.
+	QueueSampleRetention time.Duration
+
+	// Webhooks specifies the endpoints to publish operator actions and queue events to.
+	// Queue events (pause, resume and count changes) are detected by sampling the stats
+	// of all queues every QueueSampleInterval. Deliveries are logged in memory.
+	//
+	// This field is optional.
+	Webhooks []Webhook
+
+	// Set RequireConfirmation to true to reject the requests to destructive endpoints
+	// (deleting a queue, deleting all tasks in a state or a group, deleting an error cluster)
+	// without a confirmation token. The token is returned by a dry run of the same request
+	// with the dry_run=true query param, along with the number of tasks the request would affect.
+	//
+	// Dry runs are available even if RequireConfirmation is false.
+	RequireConfirmation bool
+
+	// Set TrustIdentityHeaders to true if asynqmon is served behind an authenticating proxy
+	// (e.g. oauth2-proxy) which sets the user name in the X-Forwarded-User or X-Auth-Request-User
+	// header (or in the basic auth credentials) and doesn't pass these headers from clients through.
+	// The user is then trusted to own saved views.
+	//
+	// Otherwise, anyone can send these headers, so saved views have no owner and can't be private.
+	TrustIdentityHeaders bool
 }
 
 // HTTPHandler is a http.Handler for asynqmon application.
@@ -49,6 +154,7 @@ type HTTPHandler struct {
 	router   *mux.Router
 	closers  []func() error
 	rootPath string // the value should not have the trailing slash
+	readOnly *readOnlyMode
 }
 
 func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
@@ -64,7 +170,9 @@ func New(opts Options) *HTTPHandler {
 	if !ok {
 		panic(fmt.Sprintf("asnyqmon.New: unsupported RedisConnOpt type %T", opts.RedisConnOpt))
 	}
-	i := asynq.NewInspector(opts.RedisConnOpt)
+	// Inspector shares the redis client so that the hooks added to the client
+	// apply to the commands issued by the inspector as well.
+	i := asynq.NewInspector(sharedRedisConnOpt{rc})
 
 	// Make sure that RootPath starts with a slash if provided.
 	if opts.RootPath != "" && !strings.HasPrefix(opts.RootPath, "/") {
@@ -73,10 +181,98 @@ func New(opts Options) *HTTPHandler {
 	// Remove tailing slash from RootPath.
 	opts.RootPath = strings.TrimSuffix(opts.RootPath, "/")
 
+	readOnly := &readOnlyMode{}
+	readOnly.set(opts.ReadOnly)
+
+	var m *selfMetrics
+	if opts.MetricsRegisterer != nil {
+		m = newSelfMetrics(opts.MetricsRegisterer, opts.RootPath)
+		rc.AddHook(redisMetricsHook{duration: m.redisCommandDuration})
+	}
+	rc.AddHook(redisTracingHook{})
+
+	var closers []func() error
+	var sampler *redisInfoSampler
+	if opts.RedisInfoSampleInterval > 0 {
+		sampler = newRedisInfoSampler(rc, opts.RedisInfoSampleInterval, opts.RedisInfoRetention, opts.Logger)
+		sampler.start()
+		// Stop the sampler before closing the redis client.
+		closers = append(closers, sampler.stop)
+	}
+	var servers *serverTracker
+	if opts.ServerSampleInterval > 0 {
+		servers = newServerTracker(rc, i, opts.ServerSampleInterval, opts.ServerHistoryRetention, opts.Logger)
+		servers.start()
+		// Stop the tracker before closing the redis client.
+		closers = append(closers, servers.stop)
+	}
+	timeline := newTaskTimeline(rc, readOnly, opts.Logger)
+	if opts.TaskStateSampleInterval > 0 {
+		states := newTaskStateSampler(i, timeline, opts.TaskStateSampleInterval, opts.Logger)
+		states.start()
+		// Stop the sampler before closing the redis client.
+		closers = append(closers, states.stop)
+	}
+	slos := &sloEvaluator{client: http.DefaultClient, prometheusAddr: opts.PrometheusAddress}
+	var sloQueues []string
+	retention := opts.QueueSampleRetention
+	if retention <= 0 {
+		retention = defaultQueueSampleRetention
+	}
+	for _, slo := range opts.SLOs {
+		if err := slo.normalize(); err != nil {
+			panic(fmt.Sprintf("asynqmon.New: invalid SLO for queue %q: %v", slo.Queue, err))
+		}
+		slos.slos = append(slos.slos, slo)
+		sloQueues = append(sloQueues, slo.Queue)
+		if slo.Window > retention {
+			retention = slo.Window
+		}
+	}
+	var webhooks *webhookDispatcher
+	if len(opts.Webhooks) > 0 {
+		var list []*Webhook
+		for _, wh := range opts.Webhooks {
+			wh := wh
+			if err := wh.validate(); err != nil {
+				panic(fmt.Sprintf("asynqmon.New: invalid webhook %q: %v", wh.URL, err))
+			}
+			list = append(list, &wh)
+		}
+		webhooks = newWebhookDispatcher(list, opts.RootPath, opts.Logger)
+		webhooks.start()
+	}
+	var queues *queueSampler
+	// Queues are sampled to detect the queue events of webhooks even if the stats are taken from Prometheus.
+	if (opts.PrometheusAddress == "" && (opts.QueueSampleInterval > 0 || len(slos.slos) > 0)) || webhooks != nil {
+		queues = newQueueSampler(i, sloQueues, opts.QueueSampleInterval, retention, opts.Logger)
+		if webhooks != nil {
+			queues.observers = append(queues.observers, webhooks.observeQueue)
+		}
+		queues.start()
+		// Stop the sampler before closing the redis client.
+		closers = append(closers, queues.stop)
+		slos.sampler = queues
+	}
+	if webhooks != nil {
+		// Stop the dispatcher after the sampler which publishes to it.
+		closers = append(closers, webhooks.stop)
+	}
+	closers = append(closers, i.Close) // closes rc as well
+	sentinel := newSentinelTopology(opts.RedisConnOpt)
+	if sentinel != nil {
+		if m != nil {
+			sentinel.addHook(redisMetricsHook{duration: m.redisCommandDuration})
+		}
+		sentinel.addHook(redisTracingHook{})
+		closers = append(closers, sentinel.close)
+	}
+
 	return &HTTPHandler{
-		router:   muxRouter(opts, rc, i),
-		closers:  []func() error{rc.Close, i.Close},
+		router:   muxRouter(opts, rc, i, sentinel, sampler, servers, queues, slos, webhooks, readOnly, timeline, m),
+		closers:  closers,
 		rootPath: opts.RootPath,
+		readOnly: readOnly,
 	}
 }
 
@@ -96,10 +292,48 @@ func (h *HTTPHandler) RootPath() string {
 	return h.rootPath
 }
 
+// SetReadOnly enables or disables read-only mode.
+// It is safe to call SetReadOnly while the handler is serving requests.
+func (h *HTTPHandler) SetReadOnly(readOnly bool) {
+	h.readOnly.set(readOnly)
+}
+
+// ReadOnly reports whether the handler is running in read-only mode.
+func (h *HTTPHandler) ReadOnly() bool {
+	return h.readOnly.enabled()
+}
+
+// sharedRedisConnOpt is a asynq.RedisConnOpt which returns an existing redis client.
+type sharedRedisConnOpt struct {
+	client redis.UniversalClient
+}
+
+func (opt sharedRedisConnOpt) MakeRedisClient() interface{} {
+	return opt.client
+}
+
+// readOnlyMode holds whether users are restricted to view-only mode.
+// The value may change while serving requests.
+type readOnlyMode struct {
+	v int32
+}
+
+func (m *readOnlyMode) enabled() bool {
+	return atomic.LoadInt32(&m.v) == 1
+}
+
+func (m *readOnlyMode) set(readOnly bool) {
+	var v int32
+	if readOnly {
+		v = 1
+	}
+	atomic.StoreInt32(&m.v, v)
+}
+
 //go:embed ui/build/*
 var staticContents embed.FS
 
-func muxRouter(opts Options, rc redis.UniversalClient, inspector *asynq.Inspector) *mux.Router {
+func muxRouter(opts Options, rc redis.UniversalClient, inspector *asynq.Inspector, sentinel *sentinelTopology, sampler *redisInfoSampler, servers *serverTracker, queues *queueSampler, slos *sloEvaluator, webhooks *webhookDispatcher, readOnly *readOnlyMode, timeline *taskTimeline, m *selfMetrics) *mux.Router {
 	router := mux.NewRouter().PathPrefix(opts.RootPath).Subrouter()
 
 	var payloadFmt PayloadFormatter = DefaultPayloadFormatter
@@ -112,85 +346,121 @@ func muxRouter(opts Options, rc redis.UniversalClient, inspector *asynq.Inspecto
 		resultFmt = opts.ResultFormatter
 	}
 
+	notes := newNoteStore(rc)
+	views := newViewStore(rc, opts.TrustIdentityHeaders)
+	guard := newConfirmationGuard(rc, opts.RequireConfirmation)
+
+	// Health check endpoints.
+	router.HandleFunc("/healthz", newHealthzHandlerFunc()).Methods("GET")
+	router.HandleFunc("/readyz", newReadyzHandlerFunc(rc)).Methods("GET")
+
 	api := router.PathPrefix("/api").Subrouter()
+	api.NotFoundHandler = http.HandlerFunc(notFoundAPIHandler)
+	api.MethodNotAllowedHandler = http.HandlerFunc(methodNotAllowedAPIHandler)
 
 	// Queue endpoints.
 	api.HandleFunc("/queues", newListQueuesHandlerFunc(inspector)).Methods("GET")
-	api.HandleFunc("/queues/{qname}", newGetQueueHandlerFunc(inspector)).Methods("GET")
-	api.HandleFunc("/queues/{qname}", newDeleteQueueHandlerFunc(inspector)).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}", newGetQueueHandlerFunc(inspector, notes)).Methods("GET")
+	api.HandleFunc("/queues/{qname}", guard.wrap(countQueueTasks(inspector), newDeleteQueueHandlerFunc(inspector))).Methods("DELETE")
 	api.HandleFunc("/queues/{qname}:pause", newPauseQueueHandlerFunc(inspector)).Methods("POST")
 	api.HandleFunc("/queues/{qname}:resume", newResumeQueueHandlerFunc(inspector)).Methods("POST")
 
+	// Queue coverage endpoint.
+	api.HandleFunc("/queue_coverage", newQueueCoverageHandlerFunc(inspector)).Methods("GET")
+
+	// Queue ranking endpoint.
+	api.HandleFunc("/queue_ranking", newQueueRankingHandlerFunc(inspector, http.DefaultClient, opts.PrometheusAddress, queues)).Methods("GET")
+
+	// SLO endpoint.
+	api.HandleFunc("/slos", newListSLOsHandlerFunc(slos)).Methods("GET")
+
 	// Queue Historical Stats endpoint.
 	api.HandleFunc("/queue_stats", newListQueueStatsHandlerFunc(inspector)).Methods("GET")
 
 	// Task endpoints.
 	api.HandleFunc("/queues/{qname}/active_tasks", newListActiveTasksHandlerFunc(inspector, payloadFmt)).Methods("GET")
-	api.HandleFunc("/queues/{qname}/active_tasks/{task_id}:cancel", newCancelActiveTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/active_tasks:cancel_all", newCancelAllActiveTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/active_tasks:batch_cancel", newBatchCancelActiveTasksHandlerFunc(inspector)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/active_tasks/{task_id}:cancel", newCancelActiveTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/active_tasks:cancel_all", newCancelAllActiveTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/active_tasks:batch_cancel", newBatchCancelActiveTasksHandlerFunc(inspector, timeline)).Methods("POST")
 
 	api.HandleFunc("/queues/{qname}/pending_tasks", newListPendingTasksHandlerFunc(inspector, payloadFmt)).Methods("GET")
-	api.HandleFunc("/queues/{qname}/pending_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/pending_tasks:delete_all", newDeleteAllPendingTasksHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/pending_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/pending_tasks/{task_id}:archive", newArchiveTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/pending_tasks:archive_all", newArchiveAllPendingTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/pending_tasks:batch_archive", newBatchArchiveTasksHandlerFunc(inspector)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/pending_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector, timeline)).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/pending_tasks:delete_all", guard.wrap(countTasksInState(inspector, asynq.TaskStatePending), newDeleteAllPendingTasksHandlerFunc(inspector, timeline))).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/pending_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/pending_tasks/{task_id}:archive", newArchiveTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/pending_tasks:archive_all", newArchiveAllPendingTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/pending_tasks:batch_archive", newBatchArchiveTasksHandlerFunc(inspector, timeline)).Methods("POST")
 
 	api.HandleFunc("/queues/{qname}/scheduled_tasks", newListScheduledTasksHandlerFunc(inspector, payloadFmt)).Methods("GET")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks:delete_all", newDeleteAllScheduledTasksHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks/{task_id}:run", newRunTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks:run_all", newRunAllScheduledTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks:batch_run", newBatchRunTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks/{task_id}:archive", newArchiveTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks:archive_all", newArchiveAllScheduledTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/scheduled_tasks:batch_archive", newBatchArchiveTasksHandlerFunc(inspector)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector, timeline)).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks:delete_all", guard.wrap(countTasksInState(inspector, asynq.TaskStateScheduled), newDeleteAllScheduledTasksHandlerFunc(inspector, timeline))).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks/{task_id}:run", newRunTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks:run_all", newRunAllScheduledTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks:batch_run", newBatchRunTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks/{task_id}:archive", newArchiveTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks:archive_all", newArchiveAllScheduledTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/scheduled_tasks:batch_archive", newBatchArchiveTasksHandlerFunc(inspector, timeline)).Methods("POST")
 
 	api.HandleFunc("/queues/{qname}/retry_tasks", newListRetryTasksHandlerFunc(inspector, payloadFmt)).Methods("GET")
-	api.HandleFunc("/queues/{qname}/retry_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/retry_tasks:delete_all", newDeleteAllRetryTasksHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/retry_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/retry_tasks/{task_id}:run", newRunTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/retry_tasks:run_all", newRunAllRetryTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/retry_tasks:batch_run", newBatchRunTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/retry_tasks/{task_id}:archive", newArchiveTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/retry_tasks:archive_all", newArchiveAllRetryTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/retry_tasks:batch_archive", newBatchArchiveTasksHandlerFunc(inspector)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/retry_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector, timeline)).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/retry_tasks:delete_all", guard.wrap(countTasksInState(inspector, asynq.TaskStateRetry), newDeleteAllRetryTasksHandlerFunc(inspector, timeline))).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/retry_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/retry_tasks/{task_id}:run", newRunTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/retry_tasks:run_all", newRunAllRetryTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/retry_tasks:batch_run", newBatchRunTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/retry_tasks/{task_id}:archive", newArchiveTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/retry_tasks:archive_all", newArchiveAllRetryTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/retry_tasks:batch_archive", newBatchArchiveTasksHandlerFunc(inspector, timeline)).Methods("POST")
 
 	api.HandleFunc("/queues/{qname}/archived_tasks", newListArchivedTasksHandlerFunc(inspector, payloadFmt)).Methods("GET")
-	api.HandleFunc("/queues/{qname}/archived_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/archived_tasks:delete_all", newDeleteAllArchivedTasksHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/archived_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/archived_tasks/{task_id}:run", newRunTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/archived_tasks:run_all", newRunAllArchivedTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/archived_tasks:batch_run", newBatchRunTasksHandlerFunc(inspector)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/archived_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector, timeline)).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/archived_tasks:delete_all", guard.wrap(countTasksInState(inspector, asynq.TaskStateArchived), newDeleteAllArchivedTasksHandlerFunc(inspector, timeline))).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/archived_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/archived_tasks/{task_id}:run", newRunTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/archived_tasks:run_all", newRunAllArchivedTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/archived_tasks:batch_run", newBatchRunTasksHandlerFunc(inspector, timeline)).Methods("POST")
 
 	api.HandleFunc("/queues/{qname}/completed_tasks", newListCompletedTasksHandlerFunc(inspector, payloadFmt, resultFmt)).Methods("GET")
-	api.HandleFunc("/queues/{qname}/completed_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/completed_tasks:delete_all", newDeleteAllCompletedTasksHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/completed_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/completed_task_stats", newCompletedTaskStatsHandlerFunc(inspector)).Methods("GET")
+	api.HandleFunc("/queues/{qname}/completed_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector, timeline)).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/completed_tasks:delete_all", guard.wrap(countTasksInState(inspector, asynq.TaskStateCompleted), newDeleteAllCompletedTasksHandlerFunc(inspector, timeline))).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/completed_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector, timeline)).Methods("POST")
 
 	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks", newListAggregatingTasksHandlerFunc(inspector, payloadFmt)).Methods("GET")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:delete_all", newDeleteAllAggregatingTasksHandlerFunc(inspector)).Methods("DELETE")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks/{task_id}:run", newRunTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:run_all", newRunAllAggregatingTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:batch_run", newBatchRunTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks/{task_id}:archive", newArchiveTaskHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:archive_all", newArchiveAllAggregatingTasksHandlerFunc(inspector)).Methods("POST")
-	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:batch_archive", newBatchArchiveTasksHandlerFunc(inspector)).Methods("POST")
-
-	api.HandleFunc("/queues/{qname}/tasks/{task_id}", newGetTaskHandlerFunc(inspector, payloadFmt, resultFmt)).Methods("GET")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks/{task_id}", newDeleteTaskHandlerFunc(inspector, timeline)).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:delete_all", guard.wrap(countGroupTasks(inspector), newDeleteAllAggregatingTasksHandlerFunc(inspector, timeline))).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:batch_delete", newBatchDeleteTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks/{task_id}:run", newRunTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:run_all", newRunAllAggregatingTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:batch_run", newBatchRunTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks/{task_id}:archive", newArchiveTaskHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:archive_all", newArchiveAllAggregatingTasksHandlerFunc(inspector, timeline)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/groups/{gname}/aggregating_tasks:batch_archive", newBatchArchiveTasksHandlerFunc(inspector, timeline)).Methods("POST")
+
+	api.HandleFunc("/queues/{qname}/tasks/{task_id}", newGetTaskHandlerFunc(inspector, payloadFmt, resultFmt, timeline, notes)).Methods("GET")
+
+	// Error cluster endpoints.
+	api.HandleFunc("/queues/{qname}/retry_tasks/error_clusters", newListErrorClustersHandlerFunc(inspector, asynq.TaskStateRetry, notes)).Methods("GET")
+	api.HandleFunc("/queues/{qname}/retry_tasks/error_clusters/{cluster_id}", guard.wrap(countErrorClusterTasks(inspector, asynq.TaskStateRetry), newDeleteErrorClusterHandlerFunc(inspector, asynq.TaskStateRetry, timeline, opts.Logger))).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/retry_tasks/error_clusters/{cluster_id}:run", newRunErrorClusterHandlerFunc(inspector, asynq.TaskStateRetry, timeline, opts.Logger)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/retry_tasks/error_clusters/{cluster_id}:archive", newArchiveErrorClusterHandlerFunc(inspector, asynq.TaskStateRetry, timeline, opts.Logger)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/archived_tasks/error_clusters", newListErrorClustersHandlerFunc(inspector, asynq.TaskStateArchived, notes)).Methods("GET")
+	api.HandleFunc("/queues/{qname}/archived_tasks/error_clusters/{cluster_id}", guard.wrap(countErrorClusterTasks(inspector, asynq.TaskStateArchived), newDeleteErrorClusterHandlerFunc(inspector, asynq.TaskStateArchived, timeline, opts.Logger))).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/archived_tasks/error_clusters/{cluster_id}:run", newRunErrorClusterHandlerFunc(inspector, asynq.TaskStateArchived, timeline, opts.Logger)).Methods("POST")
 
 	// Groups endponts
 	api.HandleFunc("/queues/{qname}/groups", newListGroupsHandlerFunc(inspector)).Methods("GET")
+	api.HandleFunc("/queues/{qname}/group_details", newListGroupDetailsHandlerFunc(inspector, rc)).Methods("GET")
+	api.HandleFunc("/queues/{qname}/groups:flush", newFlushGroupsHandlerFunc(inspector, rc, opts.Logger)).Methods("POST")
 
 	// Servers endpoints.
 	api.HandleFunc("/servers", newListServersHandlerFunc(inspector, payloadFmt)).Methods("GET")
+	api.HandleFunc("/server_history", newServerHistoryHandlerFunc(servers)).Methods("GET")
+
+	// Workers endpoints.
+	api.HandleFunc("/workers", newListWorkersHandlerFunc(inspector, payloadFmt)).Methods("GET")
+	api.HandleFunc("/workers:cancel_flagged", newCancelFlaggedWorkersHandlerFunc(inspector, payloadFmt, timeline, opts.Logger)).Methods("POST")
 
 	// Scheduler Entry endpoints.
 	api.HandleFunc("/scheduler_entries", newListSchedulerEntriesHandlerFunc(inspector, payloadFmt)).Methods("GET")
@@ -201,15 +471,65 @@ func muxRouter(opts Options, rc redis.UniversalClient, inspector *asynq.Inspecto
 	case *redis.ClusterClient:
 		api.HandleFunc("/redis_info", newRedisClusterInfoHandlerFunc(c, inspector)).Methods("GET")
 	case *redis.Client:
-		api.HandleFunc("/redis_info", newRedisInfoHandlerFunc(c)).Methods("GET")
+		api.HandleFunc("/redis_info", newRedisInfoHandlerFunc(c, sentinel)).Methods("GET")
 	}
 
+	// Redis info history endpoint.
+	api.HandleFunc("/redis_info_history", newRedisInfoHistoryHandlerFunc(sampler)).Methods("GET")
+
+	// Redis memory analysis endpoint.
+	api.HandleFunc("/redis_memory", newRedisMemoryHandlerFunc(rc, inspector)).Methods("GET")
+
+	// Redis diagnostics endpoints.
+	api.HandleFunc("/redis_slowlog", newRedisSlowLogHandlerFunc(rc)).Methods("GET")
+	api.HandleFunc("/redis_latency", newRedisLatencyHandlerFunc(rc)).Methods("GET")
+	api.HandleFunc("/redis_clients", newRedisClientsHandlerFunc(rc)).Methods("GET")
+	api.HandleFunc("/redis_commandstats", newRedisCommandStatsHandlerFunc(rc)).Methods("GET")
+
+	// Note and annotation endpoints.
+	api.HandleFunc("/queues/{qname}/notes", newListNotesHandlerFunc(notes, queueNoteTarget)).Methods("GET")
+	api.HandleFunc("/queues/{qname}/notes", newCreateNoteHandlerFunc(inspector, notes, queueNoteTarget)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/notes/{note_id}", newDeleteNoteHandlerFunc(notes, queueNoteTarget)).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}/tasks/{task_id}/notes", newListNotesHandlerFunc(notes, taskNoteTarget)).Methods("GET")
+	api.HandleFunc("/queues/{qname}/tasks/{task_id}/notes", newCreateNoteHandlerFunc(inspector, notes, taskNoteTarget)).Methods("POST")
+	api.HandleFunc("/queues/{qname}/tasks/{task_id}/notes/{note_id}", newDeleteNoteHandlerFunc(notes, taskNoteTarget)).Methods("DELETE")
+	api.HandleFunc("/error_clusters/{cluster_id}/notes", newListNotesHandlerFunc(notes, errorClusterNoteTarget)).Methods("GET")
+	api.HandleFunc("/error_clusters/{cluster_id}/notes", newCreateNoteHandlerFunc(inspector, notes, errorClusterNoteTarget)).Methods("POST")
+	api.HandleFunc("/error_clusters/{cluster_id}/notes/{note_id}", newDeleteNoteHandlerFunc(notes, errorClusterNoteTarget)).Methods("DELETE")
+	api.HandleFunc("/annotations", newListAnnotationsHandlerFunc(notes)).Methods("GET")
+	api.HandleFunc("/annotations", newCreateAnnotationHandlerFunc(notes)).Methods("POST")
+	api.HandleFunc("/annotations/{annotation_id}", newDeleteAnnotationHandlerFunc(notes)).Methods("DELETE")
+
+	// Saved view endpoints.
+	api.HandleFunc("/views", newListViewsHandlerFunc(views)).Methods("GET")
+	api.HandleFunc("/views", newCreateViewHandlerFunc(views)).Methods("POST")
+	api.HandleFunc("/views/{view_id}", newGetViewHandlerFunc(views)).Methods("GET")
+	api.HandleFunc("/views/{view_id}", newUpdateViewHandlerFunc(views)).Methods("PUT")
+	api.HandleFunc("/views/{view_id}", newDeleteViewHandlerFunc(views)).Methods("DELETE")
+
+	// Webhook endpoints.
+	api.HandleFunc("/webhooks", newListWebhooksHandlerFunc(webhooks)).Methods("GET")
+	api.HandleFunc("/webhook_deliveries", newListWebhookDeliveriesHandlerFunc(webhooks)).Methods("GET")
+
 	// Time series metrics endpoints.
 	api.HandleFunc("/metrics", newGetMetricsHandlerFunc(http.DefaultClient, opts.PrometheusAddress)).Methods("GET")
 
+	api.Use(requestIDMiddleware)
+	api.Use(newRequestTracer(opts.TracerProvider, opts.RootPath).middleware)
+	if opts.Logger != nil {
+		api.Use((&requestLogger{logger: opts.Logger, rootPath: opts.RootPath}).middleware)
+	}
+	// Record metrics about API requests, including the ones rejected in read-only mode.
+	if m != nil {
+		api.Use(m.instrumentAPI)
+	}
+
 	// Restrict APIs when running in read-only mode.
-	if opts.ReadOnly {
-		api.Use(restrictToReadOnly)
+	api.Use(restrictToReadOnly(readOnly))
+
+	// Publish the operator actions to webhooks.
+	if webhooks != nil {
+		api.Use(webhooks.middleware)
 	}
 
 	// Everything else, route to uiAssetsHandler.
@@ -219,19 +539,22 @@ func muxRouter(opts Options, rc redis.UniversalClient, inspector *asynq.Inspecto
 		staticDirPath:  "ui/build",
 		indexFileName:  "index.html",
 		prometheusAddr: opts.PrometheusAddress,
-		readOnly:       opts.ReadOnly,
+		readOnly:       readOnly,
 	}
 
 	return router
 }
 
-// restrictToReadOnly is a middleware function to restrict users to perform only GET requests.
-func restrictToReadOnly(h http.Handler) http.Handler {
-	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		if r.Method != "GET" && r.Method != "" {
-			http.Error(w, fmt.Sprintf("API Server is running in read-only mode: %s request is not allowed", r.Method), http.StatusMethodNotAllowed)
-			return
-		}
-		h.ServeHTTP(w, r)
-	})
+// restrictToReadOnly returns a middleware function to restrict users to perform only GET requests
+// while read-only mode is enabled.
+func restrictToReadOnly(readOnly *readOnlyMode) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if readOnly.enabled() && r.Method != "GET" && r.Method != "" {
+				writeError(w, r, http.StatusMethodNotAllowed, errCodeReadOnly, fmt.Sprintf("API Server is running in read-only mode: %s request is not allowed", r.Method))
+				return
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
 }
@@ -0,0 +1,43 @@
+package asynqmon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - http.Handler(s) for liveness and readiness probes
+// ****************************************************************************
+
+// Maximum time to wait for redis to respond to a readiness check.
+const readinessTimeout = 2 * time.Second
+
+type healthResponse struct {
+	Status string `json:"status"`
+}
+
+// newHealthzHandlerFunc returns a handler which reports that the process is up.
+// It does not check any dependencies.
+func newHealthzHandlerFunc() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeResponseJSON(w, healthResponse{Status: "ok"})
+	}
+}
+
+// newReadyzHandlerFunc returns a handler which reports whether redis is reachable.
+func newReadyzHandlerFunc(rc redis.UniversalClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		ctx, cancel := context.WithTimeout(r.Context(), readinessTimeout)
+		defer cancel()
+		if err := rc.Ping(ctx).Err(); err != nil {
+			writeError(w, r, http.StatusServiceUnavailable, errCodeUnavailable, fmt.Sprintf("cannot connect to redis: %v", err))
+			return
+		}
+		writeResponseJSON(w, healthResponse{Status: "ok"})
+	}
+}
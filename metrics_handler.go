@@ -63,7 +63,7 @@ func newGetMetricsHandlerFunc(client *http.Client, prometheusAddr string) http.H
 	return func(w http.ResponseWriter, r *http.Request) {
 		opts, err := extractMetricsFetchOptions(r)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("invalid query parameter: %v", err), http.StatusBadRequest)
+			writeBadRequest(w, r, "invalid query parameter: %v", err)
 			return
 		}
 		// List of queries (i.e. promQL) to send to prometheus server.
@@ -89,31 +89,31 @@ func newGetMetricsHandlerFunc(client *http.Client, prometheusAddr string) http.H
 				ch <- res{q, msg, err}
 			}(q)
 		}
-		for r := range ch {
+		for res := range ch {
 			n--
-			if r.err != nil {
-				http.Error(w, fmt.Sprintf("failed to fetch %q: %v", r.query, r.err), http.StatusInternalServerError)
+			if res.err != nil {
+				writeError(w, r, http.StatusInternalServerError, errCodeInternal, fmt.Sprintf("failed to fetch %q: %v", res.query, res.err))
 				return
 			}
-			switch r.query {
+			switch res.query {
 			case promQLQueueSize:
-				resp.QueueSize = r.msg
+				resp.QueueSize = res.msg
 			case promQLQueueLatency:
-				resp.QueueLatency = r.msg
+				resp.QueueLatency = res.msg
 			case promQLMemUsage:
-				resp.QueueMemUsgApprox = r.msg
+				resp.QueueMemUsgApprox = res.msg
 			case promQLProcessedTasks:
-				resp.ProcessedPerSecond = r.msg
+				resp.ProcessedPerSecond = res.msg
 			case promQLFailedTasks:
-				resp.FailedPerSecond = r.msg
+				resp.FailedPerSecond = res.msg
 			case promQLErrorRate:
-				resp.ErrorRate = r.msg
+				resp.ErrorRate = res.msg
 			case promQLPendingTasks:
-				resp.PendingTasksByQueue = r.msg
+				resp.PendingTasksByQueue = res.msg
 			case promQLRetryTasks:
-				resp.RetryTasksByQueue = r.msg
+				resp.RetryTasksByQueue = res.msg
 			case promQLArchivedTasks:
-				resp.ArchivedTasksByQueue = r.msg
+				resp.ArchivedTasksByQueue = res.msg
 			}
 			if n == 0 {
 				break // fetched all metrics
@@ -121,11 +121,11 @@ func newGetMetricsHandlerFunc(client *http.Client, prometheusAddr string) http.H
 		}
 		bytes, err := json.Marshal(resp)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("failed to marshal response into JSON: %v", err), http.StatusInternalServerError)
+			writeError(w, r, http.StatusInternalServerError, errCodeInternal, fmt.Sprintf("failed to marshal response into JSON: %v", err))
 			return
 		}
 		if _, err := w.Write(bytes); err != nil {
-			http.Error(w, fmt.Sprintf("failed to write to response: %v", err), http.StatusInternalServerError)
+			writeError(w, r, http.StatusInternalServerError, errCodeInternal, fmt.Sprintf("failed to write to response: %v", err))
 			return
 		}
 	}
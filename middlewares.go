@@ -0,0 +1,150 @@
+package asynqmon
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - middleware functions shared by the API endpoints (request ID and
+//     request logging)
+// ****************************************************************************
+
+// statusRecorder is a http.ResponseWriter which records the status code and the size of the response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	size   int
+}
+
+func (w *statusRecorder) WriteHeader(status int) {
+	w.status = status
+	w.ResponseWriter.WriteHeader(status)
+}
+
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	w.size += n
+	return n, err
+}
+
+// routeTemplate returns the path template of the route matched by r without the root path
+// (e.g. /api/queues/{qname}).
+func routeTemplate(r *http.Request, rootPath string) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return "unknown"
+	}
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimPrefix(tmpl, rootPath)
+}
+
+// Header used to pass the request ID from the client (or a proxy) and back to the client.
+const requestIDHeader = "X-Request-ID"
+
+// Maximum length of a request ID accepted from the client.
+const maxRequestIDLen = 128
+
+type requestIDKey struct{}
+
+// requestIDMiddleware is a middleware function to assign an ID to each request.
+// The ID given in the X-Request-ID header is used if present, so that requests can be correlated
+// with the logs of a proxy. The ID is returned in the X-Request-ID response header.
+func requestIDMiddleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(requestIDHeader)
+		if !validRequestID(id) {
+			id = newRequestID()
+		}
+		w.Header().Set(requestIDHeader, id)
+		h.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), requestIDKey{}, id)))
+	})
+}
+
+// requestIDFromContext returns the request ID in ctx, or an empty string if ctx has none.
+func requestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLen {
+		return false
+	}
+	for _, c := range id {
+		if c < 0x21 || c > 0x7e { // printable ASCII without space
+			return false
+		}
+	}
+	return true
+}
+
+func newRequestID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// requestLogger logs each API request.
+type requestLogger struct {
+	logger   *slog.Logger
+	rootPath string
+}
+
+// middleware is a middleware function to log API requests after they are handled.
+// Server errors are logged at error level and client errors at warn level.
+func (l *requestLogger) middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		level := slog.LevelInfo
+		switch {
+		case rec.status >= 500:
+			level = slog.LevelError
+		case rec.status >= 400:
+			level = slog.LevelWarn
+		}
+		l.logger.LogAttrs(r.Context(), level, "api request",
+			slog.String("request_id", requestIDFromContext(r.Context())),
+			slog.String("method", r.Method),
+			slog.String("route", routeTemplate(r, l.rootPath)),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rec.status),
+			slog.Int("size", rec.size),
+			slog.Duration("latency", time.Since(start)),
+			slog.String("user", requestUser(r)),
+			slog.String("remote_addr", r.RemoteAddr),
+		)
+	})
+}
+
+// requestUser returns the name of the user who made the request, if known.
+// The name is taken from the basic auth credentials, or from the headers set by
+// authenticating proxies (e.g. oauth2-proxy).
+//
+// The name is not verified, so it is only used to attribute requests (e.g. in logs),
+// unless Options.TrustIdentityHeaders is set.
+func requestUser(r *http.Request) string {
+	if user, _, ok := r.BasicAuth(); ok {
+		return user
+	}
+	for _, h := range []string{"X-Forwarded-User", "X-Auth-Request-User"} {
+		if user := r.Header.Get(h); user != "" {
+			return user
+		}
+	}
+	return ""
+}
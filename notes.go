@@ -0,0 +1,415 @@
+package asynqmon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - notes left by operators on tasks, queues and error clusters
+//   - time-ranged annotations overlaid on the metrics charts
+//   - http.Handler(s) for note and annotation related endpoints
+// ****************************************************************************
+
+const (
+	// Maximum length of the text of a note or an annotation.
+	maxNoteLength = 10000
+
+	// Time to keep the notes of a task after the last note was added.
+	// Archived tasks are kept for 90 days by asynq.
+	taskNotesTTL = 90 * 24 * time.Hour
+)
+
+// note is a comment left by an operator.
+type note struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// annotation marks a time range (e.g. an incident or a deploy) on the metrics charts.
+type annotation struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Text      string    `json:"text"`
+	StartTime time.Time `json:"start_time"`
+	// Equal to StartTime for an annotation of a point in time.
+	EndTime time.Time `json:"end_time"`
+	// Queues the annotation applies to. Empty for all queues.
+	Queues    []string  `json:"queues"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// noteStore stores notes and annotations in redis.
+//
+// Notes are stored in a hash per target, mapping the note ID to the note encoded in JSON.
+// Notes of tasks and queues are in the same hash slot as the queue so that they live
+// on the same node in cluster mode:
+//
+//	asynqmon:{<qname>}:notes:task:<task_id>
+//	asynqmon:{<qname>}:notes
+//	asynqmon:notes:error_cluster:<cluster_id>
+//
+// Annotations are stored in a single hash:
+//
+//	asynqmon:annotations
+type noteStore struct {
+	rc redis.UniversalClient
+}
+
+func newNoteStore(rc redis.UniversalClient) *noteStore {
+	return &noteStore{rc: rc}
+}
+
+func taskNotesKey(qname, taskID string) string {
+	return fmt.Sprintf("asynqmon:{%s}:notes:task:%s", qname, taskID)
+}
+
+func queueNotesKey(qname string) string {
+	return fmt.Sprintf("asynqmon:{%s}:notes", qname)
+}
+
+// Error clusters are identified by their error message only, so their notes are shared by all queues.
+func errorClusterNotesKey(clusterID string) string {
+	return fmt.Sprintf("asynqmon:notes:error_cluster:%s", clusterID)
+}
+
+const annotationsKey = "asynqmon:annotations"
+
+// add adds the note to the hash at key, and sets the expiration of the hash if ttl is positive.
+func (s *noteStore) add(ctx context.Context, key string, ttl time.Duration, n *note) error {
+	data, err := json.Marshal(n)
+	if err != nil {
+		return err
+	}
+	_, err = s.rc.TxPipelined(ctx, func(p redis.Pipeliner) error {
+		p.HSet(ctx, key, n.ID, data)
+		if ttl > 0 {
+			p.Expire(ctx, key, ttl)
+		}
+		return nil
+	})
+	return err
+}
+
+// remove removes the note or annotation with the ID from the hash at key.
+// It reports whether the note existed.
+func (s *noteStore) remove(ctx context.Context, key, id string) (bool, error) {
+	n, err := s.rc.HDel(ctx, key, id).Result()
+	return n > 0, err
+}
+
+// list returns the notes of each key, oldest first.
+func (s *noteStore) list(ctx context.Context, keys ...string) ([][]*note, error) {
+	if len(keys) == 0 {
+		return nil, nil
+	}
+	cmds := make([]*redis.MapStringStringCmd, len(keys))
+	_, err := s.rc.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for i, key := range keys {
+			cmds[i] = p.HGetAll(ctx, key)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	res := make([][]*note, len(keys))
+	for i, cmd := range cmds {
+		res[i] = make([]*note, 0, len(cmd.Val()))
+		for _, data := range cmd.Val() {
+			var n note
+			if err := json.Unmarshal([]byte(data), &n); err != nil {
+				return nil, fmt.Errorf("could not decode note %q: %v", data, err)
+			}
+			res[i] = append(res[i], &n)
+		}
+		sort.Slice(res[i], func(a, b int) bool { return res[i][a].CreatedAt.Before(res[i][b].CreatedAt) })
+	}
+	return res, nil
+}
+
+func (s *noteStore) addAnnotation(ctx context.Context, a *annotation) error {
+	data, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+	return s.rc.HSet(ctx, annotationsKey, a.ID, data).Err()
+}
+
+// annotations returns the annotations overlapping the time range which apply to one of the queues
+// (all annotations if qnames is empty), sorted by start time.
+func (s *noteStore) annotations(ctx context.Context, start, end time.Time, qnames []string) ([]*annotation, error) {
+	all, err := s.rc.HGetAll(ctx, annotationsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*annotation, 0)
+	for _, data := range all {
+		var a annotation
+		if err := json.Unmarshal([]byte(data), &a); err != nil {
+			return nil, fmt.Errorf("could not decode annotation %q: %v", data, err)
+		}
+		if a.EndTime.Before(start) || a.StartTime.After(end) || !annotationAppliesTo(&a, qnames) {
+			continue
+		}
+		res = append(res, &a)
+	}
+	sort.Slice(res, func(i, j int) bool { return res[i].StartTime.Before(res[j].StartTime) })
+	return res, nil
+}
+
+// annotationAppliesTo reports whether the annotation applies to one of the queues.
+func annotationAppliesTo(a *annotation, qnames []string) bool {
+	if len(a.Queues) == 0 || len(qnames) == 0 {
+		return true
+	}
+	for _, qname := range qnames {
+		if containsString(a.Queues, qname) {
+			return true
+		}
+	}
+	return false
+}
+
+// noteAuthor returns the author of a note created by the request: the authenticated user
+// if known, or the author given in the request body otherwise.
+func noteAuthor(r *http.Request, author string) string {
+	if user := requestUser(r); user != "" {
+		return user
+	}
+	return strings.TrimSpace(author)
+}
+
+// noteTarget is the object notes are attached to.
+type noteTarget struct {
+	// key returns the key of the hash of the notes from the route variables.
+	key func(vars map[string]string) string
+	// ttl is the expiration of the notes after the last note was added. Zero for no expiration.
+	ttl time.Duration
+	// check returns an error if the target doesn't exist. Nil to skip the check.
+	check func(r *http.Request, inspector *asynq.Inspector, vars map[string]string) error
+}
+
+var (
+	taskNoteTarget = &noteTarget{
+		key: func(vars map[string]string) string { return taskNotesKey(vars["qname"], vars["task_id"]) },
+		ttl: taskNotesTTL,
+		check: func(r *http.Request, inspector *asynq.Inspector, vars map[string]string) error {
+			span := startSpan(r.Context(), "asynq.Inspector/GetTaskInfo")
+			_, err := inspector.GetTaskInfo(vars["qname"], vars["task_id"])
+			endSpan(span, err)
+			return err
+		},
+	}
+	queueNoteTarget = &noteTarget{
+		key: func(vars map[string]string) string { return queueNotesKey(vars["qname"]) },
+		check: func(r *http.Request, inspector *asynq.Inspector, vars map[string]string) error {
+			span := startSpan(r.Context(), "asynq.Inspector/Queues")
+			qnames, err := inspector.Queues()
+			endSpan(span, err)
+			if err != nil {
+				return err
+			}
+			if !containsString(qnames, vars["qname"]) {
+				return fmt.Errorf("queue %q: %w", vars["qname"], asynq.ErrQueueNotFound)
+			}
+			return nil
+		},
+	}
+	errorClusterNoteTarget = &noteTarget{
+		key: func(vars map[string]string) string { return errorClusterNotesKey(vars["cluster_id"]) },
+	}
+)
+
+type listNotesResponse struct {
+	Notes []*note `json:"notes"`
+}
+
+func newListNotesHandlerFunc(ns *noteStore, target *noteTarget) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		notes, err := ns.list(r.Context(), target.key(mux.Vars(r)))
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeResponseJSON(w, listNotesResponse{Notes: notes[0]})
+	}
+}
+
+type createNoteRequest struct {
+	Text string `json:"text"`
+	// Author of the note, used if the user is not authenticated.
+	Author string `json:"author"`
+}
+
+func newCreateNoteHandlerFunc(inspector *asynq.Inspector, ns *noteStore, target *noteTarget) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		var req createNoteRequest
+		if err := dec.Decode(&req); err != nil {
+			writeBadRequest(w, r, "invalid request body: %v", err)
+			return
+		}
+		req.Text = strings.TrimSpace(req.Text)
+		if req.Text == "" {
+			writeBadRequest(w, r, "text cannot be empty")
+			return
+		}
+		if len(req.Text) > maxNoteLength {
+			writeBadRequest(w, r, "text cannot be longer than %d bytes", maxNoteLength)
+			return
+		}
+		vars := mux.Vars(r)
+		if target.check != nil {
+			if err := target.check(r, inspector, vars); err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+		}
+		n := &note{
+			ID:        newRequestID(),
+			Author:    noteAuthor(r, req.Author),
+			Text:      req.Text,
+			CreatedAt: time.Now().UTC(),
+		}
+		if err := ns.add(r.Context(), target.key(vars), target.ttl, n); err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeResponseJSON(w, n)
+	}
+}
+
+func newDeleteNoteHandlerFunc(ns *noteStore, target *noteTarget) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		vars := mux.Vars(r)
+		ok, err := ns.remove(r.Context(), target.key(vars), vars["note_id"])
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		if !ok {
+			writeError(w, r, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("note %q not found", vars["note_id"]))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type listAnnotationsResponse struct {
+	Annotations []*annotation `json:"annotations"`
+}
+
+// newListAnnotationsHandlerFunc returns the annotations overlapping the time range of the metrics charts.
+//
+// Optional query params:
+// `duration`: number of seconds of the time range (default 3600)
+// `endtime`:  end of the time range in Unix time seconds (default now)
+// `queues`:   comma separated list of queues the annotations apply to
+func newListAnnotationsHandlerFunc(ns *noteStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		opts, err := extractMetricsFetchOptions(r)
+		if err != nil {
+			writeBadRequest(w, r, "invalid query parameter: %v", err)
+			return
+		}
+		list, err := ns.annotations(r.Context(), opts.endTime.Add(-opts.duration), opts.endTime, opts.queues)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeResponseJSON(w, listAnnotationsResponse{Annotations: list})
+	}
+}
+
+type createAnnotationRequest struct {
+	Text   string `json:"text"`
+	Author string `json:"author"`
+	// Start and end of the time range. EndTime defaults to StartTime.
+	StartTime time.Time  `json:"start_time"`
+	EndTime   *time.Time `json:"end_time"`
+	Queues    []string   `json:"queues"`
+}
+
+func newCreateAnnotationHandlerFunc(ns *noteStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+		dec := json.NewDecoder(r.Body)
+		dec.DisallowUnknownFields()
+		var req createAnnotationRequest
+		if err := dec.Decode(&req); err != nil {
+			writeBadRequest(w, r, "invalid request body: %v", err)
+			return
+		}
+		req.Text = strings.TrimSpace(req.Text)
+		if req.Text == "" {
+			writeBadRequest(w, r, "text cannot be empty")
+			return
+		}
+		if len(req.Text) > maxNoteLength {
+			writeBadRequest(w, r, "text cannot be longer than %d bytes", maxNoteLength)
+			return
+		}
+		if req.StartTime.IsZero() {
+			writeBadRequest(w, r, "start_time is required")
+			return
+		}
+		end := req.StartTime
+		if req.EndTime != nil {
+			end = *req.EndTime
+		}
+		if end.Before(req.StartTime) {
+			writeBadRequest(w, r, "end_time cannot be before start_time")
+			return
+		}
+		a := &annotation{
+			ID:        newRequestID(),
+			Author:    noteAuthor(r, req.Author),
+			Text:      req.Text,
+			StartTime: req.StartTime.UTC(),
+			EndTime:   end.UTC(),
+			Queues:    make([]string, 0, len(req.Queues)),
+			CreatedAt: time.Now().UTC(),
+		}
+		for _, qname := range req.Queues {
+			if qname = strings.TrimSpace(qname); qname != "" && !containsString(a.Queues, qname) {
+				a.Queues = append(a.Queues, qname)
+			}
+		}
+		if err := ns.addAnnotation(r.Context(), a); err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeResponseJSON(w, a)
+	}
+}
+
+func newDeleteAnnotationHandlerFunc(ns *noteStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["annotation_id"]
+		ok, err := ns.remove(r.Context(), annotationsKey, id)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		if !ok {
+			writeError(w, r, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("annotation %q not found", id))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,75 @@
+package asynqmon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAnnotationAppliesTo(t *testing.T) {
+	tests := []struct {
+		queues []string
+		qnames []string
+		want   bool
+	}{
+		{nil, []string{"critical"}, true},
+		{[]string{"critical"}, nil, true},
+		{[]string{"critical"}, []string{"default", "critical"}, true},
+		{[]string{"critical"}, []string{"default"}, false},
+	}
+	for _, tc := range tests {
+		if got := annotationAppliesTo(&annotation{Queues: tc.queues}, tc.qnames); got != tc.want {
+			t.Errorf("annotationAppliesTo(%v, %v) = %t, want %t", tc.queues, tc.qnames, got, tc.want)
+		}
+	}
+}
+
+func TestNoteAuthor(t *testing.T) {
+	r := httptest.NewRequest("POST", "/api/queues/default/notes", nil)
+	if got := noteAuthor(r, " bob "); got != "bob" {
+		t.Errorf("noteAuthor without authenticated user = %q, want %q", got, "bob")
+	}
+	r.Header.Set("X-Forwarded-User", "alice")
+	if got := noteAuthor(r, "bob"); got != "alice" {
+		t.Errorf("noteAuthor with authenticated user = %q, want %q", got, "alice")
+	}
+}
+
+func TestCreateAnnotationValidation(t *testing.T) {
+	// The request is rejected before reaching redis.
+	h := newCreateAnnotationHandlerFunc(newNoteStore(nil))
+	for _, body := range []string{
+		`{"text":"","start_time":"2023-03-01T10:00:00Z"}`,
+		`{"text":"deploy"}`,
+		`{"text":"incident","start_time":"2023-03-01T10:00:00Z","end_time":"2023-03-01T09:00:00Z"}`,
+		`{"text":"` + strings.Repeat("x", maxNoteLength+1) + `","start_time":"2023-03-01T10:00:00Z"}`,
+		`not json`,
+		`{"text":"deploy","start_time":"2023-03-01T10:00:00Z","queue":"default"}`,
+		`{"text":"` + strings.Repeat("x", maxRequestBodySize) + `","start_time":"2023-03-01T10:00:00Z"}`,
+	} {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest("POST", "/api/annotations", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("POST /api/annotations with %.50q returned status %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
+
+func TestCreateNoteValidation(t *testing.T) {
+	// The request is rejected before reaching redis.
+	h := newCreateNoteHandlerFunc(nil, newNoteStore(nil), &noteTarget{})
+	for _, body := range []string{
+		`{"text":""}`,
+		`{"text":"` + strings.Repeat("x", maxNoteLength+1) + `"}`,
+		`{"text":"retried after the fix","queue":"default"}`,
+		`{"text":"` + strings.Repeat("x", maxRequestBodySize) + `"}`,
+		`not json`,
+	} {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest("POST", "/api/queues/default/notes", strings.NewReader(body)))
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("POST /api/queues/default/notes with %.50q returned status %d, want %d", body, w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+package asynqmon
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - coverage of the queues by the servers consuming them
+//   - http.Handler(s) for queue coverage endpoint
+// ****************************************************************************
+
+// queueCoverage describes the servers consuming a queue and the worker capacity the queue gets.
+type queueCoverage struct {
+	Queue   string `json:"queue"`
+	Paused  bool   `json:"paused"`
+	Pending int    `json:"pending"`
+	// IDs of the servers listening on the queue.
+	ServerIDs []string `json:"server_ids"`
+	// Total concurrency of the servers listening on the queue.
+	Concurrency int `json:"concurrency"`
+	// WeightedConcurrency is the number of workers the queue is expected to get.
+	// For servers without strict priority, it is the concurrency of the server weighted by the
+	// priority of the queue over the sum of its priorities. For servers with strict priority,
+	// the queues of the highest priority with pending tasks share all the workers.
+	WeightedConcurrency float64 `json:"weighted_concurrency"`
+	// Share is the weighted concurrency of the queue over the total concurrency of the servers.
+	Share float64 `json:"share"`
+	// Number of servers listening on the queue with strict priority.
+	StrictPriorityServers int `json:"strict_priority_servers"`
+	// Orphaned indicates no server is listening on the queue.
+	Orphaned bool `json:"orphaned"`
+}
+
+type queueCoverageWarning struct {
+	Queue   string `json:"queue"`
+	Message string `json:"message"`
+}
+
+type queueCoverageResponse struct {
+	Queues []*queueCoverage `json:"queues"`
+	// Total concurrency of all servers.
+	TotalConcurrency int `json:"total_concurrency"`
+	// Names of the queues no server is listening on.
+	OrphanedQueues []string `json:"orphaned_queues"`
+	// Names of the queues servers listen on which do not exist in redis.
+	UnknownQueues []string                `json:"unknown_queues"`
+	Warnings      []*queueCoverageWarning `json:"warnings"`
+}
+
+// computeQueueCoverage cross-references the queues with the queue priorities of the servers.
+func computeQueueCoverage(queues []*asynq.QueueInfo, servers []*asynq.ServerInfo) *queueCoverageResponse {
+	resp := &queueCoverageResponse{
+		Queues:         make([]*queueCoverage, 0, len(queues)),
+		OrphanedQueues: make([]string, 0),
+		UnknownQueues:  make([]string, 0),
+		Warnings:       make([]*queueCoverageWarning, 0),
+	}
+	byName := make(map[string]*queueCoverage)
+	pending := make(map[string]int)
+	for _, q := range queues {
+		c := &queueCoverage{Queue: q.Queue, Paused: q.Paused, Pending: q.Pending, ServerIDs: make([]string, 0)}
+		byName[q.Queue] = c
+		if !q.Paused {
+			// Tasks in paused queues are not processed, so they do not hold up lower priority queues.
+			pending[q.Queue] = q.Pending
+		}
+		resp.Queues = append(resp.Queues, c)
+	}
+	unknown := make(map[string]bool)
+	for _, srv := range servers {
+		resp.TotalConcurrency += srv.Concurrency
+		for qname, weight := range serverQueueWeights(srv, pending) {
+			c, ok := byName[qname]
+			if !ok {
+				unknown[qname] = true
+				continue
+			}
+			c.ServerIDs = append(c.ServerIDs, srv.ID)
+			c.Concurrency += srv.Concurrency
+			c.WeightedConcurrency += float64(srv.Concurrency) * weight
+			if srv.StrictPriority {
+				c.StrictPriorityServers++
+			}
+		}
+	}
+	for _, c := range resp.Queues {
+		sort.Strings(c.ServerIDs)
+		if resp.TotalConcurrency > 0 {
+			c.Share = c.WeightedConcurrency / float64(resp.TotalConcurrency)
+		}
+		if len(c.ServerIDs) == 0 {
+			c.Orphaned = true
+			resp.OrphanedQueues = append(resp.OrphanedQueues, c.Queue)
+		}
+		switch {
+		case c.Orphaned && c.Pending > 0:
+			resp.Warnings = append(resp.Warnings, &queueCoverageWarning{
+				Queue:   c.Queue,
+				Message: fmt.Sprintf("Queue %q has %d pending tasks but no server is listening on it", c.Queue, c.Pending),
+			})
+		case !c.Orphaned && !c.Paused && c.Pending > 0 && c.WeightedConcurrency == 0:
+			resp.Warnings = append(resp.Warnings, &queueCoverageWarning{
+				Queue:   c.Queue,
+				Message: fmt.Sprintf("Queue %q has %d pending tasks but gets no workers because higher priority queues have pending tasks", c.Queue, c.Pending),
+			})
+		}
+	}
+	for qname := range unknown {
+		resp.UnknownQueues = append(resp.UnknownQueues, qname)
+	}
+	sort.Strings(resp.UnknownQueues)
+	return resp
+}
+
+// serverQueueWeights returns the fraction of the workers of the server each of its queues is
+// expected to get, given the number of pending tasks in each queue.
+func serverQueueWeights(srv *asynq.ServerInfo, pending map[string]int) map[string]float64 {
+	weights := make(map[string]float64, len(srv.Queues))
+	if !srv.StrictPriority {
+		var sum int
+		for _, p := range srv.Queues {
+			sum += p
+		}
+		for qname, p := range srv.Queues {
+			weights[qname] = 0
+			if sum > 0 {
+				weights[qname] = float64(p) / float64(sum)
+			}
+		}
+		return weights
+	}
+	// With strict priority, tasks in lower priority queues are processed only if
+	// the higher priority queues are empty.
+	top := -1
+	for qname, p := range srv.Queues {
+		weights[qname] = 0
+		if pending[qname] > 0 && p > top {
+			top = p
+		}
+	}
+	if top < 0 {
+		// No pending tasks: the queues with the highest priority get the workers first.
+		for _, p := range srv.Queues {
+			if p > top {
+				top = p
+			}
+		}
+	}
+	var n int
+	for _, p := range srv.Queues {
+		if p == top {
+			n++
+		}
+	}
+	for qname, p := range srv.Queues {
+		if p == top {
+			weights[qname] = 1 / float64(n)
+		}
+	}
+	return weights
+}
+
+func newQueueCoverageHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		span := startSpan(r.Context(), "asynq.Inspector/Queues")
+		qnames, err := inspector.Queues()
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		queues := make([]*asynq.QueueInfo, len(qnames))
+		for i, qname := range qnames {
+			span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
+			qinfo, err := inspector.GetQueueInfo(qname)
+			endSpan(span, err)
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			queues[i] = qinfo
+		}
+		span = startSpan(r.Context(), "asynq.Inspector/Servers")
+		servers, err := inspector.Servers()
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeResponseJSON(w, computeQueueCoverage(queues, servers))
+	}
+}
@@ -0,0 +1,65 @@
+package asynqmon
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hibiken/asynq"
+)
+
+func TestComputeQueueCoverage(t *testing.T) {
+	queues := []*asynq.QueueInfo{
+		{Queue: "critical", Pending: 0},
+		{Queue: "default", Pending: 5},
+		{Queue: "low", Pending: 3},
+		{Queue: "orphan", Pending: 2},
+		{Queue: "idle", Pending: 0},
+	}
+	servers := []*asynq.ServerInfo{
+		{ID: "s1", Concurrency: 10, Queues: map[string]int{"critical": 6, "default": 3, "low": 1}},
+		{ID: "s2", Concurrency: 10, Queues: map[string]int{"critical": 3, "default": 2, "low": 1, "removed": 1}, StrictPriority: true},
+	}
+	got := computeQueueCoverage(queues, servers)
+
+	want := &queueCoverageResponse{
+		Queues: []*queueCoverage{
+			{Queue: "critical", ServerIDs: []string{"s1", "s2"}, Concurrency: 20, WeightedConcurrency: 6, Share: 0.3, StrictPriorityServers: 1},
+			{Queue: "default", Pending: 5, ServerIDs: []string{"s1", "s2"}, Concurrency: 20, WeightedConcurrency: 13, Share: 0.65, StrictPriorityServers: 1},
+			{Queue: "low", Pending: 3, ServerIDs: []string{"s1", "s2"}, Concurrency: 20, WeightedConcurrency: 1, Share: 0.05, StrictPriorityServers: 1},
+			{Queue: "orphan", Pending: 2, ServerIDs: []string{}, Orphaned: true},
+			{Queue: "idle", ServerIDs: []string{}, Orphaned: true},
+		},
+		TotalConcurrency: 20,
+		OrphanedQueues:   []string{"orphan", "idle"},
+		UnknownQueues:    []string{"removed"},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(queueCoverageResponse{}, "Warnings"), cmpopts.EquateApprox(0, 1e-9)); diff != "" {
+		t.Errorf("computeQueueCoverage mismatch (-want,+got)\n%s", diff)
+	}
+	var warned []string
+	for _, w := range got.Warnings {
+		warned = append(warned, w.Queue)
+	}
+	if diff := cmp.Diff([]string{"orphan"}, warned); diff != "" {
+		t.Errorf("warnings mismatch (-want,+got)\n%s", diff)
+	}
+}
+
+func TestServerQueueWeightsStrictPriority(t *testing.T) {
+	srv := &asynq.ServerInfo{Queues: map[string]int{"a": 3, "b": 2, "c": 2, "d": 1}, StrictPriority: true}
+	tests := []struct {
+		pending map[string]int
+		want    map[string]float64
+	}{
+		{map[string]int{"a": 1, "b": 1}, map[string]float64{"a": 1, "b": 0, "c": 0, "d": 0}},
+		{map[string]int{"b": 1, "d": 1}, map[string]float64{"a": 0, "b": 0.5, "c": 0.5, "d": 0}},
+		{map[string]int{"d": 1}, map[string]float64{"a": 0, "b": 0, "c": 0, "d": 1}},
+		{map[string]int{}, map[string]float64{"a": 1, "b": 0, "c": 0, "d": 0}},
+	}
+	for _, tc := range tests {
+		if diff := cmp.Diff(tc.want, serverQueueWeights(srv, tc.pending)); diff != "" {
+			t.Errorf("serverQueueWeights(%v) mismatch (-want,+got)\n%s", tc.pending, diff)
+		}
+	}
+}
@@ -2,7 +2,6 @@ package asynqmon
 
 import (
 	"encoding/json"
-	"errors"
 	"net/http"
 
 	"github.com/gorilla/mux"
@@ -17,16 +16,20 @@ import (
 
 func newListQueuesHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		span := startSpan(r.Context(), "asynq.Inspector/Queues")
 		qnames, err := inspector.Queues()
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		snapshots := make([]*queueStateSnapshot, len(qnames))
 		for i, qname := range qnames {
+			span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 			qinfo, err := inspector.GetQueueInfo(qname)
+			endSpan(span, err)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeErrorResponse(w, r, err)
 				return
 			}
 			snapshots[i] = toQueueStateSnapshot(qinfo)
@@ -36,31 +39,46 @@ func newListQueuesHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 	}
 }
 
-func newGetQueueHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newGetQueueHandlerFunc(inspector *asynq.Inspector, ns *noteStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname := vars["qname"]
+		params, err := parseHistoryParams(r, defaultQueueHistoryDays)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
 
 		payload := make(map[string]interface{})
+		span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			// TODO: Check for queue not found error.
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		payload["current"] = toQueueStateSnapshot(qinfo)
 
-		// TODO: make this n a variable
-		data, err := inspector.History(qname, 10)
+		span = startSpan(r.Context(), "asynq.Inspector/History")
+		data, err := inspector.History(qname, params.days)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
+			return
+		}
+		history := aggregateDailyStats(data, params.groupBy)
+		if params.format != "" {
+			writeDailyStatsExport(w, params, qname+"-stats", map[string][]*dailyStats{qname: history})
 			return
 		}
-		var dailyStats []*dailyStats
-		for _, s := range data {
-			dailyStats = append(dailyStats, toDailyStats(s))
+		payload["history"] = history
+
+		notes, err := ns.list(r.Context(), queueNotesKey(qname))
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
 		}
-		payload["history"] = dailyStats
+		payload["notes"] = notes[0]
 		json.NewEncoder(w).Encode(payload)
 	}
 }
@@ -69,16 +87,11 @@ func newDeleteQueueHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname := vars["qname"]
-		if err := inspector.DeleteQueue(qname, false); err != nil {
-			if errors.Is(err, asynq.ErrQueueNotFound) {
-				http.Error(w, err.Error(), http.StatusNotFound)
-				return
-			}
-			if errors.Is(err, asynq.ErrQueueNotEmpty) {
-				http.Error(w, err.Error(), http.StatusBadRequest)
-				return
-			}
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		span := startSpan(r.Context(), "asynq.Inspector/DeleteQueue")
+		err := inspector.DeleteQueue(qname, false)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
 			return
 		}
 		w.WriteHeader(http.StatusNoContent)
@@ -89,8 +102,11 @@ func newPauseQueueHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname := vars["qname"]
-		if err := inspector.PauseQueue(qname); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		span := startSpan(r.Context(), "asynq.Inspector/PauseQueue")
+		err := inspector.PauseQueue(qname)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
 			return
 		}
 		w.WriteHeader(http.StatusNoContent)
@@ -101,8 +117,11 @@ func newResumeQueueHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname := vars["qname"]
-		if err := inspector.UnpauseQueue(qname); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		span := startSpan(r.Context(), "asynq.Inspector/UnpauseQueue")
+		err := inspector.UnpauseQueue(qname)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
 			return
 		}
 		w.WriteHeader(http.StatusNoContent)
@@ -115,23 +134,35 @@ type listQueueStatsResponse struct {
 
 func newListQueueStatsHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		params, err := parseHistoryParams(r, defaultQueueStatsDays)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		span := startSpan(r.Context(), "asynq.Inspector/Queues")
 		qnames, err := inspector.Queues()
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		resp := listQueueStatsResponse{Stats: make(map[string][]*dailyStats)}
-		const numdays = 90 // Get stats for the last 90 days.
 		for _, qname := range qnames {
-			stats, err := inspector.History(qname, numdays)
+			span := startSpan(r.Context(), "asynq.Inspector/History")
+			stats, err := inspector.History(qname, params.days)
+			endSpan(span, err)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeErrorResponse(w, r, err)
 				return
 			}
-			resp.Stats[qname] = toDailyStatsList(stats)
+			resp.Stats[qname] = aggregateDailyStats(stats, params.groupBy)
+		}
+		if params.format != "" {
+			writeDailyStatsExport(w, params, "queue-stats", resp.Stats)
+			return
 		}
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 	}
@@ -0,0 +1,157 @@
+package asynqmon
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - query parameters selecting the range and aggregation of daily stats
+//   - aggregation of daily stats by week and month
+//   - CSV and JSON export of daily stats
+// ****************************************************************************
+
+const (
+	// asynq keeps the daily stats of a queue for 90 days.
+	maxHistoryDays = 90
+
+	defaultQueueHistoryDays = 10
+	defaultQueueStatsDays   = 90
+)
+
+// Values of the group_by query parameter.
+const (
+	groupByDay   = "day"
+	groupByWeek  = "week"
+	groupByMonth = "month"
+)
+
+// Values of the format query parameter.
+const (
+	exportFormatCSV  = "csv"
+	exportFormatJSON = "json"
+)
+
+// historyParams are the query parameters of the endpoints returning daily stats.
+type historyParams struct {
+	// Number of days, counting today.
+	days int
+	// Period to aggregate the stats by: day, week or month.
+	groupBy string
+	// Export format: csv, json or empty for the regular response.
+	format string
+}
+
+func parseHistoryParams(r *http.Request, defaultDays int) (*historyParams, error) {
+	days, err := intQueryParam(r, "days", defaultDays, 1, maxHistoryDays)
+	if err != nil {
+		return nil, err
+	}
+	p := &historyParams{days: days, groupBy: groupByDay}
+	if s := r.URL.Query().Get("group_by"); s != "" {
+		if s != groupByDay && s != groupByWeek && s != groupByMonth {
+			return nil, fmt.Errorf("invalid value %q for group_by: must be one of day, week or month", s)
+		}
+		p.groupBy = s
+	}
+	if s := r.URL.Query().Get("format"); s != "" {
+		if s != exportFormatCSV && s != exportFormatJSON {
+			return nil, fmt.Errorf("invalid value %q for format: must be csv or json", s)
+		}
+		p.format = s
+	}
+	return p, nil
+}
+
+// periodStart returns the first day of the period containing t.
+// Weeks start on Monday.
+func periodStart(t time.Time, groupBy string) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	switch groupBy {
+	case groupByWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case groupByMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// aggregateDailyStats sums up the daily stats of a queue by period.
+// The order of the periods follows the order of the daily stats (most recent first, as returned by asynq).
+// Days is set to the number of days included in each period, which is less than the length of the period
+// at both ends of the range.
+func aggregateDailyStats(stats []*asynq.DailyStats, groupBy string) []*dailyStats {
+	if groupBy == groupByDay || groupBy == "" {
+		return toDailyStatsList(stats)
+	}
+	out := make([]*dailyStats, 0)
+	periods := make(map[string]*dailyStats)
+	for _, s := range stats {
+		date := periodStart(s.Date, groupBy).Format("2006-01-02")
+		p, ok := periods[date]
+		if !ok {
+			p = &dailyStats{Queue: s.Queue, Date: date}
+			periods[date] = p
+			out = append(out, p)
+		}
+		p.Processed += s.Processed
+		p.Failed += s.Failed
+		p.Succeeded += s.Processed - s.Failed
+		p.Days++
+	}
+	return out
+}
+
+// writeDailyStatsExport writes the daily stats of the queues as a file attachment in the given format.
+// Queues are sorted by name.
+func writeDailyStatsExport(w http.ResponseWriter, p *historyParams, name string, stats map[string][]*dailyStats) {
+	qnames := make([]string, 0, len(stats))
+	for qname := range stats {
+		qnames = append(qnames, qname)
+	}
+	sort.Strings(qnames)
+	rows := make([]*dailyStats, 0)
+	for _, qname := range qnames {
+		for _, s := range stats[qname] {
+			row := *s
+			if row.Days == 0 {
+				row.Days = 1
+			}
+			rows = append(rows, &row)
+		}
+	}
+
+	filename := fmt.Sprintf("%s-%s-%dd-%s.%s", name, p.groupBy, p.days, time.Now().UTC().Format("20060102"), p.format)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+	switch p.format {
+	case exportFormatCSV:
+		w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+		cw := csv.NewWriter(w)
+		cw.Write([]string{"queue", "date", "days", "processed", "succeeded", "failed"})
+		for _, s := range rows {
+			cw.Write([]string{
+				s.Queue,
+				s.Date,
+				strconv.Itoa(s.Days),
+				strconv.Itoa(s.Processed),
+				strconv.Itoa(s.Succeeded),
+				strconv.Itoa(s.Failed),
+			})
+		}
+		cw.Flush()
+	default:
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		json.NewEncoder(w).Encode(rows)
+	}
+}
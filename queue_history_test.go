@@ -0,0 +1,97 @@
+package asynqmon
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hibiken/asynq"
+)
+
+func TestAggregateDailyStats(t *testing.T) {
+	// Wednesday, March 1 back to Saturday, February 25, most recent first.
+	var stats []*asynq.DailyStats
+	for i := 0; i < 5; i++ {
+		stats = append(stats, &asynq.DailyStats{
+			Queue:     "default",
+			Processed: 10,
+			Failed:    i,
+			Date:      time.Date(2023, 3, 1, 12, 0, 0, 0, time.UTC).AddDate(0, 0, -i),
+		})
+	}
+
+	tests := []struct {
+		groupBy string
+		want    []*dailyStats
+	}{
+		{
+			groupBy: groupByWeek,
+			want: []*dailyStats{
+				{Queue: "default", Date: "2023-02-27", Days: 3, Processed: 30, Succeeded: 27, Failed: 3},
+				{Queue: "default", Date: "2023-02-20", Days: 2, Processed: 20, Succeeded: 13, Failed: 7},
+			},
+		},
+		{
+			groupBy: groupByMonth,
+			want: []*dailyStats{
+				{Queue: "default", Date: "2023-03-01", Days: 1, Processed: 10, Succeeded: 10, Failed: 0},
+				{Queue: "default", Date: "2023-02-01", Days: 4, Processed: 40, Succeeded: 30, Failed: 10},
+			},
+		},
+	}
+	for _, tc := range tests {
+		got := aggregateDailyStats(stats, tc.groupBy)
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("aggregateDailyStats(%q) mismatch (-want,+got)\n%s", tc.groupBy, diff)
+		}
+	}
+
+	if got := aggregateDailyStats(stats, groupByDay); len(got) != 5 || got[0].Date != "2023-03-01" || got[0].Days != 0 {
+		t.Errorf("aggregateDailyStats(%q) = %+v, want the daily stats", groupByDay, got)
+	}
+}
+
+func TestParseHistoryParams(t *testing.T) {
+	tests := []struct {
+		query   string
+		want    historyParams
+		wantErr bool
+	}{
+		{"", historyParams{days: 10, groupBy: groupByDay}, false},
+		{"days=30&group_by=week&format=csv", historyParams{days: 30, groupBy: groupByWeek, format: exportFormatCSV}, false},
+		{"days=91", historyParams{}, true},
+		{"group_by=year", historyParams{}, true},
+		{"format=xml", historyParams{}, true},
+	}
+	for _, tc := range tests {
+		got, err := parseHistoryParams(httptest.NewRequest("GET", "/api/queue_stats?"+tc.query, nil), 10)
+		if (err != nil) != tc.wantErr {
+			t.Errorf("parseHistoryParams(%q) returned error %v, want error %t", tc.query, err, tc.wantErr)
+			continue
+		}
+		if err == nil && *got != tc.want {
+			t.Errorf("parseHistoryParams(%q) = %+v, want %+v", tc.query, *got, tc.want)
+		}
+	}
+}
+
+func TestWriteDailyStatsExportCSV(t *testing.T) {
+	stats := map[string][]*dailyStats{
+		"low":     {{Queue: "low", Date: "2023-03-01", Processed: 5, Succeeded: 5}},
+		"default": {{Queue: "default", Date: "2023-02-27", Days: 3, Processed: 30, Succeeded: 27, Failed: 3}},
+	}
+	w := httptest.NewRecorder()
+	writeDailyStatsExport(w, &historyParams{days: 7, groupBy: groupByWeek, format: exportFormatCSV}, "queue-stats", stats)
+
+	want := "queue,date,days,processed,succeeded,failed\n" +
+		"default,2023-02-27,3,30,27,3\n" +
+		"low,2023-03-01,1,5,5,0\n"
+	if diff := cmp.Diff(want, w.Body.String()); diff != "" {
+		t.Errorf("CSV mismatch (-want,+got)\n%s", diff)
+	}
+	if cd := w.Header().Get("Content-Disposition"); !strings.HasPrefix(cd, "attachment; filename=queue-stats-week-7d-") {
+		t.Errorf("Content-Disposition = %q", cd)
+	}
+}
@@ -0,0 +1,316 @@
+package asynqmon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - ranking of queues by growth, latency, failure ratio or memory usage
+//   - http.Handler(s) for queue ranking endpoint
+// ****************************************************************************
+
+// Values of the sort_by query parameter.
+const (
+	rankByGrowth      = "growth"
+	rankByLatency     = "latency"
+	rankByFailureRate = "failure_rate"
+	rankByMemory      = "memory"
+)
+
+// Maximum number of points in the trend of a queue.
+const rankingTrendPoints = 30
+
+// queueRanking is the change of the stats of a queue over the ranking period.
+type queueRanking struct {
+	Queue string `json:"queue"`
+	// Current values and their change over the period.
+	Size                int     `json:"size"`
+	SizeDelta           int     `json:"size_delta"`
+	LatencySeconds      float64 `json:"latency_seconds"`
+	LatencyDeltaSeconds float64 `json:"latency_delta_seconds"`
+	MemoryUsage         int64   `json:"memory_usage_bytes"`
+	MemoryDelta         int64   `json:"memory_delta_bytes"`
+	// Number of tasks processed and failed over the period.
+	Processed int `json:"processed"`
+	Failed    int `json:"failed"`
+	// Ratio of failed tasks over the period. Null if no task was processed.
+	FailureRate *float64 `json:"failure_rate"`
+	// Time span covered by the data. It can be shorter than the period
+	// when the data doesn't go back far enough.
+	CoverageSeconds float64 `json:"coverage_seconds"`
+	// Values of the ranking metric over the period: size for growth,
+	// latency in seconds, failure ratio between points, or memory usage in bytes.
+	Trend []*rankingTrendPoint `json:"trend"`
+}
+
+type rankingTrendPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// rankQueue computes the ranking stats of a queue from its samples in chronological order,
+// starting from the last sample at or before since.
+func rankQueue(qname string, samples []*queueSample, since time.Time, sortBy string) *queueRanking {
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].time.After(since) })
+	if i > 0 {
+		i--
+	}
+	in := samples[i:]
+	first, last := in[0], in[len(in)-1]
+	r := &queueRanking{
+		Queue:               qname,
+		Size:                last.size,
+		SizeDelta:           last.size - first.size,
+		LatencySeconds:      last.latency.Seconds(),
+		LatencyDeltaSeconds: (last.latency - first.latency).Seconds(),
+		MemoryUsage:         last.memory,
+		MemoryDelta:         last.memory - first.memory,
+		Trend:               make([]*rankingTrendPoint, 0),
+	}
+	start := first.time
+	if start.Before(since) {
+		start = since
+	}
+	r.CoverageSeconds = last.time.Sub(start).Seconds()
+	for j := 1; j < len(in); j++ {
+		dp, df := countDeltas(in[j-1], in[j])
+		r.Processed += dp
+		r.Failed += df
+	}
+	if r.Processed > 0 {
+		f := float64(r.Failed) / float64(r.Processed)
+		r.FailureRate = &f
+	}
+
+	// Pick evenly spaced samples for the trend, including the first and the last one.
+	n := len(in)
+	if n > rankingTrendPoints {
+		n = rankingTrendPoints
+	}
+	var prev *queueSample
+	for k := 0; k < n; k++ {
+		s := in[0]
+		if n > 1 {
+			s = in[k*(len(in)-1)/(n-1)]
+		}
+		var v float64
+		switch sortBy {
+		case rankByLatency:
+			v = s.latency.Seconds()
+		case rankByMemory:
+			v = float64(s.memory)
+		case rankByFailureRate:
+			if prev == nil {
+				prev = s
+				continue
+			}
+			if dp, df := countDeltas(prev, s); dp > 0 {
+				v = float64(df) / float64(dp)
+			}
+			prev = s
+		default:
+			v = float64(s.size)
+		}
+		r.Trend = append(r.Trend, &rankingTrendPoint{Time: s.time, Value: v})
+	}
+	return r
+}
+
+// countDeltas returns the number of tasks processed and failed between two samples.
+// It returns zeros if the counters were reset (e.g. the queue was deleted).
+func countDeltas(prev, cur *queueSample) (processed, failed int) {
+	dp, df := cur.processed-prev.processed, cur.failed-prev.failed
+	if dp < 0 || df < 0 {
+		return 0, 0
+	}
+	return dp, df
+}
+
+// sortRankings sorts the rankings by the metric, highest first.
+// Queues with no failure ratio come last when sorting by failure ratio.
+func sortRankings(rankings []*queueRanking, sortBy string) {
+	key := func(r *queueRanking) float64 {
+		switch sortBy {
+		case rankByLatency:
+			return r.LatencySeconds
+		case rankByMemory:
+			return float64(r.MemoryUsage)
+		case rankByFailureRate:
+			if r.FailureRate == nil {
+				return -1
+			}
+			return *r.FailureRate
+		default:
+			return float64(r.SizeDelta)
+		}
+	}
+	sort.SliceStable(rankings, func(i, j int) bool {
+		ki, kj := key(rankings[i]), key(rankings[j])
+		if ki != kj {
+			return ki > kj
+		}
+		return rankings[i].Queue < rankings[j].Queue
+	})
+}
+
+// PromQL queries of the queue stats, by queue.
+var promRankingQueries = map[string]string{
+	"size":      "sum by (queue) (asynq_queue_size)",
+	"latency":   "max by (queue) (asynq_queue_latency_seconds)",
+	"memory":    "sum by (queue) (asynq_queue_memory_usage_approx_bytes)",
+	"processed": "sum by (queue) (asynq_tasks_processed_total)",
+	"failed":    "sum by (queue) (asynq_tasks_failed_total)",
+}
+
+// prometheusQueueSamples returns samples of the queue stats over the period ending at now,
+// built from range queries with rankingTrendPoints steps.
+func prometheusQueueSamples(ctx context.Context, client *http.Client, prometheusAddr string, now time.Time, period time.Duration) (map[string][]*queueSample, error) {
+	step := period / rankingTrendPoints
+	// samples by queue and timestamp, and the number of fields set in each sample.
+	points := make(map[string]map[int64]*queueSample)
+	fields := make(map[*queueSample]int)
+	for field, query := range promRankingQueries {
+		v := url.Values{}
+		v.Add("query", query)
+		v.Add("start", unixTimeString(now.Add(-period)))
+		v.Add("end", unixTimeString(now))
+		v.Add("step", strconv.Itoa(int(step.Seconds())))
+		res, err := queryPrometheus(ctx, client, prometheusAddr, prometheusAPIPath, v)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch %q: %v", query, err)
+		}
+		for _, series := range res.Data.Result {
+			qname := series.Metric["queue"]
+			if points[qname] == nil {
+				points[qname] = make(map[int64]*queueSample)
+			}
+			for _, value := range series.Values {
+				ts, ok := value[0].(float64)
+				f, valid := parsePromValue(value)
+				if !ok || !valid {
+					continue
+				}
+				s := points[qname][int64(ts)]
+				if s == nil {
+					s = &queueSample{time: time.Unix(int64(ts), 0)}
+					points[qname][int64(ts)] = s
+				}
+				fields[s]++
+				switch field {
+				case "size":
+					s.size = int(f)
+				case "latency":
+					s.latency = time.Duration(f * float64(time.Second))
+				case "memory":
+					s.memory = int64(f)
+				case "processed":
+					s.processed = int(f)
+				case "failed":
+					s.failed = int(f)
+				}
+			}
+		}
+	}
+	res := make(map[string][]*queueSample, len(points))
+	for qname, byTime := range points {
+		list := make([]*queueSample, 0, len(byTime))
+		for _, s := range byTime {
+			// Skip the samples with missing values, which would be mistaken for counter resets.
+			if fields[s] == len(promRankingQueries) {
+				list = append(list, s)
+			}
+		}
+		sort.Slice(list, func(i, j int) bool { return list[i].time.Before(list[j].time) })
+		res[qname] = list
+	}
+	return res, nil
+}
+
+type queueRankingResponse struct {
+	// Source of the history of the queue stats: "prometheus", "samples"
+	// or "snapshot" if no history is available.
+	Source          string          `json:"source"`
+	SortBy          string          `json:"sort_by"`
+	DurationSeconds float64         `json:"duration_seconds"`
+	Total           int             `json:"total"`
+	Queues          []*queueRanking `json:"queues"`
+}
+
+// newQueueRankingHandlerFunc returns the top queues by growth, latency, failure ratio or memory usage
+// over the last duration seconds. The history of the queue stats is taken from Prometheus if prometheusAddr is set,
+// or from the samples of the sampler otherwise, and ends with the current stats of the queues.
+func newQueueRankingHandlerFunc(inspector *asynq.Inspector, client *http.Client, prometheusAddr string, sampler *queueSampler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sortBy := r.URL.Query().Get("sort_by")
+		if sortBy == "" {
+			sortBy = rankByGrowth
+		}
+		if !containsString([]string{rankByGrowth, rankByLatency, rankByFailureRate, rankByMemory}, sortBy) {
+			writeBadRequest(w, r, "invalid value %q for sort_by: must be one of growth, latency, failure_rate or memory", sortBy)
+			return
+		}
+		limit, err := intQueryParam(r, "limit", 10, 1, 100)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		secs, err := intQueryParam(r, "duration", 3600, 60, 7*24*3600)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		period := time.Duration(secs) * time.Second
+		now := time.Now()
+
+		resp := queueRankingResponse{Source: "snapshot", SortBy: sortBy, DurationSeconds: period.Seconds()}
+		var history map[string][]*queueSample
+		switch {
+		case prometheusAddr != "":
+			resp.Source = "prometheus"
+			history, err = prometheusQueueSamples(r.Context(), client, prometheusAddr, now, period)
+			if err != nil {
+				writeError(w, r, http.StatusInternalServerError, errCodeInternal, err.Error())
+				return
+			}
+		case sampler != nil:
+			resp.Source = "samples"
+			history = sampler.all()
+		}
+
+		span := startSpan(r.Context(), "asynq.Inspector/Queues")
+		qnames, err := inspector.Queues()
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		rankings := make([]*queueRanking, 0, len(qnames))
+		for _, qname := range qnames {
+			span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
+			info, err := inspector.GetQueueInfo(qname)
+			endSpan(span, err)
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			samples := append(history[qname], toQueueSample(now, info))
+			rankings = append(rankings, rankQueue(qname, samples, now.Add(-period), sortBy))
+		}
+		sortRankings(rankings, sortBy)
+		resp.Total = len(rankings)
+		if len(rankings) > limit {
+			rankings = rankings[:limit]
+		}
+		resp.Queues = rankings
+		writeResponseJSON(w, resp)
+	}
+}
@@ -0,0 +1,101 @@
+package asynqmon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRankQueue(t *testing.T) {
+	t0 := time.Now().Truncate(time.Second)
+	samples := []*queueSample{
+		{time: t0, size: 50, latency: time.Second, memory: 1000, processed: 100, failed: 1},
+		{time: t0.Add(time.Minute), size: 10, latency: 2 * time.Second, memory: 2000, processed: 200, failed: 2},
+		{time: t0.Add(2 * time.Minute), size: 40, latency: 5 * time.Second, memory: 5000, processed: 300, failed: 12},
+		// Counters reset.
+		{time: t0.Add(3 * time.Minute), size: 30, latency: 3 * time.Second, memory: 4000, processed: 10, failed: 0},
+	}
+
+	// Starts from the last sample before since.
+	got := rankQueue("default", samples, t0.Add(90*time.Second), rankByFailureRate)
+	if got.Size != 30 || got.SizeDelta != 20 || got.LatencyDeltaSeconds != 1 || got.MemoryDelta != 2000 {
+		t.Errorf("rankQueue = %+v, want size 30, size delta 20, latency delta 1s and memory delta 2000", got)
+	}
+	if got.Processed != 100 || got.Failed != 10 || got.FailureRate == nil || *got.FailureRate != 0.1 {
+		t.Errorf("rankQueue processed, failed, failure rate = %d, %d, %v; want 100, 10, 0.1", got.Processed, got.Failed, got.FailureRate)
+	}
+	if got.CoverageSeconds != 90 {
+		t.Errorf("rankQueue coverage = %v, want 90", got.CoverageSeconds)
+	}
+	var trend []float64
+	for _, p := range got.Trend {
+		trend = append(trend, p.Value)
+	}
+	if diff := cmp.Diff([]float64{0.1, 0}, trend); diff != "" {
+		t.Errorf("failure rate trend mismatch (-want,+got)\n%s", diff)
+	}
+
+	// A single sample has no change.
+	got = rankQueue("default", samples[:1], t0.Add(-time.Hour), rankByGrowth)
+	if got.SizeDelta != 0 || got.FailureRate != nil || len(got.Trend) != 1 || got.Trend[0].Value != 50 {
+		t.Errorf("rankQueue with a single sample = %+v", got)
+	}
+}
+
+func TestSortRankings(t *testing.T) {
+	rate := func(f float64) *float64 { return &f }
+	rankings := []*queueRanking{
+		{Queue: "a", SizeDelta: 5, LatencySeconds: 1, MemoryUsage: 300},
+		{Queue: "b", SizeDelta: 50, LatencySeconds: 10, MemoryUsage: 100, FailureRate: rate(0.01)},
+		{Queue: "c", SizeDelta: -5, LatencySeconds: 10, MemoryUsage: 200, FailureRate: rate(0.5)},
+	}
+	tests := []struct {
+		sortBy string
+		want   []string
+	}{
+		{rankByGrowth, []string{"b", "a", "c"}},
+		{rankByLatency, []string{"b", "c", "a"}},
+		{rankByFailureRate, []string{"c", "b", "a"}},
+		{rankByMemory, []string{"a", "c", "b"}},
+	}
+	for _, tc := range tests {
+		sortRankings(rankings, tc.sortBy)
+		var got []string
+		for _, r := range rankings {
+			got = append(got, r.Queue)
+		}
+		if diff := cmp.Diff(tc.want, got); diff != "" {
+			t.Errorf("sortRankings(%q) mismatch (-want,+got)\n%s", tc.sortBy, diff)
+		}
+	}
+}
+
+func TestPrometheusQueueSamples(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		// The size is missing at the second timestamp.
+		values := `[1700000000,"10"],[1700000060,"20"]`
+		if strings.Contains(query, "asynq_queue_size") {
+			values = `[1700000000,"10"]`
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"matrix","result":[{"metric":{"queue":"default"},"values":[%s]}]}}`, values)
+	}))
+	defer srv.Close()
+
+	got, err := prometheusQueueSamples(context.Background(), srv.Client(), srv.URL, time.Unix(1700000060, 0), time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string][]*queueSample{
+		"default": {{time: time.Unix(1700000000, 0), size: 10, latency: 10 * time.Second, memory: 10, processed: 10, failed: 10}},
+	}
+	if diff := cmp.Diff(want, got, cmp.AllowUnexported(queueSample{})); diff != "" {
+		t.Errorf("prometheusQueueSamples mismatch (-want,+got)\n%s", diff)
+	}
+}
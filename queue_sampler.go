@@ -0,0 +1,196 @@
+package asynqmon
+
+import (
+	"log/slog"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - sampler which periodically records the stats of all queues in memory
+// ****************************************************************************
+
+const (
+	// Default interval of the queue samples when SLOs are set.
+	defaultQueueSampleInterval = time.Minute
+
+	// Default retention of the queue samples.
+	defaultQueueSampleRetention = 24 * time.Hour
+)
+
+// queueSample is a sample of the stats of a queue.
+type queueSample struct {
+	time    time.Time
+	size    int
+	latency time.Duration
+	memory  int64
+	// Number of processed and failed tasks since the queue was created.
+	processed int
+	failed    int
+	paused    bool
+	// Number of tasks by state. Not set in samples built from Prometheus.
+	counts map[string]int
+}
+
+func toQueueSample(now time.Time, info *asynq.QueueInfo) *queueSample {
+	return &queueSample{
+		time:      now,
+		size:      info.Size,
+		latency:   info.Latency,
+		memory:    info.MemoryUsage,
+		processed: info.ProcessedTotal,
+		failed:    info.FailedTotal,
+		paused:    info.Paused,
+		counts: map[string]int{
+			"active":      info.Active,
+			"pending":     info.Pending,
+			"aggregating": info.Aggregating,
+			"scheduled":   info.Scheduled,
+			"retry":       info.Retry,
+			"archived":    info.Archived,
+			"completed":   info.Completed,
+		},
+	}
+}
+
+// queueSampler periodically samples the stats of all queues, and of the
+// queues given to the constructor even if they don't exist yet, and keeps
+// the samples in memory for the retention period.
+type queueSampler struct {
+	inspector *asynq.Inspector
+	queues    []string // sampled in addition to the existing queues
+	interval  time.Duration
+	retention time.Duration
+	logger    *slog.Logger // may be nil
+
+	// observers are called with the previous and the new sample of a queue after each sampling.
+	observers []func(qname string, prev, cur *queueSample)
+
+	mu       sync.Mutex
+	samples  map[string][]*queueSample // in chronological order
+	lastErrs map[string]error
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newQueueSampler(inspector *asynq.Inspector, queues []string, interval, retention time.Duration, logger *slog.Logger) *queueSampler {
+	if interval <= 0 {
+		interval = defaultQueueSampleInterval
+	}
+	if retention <= 0 {
+		retention = defaultQueueSampleRetention
+	}
+	return &queueSampler{
+		inspector: inspector,
+		queues:    queues,
+		interval:  interval,
+		retention: retention,
+		logger:    logger,
+		samples:   make(map[string][]*queueSample),
+		lastErrs:  make(map[string]error),
+		done:      make(chan struct{}),
+	}
+}
+
+// start starts sampling in a background goroutine until stop is called.
+func (s *queueSampler) start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.sample()
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop stops sampling and waits for the background goroutine to exit.
+func (s *queueSampler) stop() error {
+	close(s.done)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *queueSampler) sample() {
+	now := time.Now()
+	qnames, err := s.inspector.Queues()
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn("Failed to list queues to sample", slog.String("error", err.Error()))
+		}
+	}
+	for _, qname := range s.queues {
+		if !containsString(qnames, qname) {
+			qnames = append(qnames, qname)
+		}
+	}
+	samples := make(map[string]*queueSample)
+	errs := make(map[string]error)
+	for _, qname := range qnames {
+		info, err := s.inspector.GetQueueInfo(qname)
+		if err != nil {
+			errs[qname] = err
+			if s.logger != nil {
+				s.logger.Warn("Failed to sample queue", slog.String("queue", qname), slog.String("error", err.Error()))
+			}
+			continue
+		}
+		samples[qname] = toQueueSample(now, info)
+	}
+
+	prevs := make(map[string]*queueSample)
+	s.mu.Lock()
+	s.lastErrs = errs
+	cutoff := now.Add(-s.retention)
+	for qname, sample := range samples {
+		if list := s.samples[qname]; len(list) > 0 {
+			prevs[qname] = list[len(list)-1]
+		}
+		s.samples[qname] = append(s.samples[qname], sample)
+	}
+	for qname, list := range s.samples {
+		// Drop the samples older than the retention period, and the queues deleted since.
+		i := sort.Search(len(list), func(i int) bool { return !list[i].time.Before(cutoff) })
+		if i == len(list) {
+			delete(s.samples, qname)
+			continue
+		}
+		s.samples[qname] = list[i:]
+	}
+	s.mu.Unlock()
+
+	for qname, prev := range prevs {
+		for _, f := range s.observers {
+			f(qname, prev, samples[qname])
+		}
+	}
+}
+
+// history returns the samples of the queue, and the error of the last sampling of the queue.
+func (s *queueSampler) history(qname string) ([]*queueSample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*queueSample(nil), s.samples[qname]...), s.lastErrs[qname]
+}
+
+// all returns the samples of all queues.
+func (s *queueSampler) all() map[string][]*queueSample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	res := make(map[string][]*queueSample, len(s.samples))
+	for qname, list := range s.samples {
+		res[qname] = append([]*queueSample(nil), list...)
+	}
+	return res
+}
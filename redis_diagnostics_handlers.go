@@ -0,0 +1,460 @@
+package asynqmon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - http.Handler(s) for redis diagnostics endpoints (SLOWLOG, LATENCY,
+//     CLIENT LIST and COMMANDSTATS)
+// ****************************************************************************
+
+const (
+	// Default and maximum number of slow log entries to return per node.
+	defaultSlowLogEntries = 50
+	maxSlowLogEntries     = 1000
+)
+
+// redisNode is a redis server to run diagnostics commands on.
+type redisNode struct {
+	Addr string `json:"address"`
+	// Role of the node in the cluster ("master" or "replica").
+	// Empty if not connected to redis cluster.
+	Role string `json:"role,omitempty"`
+	// Error running the diagnostics command on the node, if any.
+	// The node's diagnostics are empty in that case.
+	Error string `json:"error,omitempty"`
+
+	client *redis.Client
+}
+
+// redisNodes returns the redis servers asynqmon is connected to, sorted by address.
+// In cluster mode, all masters and replicas are returned.
+func redisNodes(ctx context.Context, rc redis.UniversalClient) ([]*redisNode, error) {
+	switch c := rc.(type) {
+	case *redis.Client:
+		return []*redisNode{{Addr: c.Options().Addr, client: c}}, nil
+	case *redis.ClusterClient:
+		var (
+			mu    sync.Mutex
+			nodes []*redisNode
+		)
+		add := func(role string) func(context.Context, *redis.Client) error {
+			return func(ctx context.Context, client *redis.Client) error {
+				mu.Lock()
+				defer mu.Unlock()
+				nodes = append(nodes, &redisNode{Addr: client.Options().Addr, Role: role, client: client})
+				return nil
+			}
+		}
+		if err := c.ForEachMaster(ctx, add("master")); err != nil {
+			return nil, err
+		}
+		if err := c.ForEachSlave(ctx, add("replica")); err != nil {
+			return nil, err
+		}
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Addr < nodes[j].Addr })
+		return nodes, nil
+	default:
+		return nil, fmt.Errorf("unsupported redis client type %T", rc)
+	}
+}
+
+// ****************************************************************************
+// SLOWLOG
+// ****************************************************************************
+
+type redisSlowLogResponse struct {
+	Nodes []*redisSlowLogNode `json:"nodes"`
+}
+
+type redisSlowLogNode struct {
+	*redisNode
+	// Slow log entries in reverse chronological order.
+	Entries []*slowLogEntry `json:"entries"`
+}
+
+type slowLogEntry struct {
+	ID   int64     `json:"id"`
+	Time time.Time `json:"time"`
+	// Execution time of the command in microseconds.
+	DurationMicros int64 `json:"duration_us"`
+	// Command and its arguments (redis truncates long arguments).
+	Args       []string `json:"args"`
+	ClientAddr string   `json:"client_addr"`
+	ClientName string   `json:"client_name"`
+}
+
+func newRedisSlowLogHandlerFunc(rc redis.UniversalClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		count, err := intQueryParam(r, "count", defaultSlowLogEntries, 1, maxSlowLogEntries)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		nodes, err := redisNodes(r.Context(), rc)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		resp := redisSlowLogResponse{Nodes: []*redisSlowLogNode{}}
+		for _, n := range nodes {
+			logs, err := n.client.SlowLogGet(r.Context(), int64(count)).Result()
+			if err != nil {
+				n.Error = err.Error()
+				resp.Nodes = append(resp.Nodes, &redisSlowLogNode{redisNode: n, Entries: []*slowLogEntry{}})
+				continue
+			}
+			entries := make([]*slowLogEntry, 0, len(logs))
+			for _, l := range logs {
+				entries = append(entries, &slowLogEntry{
+					ID:             l.ID,
+					Time:           l.Time,
+					DurationMicros: l.Duration.Microseconds(),
+					Args:           l.Args,
+					ClientAddr:     l.ClientAddr,
+					ClientName:     l.ClientName,
+				})
+			}
+			resp.Nodes = append(resp.Nodes, &redisSlowLogNode{redisNode: n, Entries: entries})
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+// ****************************************************************************
+// LATENCY
+// ****************************************************************************
+
+type redisLatencyResponse struct {
+	Nodes []*redisLatencyNode `json:"nodes"`
+}
+
+type redisLatencyNode struct {
+	*redisNode
+	// Latency events sorted by name.
+	// Empty if the latency monitor is disabled (i.e. latency-monitor-threshold is 0).
+	Events []*latencyEvent `json:"events"`
+}
+
+type latencyEvent struct {
+	Event string `json:"event"`
+	// Time and latency of the latest spike.
+	Time      time.Time `json:"time"`
+	LatencyMs int64     `json:"latency_ms"`
+	// All-time maximum latency of the event.
+	MaxLatencyMs int64 `json:"max_latency_ms"`
+	// Latest latency spikes of the event (up to 160 samples) in chronological order.
+	History []*latencySample `json:"history"`
+}
+
+type latencySample struct {
+	Time      time.Time `json:"time"`
+	LatencyMs int64     `json:"latency_ms"`
+}
+
+func newRedisLatencyHandlerFunc(rc redis.UniversalClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		nodes, err := redisNodes(r.Context(), rc)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		resp := redisLatencyResponse{Nodes: []*redisLatencyNode{}}
+		for _, n := range nodes {
+			events, err := latencyEvents(r.Context(), n.client)
+			if err != nil {
+				n.Error = err.Error()
+				resp.Nodes = append(resp.Nodes, &redisLatencyNode{redisNode: n, Events: []*latencyEvent{}})
+				continue
+			}
+			resp.Nodes = append(resp.Nodes, &redisLatencyNode{redisNode: n, Events: events})
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+// latencyEvents returns the latency events reported by LATENCY LATEST along with their LATENCY HISTORY.
+func latencyEvents(ctx context.Context, client *redis.Client) ([]*latencyEvent, error) {
+	latest, err := client.Do(ctx, "latency", "latest").Slice()
+	if err != nil {
+		return nil, err
+	}
+	events := make([]*latencyEvent, 0, len(latest))
+	for _, v := range latest {
+		fields, ok := v.([]interface{})
+		if !ok || len(fields) < 4 {
+			return nil, fmt.Errorf("unexpected LATENCY LATEST reply: %v", v)
+		}
+		e := &latencyEvent{Event: fmt.Sprint(fields[0])}
+		ts, _ := fields[1].(int64)
+		e.Time = time.Unix(ts, 0)
+		e.LatencyMs, _ = fields[2].(int64)
+		e.MaxLatencyMs, _ = fields[3].(int64)
+		events = append(events, e)
+	}
+	for _, e := range events {
+		history, err := client.Do(ctx, "latency", "history", e.Event).Slice()
+		if err != nil {
+			return nil, err
+		}
+		e.History = make([]*latencySample, 0, len(history))
+		for _, v := range history {
+			fields, ok := v.([]interface{})
+			if !ok || len(fields) < 2 {
+				return nil, fmt.Errorf("unexpected LATENCY HISTORY reply: %v", v)
+			}
+			ts, _ := fields[0].(int64)
+			ms, _ := fields[1].(int64)
+			e.History = append(e.History, &latencySample{Time: time.Unix(ts, 0), LatencyMs: ms})
+		}
+	}
+	sort.Slice(events, func(i, j int) bool { return events[i].Event < events[j].Event })
+	return events, nil
+}
+
+// ****************************************************************************
+// CLIENT LIST
+// ****************************************************************************
+
+type redisClientsResponse struct {
+	Nodes []*redisClientsNode `json:"nodes"`
+}
+
+type redisClientsNode struct {
+	*redisNode
+	TotalClients int `json:"total_clients"`
+	// Connected clients grouped by client name and by host of the client address,
+	// in descending order of the number of clients.
+	ByName []*redisClientGroup `json:"by_name"`
+	ByHost []*redisClientGroup `json:"by_host"`
+}
+
+// redisClientGroup is a group of connected clients with the same name or host.
+// A group of connections from the same host typically is a pool of an asynq server.
+type redisClientGroup struct {
+	// Client name or host. Empty for clients without a name.
+	Key     string `json:"key"`
+	Clients int    `json:"clients"`
+	// Number of clients blocked in a blocking command.
+	Blocked int `json:"blocked"`
+	// Maximum connection age and idle time in seconds.
+	MaxAgeSeconds  int64 `json:"max_age_seconds"`
+	MaxIdleSeconds int64 `json:"max_idle_seconds"`
+	// Number of clients by the last command run by the client.
+	LastCommands map[string]int `json:"last_commands"`
+}
+
+// redisClient is a client connection reported by CLIENT LIST.
+type redisClient struct {
+	addr        string
+	name        string
+	ageSeconds  int64
+	idleSeconds int64
+	flags       string
+	cmd         string
+}
+
+func newRedisClientsHandlerFunc(rc redis.UniversalClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		nodes, err := redisNodes(r.Context(), rc)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		resp := redisClientsResponse{Nodes: []*redisClientsNode{}}
+		for _, n := range nodes {
+			res, err := n.client.ClientList(r.Context()).Result()
+			if err != nil {
+				n.Error = err.Error()
+				resp.Nodes = append(resp.Nodes, &redisClientsNode{
+					redisNode: n,
+					ByName:    []*redisClientGroup{},
+					ByHost:    []*redisClientGroup{},
+				})
+				continue
+			}
+			clients := parseClientList(res)
+			resp.Nodes = append(resp.Nodes, &redisClientsNode{
+				redisNode:    n,
+				TotalClients: len(clients),
+				ByName:       groupClients(clients, func(c *redisClient) string { return c.name }),
+				ByHost:       groupClients(clients, func(c *redisClient) string { return clientHost(c.addr) }),
+			})
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+// Parses the return value from the CLIENT LIST command.
+// See https://redis.io/commands/client-list#return.
+func parseClientList(s string) []*redisClient {
+	var clients []*redisClient
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		var c redisClient
+		for _, field := range strings.Fields(line) {
+			k, v, ok := strings.Cut(field, "=")
+			if !ok {
+				continue
+			}
+			switch k {
+			case "addr":
+				c.addr = v
+			case "name":
+				c.name = v
+			case "age":
+				c.ageSeconds, _ = strconv.ParseInt(v, 10, 64)
+			case "idle":
+				c.idleSeconds, _ = strconv.ParseInt(v, 10, 64)
+			case "flags":
+				c.flags = v
+			case "cmd":
+				c.cmd = v
+			}
+		}
+		clients = append(clients, &c)
+	}
+	return clients
+}
+
+// clientHost returns the host part of a client address (e.g. "10.0.0.1:53412").
+// Unix socket addresses are returned as is.
+func clientHost(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		return addr
+	}
+	return host
+}
+
+func groupClients(clients []*redisClient, key func(*redisClient) string) []*redisClientGroup {
+	m := make(map[string]*redisClientGroup)
+	for _, c := range clients {
+		k := key(c)
+		g, ok := m[k]
+		if !ok {
+			g = &redisClientGroup{Key: k, LastCommands: make(map[string]int)}
+			m[k] = g
+		}
+		g.Clients++
+		if strings.Contains(c.flags, "b") {
+			g.Blocked++
+		}
+		if c.ageSeconds > g.MaxAgeSeconds {
+			g.MaxAgeSeconds = c.ageSeconds
+		}
+		if c.idleSeconds > g.MaxIdleSeconds {
+			g.MaxIdleSeconds = c.idleSeconds
+		}
+		if c.cmd != "" {
+			g.LastCommands[c.cmd]++
+		}
+	}
+	groups := make([]*redisClientGroup, 0, len(m))
+	for _, g := range m {
+		groups = append(groups, g)
+	}
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Clients != groups[j].Clients {
+			return groups[i].Clients > groups[j].Clients
+		}
+		return groups[i].Key < groups[j].Key
+	})
+	return groups
+}
+
+// ****************************************************************************
+// COMMANDSTATS
+// ****************************************************************************
+
+type redisCommandStatsResponse struct {
+	Nodes []*redisCommandStatsNode `json:"nodes"`
+}
+
+type redisCommandStatsNode struct {
+	*redisNode
+	// Command stats in descending order of the total time spent.
+	Commands []*redisCommandStat `json:"commands"`
+}
+
+type redisCommandStat struct {
+	Command string `json:"command"`
+	Calls   int64  `json:"calls"`
+	// Total and average CPU time consumed by the command in microseconds.
+	Usec        int64   `json:"usec"`
+	UsecPerCall float64 `json:"usec_per_call"`
+	// Following fields are only reported by redis 6.2 or later.
+	RejectedCalls int64 `json:"rejected_calls"`
+	FailedCalls   int64 `json:"failed_calls"`
+}
+
+func newRedisCommandStatsHandlerFunc(rc redis.UniversalClient) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		nodes, err := redisNodes(r.Context(), rc)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		resp := redisCommandStatsResponse{Nodes: []*redisCommandStatsNode{}}
+		for _, n := range nodes {
+			res, err := n.client.Info(r.Context(), "commandstats").Result()
+			if err != nil {
+				n.Error = err.Error()
+				resp.Nodes = append(resp.Nodes, &redisCommandStatsNode{redisNode: n, Commands: []*redisCommandStat{}})
+				continue
+			}
+			resp.Nodes = append(resp.Nodes, &redisCommandStatsNode{redisNode: n, Commands: parseCommandStats(res)})
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+// Parses the commandstats section of the INFO command.
+// See https://redis.io/commands/info#return-value.
+func parseCommandStats(infoStr string) []*redisCommandStat {
+	stats := []*redisCommandStat{}
+	for k, v := range parseRedisInfo(infoStr) {
+		if !strings.HasPrefix(k, "cmdstat_") {
+			continue
+		}
+		s := &redisCommandStat{Command: strings.TrimPrefix(k, "cmdstat_")}
+		for _, field := range strings.Split(v, ",") {
+			name, val, _ := strings.Cut(field, "=")
+			switch name {
+			case "calls":
+				s.Calls, _ = strconv.ParseInt(val, 10, 64)
+			case "usec":
+				s.Usec, _ = strconv.ParseInt(val, 10, 64)
+			case "usec_per_call":
+				s.UsecPerCall, _ = strconv.ParseFloat(val, 64)
+			case "rejected_calls":
+				s.RejectedCalls, _ = strconv.ParseInt(val, 10, 64)
+			case "failed_calls":
+				s.FailedCalls, _ = strconv.ParseInt(val, 10, 64)
+			}
+		}
+		stats = append(stats, s)
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		if stats[i].Usec != stats[j].Usec {
+			return stats[i].Usec > stats[j].Usec
+		}
+		return stats[i].Command < stats[j].Command
+	})
+	return stats
+}
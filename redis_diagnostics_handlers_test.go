@@ -0,0 +1,91 @@
+package asynqmon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestParseCommandStats(t *testing.T) {
+	info := "# Commandstats\r\n" +
+		"cmdstat_zadd:calls=10,usec=50,usec_per_call=5.00,rejected_calls=0,failed_calls=1\r\n" +
+		"cmdstat_evalsha:calls=200,usec=9000,usec_per_call=45.00,rejected_calls=2,failed_calls=0\r\n" +
+		"cmdstat_client|list:calls=1,usec=50,usec_per_call=50.00\r\n"
+	want := []*redisCommandStat{
+		{Command: "evalsha", Calls: 200, Usec: 9000, UsecPerCall: 45, RejectedCalls: 2},
+		{Command: "client|list", Calls: 1, Usec: 50, UsecPerCall: 50},
+		{Command: "zadd", Calls: 10, Usec: 50, UsecPerCall: 5, FailedCalls: 1},
+	}
+	if diff := cmp.Diff(want, parseCommandStats(info)); diff != "" {
+		t.Errorf("parseCommandStats(%q) diff (-want, +got):\n%s", info, diff)
+	}
+}
+
+func TestGroupClients(t *testing.T) {
+	list := "id=3 addr=10.0.0.1:52410 laddr=10.0.0.9:6379 fd=8 name= age=120 idle=0 flags=b db=0 cmd=brpoplpush\n" +
+		"id=4 addr=10.0.0.1:52411 laddr=10.0.0.9:6379 fd=9 name= age=100 idle=5 flags=N db=0 cmd=evalsha\n" +
+		"id=5 addr=10.0.0.2:40000 laddr=10.0.0.9:6379 fd=10 name=asynqmon age=30 idle=30 flags=N db=0 cmd=client|list\n"
+	clients := parseClientList(list)
+
+	wantByHost := []*redisClientGroup{
+		{Key: "10.0.0.1", Clients: 2, Blocked: 1, MaxAgeSeconds: 120, MaxIdleSeconds: 5, LastCommands: map[string]int{"brpoplpush": 1, "evalsha": 1}},
+		{Key: "10.0.0.2", Clients: 1, MaxAgeSeconds: 30, MaxIdleSeconds: 30, LastCommands: map[string]int{"client|list": 1}},
+	}
+	byHost := groupClients(clients, func(c *redisClient) string { return clientHost(c.addr) })
+	if diff := cmp.Diff(wantByHost, byHost); diff != "" {
+		t.Errorf("clients grouped by host diff (-want, +got):\n%s", diff)
+	}
+
+	wantByName := []*redisClientGroup{
+		{Key: "", Clients: 2, Blocked: 1, MaxAgeSeconds: 120, MaxIdleSeconds: 5, LastCommands: map[string]int{"brpoplpush": 1, "evalsha": 1}},
+		{Key: "asynqmon", Clients: 1, MaxAgeSeconds: 30, MaxIdleSeconds: 30, LastCommands: map[string]int{"client|list": 1}},
+	}
+	byName := groupClients(clients, func(c *redisClient) string { return c.name })
+	if diff := cmp.Diff(wantByName, byName); diff != "" {
+		t.Errorf("clients grouped by name diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestRedisDiagnosticsReportNodeErrors(t *testing.T) {
+	client := redis.NewClient(&redis.Options{
+		Addr:        "127.0.0.1:1",
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	defer client.Close()
+	tests := []struct {
+		desc    string
+		handler http.HandlerFunc
+	}{
+		{"slowlog", newRedisSlowLogHandlerFunc(client)},
+		{"latency", newRedisLatencyHandlerFunc(client)},
+		{"clients", newRedisClientsHandlerFunc(client)},
+		{"commandstats", newRedisCommandStatsHandlerFunc(client)},
+	}
+	for _, tc := range tests {
+		rec := httptest.NewRecorder()
+		tc.handler(rec, httptest.NewRequest("GET", "/api/redis_diagnostics", nil))
+		if rec.Code != http.StatusOK {
+			t.Errorf("%s: status = %d, want %d when a node is not reachable", tc.desc, rec.Code, http.StatusOK)
+			continue
+		}
+		var resp struct {
+			Nodes []struct {
+				Addr  string `json:"address"`
+				Error string `json:"error"`
+			} `json:"nodes"`
+		}
+		if err := json.Unmarshal(rec.Body.Bytes(), &resp); err != nil {
+			t.Errorf("%s: failed to decode response: %v", tc.desc, err)
+			continue
+		}
+		if len(resp.Nodes) != 1 || resp.Nodes[0].Addr != "127.0.0.1:1" || resp.Nodes[0].Error == "" {
+			t.Errorf("%s: nodes = %+v, want the unreachable node with an error", tc.desc, resp.Nodes)
+		}
+	}
+}
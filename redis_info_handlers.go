@@ -3,7 +3,10 @@ package asynqmon
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/hibiken/asynq"
@@ -24,6 +27,12 @@ type redisInfoResponse struct {
 	// Following fields are only set when connected to redis cluster.
 	RawClusterNodes string               `json:"raw_cluster_nodes"`
 	QueueLocations  []*queueLocationInfo `json:"queue_locations"`
+	Nodes           []*clusterNodeInfo   `json:"nodes"`
+	// Warnings about the cluster (e.g. queues concentrated on one node).
+	Warnings []string `json:"warnings"`
+
+	// Following field is only set when connected to redis through sentinels.
+	Sentinel *sentinelInfo `json:"sentinel,omitempty"`
 }
 
 type queueLocationInfo struct {
@@ -32,11 +41,201 @@ type queueLocationInfo struct {
 	Nodes   []string `json:"nodes"`   // list of cluster node addresses
 }
 
-func newRedisInfoHandlerFunc(client *redis.Client) http.HandlerFunc {
+// clusterNodeInfo describes a master or replica node in redis cluster.
+// The fields from INFO are zero and Error is set if the node could not be queried.
+type clusterNodeInfo struct {
+	ID   string `json:"id"`
+	Addr string `json:"address"`
+	// "master" or "replica".
+	Role string `json:"role"`
+	// ID of the master if the node is a replica.
+	MasterID string `json:"master_id,omitempty"`
+	// Flags reported by CLUSTER NODES (e.g. "myself", "fail?").
+	Flags     []string `json:"flags"`
+	Connected bool     `json:"connected"`
+
+	// Slot ranges served by the node (masters only).
+	Slots     []*slotRange `json:"slots"`
+	SlotCount int          `json:"slot_count"`
+	// Queues whose key slot is served by the node (masters only).
+	Queues []string `json:"queues"`
+
+	UsedMemory        int64  `json:"used_memory"`
+	UsedMemoryHuman   string `json:"used_memory_human"`
+	MaxMemory         int64  `json:"maxmemory"`
+	OpsPerSec         int64  `json:"ops_per_sec"`
+	ConnectedClients  int64  `json:"connected_clients"`
+	ReplicationOffset int64  `json:"replication_offset"`
+	// Number of bytes the replica is behind its master (replicas only).
+	ReplicationLag int64 `json:"replication_lag"`
+	// Reason the fields from INFO are missing, empty if the node was queried successfully.
+	Error string `json:"error,omitempty"`
+}
+
+type slotRange struct {
+	Start int64 `json:"start"`
+	End   int64 `json:"end"`
+}
+
+// sentinelInfo is the view of a sentinel on the master asynqmon is connected to.
+type sentinelInfo struct {
+	// Address of the sentinel which reported the information.
+	SentinelAddr string `json:"sentinel_address"`
+	MasterName   string `json:"master_name"`
+
+	Master    *sentinelInstance   `json:"master"`
+	Replicas  []*sentinelInstance `json:"replicas"`
+	Sentinels []*sentinelInstance `json:"sentinels"`
+
+	// Number of sentinels needed to agree that the master is down.
+	Quorum int `json:"quorum"`
+	// Result of SENTINEL CKQUORUM, i.e. whether the sentinels can authorize a failover.
+	QuorumOK     bool   `json:"quorum_ok"`
+	QuorumStatus string `json:"quorum_status"`
+
+	FailoverInProgress bool `json:"failover_in_progress"`
+	// State of the ongoing failover (e.g. "wait_promotion"). Empty if no failover is in progress.
+	FailoverState string `json:"failover_state"`
+
+	// Error is set if no sentinel could be queried. Other fields are empty in that case.
+	Error string `json:"error,omitempty"`
+}
+
+// sentinelInstance is a master, replica or sentinel as seen by a sentinel.
+type sentinelInstance struct {
+	Addr  string   `json:"address"`
+	RunID string   `json:"run_id"`
+	Flags []string `json:"flags"`
+	// Down is true if the instance is subjectively or objectively down or disconnected.
+	Down bool `json:"down"`
+	// Milliseconds since the last successful reply to PING.
+	LastOKPingReplyMs int64 `json:"last_ok_ping_reply_ms"`
+
+	// Following fields are only set for replicas.
+	MasterLinkStatus  string `json:"master_link_status,omitempty"`
+	ReplicationOffset int64  `json:"replication_offset,omitempty"`
+}
+
+// sentinelTopology holds the clients to the sentinels monitoring the master asynqmon is connected to.
+type sentinelTopology struct {
+	masterName string
+	addrs      []string
+	clients    []*redis.SentinelClient
+}
+
+// newSentinelTopology returns the sentinels to query if opt connects to redis through sentinels.
+// Otherwise it returns nil.
+func newSentinelTopology(opt asynq.RedisConnOpt) *sentinelTopology {
+	var failoverOpt asynq.RedisFailoverClientOpt
+	switch o := opt.(type) {
+	case asynq.RedisFailoverClientOpt:
+		failoverOpt = o
+	case *asynq.RedisFailoverClientOpt:
+		failoverOpt = *o
+	default:
+		return nil
+	}
+	t := &sentinelTopology{masterName: failoverOpt.MasterName, addrs: failoverOpt.SentinelAddrs}
+	for _, addr := range failoverOpt.SentinelAddrs {
+		t.clients = append(t.clients, redis.NewSentinelClient(&redis.Options{
+			Addr:        addr,
+			Password:    failoverOpt.SentinelPassword,
+			DialTimeout: failoverOpt.DialTimeout,
+			TLSConfig:   failoverOpt.TLSConfig,
+		}))
+	}
+	return t
+}
+
+func (t *sentinelTopology) addHook(h redis.Hook) {
+	for _, c := range t.clients {
+		c.AddHook(h)
+	}
+}
+
+func (t *sentinelTopology) close() error {
+	for _, c := range t.clients {
+		if err := c.Close(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// info returns the view of the first sentinel which responds.
+func (t *sentinelTopology) info(ctx context.Context) *sentinelInfo {
+	var lastErr error
+	for i, c := range t.clients {
+		info, err := querySentinel(ctx, c, t.masterName)
+		if err == nil {
+			info.SentinelAddr = t.addrs[i]
+			return info
+		}
+		lastErr = fmt.Errorf("sentinel %s: %v", t.addrs[i], err)
+	}
+	if lastErr == nil {
+		lastErr = fmt.Errorf("no sentinel addresses")
+	}
+	return &sentinelInfo{MasterName: t.masterName, Error: lastErr.Error()}
+}
+
+func querySentinel(ctx context.Context, c *redis.SentinelClient, masterName string) (*sentinelInfo, error) {
+	master, err := c.Master(ctx, masterName).Result()
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := c.Replicas(ctx, masterName).Result()
+	if err != nil {
+		return nil, err
+	}
+	sentinels, err := c.Sentinels(ctx, masterName).Result()
+	if err != nil {
+		return nil, err
+	}
+	info := &sentinelInfo{
+		MasterName:    masterName,
+		Master:        toSentinelInstance(master),
+		FailoverState: master["failover-state"],
+	}
+	info.Quorum, _ = strconv.Atoi(master["quorum"])
+	info.FailoverInProgress = containsString(info.Master.Flags, "failover_in_progress")
+	for _, r := range replicas {
+		info.Replicas = append(info.Replicas, toSentinelInstance(r))
+	}
+	for _, s := range sentinels {
+		info.Sentinels = append(info.Sentinels, toSentinelInstance(s))
+	}
+	// CKQUORUM replies with an error if the quorum cannot be reached.
+	info.QuorumStatus, err = c.CkQuorum(ctx, masterName).Result()
+	info.QuorumOK = err == nil
+	if err != nil {
+		info.QuorumStatus = err.Error()
+	}
+	return info, nil
+}
+
+func toSentinelInstance(m map[string]string) *sentinelInstance {
+	inst := &sentinelInstance{
+		Addr:             m["ip"] + ":" + m["port"],
+		RunID:            m["runid"],
+		Flags:            strings.Split(m["flags"], ","),
+		MasterLinkStatus: m["master-link-status"],
+	}
+	for _, f := range inst.Flags {
+		if f == "s_down" || f == "o_down" || f == "disconnected" {
+			inst.Down = true
+		}
+	}
+	inst.LastOKPingReplyMs, _ = strconv.ParseInt(m["last-ok-ping-reply"], 10, 64)
+	inst.ReplicationOffset, _ = strconv.ParseInt(m["slave-repl-offset"], 10, 64)
+	return inst
+}
+
+func newRedisInfoHandlerFunc(client *redis.Client, sentinel *sentinelTopology) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		res, err := client.Info(context.Background()).Result()
+		res, err := client.Info(r.Context()).Result()
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		info := parseRedisInfo(res)
@@ -46,8 +245,11 @@ func newRedisInfoHandlerFunc(client *redis.Client) http.HandlerFunc {
 			RawInfo: res,
 			Cluster: false,
 		}
+		if sentinel != nil {
+			resp.Sentinel = sentinel.info(r.Context())
+		}
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 	}
@@ -55,34 +257,40 @@ func newRedisInfoHandlerFunc(client *redis.Client) http.HandlerFunc {
 
 func newRedisClusterInfoHandlerFunc(client *redis.ClusterClient, inspector *asynq.Inspector) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		ctx := context.Background()
+		ctx := r.Context()
 		rawClusterInfo, err := client.ClusterInfo(ctx).Result()
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		info := parseRedisInfo(rawClusterInfo)
 		rawClusterNodes, err := client.ClusterNodes(ctx).Result()
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span := startSpan(r.Context(), "asynq.Inspector/Queues")
 		queues, err := inspector.Queues()
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		var queueLocations []*queueLocationInfo
 		for _, qname := range queues {
 			q := queueLocationInfo{Queue: qname}
+			span := startSpan(r.Context(), "asynq.Inspector/ClusterKeySlot")
 			q.KeySlot, err = inspector.ClusterKeySlot(qname)
+			endSpan(span, err)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeErrorResponse(w, r, err)
 				return
 			}
+			span = startSpan(r.Context(), "asynq.Inspector/ClusterNodes")
 			nodes, err := inspector.ClusterNodes(qname)
+			endSpan(span, err)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeErrorResponse(w, r, err)
 				return
 			}
 			for _, n := range nodes {
@@ -91,6 +299,10 @@ func newRedisClusterInfoHandlerFunc(client *redis.ClusterClient, inspector *asyn
 			queueLocations = append(queueLocations, &q)
 		}
 
+		nodes := parseClusterNodes(rawClusterNodes)
+		addClusterNodeInfo(ctx, client, nodes)
+		assignQueuesToNodes(nodes, queueLocations)
+
 		resp := redisInfoResponse{
 			Addr:            strings.Join(client.Options().Addrs, ","),
 			Info:            info,
@@ -98,9 +310,11 @@ func newRedisClusterInfoHandlerFunc(client *redis.ClusterClient, inspector *asyn
 			Cluster:         true,
 			RawClusterNodes: rawClusterNodes,
 			QueueLocations:  queueLocations,
+			Nodes:           nodes,
+			Warnings:        clusterWarnings(nodes, len(queueLocations)),
 		}
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 	}
@@ -120,3 +334,196 @@ func parseRedisInfo(infoStr string) map[string]string {
 	return info
 
 }
+
+// Parses the return value from the CLUSTER NODES command.
+// See https://redis.io/commands/cluster-nodes#serialization-format.
+func parseClusterNodes(s string) []*clusterNodeInfo {
+	var nodes []*clusterNodeInfo
+	for _, line := range strings.Split(s, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) < 8 {
+			continue
+		}
+		n := &clusterNodeInfo{
+			ID:        fields[0],
+			Flags:     strings.Split(fields[2], ","),
+			Connected: fields[7] == "connected",
+		}
+		// Address is formatted as ip:port@cport[,hostname].
+		n.Addr, _, _ = strings.Cut(fields[1], "@")
+		n.Role = "master"
+		if containsString(n.Flags, "slave") {
+			n.Role = "replica"
+		}
+		if fields[3] != "-" {
+			n.MasterID = fields[3]
+		}
+		for _, slot := range fields[8:] {
+			// Slots being imported or migrated are formatted as [slot->-id] or [slot-<-id].
+			if strings.HasPrefix(slot, "[") {
+				continue
+			}
+			start, end, found := strings.Cut(slot, "-")
+			if !found {
+				end = start
+			}
+			rng := &slotRange{}
+			var err1, err2 error
+			rng.Start, err1 = strconv.ParseInt(start, 10, 64)
+			rng.End, err2 = strconv.ParseInt(end, 10, 64)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			n.Slots = append(n.Slots, rng)
+			n.SlotCount += int(rng.End - rng.Start + 1)
+		}
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		if nodes[i].Role != nodes[j].Role {
+			return nodes[i].Role == "master"
+		}
+		return nodes[i].Addr < nodes[j].Addr
+	})
+	return nodes
+}
+
+// addClusterNodeInfo sets the fields from INFO of each reachable node.
+// A node which cannot be queried gets an error message instead of failing the request,
+// so that the other nodes are still reported when a node is down.
+func addClusterNodeInfo(ctx context.Context, client *redis.ClusterClient, nodes []*clusterNodeInfo) {
+	reachable, err := redisNodes(ctx, client)
+	if err != nil {
+		for _, n := range nodes {
+			n.Error = err.Error()
+		}
+		return
+	}
+	var results []*clusterNodeResult
+	for _, rn := range reachable {
+		id := redis.NewStringCmd(ctx, "cluster", "myid")
+		var info *redis.StringCmd
+		_, err := rn.client.Pipelined(ctx, func(p redis.Pipeliner) error {
+			p.Process(ctx, id)
+			info = p.Info(ctx)
+			return nil
+		})
+		res := &clusterNodeResult{Addr: rn.Addr, Err: err}
+		if err == nil {
+			res.ID = id.Val()
+			res.Info = info.Val()
+		}
+		results = append(results, res)
+	}
+	applyClusterNodeInfo(nodes, results)
+}
+
+// clusterNodeResult is the result of querying a single cluster node.
+type clusterNodeResult struct {
+	Addr string
+	// ID returned by CLUSTER MYID and the return value of INFO if the query succeeded.
+	ID   string
+	Info string
+	Err  error
+}
+
+// applyClusterNodeInfo sets the fields from INFO of each node from the query results.
+// Successful results are matched by cluster node ID since the addresses in CLUSTER NODES
+// may differ from the addresses used by the client (e.g. behind NAT); failed results
+// are matched by address. Nodes without a result are reported as not reachable.
+func applyClusterNodeInfo(nodes []*clusterNodeInfo, results []*clusterNodeResult) {
+	byID := make(map[string]*clusterNodeInfo)
+	byAddr := make(map[string]*clusterNodeInfo)
+	for _, n := range nodes {
+		byID[n.ID] = n
+		byAddr[n.Addr] = n
+	}
+	seen := make(map[*clusterNodeInfo]bool)
+	for _, res := range results {
+		if res.Err != nil {
+			if n, ok := byAddr[res.Addr]; ok {
+				n.Error = res.Err.Error()
+				seen[n] = true
+			}
+			continue
+		}
+		n, ok := byID[res.ID]
+		if !ok {
+			continue
+		}
+		seen[n] = true
+		m := parseRedisInfo(res.Info)
+		n.UsedMemory, _ = strconv.ParseInt(m["used_memory"], 10, 64)
+		n.UsedMemoryHuman = m["used_memory_human"]
+		n.MaxMemory, _ = strconv.ParseInt(m["maxmemory"], 10, 64)
+		n.OpsPerSec, _ = strconv.ParseInt(m["instantaneous_ops_per_sec"], 10, 64)
+		n.ConnectedClients, _ = strconv.ParseInt(m["connected_clients"], 10, 64)
+		n.ReplicationOffset, _ = strconv.ParseInt(m["master_repl_offset"], 10, 64)
+	}
+	for _, n := range nodes {
+		if !seen[n] {
+			n.Error = "not reachable"
+		}
+	}
+	for _, n := range nodes {
+		if master, ok := byID[n.MasterID]; ok && master.Error == "" && n.Error == "" && n.ReplicationOffset > 0 {
+			n.ReplicationLag = master.ReplicationOffset - n.ReplicationOffset
+		}
+	}
+}
+
+// assignQueuesToNodes sets the queues served by each master node from the key slots of the queues.
+func assignQueuesToNodes(nodes []*clusterNodeInfo, locations []*queueLocationInfo) {
+	for _, loc := range locations {
+		for _, n := range nodes {
+			if n.Role == "master" && n.servesSlot(loc.KeySlot) {
+				n.Queues = append(n.Queues, loc.Queue)
+				break
+			}
+		}
+	}
+}
+
+func (n *clusterNodeInfo) servesSlot(slot int64) bool {
+	for _, rng := range n.Slots {
+		if rng.Start <= slot && slot <= rng.End {
+			return true
+		}
+	}
+	return false
+}
+
+// clusterWarnings returns warnings about failing nodes and about queues concentrated on one node.
+//
+// All keys of a queue are stored on the node serving the key slot of the queue,
+// so a node serving many more queues than the others receives most of the load.
+// A node is reported if it serves at least twice its fair share of the queues.
+func clusterWarnings(nodes []*clusterNodeInfo, numQueues int) []string {
+	warnings := []string{}
+	var masters []*clusterNodeInfo
+	for _, n := range nodes {
+		if containsString(n.Flags, "fail") || containsString(n.Flags, "fail?") {
+			warnings = append(warnings, fmt.Sprintf("%s node %s is failing (flags: %s)", n.Role, n.Addr, strings.Join(n.Flags, ",")))
+		}
+		if n.Role == "master" && n.SlotCount > 0 {
+			masters = append(masters, n)
+		}
+	}
+	if len(masters) < 2 || numQueues < 2 {
+		return warnings
+	}
+	threshold := 2 * numQueues / len(masters)
+	if threshold > numQueues {
+		threshold = numQueues
+	}
+	if threshold < 2 {
+		threshold = 2
+	}
+	for _, n := range masters {
+		if len(n.Queues) >= threshold {
+			warnings = append(warnings, fmt.Sprintf("%d of %d queues are on node %s (%s): keys of a queue are stored on a single node, consider using queue names which hash to other slots",
+				len(n.Queues), numQueues, n.Addr, strings.Join(n.Queues, ", ")))
+		}
+	}
+	return warnings
+}
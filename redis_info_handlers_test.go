@@ -0,0 +1,149 @@
+package asynqmon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestParseClusterNodes(t *testing.T) {
+	raw := "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 127.0.0.1:30001@31001 myself,master - 0 0 1 connected 0-5460\n" +
+		"67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1 127.0.0.1:30002@31002 master - 0 1426238316232 2 connected 5461-10922 [10923->-292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f]\n" +
+		"292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f 127.0.0.1:30003@31003 master,fail? - 0 1426238318243 3 disconnected 10923-16383\n" +
+		"6ec23923021cf3ffec47632106199cb7f496ce01 127.0.0.1:30004@31004,replica-1.example.com slave e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca 0 1426238316232 1 connected\n"
+	want := []*clusterNodeInfo{
+		{ID: "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca", Addr: "127.0.0.1:30001", Role: "master", Flags: []string{"myself", "master"}, Connected: true,
+			Slots: []*slotRange{{0, 5460}}, SlotCount: 5461},
+		{ID: "67ed2db8d677e59ec4a4cefb06858cf2a1a89fa1", Addr: "127.0.0.1:30002", Role: "master", Flags: []string{"master"}, Connected: true,
+			Slots: []*slotRange{{5461, 10922}}, SlotCount: 5462},
+		{ID: "292f8b365bb7edb5e285caf0b7e6ddc7265d2f4f", Addr: "127.0.0.1:30003", Role: "master", Flags: []string{"master", "fail?"}, Connected: false,
+			Slots: []*slotRange{{10923, 16383}}, SlotCount: 5461},
+		{ID: "6ec23923021cf3ffec47632106199cb7f496ce01", Addr: "127.0.0.1:30004", Role: "replica", MasterID: "e7d1eecce10fd6bb5eb35b9f99a514335d9ba9ca",
+			Flags: []string{"slave"}, Connected: true},
+	}
+	if diff := cmp.Diff(want, parseClusterNodes(raw)); diff != "" {
+		t.Errorf("parseClusterNodes(%q) diff (-want, +got):\n%s", raw, diff)
+	}
+}
+
+func TestClusterWarnings(t *testing.T) {
+	tests := []struct {
+		desc  string
+		nodes []*clusterNodeInfo
+		want  int // number of warnings
+	}{
+		{
+			desc: "queues spread across masters",
+			nodes: []*clusterNodeInfo{
+				{Addr: "a", Role: "master", SlotCount: 8192, Queues: []string{"critical", "default"}},
+				{Addr: "b", Role: "master", SlotCount: 8192, Queues: []string{"low"}},
+			},
+			want: 0,
+		},
+		{
+			desc: "all queues on one master",
+			nodes: []*clusterNodeInfo{
+				{Addr: "a", Role: "master", SlotCount: 8192, Queues: []string{"critical", "default", "low"}},
+				{Addr: "b", Role: "master", SlotCount: 8192},
+			},
+			want: 1,
+		},
+		{
+			desc: "failing replica",
+			nodes: []*clusterNodeInfo{
+				{Addr: "a", Role: "master", SlotCount: 16384, Queues: []string{"default"}},
+				{Addr: "b", Role: "replica", Flags: []string{"slave", "fail"}},
+			},
+			want: 1,
+		},
+	}
+	for _, tc := range tests {
+		numQueues := 0
+		for _, n := range tc.nodes {
+			numQueues += len(n.Queues)
+		}
+		got := clusterWarnings(tc.nodes, numQueues)
+		if len(got) != tc.want {
+			t.Errorf("%s: clusterWarnings returned %d warnings, want %d: %v", tc.desc, len(got), tc.want, got)
+		}
+	}
+}
+
+func TestApplyClusterNodeInfo(t *testing.T) {
+	nodes := []*clusterNodeInfo{
+		{ID: "m1", Addr: "127.0.0.1:30001", Role: "master"},
+		{ID: "m2", Addr: "127.0.0.1:30002", Role: "master"},
+		{ID: "m3", Addr: "127.0.0.1:30003", Role: "master"},
+		{ID: "r1", Addr: "127.0.0.1:30004", Role: "replica", MasterID: "m1"},
+		{ID: "r2", Addr: "127.0.0.1:30005", Role: "replica", MasterID: "m2"},
+	}
+	info := func(usedMemory, offset int) string {
+		return fmt.Sprintf("# Memory\r\nused_memory:%d\r\nused_memory_human:%dB\r\n# Replication\r\nmaster_repl_offset:%d\r\n", usedMemory, usedMemory, offset)
+	}
+	results := []*clusterNodeResult{
+		{Addr: "10.0.0.1:6379", ID: "m1", Info: info(100, 500)},
+		{Addr: "127.0.0.1:30002", Err: errors.New("dial tcp 127.0.0.1:30002: connect: connection refused")},
+		{Addr: "10.0.0.4:6379", ID: "r1", Info: info(90, 480)},
+		{Addr: "10.0.0.5:6379", ID: "r2", Info: info(80, 300)},
+	}
+	applyClusterNodeInfo(nodes, results)
+	want := []*clusterNodeInfo{
+		{ID: "m1", Addr: "127.0.0.1:30001", Role: "master", UsedMemory: 100, UsedMemoryHuman: "100B", ReplicationOffset: 500},
+		{ID: "m2", Addr: "127.0.0.1:30002", Role: "master", Error: "dial tcp 127.0.0.1:30002: connect: connection refused"},
+		{ID: "m3", Addr: "127.0.0.1:30003", Role: "master", Error: "not reachable"},
+		{ID: "r1", Addr: "127.0.0.1:30004", Role: "replica", MasterID: "m1", UsedMemory: 90, UsedMemoryHuman: "90B", ReplicationOffset: 480, ReplicationLag: 20},
+		{ID: "r2", Addr: "127.0.0.1:30005", Role: "replica", MasterID: "m2", UsedMemory: 80, UsedMemoryHuman: "80B", ReplicationOffset: 300},
+	}
+	if diff := cmp.Diff(want, nodes); diff != "" {
+		t.Errorf("applyClusterNodeInfo diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestAddClusterNodeInfoUnreachableCluster(t *testing.T) {
+	client := redis.NewClusterClient(&redis.ClusterOptions{
+		Addrs:       []string{"127.0.0.1:1"},
+		DialTimeout: 100 * time.Millisecond,
+		MaxRetries:  -1,
+	})
+	defer client.Close()
+	nodes := []*clusterNodeInfo{
+		{ID: "m1", Addr: "127.0.0.1:30001", Role: "master"},
+		{ID: "r1", Addr: "127.0.0.1:30002", Role: "replica", MasterID: "m1"},
+	}
+	addClusterNodeInfo(context.Background(), client, nodes)
+	for _, n := range nodes {
+		if n.Error == "" {
+			t.Errorf("node %s: Error is empty, want an error message when the cluster is not reachable", n.Addr)
+		}
+	}
+}
+
+func TestToSentinelInstance(t *testing.T) {
+	got := toSentinelInstance(map[string]string{
+		"ip":                 "10.0.0.2",
+		"port":               "6379",
+		"runid":              "d8a1f0a3b2",
+		"flags":              "s_down,slave",
+		"last-ok-ping-reply": "4012",
+		"master-link-status": "err",
+		"slave-repl-offset":  "1234",
+	})
+	want := &sentinelInstance{
+		Addr:              "10.0.0.2:6379",
+		RunID:             "d8a1f0a3b2",
+		Flags:             []string{"s_down", "slave"},
+		Down:              true,
+		LastOKPingReplyMs: 4012,
+		MasterLinkStatus:  "err",
+		ReplicationOffset: 1234,
+	}
+	if diff := cmp.Diff(want, got, cmpopts.EquateEmpty()); diff != "" {
+		t.Errorf("toSentinelInstance diff (-want, +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,252 @@
+package asynqmon
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - sampler which periodically records redis INFO fields in memory
+//   - http.Handler(s) for redis info history endpoint
+// ****************************************************************************
+
+// Default retention of the redis INFO samples.
+const defaultRedisInfoRetention = 24 * time.Hour
+
+// redisInfoValues holds the sampled INFO fields of a redis server
+// (or the sum over the masters in cluster mode).
+type redisInfoValues struct {
+	UsedMemory       int64 `json:"used_memory"`
+	ConnectedClients int64 `json:"connected_clients"`
+	OpsPerSec        int64 `json:"ops_per_sec"`
+	// Following fields are counters since the server started.
+	EvictedKeys    int64 `json:"evicted_keys"`
+	KeyspaceHits   int64 `json:"keyspace_hits"`
+	KeyspaceMisses int64 `json:"keyspace_misses"`
+}
+
+func (v *redisInfoValues) add(o *redisInfoValues) {
+	v.UsedMemory += o.UsedMemory
+	v.ConnectedClients += o.ConnectedClients
+	v.OpsPerSec += o.OpsPerSec
+	v.EvictedKeys += o.EvictedKeys
+	v.KeyspaceHits += o.KeyspaceHits
+	v.KeyspaceMisses += o.KeyspaceMisses
+}
+
+func toRedisInfoValues(info map[string]string) *redisInfoValues {
+	parse := func(field string) int64 {
+		n, _ := strconv.ParseInt(info[field], 10, 64)
+		return n
+	}
+	return &redisInfoValues{
+		UsedMemory:       parse("used_memory"),
+		ConnectedClients: parse("connected_clients"),
+		OpsPerSec:        parse("instantaneous_ops_per_sec"),
+		EvictedKeys:      parse("evicted_keys"),
+		KeyspaceHits:     parse("keyspace_hits"),
+		KeyspaceMisses:   parse("keyspace_misses"),
+	}
+}
+
+// redisInfoSample is a sample of INFO fields of each redis server, keyed by address.
+type redisInfoSample struct {
+	time  time.Time
+	nodes map[string]*redisInfoValues
+	// Addresses of the cluster replicas, which are left out of the sums
+	// since they hold a copy of the data of their master.
+	replicas map[string]bool
+}
+
+// redisInfoSampler periodically samples INFO fields of the redis servers
+// and keeps the samples in memory for the retention period.
+type redisInfoSampler struct {
+	rc        redis.UniversalClient
+	interval  time.Duration
+	retention time.Duration
+	logger    *slog.Logger // may be nil
+
+	mu      sync.Mutex
+	samples []*redisInfoSample // in chronological order
+	lastErr error
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newRedisInfoSampler(rc redis.UniversalClient, interval, retention time.Duration, logger *slog.Logger) *redisInfoSampler {
+	if retention <= 0 {
+		retention = defaultRedisInfoRetention
+	}
+	return &redisInfoSampler{
+		rc:        rc,
+		interval:  interval,
+		retention: retention,
+		logger:    logger,
+		done:      make(chan struct{}),
+	}
+}
+
+// start starts sampling in a background goroutine until stop is called.
+func (s *redisInfoSampler) start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.sample()
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop stops sampling and waits for the background goroutine to exit.
+func (s *redisInfoSampler) stop() error {
+	close(s.done)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *redisInfoSampler) sample() {
+	ctx, cancel := context.WithTimeout(context.Background(), s.interval)
+	defer cancel()
+	sample, err := sampleRedisInfo(ctx, s.rc)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastErr = err
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn("Failed to sample redis INFO", slog.String("error", err.Error()))
+		}
+		return
+	}
+	s.samples = append(s.samples, sample)
+	// Drop the samples older than the retention period.
+	cutoff := sample.time.Add(-s.retention)
+	i := sort.Search(len(s.samples), func(i int) bool { return !s.samples[i].time.Before(cutoff) })
+	s.samples = s.samples[i:]
+}
+
+func sampleRedisInfo(ctx context.Context, rc redis.UniversalClient) (*redisInfoSample, error) {
+	nodes, err := redisNodes(ctx, rc)
+	if err != nil {
+		return nil, err
+	}
+	sample := &redisInfoSample{time: time.Now(), nodes: make(map[string]*redisInfoValues), replicas: make(map[string]bool)}
+	for _, n := range nodes {
+		if n.Role == "replica" {
+			sample.replicas[n.Addr] = true
+		}
+		res, err := n.client.Info(ctx).Result()
+		if err != nil {
+			return nil, err
+		}
+		sample.nodes[n.Addr] = toRedisInfoValues(parseRedisInfo(res))
+	}
+	return sample, nil
+}
+
+// history returns the samples taken after the given time, and the last sampling error.
+func (s *redisInfoSampler) history(since time.Time) ([]*redisInfoSample, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	i := sort.Search(len(s.samples), func(i int) bool { return s.samples[i].time.After(since) })
+	return append([]*redisInfoSample(nil), s.samples[i:]...), s.lastErr
+}
+
+type redisInfoHistoryResponse struct {
+	// Enabled is false if redis INFO is not sampled (i.e. Options.RedisInfoSampleInterval is not set).
+	Enabled          bool    `json:"enabled"`
+	IntervalSeconds  float64 `json:"interval_seconds"`
+	RetentionSeconds float64 `json:"retention_seconds"`
+	// Addresses of the redis servers in the samples.
+	Nodes   []string                `json:"nodes"`
+	Samples []*redisInfoSampleValue `json:"samples"`
+	// Error of the last sampling, if any.
+	LastError string `json:"last_error,omitempty"`
+}
+
+type redisInfoSampleValue struct {
+	Time time.Time `json:"time"`
+	redisInfoValues
+	// Ratio of keyspace hits to lookups since the previous sample.
+	// Null if there were no lookups or the counters were reset.
+	KeyspaceHitRatio *float64 `json:"keyspace_hit_ratio"`
+}
+
+func newRedisInfoHistoryHandlerFunc(s *redisInfoSampler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := redisInfoHistoryResponse{Nodes: []string{}, Samples: []*redisInfoSampleValue{}}
+		if s == nil {
+			writeResponseJSON(w, resp)
+			return
+		}
+		retention := int(s.retention.Seconds())
+		duration, err := intQueryParam(r, "duration", retention, 1, retention)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		node := r.URL.Query().Get("node")
+
+		samples, lastErr := s.history(time.Now().Add(-time.Duration(duration) * time.Second))
+		resp.Enabled = true
+		resp.IntervalSeconds = s.interval.Seconds()
+		resp.RetentionSeconds = s.retention.Seconds()
+		if lastErr != nil {
+			resp.LastError = lastErr.Error()
+		}
+		seen := make(map[string]bool)
+		for _, sample := range samples {
+			for addr := range sample.nodes {
+				if !seen[addr] {
+					seen[addr] = true
+					resp.Nodes = append(resp.Nodes, addr)
+				}
+			}
+		}
+		sort.Strings(resp.Nodes)
+		if node != "" && !seen[node] && len(samples) > 0 {
+			writeBadRequest(w, r, "unknown node %q", node)
+			return
+		}
+
+		var prev *redisInfoValues
+		for _, sample := range samples {
+			v := redisInfoSampleValue{Time: sample.time}
+			for addr, nv := range sample.nodes {
+				if (node == "" && !sample.replicas[addr]) || addr == node {
+					v.add(nv)
+				}
+			}
+			if prev != nil {
+				v.KeyspaceHitRatio = hitRatio(v.KeyspaceHits-prev.KeyspaceHits, v.KeyspaceMisses-prev.KeyspaceMisses)
+			}
+			prev = &v.redisInfoValues
+			resp.Samples = append(resp.Samples, &v)
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+func hitRatio(hits, misses int64) *float64 {
+	if hits < 0 || misses < 0 || hits+misses == 0 {
+		return nil
+	}
+	ratio := float64(hits) / float64(hits+misses)
+	return &ratio
+}
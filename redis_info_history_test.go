@@ -0,0 +1,86 @@
+package asynqmon
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRedisInfoHistoryHandler(t *testing.T) {
+	now := time.Now()
+	s := newRedisInfoSampler(nil, time.Minute, time.Hour, nil)
+	s.samples = []*redisInfoSample{
+		{time: now.Add(-50 * time.Minute), nodes: map[string]*redisInfoValues{
+			"a:6379": {UsedMemory: 100, KeyspaceHits: 10, KeyspaceMisses: 10},
+		}},
+		{time: now.Add(-2 * time.Minute), nodes: map[string]*redisInfoValues{
+			"a:6379": {UsedMemory: 100, KeyspaceHits: 10, KeyspaceMisses: 10},
+			"b:6379": {UsedMemory: 200, KeyspaceHits: 0, KeyspaceMisses: 0},
+		}},
+		{time: now.Add(-1 * time.Minute), nodes: map[string]*redisInfoValues{
+			"a:6379": {UsedMemory: 150, KeyspaceHits: 13, KeyspaceMisses: 11},
+			"b:6379": {UsedMemory: 250, KeyspaceHits: 0, KeyspaceMisses: 0},
+			"c:6379": {UsedMemory: 140, KeyspaceHits: 0, KeyspaceMisses: 0},
+		}, replicas: map[string]bool{"c:6379": true}},
+	}
+	h := newRedisInfoHistoryHandlerFunc(s)
+
+	tests := []struct {
+		query       string
+		wantStatus  int
+		wantMemory  []int64
+		wantHitRate []float64 // -1 for null
+	}{
+		{"", http.StatusOK, []int64{100, 300, 400}, []float64{-1, -1, 0.75}},
+		{"?duration=300", http.StatusOK, []int64{300, 400}, []float64{-1, 0.75}},
+		{"?duration=300&node=b:6379", http.StatusOK, []int64{200, 250}, []float64{-1, -1}},
+		{"?duration=300&node=c:6379", http.StatusOK, []int64{0, 140}, []float64{-1, -1}},
+		{"?node=d:6379", http.StatusBadRequest, nil, nil},
+		{"?duration=7200", http.StatusBadRequest, nil, nil},
+	}
+	for _, tc := range tests {
+		w := httptest.NewRecorder()
+		h(w, httptest.NewRequest("GET", "/api/redis_info_history"+tc.query, nil))
+		if w.Code != tc.wantStatus {
+			t.Errorf("GET %q returned status %d, want %d", tc.query, w.Code, tc.wantStatus)
+			continue
+		}
+		if w.Code != http.StatusOK {
+			continue
+		}
+		var resp redisInfoHistoryResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatal(err)
+		}
+		if len(resp.Samples) != len(tc.wantMemory) {
+			t.Errorf("GET %q returned %d samples, want %d", tc.query, len(resp.Samples), len(tc.wantMemory))
+			continue
+		}
+		for i, sample := range resp.Samples {
+			if sample.UsedMemory != tc.wantMemory[i] {
+				t.Errorf("GET %q: samples[%d].UsedMemory = %d, want %d", tc.query, i, sample.UsedMemory, tc.wantMemory[i])
+			}
+			got := -1.0
+			if sample.KeyspaceHitRatio != nil {
+				got = *sample.KeyspaceHitRatio
+			}
+			if got != tc.wantHitRate[i] {
+				t.Errorf("GET %q: samples[%d].KeyspaceHitRatio = %v, want %v", tc.query, i, got, tc.wantHitRate[i])
+			}
+		}
+	}
+}
+
+func TestRedisInfoHistoryHandlerDisabled(t *testing.T) {
+	w := httptest.NewRecorder()
+	newRedisInfoHistoryHandlerFunc(nil)(w, httptest.NewRequest("GET", "/api/redis_info_history", nil))
+	var resp redisInfoHistoryResponse
+	if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+		t.Fatal(err)
+	}
+	if resp.Enabled || len(resp.Samples) != 0 {
+		t.Errorf("response = %+v, want disabled response without samples", resp)
+	}
+}
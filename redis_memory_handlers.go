@@ -0,0 +1,441 @@
+package asynqmon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - http.Handler(s) for redis memory analysis endpoint
+// ****************************************************************************
+
+const (
+	// Default and maximum number of keys to scan per queue.
+	defaultMemorySampleSize = 10000
+	maxMemorySampleSize     = 1000000
+
+	// Default and maximum number of largest payloads to report.
+	defaultLargestPayloads = 10
+	maxLargestPayloads     = 100
+
+	// Number of keys requested per SCAN call.
+	memoryScanCount = 500
+)
+
+type redisMemoryResponse struct {
+	// Maximum number of keys scanned per queue.
+	SampleSize int `json:"sample_size"`
+
+	Queues []*queueMemoryUsage `json:"queues"`
+
+	// Memory usage aggregated over all queues.
+	ByState    []*stateMemoryUsage    `json:"by_state"`
+	ByTaskType []*taskTypeMemoryUsage `json:"by_task_type"`
+
+	// Largest task payloads found in the scanned keys, in descending order of size.
+	LargestPayloads []*taskPayloadSize `json:"largest_payloads"`
+}
+
+type queueMemoryUsage struct {
+	Queue string `json:"queue"`
+	// Number of keys scanned for the queue.
+	ScannedKeys int `json:"scanned_keys"`
+	// Complete is true if all keys of the queue were scanned.
+	// Otherwise the numbers are based on a sample of the keys.
+	Complete bool `json:"complete"`
+	// Total memory used by the scanned keys.
+	MemoryBytes int64 `json:"memory_bytes"`
+	// Approximate memory usage reported by asynq.
+	ApproxMemoryBytes int64 `json:"approx_memory_bytes"`
+
+	ByKeyType  []*keyTypeMemoryUsage  `json:"by_key_type"`
+	ByState    []*stateMemoryUsage    `json:"by_state"`
+	ByTaskType []*taskTypeMemoryUsage `json:"by_task_type"`
+}
+
+// keyTypeMemoryUsage is the memory used by a kind of key (e.g. task hashes, archived zset, unique locks).
+type keyTypeMemoryUsage struct {
+	KeyType     string `json:"key_type"`
+	Keys        int    `json:"keys"`
+	MemoryBytes int64  `json:"memory_bytes"`
+}
+
+// stateMemoryUsage is the memory used by the task hashes in a state.
+type stateMemoryUsage struct {
+	State string `json:"state"`
+	// Number of task hashes scanned and the memory used by them.
+	SampledTasks int   `json:"sampled_tasks"`
+	MemoryBytes  int64 `json:"memory_bytes"`
+	// Total number of tasks in the state and the memory used by them,
+	// extrapolated from the scanned tasks.
+	Tasks                int   `json:"tasks"`
+	EstimatedMemoryBytes int64 `json:"estimated_memory_bytes"`
+}
+
+// taskTypeMemoryUsage is the memory used by the task hashes of a task type.
+type taskTypeMemoryUsage struct {
+	TaskType     string `json:"task_type"`
+	SampledTasks int    `json:"sampled_tasks"`
+	MemoryBytes  int64  `json:"memory_bytes"`
+	PayloadBytes int64  `json:"payload_bytes"`
+}
+
+type taskPayloadSize struct {
+	Queue        string `json:"queue"`
+	TaskID       string `json:"task_id"`
+	TaskType     string `json:"task_type"`
+	State        string `json:"state"`
+	PayloadBytes int    `json:"payload_bytes"`
+	MemoryBytes  int64  `json:"memory_bytes"`
+}
+
+func newRedisMemoryHandlerFunc(rc redis.UniversalClient, inspector *asynq.Inspector) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		sampleSize, err := intQueryParam(r, "sample_size", defaultMemorySampleSize, 1, maxMemorySampleSize)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		top, err := intQueryParam(r, "top", defaultLargestPayloads, 0, maxLargestPayloads)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		span := startSpan(r.Context(), "asynq.Inspector/Queues")
+		qnames, err := inspector.Queues()
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		if q := r.URL.Query().Get("queue"); q != "" {
+			if !containsString(qnames, q) {
+				writeErrorResponse(w, r, fmt.Errorf("%w: %q", asynq.ErrQueueNotFound, q))
+				return
+			}
+			qnames = []string{q}
+		}
+
+		resp := redisMemoryResponse{SampleSize: sampleSize}
+		byState := make(map[string]*stateMemoryUsage)
+		byTaskType := make(map[string]*taskTypeMemoryUsage)
+		var payloads []*taskPayloadSize
+		for _, qname := range qnames {
+			span := startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
+			qinfo, err := inspector.GetQueueInfo(qname)
+			endSpan(span, err)
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			node, err := queueNodeClient(r.Context(), rc, qname)
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			usage, qpayloads, err := analyzeQueueMemory(r.Context(), node, qinfo, sampleSize)
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			resp.Queues = append(resp.Queues, usage)
+			payloads = append(payloads, qpayloads...)
+			for _, s := range usage.ByState {
+				addStateMemoryUsage(byState, s)
+			}
+			for _, t := range usage.ByTaskType {
+				addTaskTypeMemoryUsage(byTaskType, t)
+			}
+		}
+		resp.ByState = sortedStateMemoryUsage(byState)
+		resp.ByTaskType = sortedTaskTypeMemoryUsage(byTaskType)
+		sort.Slice(payloads, func(i, j int) bool { return payloads[i].PayloadBytes > payloads[j].PayloadBytes })
+		if len(payloads) > top {
+			payloads = payloads[:top]
+		}
+		resp.LargestPayloads = payloads
+		writeResponseJSON(w, resp)
+	}
+}
+
+// queueNodeClient returns the client connected to the redis server which has the keys of the queue.
+// All keys of a queue have the same hash tag, so they are stored on the same node in a cluster.
+func queueNodeClient(ctx context.Context, rc redis.UniversalClient, qname string) (*redis.Client, error) {
+	switch c := rc.(type) {
+	case *redis.ClusterClient:
+		return c.MasterForKey(ctx, queueKeyPrefix(qname))
+	case *redis.Client:
+		return c, nil
+	}
+	return nil, fmt.Errorf("unsupported redis client type %T", rc)
+}
+
+// queueKeyPrefix returns the prefix of all redis keys of the queue used by asynq.
+func queueKeyPrefix(qname string) string {
+	return fmt.Sprintf("asynq:{%s}:", qname)
+}
+
+// analyzeQueueMemory scans up to limit keys of the queue and returns the memory used by them.
+// It also returns the payload sizes of the scanned tasks.
+func analyzeQueueMemory(ctx context.Context, node *redis.Client, qinfo *asynq.QueueInfo, limit int) (*queueMemoryUsage, []*taskPayloadSize, error) {
+	prefix := queueKeyPrefix(qinfo.Queue)
+	usage := &queueMemoryUsage{Queue: qinfo.Queue, ApproxMemoryBytes: qinfo.MemoryUsage}
+	byKeyType := make(map[string]*keyTypeMemoryUsage)
+	byState := make(map[string]*stateMemoryUsage)
+	byTaskType := make(map[string]*taskTypeMemoryUsage)
+	var payloads []*taskPayloadSize
+
+	var cursor uint64
+	for {
+		keys, next, err := node.Scan(ctx, cursor, escapeGlob(prefix)+"*", memoryScanCount).Result()
+		if err != nil {
+			return nil, nil, err
+		}
+		truncated := false
+		if n := limit - usage.ScannedKeys; len(keys) > n {
+			keys = keys[:n]
+			truncated = true
+		}
+		mems := make([]*redis.IntCmd, len(keys))
+		tasks := make(map[int]*redis.SliceCmd)
+		_, err = node.Pipelined(ctx, func(p redis.Pipeliner) error {
+			for i, key := range keys {
+				mems[i] = p.MemoryUsage(ctx, key)
+				if keyType(prefix, key) == "task" {
+					tasks[i] = p.HMGet(ctx, key, "state", "msg")
+				}
+			}
+			return nil
+		})
+		if err != nil && err != redis.Nil {
+			return nil, nil, err
+		}
+		for i, key := range keys {
+			mem, err := mems[i].Result()
+			if err == redis.Nil {
+				continue // key was deleted after SCAN
+			}
+			if err != nil {
+				return nil, nil, err
+			}
+			usage.ScannedKeys++
+			usage.MemoryBytes += mem
+			kt := keyType(prefix, key)
+			k, ok := byKeyType[kt]
+			if !ok {
+				k = &keyTypeMemoryUsage{KeyType: kt}
+				byKeyType[kt] = k
+			}
+			k.Keys++
+			k.MemoryBytes += mem
+
+			cmd, ok := tasks[i]
+			if !ok {
+				continue
+			}
+			vals, err := cmd.Result()
+			if err != nil || len(vals) != 2 {
+				continue
+			}
+			state, _ := vals[0].(string)
+			msg, _ := vals[1].(string)
+			taskType, payloadSize := decodeTaskTypeAndPayloadSize([]byte(msg))
+			addStateMemoryUsage(byState, &stateMemoryUsage{State: state, SampledTasks: 1, MemoryBytes: mem})
+			addTaskTypeMemoryUsage(byTaskType, &taskTypeMemoryUsage{TaskType: taskType, SampledTasks: 1, MemoryBytes: mem, PayloadBytes: int64(payloadSize)})
+			payloads = append(payloads, &taskPayloadSize{
+				Queue:        qinfo.Queue,
+				TaskID:       strings.TrimPrefix(key, prefix+"t:"),
+				TaskType:     taskType,
+				State:        state,
+				PayloadBytes: payloadSize,
+				MemoryBytes:  mem,
+			})
+		}
+		cursor = next
+		if cursor == 0 && !truncated {
+			usage.Complete = true
+			break
+		}
+		if cursor == 0 || usage.ScannedKeys >= limit {
+			break
+		}
+	}
+
+	extrapolateStateMemoryUsage(byState, qinfo)
+
+	for _, k := range byKeyType {
+		usage.ByKeyType = append(usage.ByKeyType, k)
+	}
+	sort.Slice(usage.ByKeyType, func(i, j int) bool {
+		return usage.ByKeyType[i].MemoryBytes > usage.ByKeyType[j].MemoryBytes
+	})
+	usage.ByState = sortedStateMemoryUsage(byState)
+	usage.ByTaskType = sortedTaskTypeMemoryUsage(byTaskType)
+	return usage, payloads, nil
+}
+
+var uuidSuffix = regexp.MustCompile(`:[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}$`)
+
+// keyType returns the kind of the redis key used by asynq for the queue with the key prefix.
+// See github.com/hibiken/asynq/internal/base for the key names.
+func keyType(prefix, key string) string {
+	name := strings.TrimPrefix(key, prefix)
+	switch name {
+	case "pending", "active", "scheduled", "retry", "archived", "completed", "lease", "paused", "groups", "aggregation_sets":
+		return name
+	case "processed", "failed":
+		return "stats"
+	}
+	switch {
+	case strings.HasPrefix(name, "t:"):
+		return "task"
+	case strings.HasPrefix(name, "processed:"), strings.HasPrefix(name, "failed:"):
+		return "stats"
+	case strings.HasPrefix(name, "unique:"):
+		return "unique"
+	case strings.HasPrefix(name, "g:"):
+		if uuidSuffix.MatchString(name) {
+			return "aggregation_set"
+		}
+		return "group"
+	}
+	return "other"
+}
+
+// decodeTaskTypeAndPayloadSize returns the task type and the size of the payload
+// in the protobuf encoded task message stored in the task hash.
+func decodeTaskTypeAndPayloadSize(msg []byte) (taskType string, payloadSize int) {
+	for len(msg) > 0 {
+		num, typ, n := protowire.ConsumeTag(msg)
+		if n < 0 {
+			return taskType, payloadSize
+		}
+		msg = msg[n:]
+		switch {
+		case num == 1 && typ == protowire.BytesType: // type
+			v, n := protowire.ConsumeBytes(msg)
+			if n < 0 {
+				return taskType, payloadSize
+			}
+			taskType = string(v)
+			msg = msg[n:]
+		case num == 2 && typ == protowire.BytesType: // payload
+			v, n := protowire.ConsumeBytes(msg)
+			if n < 0 {
+				return taskType, payloadSize
+			}
+			payloadSize = len(v)
+			msg = msg[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, msg)
+			if n < 0 {
+				return taskType, payloadSize
+			}
+			msg = msg[n:]
+		}
+	}
+	return taskType, payloadSize
+}
+
+func addStateMemoryUsage(m map[string]*stateMemoryUsage, u *stateMemoryUsage) {
+	s, ok := m[u.State]
+	if !ok {
+		s = &stateMemoryUsage{State: u.State}
+		m[u.State] = s
+	}
+	s.SampledTasks += u.SampledTasks
+	s.MemoryBytes += u.MemoryBytes
+	s.Tasks += u.Tasks
+	s.EstimatedMemoryBytes += u.EstimatedMemoryBytes
+}
+
+// extrapolateStateMemoryUsage sets the number of tasks in each state of the queue and
+// estimates the memory used by them from the scanned tasks.
+func extrapolateStateMemoryUsage(byState map[string]*stateMemoryUsage, qinfo *asynq.QueueInfo) {
+	counts := map[string]int{
+		"active":      qinfo.Active,
+		"pending":     qinfo.Pending,
+		"aggregating": qinfo.Aggregating,
+		"scheduled":   qinfo.Scheduled,
+		"retry":       qinfo.Retry,
+		"archived":    qinfo.Archived,
+		"completed":   qinfo.Completed,
+	}
+	for state, n := range counts {
+		if n == 0 {
+			continue
+		}
+		s, ok := byState[state]
+		if !ok {
+			s = &stateMemoryUsage{State: state}
+			byState[state] = s
+		}
+		s.Tasks = n
+	}
+	for _, s := range byState {
+		if s.SampledTasks > 0 {
+			s.EstimatedMemoryBytes = s.MemoryBytes * int64(s.Tasks) / int64(s.SampledTasks)
+		}
+	}
+}
+
+func addTaskTypeMemoryUsage(m map[string]*taskTypeMemoryUsage, u *taskTypeMemoryUsage) {
+	t, ok := m[u.TaskType]
+	if !ok {
+		t = &taskTypeMemoryUsage{TaskType: u.TaskType}
+		m[u.TaskType] = t
+	}
+	t.SampledTasks += u.SampledTasks
+	t.MemoryBytes += u.MemoryBytes
+	t.PayloadBytes += u.PayloadBytes
+}
+
+func sortedStateMemoryUsage(m map[string]*stateMemoryUsage) []*stateMemoryUsage {
+	res := make([]*stateMemoryUsage, 0, len(m))
+	for _, s := range m {
+		res = append(res, s)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].EstimatedMemoryBytes != res[j].EstimatedMemoryBytes {
+			return res[i].EstimatedMemoryBytes > res[j].EstimatedMemoryBytes
+		}
+		return res[i].State < res[j].State
+	})
+	return res
+}
+
+func sortedTaskTypeMemoryUsage(m map[string]*taskTypeMemoryUsage) []*taskTypeMemoryUsage {
+	res := make([]*taskTypeMemoryUsage, 0, len(m))
+	for _, t := range m {
+		res = append(res, t)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].MemoryBytes != res[j].MemoryBytes {
+			return res[i].MemoryBytes > res[j].MemoryBytes
+		}
+		return res[i].TaskType < res[j].TaskType
+	})
+	return res
+}
+
+// escapeGlob escapes the special characters of the glob-style pattern used by SCAN.
+func escapeGlob(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch c {
+		case '*', '?', '[', ']', '\\':
+			b.WriteByte('\\')
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
@@ -0,0 +1,109 @@
+package asynqmon
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hibiken/asynq"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestKeyType(t *testing.T) {
+	prefix := queueKeyPrefix("default")
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"asynq:{default}:t:7b5e60b4-2516-49f3-82e8-42359527970a", "task"},
+		{"asynq:{default}:archived", "archived"},
+		{"asynq:{default}:processed:2022-04-11", "stats"},
+		{"asynq:{default}:unique:email:474bec16fe0454331478fa1e4ed478e0", "unique"},
+		{"asynq:{default}:g:daily-digest", "group"},
+		{"asynq:{default}:g:daily-digest:319e3346-bad2-452c-83ab-891c0b57cfc7", "aggregation_set"},
+		{"asynq:{default}:unknown", "other"},
+	}
+	for _, tc := range tests {
+		if got := keyType(prefix, tc.key); got != tc.want {
+			t.Errorf("keyType(%q, %q) = %q, want %q", prefix, tc.key, got, tc.want)
+		}
+	}
+}
+
+// encodeTaskMessage encodes the fields of asynq's TaskMessage used in the tests
+// in the same wire format as asynq.
+func encodeTaskMessage(taskType string, payload []byte) []byte {
+	var b []byte
+	b = protowire.AppendTag(b, 1, protowire.BytesType) // type
+	b = protowire.AppendString(b, taskType)
+	b = protowire.AppendTag(b, 2, protowire.BytesType) // payload
+	b = protowire.AppendBytes(b, payload)
+	b = protowire.AppendTag(b, 3, protowire.BytesType) // id
+	b = protowire.AppendString(b, "7b5e60b4-2516-49f3-82e8-42359527970a")
+	b = protowire.AppendTag(b, 4, protowire.BytesType) // queue
+	b = protowire.AppendString(b, "default")
+	b = protowire.AppendTag(b, 5, protowire.VarintType) // retry
+	b = protowire.AppendVarint(b, 25)
+	b = protowire.AppendTag(b, 11, protowire.VarintType) // last_failed_at
+	b = protowire.AppendVarint(b, 1649635200)
+	return b
+}
+
+func TestDecodeTaskTypeAndPayloadSize(t *testing.T) {
+	msg := encodeTaskMessage("email:send", []byte(`{"to":"user@example.com"}`))
+	tests := []struct {
+		desc        string
+		msg         []byte
+		wantType    string
+		wantPayload int
+	}{
+		{"full message", msg, "email:send", 25},
+		{"message without payload", encodeTaskMessage("email:send", nil), "email:send", 0},
+		// The type is decoded even if the rest of the message is truncated.
+		{"truncated message", msg[:15], "email:send", 0},
+		{"empty message", nil, "", 0},
+		{"invalid message", []byte{0xff}, "", 0},
+	}
+	for _, tc := range tests {
+		taskType, payloadSize := decodeTaskTypeAndPayloadSize(tc.msg)
+		if taskType != tc.wantType || payloadSize != tc.wantPayload {
+			t.Errorf("%s: decodeTaskTypeAndPayloadSize returned %q, %d, want %q, %d", tc.desc, taskType, payloadSize, tc.wantType, tc.wantPayload)
+		}
+	}
+}
+
+func TestStateAndTaskTypeMemoryUsage(t *testing.T) {
+	byState := make(map[string]*stateMemoryUsage)
+	byTaskType := make(map[string]*taskTypeMemoryUsage)
+	sampled := []struct {
+		state, taskType string
+		mem, payload    int64
+	}{
+		{"archived", "email:send", 300, 100},
+		{"archived", "email:send", 500, 300},
+		{"archived", "sms:send", 200, 50},
+		{"pending", "email:send", 400, 200},
+	}
+	for _, s := range sampled {
+		addStateMemoryUsage(byState, &stateMemoryUsage{State: s.state, SampledTasks: 1, MemoryBytes: s.mem})
+		addTaskTypeMemoryUsage(byTaskType, &taskTypeMemoryUsage{TaskType: s.taskType, SampledTasks: 1, MemoryBytes: s.mem, PayloadBytes: s.payload})
+	}
+	extrapolateStateMemoryUsage(byState, &asynq.QueueInfo{Queue: "default", Archived: 30, Pending: 2, Retry: 5})
+
+	wantStates := []*stateMemoryUsage{
+		// 3 of 30 archived tasks use 1000 bytes.
+		{State: "archived", SampledTasks: 3, MemoryBytes: 1000, Tasks: 30, EstimatedMemoryBytes: 10000},
+		{State: "pending", SampledTasks: 1, MemoryBytes: 400, Tasks: 2, EstimatedMemoryBytes: 800},
+		// No retry task was sampled, so the memory cannot be estimated.
+		{State: "retry", Tasks: 5},
+	}
+	if diff := cmp.Diff(wantStates, sortedStateMemoryUsage(byState)); diff != "" {
+		t.Errorf("memory usage by state mismatch (-want,+got)\n%s", diff)
+	}
+	wantTaskTypes := []*taskTypeMemoryUsage{
+		{TaskType: "email:send", SampledTasks: 3, MemoryBytes: 1200, PayloadBytes: 600},
+		{TaskType: "sms:send", SampledTasks: 1, MemoryBytes: 200, PayloadBytes: 50},
+	}
+	if diff := cmp.Diff(wantTaskTypes, sortedTaskTypeMemoryUsage(byTaskType)); diff != "" {
+		t.Errorf("memory usage by task type mismatch (-want,+got)\n%s", diff)
+	}
+}
@@ -0,0 +1,354 @@
+package asynqmon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - saved views: named presets of a task list shared through stable URLs
+//   - http.Handler(s) for saved view related endpoints
+// ****************************************************************************
+
+// Visibility of a saved view.
+const (
+	// Only the owner can see the view.
+	viewVisibilityPrivate = "private"
+	// Everyone can see the view, only the owner can update or delete it.
+	viewVisibilityTeam = "team"
+)
+
+const (
+	savedViewsKey = "asynqmon:views"
+
+	maxViewNameLength = 200
+	maxViewFilters    = 20
+	maxViewPageSize   = 1000
+)
+
+// Task states a saved view can list.
+var viewTaskStates = []string{"active", "pending", "aggregating", "scheduled", "retry", "archived", "completed"}
+
+// savedView is a named preset of a task list.
+type savedView struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	Queue string `json:"queue"`
+	State string `json:"state"`
+	// Filter parameters of the task list, kept as given (e.g. {"type": "payments:*"}).
+	Filters  map[string]string `json:"filters"`
+	Sort     string            `json:"sort"`
+	PageSize int               `json:"page_size"`
+	// Owner is the user who created the view, empty if the user is not authenticated
+	// (see Options.TrustIdentityHeaders).
+	Owner      string    `json:"owner"`
+	Visibility string    `json:"visibility"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// visibleTo reports whether the user can see the view.
+// Private views are visible only to their authenticated owner.
+func (v *savedView) visibleTo(user string) bool {
+	return v.Visibility == viewVisibilityTeam || (user != "" && v.Owner == user)
+}
+
+var errViewNotFound = errors.New("view not found")
+
+// viewStore stores saved views in a single redis hash, mapping the view ID to the view encoded in JSON:
+//
+//	asynqmon:views
+type viewStore struct {
+	rc redis.UniversalClient
+	// Whether the user in the identity headers of the requests is trusted.
+	trustIdentity bool
+}
+
+func newViewStore(rc redis.UniversalClient, trustIdentity bool) *viewStore {
+	return &viewStore{rc: rc, trustIdentity: trustIdentity}
+}
+
+// user returns the authenticated user who made the request, or an empty string
+// if the user is unknown or the identity headers are not trusted.
+func (s *viewStore) user(r *http.Request) string {
+	if !s.trustIdentity {
+		return ""
+	}
+	return requestUser(r)
+}
+
+func (s *viewStore) get(ctx context.Context, id string) (*savedView, error) {
+	data, err := s.rc.HGet(ctx, savedViewsKey, id).Result()
+	if err == redis.Nil {
+		return nil, errViewNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	var v savedView
+	if err := json.Unmarshal([]byte(data), &v); err != nil {
+		return nil, fmt.Errorf("could not decode view %q: %v", data, err)
+	}
+	return &v, nil
+}
+
+// list returns all views, sorted by name.
+func (s *viewStore) list(ctx context.Context) ([]*savedView, error) {
+	all, err := s.rc.HGetAll(ctx, savedViewsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make([]*savedView, 0, len(all))
+	for _, data := range all {
+		var v savedView
+		if err := json.Unmarshal([]byte(data), &v); err != nil {
+			return nil, fmt.Errorf("could not decode view %q: %v", data, err)
+		}
+		res = append(res, &v)
+	}
+	sort.Slice(res, func(i, j int) bool {
+		if res[i].Name != res[j].Name {
+			return res[i].Name < res[j].Name
+		}
+		return res[i].ID < res[j].ID
+	})
+	return res, nil
+}
+
+func (s *viewStore) save(ctx context.Context, v *savedView) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return s.rc.HSet(ctx, savedViewsKey, v.ID, data).Err()
+}
+
+func (s *viewStore) remove(ctx context.Context, id string) error {
+	return s.rc.HDel(ctx, savedViewsKey, id).Err()
+}
+
+// viewRequest is the request body to create or update a view.
+type viewRequest struct {
+	Name       string            `json:"name"`
+	Queue      string            `json:"queue"`
+	State      string            `json:"state"`
+	Filters    map[string]string `json:"filters"`
+	Sort       string            `json:"sort"`
+	PageSize   int               `json:"page_size"`
+	Visibility string            `json:"visibility"`
+}
+
+// decodeViewRequest decodes the request body to create or update a view made by the user,
+// or writes an error response and returns nil if it is invalid.
+// The visibility defaults to private if the user is authenticated, and to team otherwise.
+func decodeViewRequest(w http.ResponseWriter, r *http.Request, user string) *viewRequest {
+	r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	var req viewRequest
+	if err := dec.Decode(&req); err != nil {
+		writeBadRequest(w, r, "invalid request body: %v", err)
+		return nil
+	}
+	if req.Visibility == "" && user == "" {
+		req.Visibility = viewVisibilityTeam
+	}
+	if err := req.validate(); err != nil {
+		writeBadRequest(w, r, "%v", err)
+		return nil
+	}
+	if req.Visibility == viewVisibilityPrivate && user == "" {
+		writeBadRequest(w, r, "private views require an authenticated user")
+		return nil
+	}
+	return &req
+}
+
+// validate normalizes the request and returns an error if it is invalid.
+func (req *viewRequest) validate() error {
+	req.Name = strings.TrimSpace(req.Name)
+	if req.Name == "" {
+		return errors.New("name cannot be empty")
+	}
+	if len(req.Name) > maxViewNameLength {
+		return fmt.Errorf("name cannot be longer than %d bytes", maxViewNameLength)
+	}
+	if req.Queue = strings.TrimSpace(req.Queue); req.Queue == "" {
+		return errors.New("queue cannot be empty")
+	}
+	if !containsString(viewTaskStates, req.State) {
+		return fmt.Errorf("state must be one of %s", strings.Join(viewTaskStates, ", "))
+	}
+	if len(req.Filters) > maxViewFilters {
+		return fmt.Errorf("cannot have more than %d filters", maxViewFilters)
+	}
+	for k := range req.Filters {
+		if strings.TrimSpace(k) == "" {
+			return errors.New("filter name cannot be empty")
+		}
+	}
+	if req.PageSize < 0 || req.PageSize > maxViewPageSize {
+		return fmt.Errorf("page_size must be between 0 and %d", maxViewPageSize)
+	}
+	switch req.Visibility {
+	case "":
+		req.Visibility = viewVisibilityPrivate
+	case viewVisibilityPrivate, viewVisibilityTeam:
+	default:
+		return fmt.Errorf("visibility must be %q or %q", viewVisibilityPrivate, viewVisibilityTeam)
+	}
+	return nil
+}
+
+// apply sets the fields of the view from the request.
+func (req *viewRequest) apply(v *savedView) {
+	v.Name = req.Name
+	v.Queue = req.Queue
+	v.State = req.State
+	v.Filters = req.Filters
+	if v.Filters == nil {
+		v.Filters = make(map[string]string)
+	}
+	v.Sort = req.Sort
+	v.PageSize = req.PageSize
+	v.Visibility = req.Visibility
+}
+
+type listViewsResponse struct {
+	Views []*savedView `json:"views"`
+	// Authenticated user, empty if unknown. Only authenticated users can have private views.
+	User string `json:"user"`
+}
+
+// newListViewsHandlerFunc returns the views visible to the user: the team views and the user's own private views.
+//
+// Optional query params:
+// `queue`: only return the views of the queue
+func newListViewsHandlerFunc(vs *viewStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		views, err := vs.list(r.Context())
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		user := vs.user(r)
+		qname := r.URL.Query().Get("queue")
+		res := make([]*savedView, 0, len(views))
+		for _, v := range views {
+			if v.visibleTo(user) && (qname == "" || v.Queue == qname) {
+				res = append(res, v)
+			}
+		}
+		writeResponseJSON(w, listViewsResponse{Views: res, User: user})
+	}
+}
+
+// getVisibleView returns the view with the ID in the route variables, or writes an error response
+// and returns nil if it doesn't exist or is not visible to the user.
+// Private views of other users are reported as not found.
+func getVisibleView(w http.ResponseWriter, r *http.Request, vs *viewStore) *savedView {
+	id := mux.Vars(r)["view_id"]
+	v, err := vs.get(r.Context(), id)
+	if err == nil && !v.visibleTo(vs.user(r)) {
+		err = errViewNotFound
+	}
+	if err == errViewNotFound {
+		writeError(w, r, http.StatusNotFound, errCodeNotFound, fmt.Sprintf("view %q not found", id))
+		return nil
+	}
+	if err != nil {
+		writeErrorResponse(w, r, err)
+		return nil
+	}
+	return v
+}
+
+// getOwnedView is like getVisibleView, but also writes an error response and returns nil
+// if the user is not the owner of the view.
+func getOwnedView(w http.ResponseWriter, r *http.Request, vs *viewStore) *savedView {
+	v := getVisibleView(w, r, vs)
+	if v == nil {
+		return nil
+	}
+	if v.Owner != vs.user(r) {
+		writeError(w, r, http.StatusForbidden, errCodePermissionDenied, fmt.Sprintf("view %q is owned by %q", v.ID, v.Owner))
+		return nil
+	}
+	return v
+}
+
+func newGetViewHandlerFunc(vs *viewStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if v := getVisibleView(w, r, vs); v != nil {
+			writeResponseJSON(w, v)
+		}
+	}
+}
+
+func newCreateViewHandlerFunc(vs *viewStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		user := vs.user(r)
+		req := decodeViewRequest(w, r, user)
+		if req == nil {
+			return
+		}
+		now := time.Now().UTC()
+		v := &savedView{
+			ID:        newRequestID(),
+			Owner:     user,
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+		req.apply(v)
+		if err := vs.save(r.Context(), v); err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeResponseJSON(w, v)
+	}
+}
+
+// newUpdateViewHandlerFunc replaces the fields of the view with the request body. Only the owner can update a view.
+func newUpdateViewHandlerFunc(vs *viewStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		req := decodeViewRequest(w, r, vs.user(r))
+		if req == nil {
+			return
+		}
+		v := getOwnedView(w, r, vs)
+		if v == nil {
+			return
+		}
+		req.apply(v)
+		v.UpdatedAt = time.Now().UTC()
+		if err := vs.save(r.Context(), v); err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		writeResponseJSON(w, v)
+	}
+}
+
+func newDeleteViewHandlerFunc(vs *viewStore) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		v := getOwnedView(w, r, vs)
+		if v == nil {
+			return
+		}
+		if err := vs.remove(r.Context(), v.ID); err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
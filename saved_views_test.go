@@ -0,0 +1,119 @@
+package asynqmon
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestViewRequestValidate(t *testing.T) {
+	req := viewRequest{Name: " Failed payments ", Queue: "critical", State: "archived", Filters: map[string]string{"type": "payments:*"}}
+	if err := req.validate(); err != nil {
+		t.Fatalf("validate() returned error: %v", err)
+	}
+	want := viewRequest{Name: "Failed payments", Queue: "critical", State: "archived", Filters: map[string]string{"type": "payments:*"}, Visibility: viewVisibilityPrivate}
+	if diff := cmp.Diff(want, req); diff != "" {
+		t.Errorf("validate() normalized request mismatch (-want,+got)\n%s", diff)
+	}
+
+	tests := []struct {
+		desc string
+		req  viewRequest
+	}{
+		{"empty name", viewRequest{Name: " ", Queue: "critical", State: "archived"}},
+		{"long name", viewRequest{Name: strings.Repeat("x", maxViewNameLength+1), Queue: "critical", State: "archived"}},
+		{"empty queue", viewRequest{Name: "v", State: "archived"}},
+		{"invalid state", viewRequest{Name: "v", Queue: "critical", State: "dead"}},
+		{"empty filter name", viewRequest{Name: "v", Queue: "critical", State: "archived", Filters: map[string]string{"": "x"}}},
+		{"negative page size", viewRequest{Name: "v", Queue: "critical", State: "archived", PageSize: -1}},
+		{"invalid visibility", viewRequest{Name: "v", Queue: "critical", State: "archived", Visibility: "public"}},
+	}
+	for _, tc := range tests {
+		if err := tc.req.validate(); err == nil {
+			t.Errorf("%s: validate() returned nil, want error", tc.desc)
+		}
+	}
+}
+
+func TestSavedViewVisibleTo(t *testing.T) {
+	tests := []struct {
+		view savedView
+		user string
+		want bool
+	}{
+		{savedView{Owner: "alice", Visibility: viewVisibilityPrivate}, "alice", true},
+		{savedView{Owner: "alice", Visibility: viewVisibilityPrivate}, "bob", false},
+		{savedView{Owner: "alice", Visibility: viewVisibilityTeam}, "bob", true},
+		// Private views without an authenticated owner are not visible to anyone.
+		{savedView{Owner: "", Visibility: viewVisibilityPrivate}, "", false},
+		{savedView{Owner: "", Visibility: viewVisibilityTeam}, "", true},
+	}
+	for _, tc := range tests {
+		if got := tc.view.visibleTo(tc.user); got != tc.want {
+			t.Errorf("view owned by %q with visibility %q visibleTo(%q) = %t, want %t", tc.view.Owner, tc.view.Visibility, tc.user, got, tc.want)
+		}
+	}
+}
+
+func TestViewHandlersRejectInvalidBody(t *testing.T) {
+	// The request is rejected before reaching redis.
+	vs := newViewStore(nil, false)
+	for _, body := range []string{
+		`{"name":"v","queue":"critical","state":"archived","visibility":"private","owner":"alice"}`,
+		`{"name":"` + strings.Repeat("x", maxRequestBodySize) + `","queue":"critical","state":"archived"}`,
+		`not json`,
+	} {
+		for _, tc := range []struct {
+			method string
+			h      http.HandlerFunc
+		}{
+			{"POST", newCreateViewHandlerFunc(vs)},
+			{"PUT", newUpdateViewHandlerFunc(vs)},
+		} {
+			w := httptest.NewRecorder()
+			tc.h(w, httptest.NewRequest(tc.method, "/api/views/abc", strings.NewReader(body)))
+			if w.Code != http.StatusBadRequest {
+				t.Errorf("%s /api/views with %.50q returned status %d, want %d", tc.method, body, w.Code, http.StatusBadRequest)
+			}
+		}
+	}
+}
+
+func TestViewStoreUserSpoofing(t *testing.T) {
+	spoofed := []func(r *http.Request){
+		func(r *http.Request) { r.Header.Set("X-Forwarded-User", "alice") },
+		func(r *http.Request) { r.Header.Set("X-Auth-Request-User", "alice") },
+		func(r *http.Request) { r.SetBasicAuth("alice", "not-checked") },
+	}
+	for _, spoof := range spoofed {
+		r := httptest.NewRequest("GET", "/api/views", nil)
+		spoof(r)
+		if got := newViewStore(nil, false).user(r); got != "" {
+			t.Errorf("user() without trusted identity headers = %q, want empty", got)
+		}
+		if got := newViewStore(nil, true).user(r); got != "alice" {
+			t.Errorf("user() with trusted identity headers = %q, want %q", got, "alice")
+		}
+	}
+
+	// The request is rejected before reaching redis.
+	body := `{"name":"v","queue":"critical","state":"archived","visibility":"private"}`
+	for _, tc := range []struct {
+		method string
+		h      http.HandlerFunc
+	}{
+		{"POST", newCreateViewHandlerFunc(newViewStore(nil, false))},
+		{"PUT", newUpdateViewHandlerFunc(newViewStore(nil, false))},
+	} {
+		r := httptest.NewRequest(tc.method, "/api/views/abc", strings.NewReader(body))
+		r.Header.Set("X-Forwarded-User", "alice")
+		w := httptest.NewRecorder()
+		tc.h(w, r)
+		if w.Code != http.StatusBadRequest {
+			t.Errorf("%s /api/views of a private view with a spoofed user returned status %d, want %d", tc.method, w.Code, http.StatusBadRequest)
+		}
+	}
+}
@@ -16,9 +16,11 @@ import (
 
 func newListSchedulerEntriesHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		span := startSpan(r.Context(), "asynq.Inspector/SchedulerEntries")
 		entries, err := inspector.SchedulerEntries()
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		payload := make(map[string]interface{})
@@ -29,7 +31,7 @@ func newListSchedulerEntriesHandlerFunc(inspector *asynq.Inspector, pf PayloadFo
 			payload["entries"] = toSchedulerEntries(entries, pf)
 		}
 		if err := json.NewEncoder(w).Encode(payload); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 	}
@@ -43,17 +45,19 @@ func newListSchedulerEnqueueEventsHandlerFunc(inspector *asynq.Inspector) http.H
 	return func(w http.ResponseWriter, r *http.Request) {
 		entryID := mux.Vars(r)["entry_id"]
 		pageSize, pageNum := getPageOptions(r)
+		span := startSpan(r.Context(), "asynq.Inspector/ListSchedulerEnqueueEvents")
 		events, err := inspector.ListSchedulerEnqueueEvents(
 			entryID, asynq.PageSize(pageSize), asynq.Page(pageNum))
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		resp := listSchedulerEnqueueEventsResponse{
 			Events: toSchedulerEnqueueEvents(events),
 		}
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 	}
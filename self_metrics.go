@@ -0,0 +1,145 @@
+package asynqmon
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - prometheus metrics about asynqmon itself (API requests, redis commands
+//     and bulk operations)
+// ****************************************************************************
+
+const metricsNamespace = "asynqmon"
+
+// selfMetrics holds the prometheus collectors for the metrics about asynqmon itself.
+type selfMetrics struct {
+	rootPath string
+
+	apiRequestDuration   *prometheus.HistogramVec
+	apiRequestErrors     *prometheus.CounterVec
+	redisCommandDuration *prometheus.HistogramVec
+	bulkOperations       *prometheus.CounterVec
+}
+
+// newSelfMetrics creates the collectors and registers them with reg.
+// It panics if the registration fails.
+func newSelfMetrics(reg prometheus.Registerer, rootPath string) *selfMetrics {
+	m := &selfMetrics{
+		rootPath: rootPath,
+		apiRequestDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "api_request_duration_seconds",
+			Help:      "Latency of API requests in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method"}),
+		apiRequestErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "api_request_errors_total",
+			Help:      "Number of API requests which resulted in an error response.",
+		}, []string{"route", "method", "code"}),
+		redisCommandDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "redis_command_duration_seconds",
+			Help:      "Latency of redis commands in seconds. Pipelined commands are observed as a single \"pipeline\" command.",
+			Buckets:   []float64{.0005, .001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5},
+		}, []string{"command"}),
+		bulkOperations: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "bulk_operations_total",
+			Help:      "Number of successful bulk operations (e.g. run_all, batch_delete) performed on tasks.",
+		}, []string{"operation", "state"}),
+	}
+	reg.MustRegister(m.apiRequestDuration, m.apiRequestErrors, m.redisCommandDuration, m.bulkOperations)
+	return m
+}
+
+// instrumentAPI is a middleware function to record the latency and errors of API requests.
+// Requests are labeled with the route template (e.g. /api/queues/{qname}) to keep the cardinality low.
+func (m *selfMetrics) instrumentAPI(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+
+		route := routeTemplate(r, m.rootPath)
+		m.apiRequestDuration.WithLabelValues(route, r.Method).Observe(time.Since(start).Seconds())
+		if rec.status >= 400 {
+			m.apiRequestErrors.WithLabelValues(route, r.Method, strconv.Itoa(rec.status)).Inc()
+			return
+		}
+		if op, state, ok := bulkOperation(r.Method, route); ok {
+			m.bulkOperations.WithLabelValues(op, state).Inc()
+		}
+	})
+}
+
+// bulkOperation reports whether the request with the method and the route template
+// is for a bulk operation on tasks and returns the operation and the task state.
+// For example, "/api/queues/{qname}/retry_tasks:run_all" returns ("run_all", "retry").
+//
+// Operations on all tasks in an error cluster, flushing groups and canceling flagged
+// workers are bulk operations as well.
+func bulkOperation(method, route string) (op, state string, ok bool) {
+	switch {
+	case route == "/api/workers:cancel_flagged":
+		return "cancel_flagged", "active", true
+	case strings.HasSuffix(route, "/groups:flush"):
+		return "flush_groups", "aggregating", true
+	case strings.Contains(route, "/error_clusters/{cluster_id}"):
+		state = taskStateInRoute(route)
+		switch {
+		case strings.HasSuffix(route, "}:run"):
+			return "run_error_cluster", state, true
+		case strings.HasSuffix(route, "}:archive"):
+			return "archive_error_cluster", state, true
+		case strings.HasSuffix(route, "}") && method == http.MethodDelete:
+			return "delete_error_cluster", state, true
+		}
+		return "", "", false
+	}
+	i := strings.LastIndex(route, ":")
+	if i < 0 {
+		return "", "", false
+	}
+	op = route[i+1:]
+	if !strings.HasSuffix(op, "_all") && !strings.HasPrefix(op, "batch_") {
+		return "", "", false
+	}
+	state = route[strings.LastIndex(route[:i], "/")+1 : i]
+	return op, strings.TrimSuffix(state, "_tasks"), true
+}
+
+// redisMetricsHook is a redis.Hook to record the latency of redis commands.
+type redisMetricsHook struct {
+	duration *prometheus.HistogramVec
+}
+
+func (h redisMetricsHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (h redisMetricsHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmd)
+		h.duration.WithLabelValues(cmd.Name()).Observe(time.Since(start).Seconds())
+		return err
+	}
+}
+
+func (h redisMetricsHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		start := time.Now()
+		err := next(ctx, cmds)
+		h.duration.WithLabelValues("pipeline").Observe(time.Since(start).Seconds())
+		return err
+	}
+}
@@ -0,0 +1,33 @@
+package asynqmon
+
+import "testing"
+
+func TestBulkOperation(t *testing.T) {
+	tests := []struct {
+		method    string
+		route     string
+		wantOp    string
+		wantState string
+		wantOK    bool
+	}{
+		{"POST", "/api/queues/{qname}/retry_tasks:run_all", "run_all", "retry", true},
+		{"POST", "/api/queues/{qname}/pending_tasks:batch_archive", "batch_archive", "pending", true},
+		{"DELETE", "/api/queues/{qname}/groups/{gname}/aggregating_tasks:delete_all", "delete_all", "aggregating", true},
+		{"POST", "/api/queues/{qname}/active_tasks:cancel_all", "cancel_all", "active", true},
+		{"POST", "/api/queues/{qname}/retry_tasks/error_clusters/{cluster_id}:run", "run_error_cluster", "retry", true},
+		{"POST", "/api/queues/{qname}/retry_tasks/error_clusters/{cluster_id}:archive", "archive_error_cluster", "retry", true},
+		{"DELETE", "/api/queues/{qname}/archived_tasks/error_clusters/{cluster_id}", "delete_error_cluster", "archived", true},
+		{"POST", "/api/queues/{qname}/groups:flush", "flush_groups", "aggregating", true},
+		{"POST", "/api/workers:cancel_flagged", "cancel_flagged", "active", true},
+		{"GET", "/api/queues/{qname}/retry_tasks/error_clusters", "", "", false},
+		{"POST", "/api/queues/{qname}/retry_tasks/{task_id}:run", "", "", false},
+		{"POST", "/api/queues/{qname}:pause", "", "", false},
+		{"GET", "/api/queues", "", "", false},
+	}
+	for _, tc := range tests {
+		op, state, ok := bulkOperation(tc.method, tc.route)
+		if op != tc.wantOp || state != tc.wantState || ok != tc.wantOK {
+			t.Errorf("bulkOperation(%q, %q) = (%q, %q, %t), want (%q, %q, %t)", tc.method, tc.route, op, state, ok, tc.wantOp, tc.wantState, tc.wantOK)
+		}
+	}
+}
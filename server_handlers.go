@@ -18,16 +18,18 @@ type listServersResponse struct {
 
 func newListServersHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatter) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
+		span := startSpan(r.Context(), "asynq.Inspector/Servers")
 		srvs, err := inspector.Servers()
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		resp := listServersResponse{
 			Servers: toServerInfoList(srvs, pf),
 		}
 		if err := json.NewEncoder(w).Encode(resp); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 	}
@@ -0,0 +1,374 @@
+package asynqmon
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - tracker which periodically records the asynq servers in memory
+//   - http.Handler(s) for server history endpoint
+// ****************************************************************************
+
+// Default retention of the server history.
+const defaultServerHistoryRetention = 24 * time.Hour
+
+// Default duration after which a server with "stopped" status is flagged as stuck.
+const defaultStuckStoppedThreshold = 5 * time.Minute
+
+// Interval at which asynq servers write their heartbeat.
+const asynqHeartbeatInterval = 5 * time.Second
+
+// Redis key of the sorted set of servers scored by heartbeat expiration (see asynq base.AllServers).
+const allServersKey = "asynq:servers"
+
+// Reasons for a server to disappear.
+const (
+	// The server was removed before its heartbeat expired (i.e. shut down).
+	disappearReasonShutdown = "shutdown"
+	// The heartbeat of the server expired (e.g. the process crashed or was killed).
+	disappearReasonHeartbeatExpired = "heartbeat_expired"
+	// The server was removed after its last observed heartbeat expired,
+	// so it is not known whether it shut down or its heartbeat expired.
+	disappearReasonUnknown = "unknown"
+)
+
+// serverInfoKey returns the redis key of the server (see asynq base.ServerInfoKey),
+// which is also the member of the server in the sorted set of servers.
+func serverInfoKey(host string, pid int, serverID string) string {
+	return fmt.Sprintf("asynq:servers:{%s:%d:%s}", host, pid, serverID)
+}
+
+// serverRecord is the history of a server seen by the tracker.
+type serverRecord struct {
+	ID             string         `json:"id"`
+	Host           string         `json:"host"`
+	PID            int            `json:"pid"`
+	Concurrency    int            `json:"concurrency"`
+	Queues         map[string]int `json:"queue_priorities"`
+	StrictPriority bool           `json:"strict_priority_enabled"`
+	Started        time.Time      `json:"start_time"`
+	// Last observed status of the server.
+	Status      string    `json:"status"`
+	StatusSince time.Time `json:"status_since"`
+	FirstSeen   time.Time `json:"first_seen"`
+	LastSeen    time.Time `json:"last_seen"`
+	// Expiration of the last observed heartbeat.
+	HeartbeatExpiresAt time.Time `json:"heartbeat_expires_at"`
+	// StaleHeartbeat indicates the heartbeat was not renewed since the previous sample, or expired.
+	StaleHeartbeat bool `json:"stale_heartbeat"`
+
+	// DisappearedAt is the time the server was found missing. Nil if the server is alive.
+	DisappearedAt   *time.Time `json:"disappeared_at"`
+	DisappearReason string     `json:"disappear_reason,omitempty"`
+}
+
+// fleetSample is the size of the fleet at a point in time.
+type fleetSample struct {
+	Time          time.Time `json:"time"`
+	Servers       int       `json:"servers"`
+	Concurrency   int       `json:"concurrency"`
+	ActiveWorkers int       `json:"active_workers"`
+}
+
+// serverHistory holds the server records and the fleet samples.
+type serverHistory struct {
+	records map[string]*serverRecord // keyed by server ID
+	samples []*fleetSample           // in chronological order
+}
+
+func newServerHistory() *serverHistory {
+	return &serverHistory{records: make(map[string]*serverRecord)}
+}
+
+// update records the servers listed at the given time.
+// expirations maps the members of the sorted set of servers to their heartbeat expiration,
+// and must be read before listing the servers since the listing removes expired members.
+func (h *serverHistory) update(now time.Time, servers []*asynq.ServerInfo, expirations map[string]time.Time) {
+	sample := &fleetSample{Time: now}
+	seen := make(map[string]bool)
+	for _, srv := range servers {
+		seen[srv.ID] = true
+		sample.Servers++
+		sample.Concurrency += srv.Concurrency
+		sample.ActiveWorkers += len(srv.ActiveWorkers)
+
+		rec, ok := h.records[srv.ID]
+		if !ok || rec.DisappearedAt != nil {
+			rec = &serverRecord{ID: srv.ID, FirstSeen: now, StatusSince: now}
+			h.records[srv.ID] = rec
+		} else if rec.Status != srv.Status {
+			rec.StatusSince = now
+		}
+		rec.Host = srv.Host
+		rec.PID = srv.PID
+		rec.Concurrency = srv.Concurrency
+		rec.Queues = srv.Queues
+		rec.StrictPriority = srv.StrictPriority
+		rec.Started = srv.Started
+		rec.Status = srv.Status
+		if exp, ok := expirations[serverInfoKey(srv.Host, srv.PID, srv.ID)]; ok {
+			// The heartbeat is renewed every few seconds, so its expiration should have been
+			// extended unless the previous sample was taken moments ago.
+			notRenewed := !rec.HeartbeatExpiresAt.IsZero() && !exp.After(rec.HeartbeatExpiresAt) &&
+				now.Sub(rec.LastSeen) > asynqHeartbeatInterval
+			rec.StaleHeartbeat = !exp.After(now) || notRenewed
+			rec.HeartbeatExpiresAt = exp
+		}
+		rec.LastSeen = now
+	}
+	for _, rec := range h.records {
+		if seen[rec.ID] || rec.DisappearedAt != nil {
+			continue
+		}
+		t := now
+		rec.DisappearedAt = &t
+		exp, ok := expirations[serverInfoKey(rec.Host, rec.PID, rec.ID)]
+		switch {
+		case ok && !exp.After(now):
+			rec.DisappearReason = disappearReasonHeartbeatExpired
+			rec.StaleHeartbeat = true
+		case now.Before(rec.HeartbeatExpiresAt):
+			rec.DisappearReason = disappearReasonShutdown
+		default:
+			rec.DisappearReason = disappearReasonUnknown
+		}
+	}
+	h.samples = append(h.samples, sample)
+}
+
+// trim drops the samples and the disappeared servers older than the cutoff.
+func (h *serverHistory) trim(cutoff time.Time) {
+	i := sort.Search(len(h.samples), func(i int) bool { return !h.samples[i].Time.Before(cutoff) })
+	h.samples = h.samples[i:]
+	for id, rec := range h.records {
+		if rec.DisappearedAt != nil && rec.DisappearedAt.Before(cutoff) {
+			delete(h.records, id)
+		}
+	}
+}
+
+// serverTracker periodically lists the asynq servers and keeps
+// the history of the fleet in memory for the retention period.
+type serverTracker struct {
+	rc        redis.UniversalClient
+	inspector *asynq.Inspector
+	interval  time.Duration
+	retention time.Duration
+	logger    *slog.Logger // may be nil
+
+	mu      sync.Mutex
+	history *serverHistory
+	lastErr error
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newServerTracker(rc redis.UniversalClient, inspector *asynq.Inspector, interval, retention time.Duration, logger *slog.Logger) *serverTracker {
+	if retention <= 0 {
+		retention = defaultServerHistoryRetention
+	}
+	return &serverTracker{
+		rc:        rc,
+		inspector: inspector,
+		interval:  interval,
+		retention: retention,
+		logger:    logger,
+		history:   newServerHistory(),
+		done:      make(chan struct{}),
+	}
+}
+
+// start starts tracking in a background goroutine until stop is called.
+func (t *serverTracker) start() {
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		ticker := time.NewTicker(t.interval)
+		defer ticker.Stop()
+		for {
+			t.sample()
+			select {
+			case <-t.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop stops tracking and waits for the background goroutine to exit.
+func (t *serverTracker) stop() error {
+	close(t.done)
+	t.wg.Wait()
+	return nil
+}
+
+func (t *serverTracker) sample() {
+	ctx, cancel := context.WithTimeout(context.Background(), t.interval)
+	defer cancel()
+	expirations, err := serverHeartbeatExpirations(ctx, t.rc)
+	var servers []*asynq.ServerInfo
+	if err == nil {
+		servers, err = t.inspector.Servers()
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.lastErr = err
+	if err != nil {
+		if t.logger != nil {
+			t.logger.Warn("Failed to sample asynq servers", slog.String("error", err.Error()))
+		}
+		return
+	}
+	now := time.Now()
+	t.history.update(now, servers, expirations)
+	t.history.trim(now.Add(-t.retention))
+}
+
+// serverHeartbeatExpirations returns the heartbeat expiration of the servers
+// in the sorted set of servers, including the expired ones.
+func serverHeartbeatExpirations(ctx context.Context, rc redis.UniversalClient) (map[string]time.Time, error) {
+	zs, err := rc.ZRangeWithScores(ctx, allServersKey, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	res := make(map[string]time.Time)
+	for _, z := range zs {
+		if member, ok := z.Member.(string); ok {
+			res[member] = time.Unix(int64(z.Score), 0)
+		}
+	}
+	return res, nil
+}
+
+// snapshot returns copies of the server records and the samples taken after the given time,
+// and the last sampling error.
+func (t *serverTracker) snapshot(since time.Time) ([]serverRecord, []*fleetSample, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	records := make([]serverRecord, 0, len(t.history.records))
+	for _, rec := range t.history.records {
+		if rec.DisappearedAt == nil || rec.DisappearedAt.After(since) {
+			records = append(records, *rec)
+		}
+	}
+	samples := t.history.samples
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].Time.After(since) })
+	return records, append([]*fleetSample(nil), samples[i:]...), t.lastErr
+}
+
+type serverHistoryEntry struct {
+	serverRecord
+	// StuckStopped indicates the status has been "stopped" for longer than the threshold.
+	StuckStopped bool `json:"stuck_stopped"`
+}
+
+// serverFleetEvent is an appearance or a disappearance of a server.
+type serverFleetEvent struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"` // "appeared" or "disappeared"
+	ServerID string    `json:"server_id"`
+	Host     string    `json:"host"`
+	PID      int       `json:"pid"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+type serverHistoryResponse struct {
+	// Enabled is false if servers are not tracked (i.e. Options.ServerSampleInterval is not set).
+	Enabled                  bool                  `json:"enabled"`
+	IntervalSeconds          float64               `json:"interval_seconds"`
+	RetentionSeconds         float64               `json:"retention_seconds"`
+	StuckStoppedAfterSeconds float64               `json:"stuck_stopped_after_seconds"`
+	Servers                  []*serverHistoryEntry `json:"servers"`
+	Events                   []*serverFleetEvent   `json:"events"`
+	Samples                  []*fleetSample        `json:"samples"`
+	StuckStopped             int                   `json:"stuck_stopped"`
+	StaleHeartbeat           int                   `json:"stale_heartbeat"`
+	// Error of the last sampling, if any.
+	LastError string `json:"last_error,omitempty"`
+}
+
+// buildServerHistory returns the server entries, most recently seen first, and the
+// appearances and disappearances after the given time in chronological order.
+func buildServerHistory(records []serverRecord, since, now time.Time, stuckAfter time.Duration) ([]*serverHistoryEntry, []*serverFleetEvent) {
+	entries := make([]*serverHistoryEntry, 0, len(records))
+	events := make([]*serverFleetEvent, 0)
+	for _, rec := range records {
+		e := &serverHistoryEntry{serverRecord: rec}
+		e.StuckStopped = rec.DisappearedAt == nil && rec.Status == "stopped" && now.Sub(rec.StatusSince) > stuckAfter
+		entries = append(entries, e)
+		if rec.FirstSeen.After(since) {
+			events = append(events, &serverFleetEvent{Time: rec.FirstSeen, Type: "appeared", ServerID: rec.ID, Host: rec.Host, PID: rec.PID})
+		}
+		if rec.DisappearedAt != nil {
+			events = append(events, &serverFleetEvent{Time: *rec.DisappearedAt, Type: "disappeared", ServerID: rec.ID, Host: rec.Host, PID: rec.PID, Reason: rec.DisappearReason})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if !entries[i].LastSeen.Equal(entries[j].LastSeen) {
+			return entries[i].LastSeen.After(entries[j].LastSeen)
+		}
+		return entries[i].ID < entries[j].ID
+	})
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return entries, events
+}
+
+func newServerHistoryHandlerFunc(t *serverTracker) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := serverHistoryResponse{
+			Servers: []*serverHistoryEntry{},
+			Events:  []*serverFleetEvent{},
+			Samples: []*fleetSample{},
+		}
+		if t == nil {
+			writeResponseJSON(w, resp)
+			return
+		}
+		retention := int(t.retention.Seconds())
+		duration, err := intQueryParam(r, "duration", retention, 1, retention)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		stuckAfter, err := intQueryParam(r, "stuck_after", int(defaultStuckStoppedThreshold.Seconds()), 1, retention)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+
+		now := time.Now()
+		since := now.Add(-time.Duration(duration) * time.Second)
+		records, samples, lastErr := t.snapshot(since)
+		resp.Enabled = true
+		resp.IntervalSeconds = t.interval.Seconds()
+		resp.RetentionSeconds = t.retention.Seconds()
+		resp.StuckStoppedAfterSeconds = float64(stuckAfter)
+		if lastErr != nil {
+			resp.LastError = lastErr.Error()
+		}
+		resp.Servers, resp.Events = buildServerHistory(records, since, now, time.Duration(stuckAfter)*time.Second)
+		resp.Samples = samples
+		for _, e := range resp.Servers {
+			if e.StuckStopped {
+				resp.StuckStopped++
+			}
+			if e.StaleHeartbeat && e.DisappearedAt == nil {
+				resp.StaleHeartbeat++
+			}
+		}
+		writeResponseJSON(w, resp)
+	}
+}
@@ -0,0 +1,106 @@
+package asynqmon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hibiken/asynq"
+)
+
+func TestServerHistoryUpdate(t *testing.T) {
+	t0 := time.Now().Truncate(time.Second)
+	srv := func(id, status string, concurrency int) *asynq.ServerInfo {
+		return &asynq.ServerInfo{ID: id, Host: "host", PID: 1, Concurrency: concurrency, Status: status}
+	}
+	key := func(id string) string { return serverInfoKey("host", 1, id) }
+
+	h := newServerHistory()
+	// t0: a, b and c are alive.
+	h.update(t0, []*asynq.ServerInfo{srv("a", "active", 10), srv("b", "active", 20), srv("c", "active", 5)}, map[string]time.Time{
+		key("a"): t0.Add(10 * time.Second),
+		key("b"): t0.Add(10 * time.Second),
+		key("c"): t0.Add(10 * time.Second),
+	})
+	// t1: a is stopped and its heartbeat is not renewed, b is shut down, c crashed but has not expired yet.
+	t1 := t0.Add(30 * time.Second)
+	h.update(t1, []*asynq.ServerInfo{srv("a", "stopped", 10), srv("c", "active", 5)}, map[string]time.Time{
+		key("a"): t0.Add(10 * time.Second),
+		key("c"): t1.Add(5 * time.Second),
+	})
+	// t2: heartbeat of c expired.
+	t2 := t1.Add(30 * time.Second)
+	h.update(t2, []*asynq.ServerInfo{srv("a", "stopped", 10)}, map[string]time.Time{
+		key("a"): t2.Add(10 * time.Second),
+		key("c"): t1.Add(5 * time.Second),
+	})
+
+	a, b, c := h.records["a"], h.records["b"], h.records["c"]
+	if a.DisappearedAt != nil || !a.StatusSince.Equal(t1) || a.StaleHeartbeat {
+		t.Errorf("server a = %+v, want alive with status since %v and renewed heartbeat", a, t1)
+	}
+	if b.DisappearedAt == nil || !b.DisappearedAt.Equal(t1) || b.DisappearReason != disappearReasonUnknown {
+		t.Errorf("server b = %+v, want disappeared at %v with reason %q", b, t1, disappearReasonUnknown)
+	}
+	if c.DisappearedAt == nil || !c.DisappearedAt.Equal(t2) || c.DisappearReason != disappearReasonHeartbeatExpired || !c.StaleHeartbeat {
+		t.Errorf("server c = %+v, want disappeared at %v with reason %q", c, t2, disappearReasonHeartbeatExpired)
+	}
+
+	wantSamples := []fleetSample{{t0, 3, 35, 0}, {t1, 2, 15, 0}, {t2, 1, 10, 0}}
+	if len(h.samples) != len(wantSamples) {
+		t.Fatalf("got %d samples, want %d", len(h.samples), len(wantSamples))
+	}
+	for i, want := range wantSamples {
+		if *h.samples[i] != want {
+			t.Errorf("sample %d = %+v, want %+v", i, *h.samples[i], want)
+		}
+	}
+
+	h.trim(t1.Add(time.Second))
+	if len(h.samples) != 1 || len(h.records) != 2 {
+		t.Errorf("after trim got %d samples and %d records, want 1 and 2", len(h.samples), len(h.records))
+	}
+}
+
+func TestServerHistoryUpdateShutdown(t *testing.T) {
+	t0 := time.Now().Truncate(time.Second)
+	h := newServerHistory()
+	h.update(t0, []*asynq.ServerInfo{{ID: "a", Host: "host", PID: 1, Status: "active"}}, map[string]time.Time{
+		serverInfoKey("host", 1, "a"): t0.Add(10 * time.Second),
+	})
+	h.update(t0.Add(5*time.Second), nil, map[string]time.Time{})
+	if got := h.records["a"].DisappearReason; got != disappearReasonShutdown {
+		t.Errorf("DisappearReason = %q, want %q", got, disappearReasonShutdown)
+	}
+}
+
+func TestBuildServerHistory(t *testing.T) {
+	now := time.Now()
+	disappeared := now.Add(-time.Minute)
+	records := []serverRecord{
+		{ID: "a", Status: "stopped", StatusSince: now.Add(-10 * time.Minute), FirstSeen: now.Add(-2 * time.Hour), LastSeen: now},
+		{ID: "b", Status: "stopped", StatusSince: now.Add(-time.Minute), FirstSeen: now.Add(-30 * time.Minute), LastSeen: now},
+		{ID: "c", Status: "active", FirstSeen: now.Add(-20 * time.Minute), LastSeen: disappeared.Add(-30 * time.Second),
+			DisappearedAt: &disappeared, DisappearReason: disappearReasonHeartbeatExpired},
+	}
+	entries, events := buildServerHistory(records, now.Add(-time.Hour), now, 5*time.Minute)
+
+	var gotIDs []string
+	for _, e := range entries {
+		gotIDs = append(gotIDs, e.ID)
+	}
+	if diff := cmp.Diff([]string{"a", "b", "c"}, gotIDs); diff != "" {
+		t.Errorf("entries mismatch (-want,+got)\n%s", diff)
+	}
+	if !entries[0].StuckStopped || entries[1].StuckStopped || entries[2].StuckStopped {
+		t.Errorf("only a should be stuck stopped")
+	}
+
+	var gotEvents []string
+	for _, e := range events {
+		gotEvents = append(gotEvents, e.Type+":"+e.ServerID)
+	}
+	if diff := cmp.Diff([]string{"appeared:b", "appeared:c", "disappeared:c"}, gotEvents); diff != "" {
+		t.Errorf("events mismatch (-want,+got)\n%s", diff)
+	}
+}
@@ -0,0 +1,398 @@
+package asynqmon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - service level objectives of queues and their evaluation
+//   - http.Handler(s) for SLO endpoint
+// ****************************************************************************
+
+// SLO is a service level objective of a queue.
+//
+// An SLO is either a latency SLO (e.g. latency below 30s for 95% of the time),
+// if LatencyThreshold is set, or a failure ratio SLO (e.g. less than 1% of
+// the processed tasks fail), if MaxFailureRatio is set.
+type SLO struct {
+	// Name of the SLO.
+	//
+	// This field is optional. Default is the queue name followed by the kind of the SLO.
+	Name string
+
+	// Queue the SLO applies to.
+	//
+	// This field is required.
+	Queue string
+
+	// LatencyThreshold is the queue latency which should not be exceeded
+	// for at least Target of the time.
+	LatencyThreshold time.Duration
+
+	// Target is the fraction of time the queue latency should be below LatencyThreshold (e.g. 0.95).
+	// Only used by latency SLOs.
+	Target float64
+
+	// MaxFailureRatio is the ratio of failed tasks to processed tasks
+	// which should not be exceeded over the window (e.g. 0.01).
+	MaxFailureRatio float64
+
+	// Window over which the SLO is evaluated.
+	//
+	// This field is optional. Default is 7 days.
+	Window time.Duration
+}
+
+// Kinds of SLOs.
+const (
+	sloKindLatency      = "latency"
+	sloKindFailureRatio = "failure_ratio"
+)
+
+// Default window of SLOs.
+const defaultSLOWindow = 7 * 24 * time.Hour
+
+// Periods over which the burn rate of the error budget is reported, if shorter than the window.
+var sloBurnRatePeriods = []time.Duration{time.Hour, 6 * time.Hour, 24 * time.Hour}
+
+func (s *SLO) kind() string {
+	if s.LatencyThreshold > 0 {
+		return sloKindLatency
+	}
+	return sloKindFailureRatio
+}
+
+// target returns the fraction of good time or good tasks the SLO requires.
+func (s *SLO) target() float64 {
+	if s.kind() == sloKindLatency {
+		return s.Target
+	}
+	return 1 - s.MaxFailureRatio
+}
+
+// normalize validates the SLO and sets the default values of optional fields.
+func (s *SLO) normalize() error {
+	if s.Queue == "" {
+		return fmt.Errorf("queue is required")
+	}
+	switch {
+	case s.LatencyThreshold > 0 && s.MaxFailureRatio > 0:
+		return fmt.Errorf("only one of LatencyThreshold and MaxFailureRatio can be set")
+	case s.LatencyThreshold > 0:
+		if s.Target <= 0 || s.Target >= 1 {
+			return fmt.Errorf("target must be between 0 and 1 exclusive, got %v", s.Target)
+		}
+	case s.MaxFailureRatio > 0:
+		if s.MaxFailureRatio >= 1 {
+			return fmt.Errorf("max failure ratio must be between 0 and 1 exclusive, got %v", s.MaxFailureRatio)
+		}
+	default:
+		return fmt.Errorf("one of LatencyThreshold and MaxFailureRatio is required")
+	}
+	if s.Window < 0 {
+		return fmt.Errorf("window must not be negative")
+	}
+	if s.Window == 0 {
+		s.Window = defaultSLOWindow
+	}
+	if s.Name == "" {
+		s.Name = s.Queue + " " + s.kind()
+	}
+	return nil
+}
+
+// objective returns a human readable description of the SLO.
+func (s *SLO) objective() string {
+	if s.kind() == sloKindLatency {
+		return fmt.Sprintf("latency < %v for %v%% of the time over %v", s.LatencyThreshold, formatPercent(s.Target), s.Window)
+	}
+	return fmt.Sprintf("failure ratio < %v%% over %v", formatPercent(s.MaxFailureRatio), s.Window)
+}
+
+func formatPercent(f float64) string {
+	return strconv.FormatFloat(f*100, 'f', -1, 64)
+}
+
+// sampleBadFraction returns the fraction of bad time (latency SLO) or of failed tasks
+// (failure ratio SLO) in the samples taken after since, and the time span covered by the samples.
+// It returns nil if the samples are not enough to evaluate the SLO.
+func sampleBadFraction(slo *SLO, samples []*queueSample, since time.Time) (*float64, time.Duration) {
+	i := sort.Search(len(samples), func(i int) bool { return samples[i].time.After(since) })
+	if slo.kind() == sloKindLatency {
+		in := samples[i:]
+		if len(in) == 0 {
+			return nil, 0
+		}
+		var bad int
+		for _, s := range in {
+			if s.latency >= slo.LatencyThreshold {
+				bad++
+			}
+		}
+		f := float64(bad) / float64(len(in))
+		return &f, in[len(in)-1].time.Sub(in[0].time)
+	}
+	// The counts are differences between consecutive samples, starting from
+	// the last sample before the period if any.
+	if i > 0 {
+		i--
+	}
+	in := samples[i:]
+	if len(in) < 2 {
+		return nil, 0
+	}
+	var processed, failed int
+	for j := 1; j < len(in); j++ {
+		dp, df := in[j].processed-in[j-1].processed, in[j].failed-in[j-1].failed
+		if dp < 0 || df < 0 {
+			// Counters were reset (e.g. the queue was deleted).
+			continue
+		}
+		processed += dp
+		failed += df
+	}
+	start := in[0].time
+	if start.Before(since) {
+		start = since
+	}
+	coverage := in[len(in)-1].time.Sub(start)
+	if processed == 0 {
+		return nil, coverage
+	}
+	f := float64(failed) / float64(processed)
+	return &f, coverage
+}
+
+// promBadFractionQuery returns the PromQL query of the fraction of bad time or failed tasks over the period.
+func promBadFractionQuery(slo *SLO, period time.Duration) string {
+	queue := "queue=" + strconv.Quote(slo.Queue)
+	secs := int(period.Seconds())
+	if slo.kind() == sloKindLatency {
+		res := time.Minute
+		if period > 24*time.Hour {
+			res = 5 * time.Minute
+		}
+		return fmt.Sprintf("avg_over_time((asynq_queue_latency_seconds{%s} >= bool %v)[%ds:%ds])",
+			queue, slo.LatencyThreshold.Seconds(), secs, int(res.Seconds()))
+	}
+	return fmt.Sprintf("sum(increase(asynq_tasks_failed_total{%s}[%ds])) / sum(increase(asynq_tasks_processed_total{%s}[%ds]))",
+		queue, secs, queue, secs)
+}
+
+// promQueryResponse is the response of the Prometheus query APIs.
+type promQueryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		Result []struct {
+			Metric map[string]string `json:"metric"`
+			// Value is set by instant queries and Values by range queries.
+			Value  [2]interface{}   `json:"value"`
+			Values [][2]interface{} `json:"values"`
+		} `json:"result"`
+	} `json:"data"`
+}
+
+// queryPrometheus sends a request to the Prometheus query API at path and decodes the response.
+func queryPrometheus(ctx context.Context, client *http.Client, prometheusAddr, path string, v url.Values) (*promQueryResponse, error) {
+	u := strings.TrimSuffix(prometheusAddr, "/") + path + "?" + v.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	var res promQueryResponse
+	if err := json.Unmarshal(body, &res); err != nil {
+		return nil, fmt.Errorf("could not decode prometheus response (status %d): %v", resp.StatusCode, err)
+	}
+	if res.Status != "success" {
+		return nil, fmt.Errorf("prometheus query failed: %s", res.Error)
+	}
+	return &res, nil
+}
+
+// parsePromValue parses a sample value of a Prometheus response.
+// It returns false if the value is not a finite number (e.g. division by zero).
+func parsePromValue(v [2]interface{}) (float64, bool) {
+	s, ok := v[1].(string)
+	if !ok {
+		return 0, false
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, false
+	}
+	return f, true
+}
+
+// queryPrometheusScalar runs an instant query and returns the value of its single result.
+// It returns nil if the query has no result or the value is not a number (e.g. division by zero).
+func queryPrometheusScalar(ctx context.Context, client *http.Client, prometheusAddr, query string, at time.Time) (*float64, error) {
+	v := url.Values{}
+	v.Add("query", query)
+	v.Add("time", unixTimeString(at))
+	res, err := queryPrometheus(ctx, client, prometheusAddr, "/api/v1/query", v)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Data.Result) == 0 {
+		return nil, nil
+	}
+	f, ok := parsePromValue(res.Data.Result[0].Value)
+	if !ok {
+		return nil, nil
+	}
+	return &f, nil
+}
+
+// sloEvaluator evaluates the SLOs from Prometheus if prometheusAddr is set,
+// or from the samples of the sampler otherwise.
+type sloEvaluator struct {
+	slos           []SLO
+	client         *http.Client
+	prometheusAddr string
+	sampler        *queueSampler // unused if prometheusAddr is set
+}
+
+func (e *sloEvaluator) source() string {
+	if e.prometheusAddr != "" {
+		return "prometheus"
+	}
+	return "samples"
+}
+
+// badFraction returns the fraction of bad time or failed tasks over the period ending at now,
+// and the time span covered by the data.
+func (e *sloEvaluator) badFraction(ctx context.Context, slo *SLO, samples []*queueSample, now time.Time, period time.Duration) (*float64, time.Duration, error) {
+	if e.prometheusAddr != "" {
+		f, err := queryPrometheusScalar(ctx, e.client, e.prometheusAddr, promBadFractionQuery(slo, period), now)
+		return f, period, err
+	}
+	f, coverage := sampleBadFraction(slo, samples, now.Add(-period))
+	return f, coverage, nil
+}
+
+// sloStatus is the compliance of an SLO.
+type sloStatus struct {
+	Name      string `json:"name"`
+	Queue     string `json:"queue"`
+	Kind      string `json:"kind"`
+	Objective string `json:"objective"`
+	// Fraction of good time or good tasks required by the SLO.
+	Target        float64 `json:"target"`
+	WindowSeconds float64 `json:"window_seconds"`
+	// Time span covered by the data. It can be shorter than the window when the SLO
+	// is evaluated from samples, which are kept in memory.
+	CoverageSeconds float64 `json:"coverage_seconds"`
+	// Compliance is the fraction of good time or good tasks over the window.
+	// Null if there is no data.
+	Compliance *float64 `json:"compliance"`
+	// Met reports whether the compliance is at least the target.
+	Met bool `json:"met"`
+	// ErrorBudgetRemaining is the fraction of the error budget (1 - target) left over the window.
+	// Negative if the budget is exhausted.
+	ErrorBudgetRemaining *float64 `json:"error_budget_remaining"`
+	// Burn rates of the error budget over recent periods.
+	BurnRates []*sloBurnRate `json:"burn_rates"`
+	// Error of the evaluation, if any.
+	Error string `json:"error,omitempty"`
+}
+
+// sloBurnRate is the rate at which the error budget is consumed over a period.
+// A burn rate of 1 consumes exactly the whole budget over the window of the SLO.
+type sloBurnRate struct {
+	PeriodSeconds float64 `json:"period_seconds"`
+	// Null if there is no data.
+	BurnRate *float64 `json:"burn_rate"`
+}
+
+func (e *sloEvaluator) evaluate(ctx context.Context, slo *SLO, now time.Time) *sloStatus {
+	status := &sloStatus{
+		Name:          slo.Name,
+		Queue:         slo.Queue,
+		Kind:          slo.kind(),
+		Objective:     slo.objective(),
+		Target:        slo.target(),
+		WindowSeconds: slo.Window.Seconds(),
+		BurnRates:     make([]*sloBurnRate, 0),
+	}
+	var samples []*queueSample
+	if e.sampler != nil {
+		var err error
+		samples, err = e.sampler.history(slo.Queue)
+		if err != nil {
+			status.Error = err.Error()
+		}
+	}
+	budget := 1 - slo.target()
+	bad, coverage, err := e.badFraction(ctx, slo, samples, now, slo.Window)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.CoverageSeconds = coverage.Seconds()
+	if bad != nil {
+		compliance := 1 - *bad
+		remaining := 1 - *bad/budget
+		status.Compliance = &compliance
+		status.ErrorBudgetRemaining = &remaining
+		status.Met = compliance >= slo.target()
+	}
+	for _, period := range sloBurnRatePeriods {
+		if period >= slo.Window {
+			break
+		}
+		b := &sloBurnRate{PeriodSeconds: period.Seconds()}
+		bad, _, err := e.badFraction(ctx, slo, samples, now, period)
+		if err != nil {
+			status.Error = err.Error()
+			return status
+		}
+		if bad != nil {
+			rate := *bad / budget
+			b.BurnRate = &rate
+		}
+		status.BurnRates = append(status.BurnRates, b)
+	}
+	return status
+}
+
+type listSLOsResponse struct {
+	// Source of the data used to evaluate the SLOs: "prometheus" or "samples".
+	Source string       `json:"source"`
+	SLOs   []*sloStatus `json:"slos"`
+}
+
+func newListSLOsHandlerFunc(e *sloEvaluator) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := listSLOsResponse{Source: e.source(), SLOs: make([]*sloStatus, 0, len(e.slos))}
+		qname := r.URL.Query().Get("queue")
+		now := time.Now()
+		for i := range e.slos {
+			if qname != "" && e.slos[i].Queue != qname {
+				continue
+			}
+			resp.SLOs = append(resp.SLOs, e.evaluate(r.Context(), &e.slos[i], now))
+		}
+		writeResponseJSON(w, resp)
+	}
+}
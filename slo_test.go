@@ -0,0 +1,121 @@
+package asynqmon
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSLONormalize(t *testing.T) {
+	tests := []struct {
+		slo     SLO
+		wantErr bool
+	}{
+		{SLO{Queue: "critical", LatencyThreshold: 30 * time.Second, Target: 0.95}, false},
+		{SLO{Queue: "default", MaxFailureRatio: 0.01, Window: 24 * time.Hour}, false},
+		{SLO{LatencyThreshold: 30 * time.Second, Target: 0.95}, true},
+		{SLO{Queue: "critical", LatencyThreshold: 30 * time.Second}, true},
+		{SLO{Queue: "critical", LatencyThreshold: 30 * time.Second, Target: 0.95, MaxFailureRatio: 0.01}, true},
+		{SLO{Queue: "default", MaxFailureRatio: 1}, true},
+		{SLO{Queue: "default"}, true},
+	}
+	for _, tc := range tests {
+		slo := tc.slo
+		err := slo.normalize()
+		if (err != nil) != tc.wantErr {
+			t.Errorf("normalize(%+v) returned error %v, want error %t", tc.slo, err, tc.wantErr)
+		}
+	}
+
+	slo := SLO{Queue: "critical", LatencyThreshold: 30 * time.Second, Target: 0.95}
+	if err := slo.normalize(); err != nil {
+		t.Fatal(err)
+	}
+	if slo.Name != "critical latency" || slo.Window != defaultSLOWindow {
+		t.Errorf("normalize set Name=%q Window=%v, want %q and %v", slo.Name, slo.Window, "critical latency", defaultSLOWindow)
+	}
+}
+
+func TestSampleBadFraction(t *testing.T) {
+	t0 := time.Now().Truncate(time.Second)
+	samples := []*queueSample{
+		{time: t0, latency: 40 * time.Second, processed: 100, failed: 10},
+		{time: t0.Add(time.Minute), latency: 10 * time.Second, processed: 200, failed: 11},
+		{time: t0.Add(2 * time.Minute), latency: 50 * time.Second, processed: 300, failed: 13},
+		// Counters reset.
+		{time: t0.Add(3 * time.Minute), latency: time.Second, processed: 50, failed: 5},
+		{time: t0.Add(4 * time.Minute), latency: time.Second, processed: 150, failed: 5},
+	}
+	latency := &SLO{Queue: "q", LatencyThreshold: 30 * time.Second, Target: 0.95}
+	failures := &SLO{Queue: "q", MaxFailureRatio: 0.01}
+
+	tests := []struct {
+		desc         string
+		slo          *SLO
+		since        time.Time
+		wantBad      float64
+		wantCoverage time.Duration
+	}{
+		{"latency over all samples", latency, t0.Add(-time.Second), 2.0 / 5, 4 * time.Minute},
+		{"latency over recent samples", latency, t0.Add(90 * time.Second), 1.0 / 3, 2 * time.Minute},
+		{"failures over all samples", failures, t0.Add(-time.Second), 3.0 / 300, 4 * time.Minute},
+		// Starts from the last sample before since.
+		{"failures over recent samples", failures, t0.Add(90 * time.Second), 2.0 / 200, 150 * time.Second},
+	}
+	for _, tc := range tests {
+		bad, coverage := sampleBadFraction(tc.slo, samples, tc.since)
+		if bad == nil || *bad != tc.wantBad || coverage != tc.wantCoverage {
+			t.Errorf("%s: sampleBadFraction = %v, %v; want %v, %v", tc.desc, bad, coverage, tc.wantBad, tc.wantCoverage)
+		}
+	}
+
+	if bad, _ := sampleBadFraction(failures, samples[:1], t0.Add(-time.Second)); bad != nil {
+		t.Errorf("sampleBadFraction with a single sample = %v, want nil", *bad)
+	}
+}
+
+func TestSLOEvaluateWithPrometheus(t *testing.T) {
+	// Failure ratio over the window is 0.5%, and 2% over the last hour.
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query().Get("query")
+		value := "0.005"
+		if strings.Contains(query, "[3600s]") {
+			value = "0.02"
+		}
+		if strings.Contains(query, "[21600s]") {
+			value = "NaN"
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1700000000,%q]}]}}`, value)
+	}))
+	defer srv.Close()
+
+	slo := SLO{Queue: "default", MaxFailureRatio: 0.01, Window: 24 * time.Hour}
+	if err := slo.normalize(); err != nil {
+		t.Fatal(err)
+	}
+	e := &sloEvaluator{slos: []SLO{slo}, client: srv.Client(), prometheusAddr: srv.URL}
+	got := e.evaluate(context.Background(), &slo, time.Now())
+
+	if got.Error != "" {
+		t.Fatalf("evaluate returned error %q", got.Error)
+	}
+	if got.Compliance == nil || *got.Compliance != 0.995 || !got.Met {
+		t.Errorf("Compliance = %v, Met = %t; want 0.995 and true", got.Compliance, got.Met)
+	}
+	if got.ErrorBudgetRemaining == nil || fmt.Sprintf("%.2f", *got.ErrorBudgetRemaining) != "0.50" {
+		t.Errorf("ErrorBudgetRemaining = %v, want 0.5", got.ErrorBudgetRemaining)
+	}
+	if len(got.BurnRates) != 2 {
+		t.Fatalf("got %d burn rates, want 2", len(got.BurnRates))
+	}
+	if b := got.BurnRates[0].BurnRate; b == nil || fmt.Sprintf("%.2f", *b) != "2.00" {
+		t.Errorf("burn rate over 1h = %v, want 2", b)
+	}
+	if b := got.BurnRates[1].BurnRate; b != nil {
+		t.Errorf("burn rate over 6h = %v, want nil", *b)
+	}
+}
@@ -20,7 +20,7 @@ type uiAssetsHandler struct {
 	staticDirPath  string
 	indexFileName  string
 	prometheusAddr string
-	readOnly       bool
+	readOnly       *readOnlyMode
 }
 
 // ServeHTTP inspects the URL path to locate a file within the static dir
@@ -67,7 +67,7 @@ func (h *uiAssetsHandler) renderIndexFile(w http.ResponseWriter) error {
 	}{
 		RootPath:       h.rootPath,
 		PrometheusAddr: h.prometheusAddr,
-		ReadOnly:       h.readOnly,
+		ReadOnly:       h.readOnly.enabled(),
 	}
 	return tmpl.Execute(w, data)
 }
@@ -2,11 +2,10 @@ package asynqmon
 
 import (
 	"encoding/json"
-	"errors"
+	"fmt"
 	"log"
 	"net/http"
 	"strconv"
-	"strings"
 	"time"
 
 	"github.com/gorilla/mux"
@@ -30,20 +29,26 @@ func newListActiveTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatt
 		qname := vars["qname"]
 		pageSize, pageNum := getPageOptions(r)
 
+		span := startSpan(r.Context(), "asynq.Inspector/ListActiveTasks")
 		tasks, err := inspector.ListActiveTasks(
 			qname, asynq.PageSize(pageSize), asynq.Page(pageNum))
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/Servers")
 		servers, err := inspector.Servers()
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		// m maps taskID to workerInfo.
@@ -75,33 +80,44 @@ func newListActiveTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatt
 	}
 }
 
-func newCancelActiveTaskHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newCancelActiveTaskHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
-		id := mux.Vars(r)["task_id"]
-		if err := inspector.CancelProcessing(id); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		vars := mux.Vars(r)
+		qname, id := vars["qname"], vars["task_id"]
+		span := startSpan(r.Context(), "asynq.Inspector/CancelProcessing")
+		err := inspector.CancelProcessing(id)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordAction(r, qname, id, "cancel", "")
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
 
-func newCancelAllActiveTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newCancelAllActiveTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const batchSize = 100
 		page := 1
 		qname := mux.Vars(r)["qname"]
 		for {
+			span := startSpan(r.Context(), "asynq.Inspector/ListActiveTasks")
 			tasks, err := inspector.ListActiveTasks(qname, asynq.Page(page), asynq.PageSize(batchSize))
+			endSpan(span, err)
 			if err != nil {
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				writeErrorResponse(w, r, err)
 				return
 			}
 			for _, t := range tasks {
-				if err := inspector.CancelProcessing(t.ID); err != nil {
-					http.Error(w, err.Error(), http.StatusInternalServerError)
+				span := startSpan(r.Context(), "asynq.Inspector/CancelProcessing")
+				err := inspector.CancelProcessing(t.ID)
+				endSpan(span, err)
+				if err != nil {
+					writeErrorResponse(w, r, err)
 					return
 				}
+				tl.recordAction(r, qname, t.ID, "cancel_all", "")
 			}
 			if len(tasks) < batchSize {
 				break
@@ -121,7 +137,7 @@ type batchCancelTasksResponse struct {
 	ErrorIDs    []string `json:"error_ids"`
 }
 
-func newBatchCancelActiveTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newBatchCancelActiveTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
 		dec := json.NewDecoder(r.Body)
@@ -129,21 +145,26 @@ func newBatchCancelActiveTasksHandlerFunc(inspector *asynq.Inspector) http.Handl
 
 		var req batchCancelTasksRequest
 		if err := dec.Decode(&req); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, "invalid request body: %v", err)
 			return
 		}
 
+		qname := mux.Vars(r)["qname"]
 		resp := batchCancelTasksResponse{
 			// avoid null in the json response
 			CanceledIDs: make([]string, 0),
 			ErrorIDs:    make([]string, 0),
 		}
 		for _, id := range req.TaskIDs {
-			if err := inspector.CancelProcessing(id); err != nil {
+			span := startSpan(r.Context(), "asynq.Inspector/CancelProcessing")
+			err := inspector.CancelProcessing(id)
+			endSpan(span, err)
+			if err != nil {
 				log.Printf("error: could not send cancelation signal to task %s", id)
 				resp.ErrorIDs = append(resp.ErrorIDs, id)
 			} else {
 				resp.CanceledIDs = append(resp.CanceledIDs, id)
+				tl.recordAction(r, qname, id, "batch_cancel", "")
 			}
 		}
 		writeResponseJSON(w, resp)
@@ -155,15 +176,19 @@ func newListPendingTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadFormat
 		vars := mux.Vars(r)
 		qname := vars["qname"]
 		pageSize, pageNum := getPageOptions(r)
+		span := startSpan(r.Context(), "asynq.Inspector/ListPendingTasks")
 		tasks, err := inspector.ListPendingTasks(
 			qname, asynq.PageSize(pageSize), asynq.Page(pageNum))
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		payload := make(map[string]interface{})
@@ -183,15 +208,19 @@ func newListScheduledTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadForm
 		vars := mux.Vars(r)
 		qname := vars["qname"]
 		pageSize, pageNum := getPageOptions(r)
+		span := startSpan(r.Context(), "asynq.Inspector/ListScheduledTasks")
 		tasks, err := inspector.ListScheduledTasks(
 			qname, asynq.PageSize(pageSize), asynq.Page(pageNum))
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		payload := make(map[string]interface{})
@@ -211,15 +240,19 @@ func newListRetryTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatte
 		vars := mux.Vars(r)
 		qname := vars["qname"]
 		pageSize, pageNum := getPageOptions(r)
+		span := startSpan(r.Context(), "asynq.Inspector/ListRetryTasks")
 		tasks, err := inspector.ListRetryTasks(
 			qname, asynq.PageSize(pageSize), asynq.Page(pageNum))
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		payload := make(map[string]interface{})
@@ -239,15 +272,19 @@ func newListArchivedTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadForma
 		vars := mux.Vars(r)
 		qname := vars["qname"]
 		pageSize, pageNum := getPageOptions(r)
+		span := startSpan(r.Context(), "asynq.Inspector/ListArchivedTasks")
 		tasks, err := inspector.ListArchivedTasks(
 			qname, asynq.PageSize(pageSize), asynq.Page(pageNum))
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		payload := make(map[string]interface{})
@@ -267,14 +304,18 @@ func newListCompletedTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadForm
 		vars := mux.Vars(r)
 		qname := vars["qname"]
 		pageSize, pageNum := getPageOptions(r)
+		span := startSpan(r.Context(), "asynq.Inspector/ListCompletedTasks")
 		tasks, err := inspector.ListCompletedTasks(qname, asynq.PageSize(pageSize), asynq.Page(pageNum))
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		payload := make(map[string]interface{})
@@ -295,20 +336,26 @@ func newListAggregatingTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadFo
 		qname := vars["qname"]
 		gname := vars["gname"]
 		pageSize, pageNum := getPageOptions(r)
+		span := startSpan(r.Context(), "asynq.Inspector/ListAggregatingTasks")
 		tasks, err := inspector.ListAggregatingTasks(
 			qname, gname, asynq.PageSize(pageSize), asynq.Page(pageNum))
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/GetQueueInfo")
 		qinfo, err := inspector.GetQueueInfo(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		span = startSpan(r.Context(), "asynq.Inspector/Groups")
 		groups, err := inspector.Groups(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
 		payload := make(map[string]interface{})
@@ -324,53 +371,62 @@ func newListAggregatingTasksHandlerFunc(inspector *asynq.Inspector, pf PayloadFo
 	}
 }
 
-func newDeleteTaskHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newDeleteTaskHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname, taskid := vars["qname"], vars["task_id"]
 		if qname == "" || taskid == "" {
-			http.Error(w, "route parameters should not be empty", http.StatusBadRequest)
+			writeBadRequest(w, r, "route parameters should not be empty")
 			return
 		}
-		if err := inspector.DeleteTask(qname, taskid); err != nil {
-			// TODO: Handle task not found error and return 404
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		span := startSpan(r.Context(), "asynq.Inspector/DeleteTask")
+		err := inspector.DeleteTask(qname, taskid)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordAction(r, qname, taskid, "delete", "")
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
 
-func newRunTaskHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newRunTaskHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname, taskid := vars["qname"], vars["task_id"]
 		if qname == "" || taskid == "" {
-			http.Error(w, "route parameters should not be empty", http.StatusBadRequest)
+			writeBadRequest(w, r, "route parameters should not be empty")
 			return
 		}
-		if err := inspector.RunTask(qname, taskid); err != nil {
-			// TODO: Handle task not found error and return 404
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		span := startSpan(r.Context(), "asynq.Inspector/RunTask")
+		err := inspector.RunTask(qname, taskid)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordAction(r, qname, taskid, "run", "pending")
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
 
-func newArchiveTaskHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newArchiveTaskHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname, taskid := vars["qname"], vars["task_id"]
 		if qname == "" || taskid == "" {
-			http.Error(w, "route parameters should not be empty", http.StatusBadRequest)
+			writeBadRequest(w, r, "route parameters should not be empty")
 			return
 		}
-		if err := inspector.ArchiveTask(qname, taskid); err != nil {
-			// TODO: Handle task not found error and return 404
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+		span := startSpan(r.Context(), "asynq.Inspector/ArchiveTask")
+		err := inspector.ArchiveTask(qname, taskid)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordAction(r, qname, taskid, "archive", "archived")
 		w.WriteHeader(http.StatusNoContent)
 	}
 }
@@ -380,75 +436,93 @@ type deleteAllTasksResponse struct {
 	Deleted int `json:"deleted"`
 }
 
-func newDeleteAllPendingTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newDeleteAllPendingTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/DeleteAllPendingTasks")
 		n, err := inspector.DeleteAllPendingTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "delete_all", "", n)
 		writeResponseJSON(w, deleteAllTasksResponse{n})
 	}
 }
 
-func newDeleteAllAggregatingTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newDeleteAllAggregatingTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname, gname := vars["qname"], vars["gname"]
+		span := startSpan(r.Context(), "asynq.Inspector/DeleteAllAggregatingTasks")
 		n, err := inspector.DeleteAllAggregatingTasks(qname, gname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, gname, "delete_all", "", n)
 		writeResponseJSON(w, deleteAllTasksResponse{n})
 	}
 }
 
-func newDeleteAllScheduledTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newDeleteAllScheduledTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/DeleteAllScheduledTasks")
 		n, err := inspector.DeleteAllScheduledTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "delete_all", "", n)
 		writeResponseJSON(w, deleteAllTasksResponse{n})
 	}
 }
 
-func newDeleteAllRetryTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newDeleteAllRetryTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/DeleteAllRetryTasks")
 		n, err := inspector.DeleteAllRetryTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "delete_all", "", n)
 		writeResponseJSON(w, deleteAllTasksResponse{n})
 	}
 }
 
-func newDeleteAllArchivedTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newDeleteAllArchivedTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/DeleteAllArchivedTasks")
 		n, err := inspector.DeleteAllArchivedTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "delete_all", "", n)
 		writeResponseJSON(w, deleteAllTasksResponse{n})
 	}
 }
 
-func newDeleteAllCompletedTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newDeleteAllCompletedTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/DeleteAllCompletedTasks")
 		n, err := inspector.DeleteAllCompletedTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "delete_all", "", n)
 		writeResponseJSON(w, deleteAllTasksResponse{n})
 	}
 }
@@ -458,51 +532,63 @@ type runAllTasksResponse struct {
 	Scheduled int `json:"scheduled"`
 }
 
-func newRunAllScheduledTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newRunAllScheduledTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/RunAllScheduledTasks")
 		n, err := inspector.RunAllScheduledTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "run_all", "pending", n)
 		writeResponseJSON(w, runAllTasksResponse{n})
 	}
 }
 
-func newRunAllRetryTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newRunAllRetryTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/RunAllRetryTasks")
 		n, err := inspector.RunAllRetryTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "run_all", "pending", n)
 		writeResponseJSON(w, runAllTasksResponse{n})
 	}
 }
 
-func newRunAllArchivedTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newRunAllArchivedTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/RunAllArchivedTasks")
 		n, err := inspector.RunAllArchivedTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "run_all", "pending", n)
 		writeResponseJSON(w, runAllTasksResponse{n})
 	}
 }
 
-func newRunAllAggregatingTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newRunAllAggregatingTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname, gname := vars["qname"], vars["gname"]
+		span := startSpan(r.Context(), "asynq.Inspector/RunAllAggregatingTasks")
 		n, err := inspector.RunAllAggregatingTasks(qname, gname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, gname, "run_all", "pending", n)
 		writeResponseJSON(w, runAllTasksResponse{n})
 	}
 }
@@ -514,55 +600,67 @@ type archiveAllTasksResponse struct {
 
 func writeResponseJSON(w http.ResponseWriter, resp interface{}) {
 	if err := json.NewEncoder(w).Encode(resp); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeErrorResponse(w, nil, err)
 	}
 }
 
-func newArchiveAllPendingTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newArchiveAllPendingTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/ArchiveAllPendingTasks")
 		n, err := inspector.ArchiveAllPendingTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "archive_all", "archived", n)
 		writeResponseJSON(w, archiveAllTasksResponse{n})
 	}
 }
 
-func newArchiveAllAggregatingTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newArchiveAllAggregatingTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname, gname := vars["qname"], vars["gname"]
+		span := startSpan(r.Context(), "asynq.Inspector/ArchiveAllAggregatingTasks")
 		n, err := inspector.ArchiveAllAggregatingTasks(qname, gname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, gname, "archive_all", "archived", n)
 		writeResponseJSON(w, archiveAllTasksResponse{n})
 	}
 }
 
-func newArchiveAllScheduledTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newArchiveAllScheduledTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/ArchiveAllScheduledTasks")
 		n, err := inspector.ArchiveAllScheduledTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "archive_all", "archived", n)
 		writeResponseJSON(w, archiveAllTasksResponse{n})
 	}
 }
 
-func newArchiveAllRetryTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newArchiveAllRetryTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		qname := mux.Vars(r)["qname"]
+		span := startSpan(r.Context(), "asynq.Inspector/ArchiveAllRetryTasks")
 		n, err := inspector.ArchiveAllRetryTasks(qname)
+		endSpan(span, err)
 		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeErrorResponse(w, r, err)
 			return
 		}
+		tl.recordBulkAction(r, qname, "", "archive_all", "archived", n)
 		writeResponseJSON(w, archiveAllTasksResponse{n})
 	}
 }
@@ -588,7 +686,7 @@ type batchDeleteTasksResponse struct {
 // Allow up to 1MB in size.
 const maxRequestBodySize = 1000000
 
-func newBatchDeleteTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newBatchDeleteTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
 		dec := json.NewDecoder(r.Body)
@@ -596,7 +694,7 @@ func newBatchDeleteTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc
 
 		var req batchDeleteTasksRequest
 		if err := dec.Decode(&req); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, "invalid request body: %v", err)
 			return
 		}
 
@@ -607,11 +705,15 @@ func newBatchDeleteTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc
 			FailedIDs:  make([]string, 0),
 		}
 		for _, taskid := range req.TaskIDs {
-			if err := inspector.DeleteTask(qname, taskid); err != nil {
+			span := startSpan(r.Context(), "asynq.Inspector/DeleteTask")
+			err := inspector.DeleteTask(qname, taskid)
+			endSpan(span, err)
+			if err != nil {
 				log.Printf("error: could not delete task with id %q: %v", taskid, err)
 				resp.FailedIDs = append(resp.FailedIDs, taskid)
 			} else {
 				resp.DeletedIDs = append(resp.DeletedIDs, taskid)
+				tl.recordAction(r, qname, taskid, "batch_delete", "")
 			}
 		}
 		writeResponseJSON(w, resp)
@@ -629,7 +731,7 @@ type batchRunTasksResponse struct {
 	ErrorIDs []string `json:"error_ids"`
 }
 
-func newBatchRunTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newBatchRunTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
 		dec := json.NewDecoder(r.Body)
@@ -637,7 +739,7 @@ func newBatchRunTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 
 		var req batchRunTasksRequest
 		if err := dec.Decode(&req); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, "invalid request body: %v", err)
 			return
 		}
 
@@ -648,11 +750,15 @@ func newBatchRunTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
 			ErrorIDs:   make([]string, 0),
 		}
 		for _, taskid := range req.TaskIDs {
-			if err := inspector.RunTask(qname, taskid); err != nil {
+			span := startSpan(r.Context(), "asynq.Inspector/RunTask")
+			err := inspector.RunTask(qname, taskid)
+			endSpan(span, err)
+			if err != nil {
 				log.Printf("error: could not run task with id %q: %v", taskid, err)
 				resp.ErrorIDs = append(resp.ErrorIDs, taskid)
 			} else {
 				resp.PendingIDs = append(resp.PendingIDs, taskid)
+				tl.recordAction(r, qname, taskid, "batch_run", "pending")
 			}
 		}
 		writeResponseJSON(w, resp)
@@ -670,7 +776,7 @@ type batchArchiveTasksResponse struct {
 	ErrorIDs []string `json:"error_ids"`
 }
 
-func newBatchArchiveTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFunc {
+func newBatchArchiveTasksHandlerFunc(inspector *asynq.Inspector, tl *taskTimeline) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
 		dec := json.NewDecoder(r.Body)
@@ -678,7 +784,7 @@ func newBatchArchiveTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFun
 
 		var req batchArchiveTasksRequest
 		if err := dec.Decode(&req); err != nil {
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeBadRequest(w, r, "invalid request body: %v", err)
 			return
 		}
 
@@ -689,11 +795,15 @@ func newBatchArchiveTasksHandlerFunc(inspector *asynq.Inspector) http.HandlerFun
 			ErrorIDs:    make([]string, 0),
 		}
 		for _, taskid := range req.TaskIDs {
-			if err := inspector.ArchiveTask(qname, taskid); err != nil {
+			span := startSpan(r.Context(), "asynq.Inspector/ArchiveTask")
+			err := inspector.ArchiveTask(qname, taskid)
+			endSpan(span, err)
+			if err != nil {
 				log.Printf("error: could not archive task with id %q: %v", taskid, err)
 				resp.ErrorIDs = append(resp.ErrorIDs, taskid)
 			} else {
 				resp.ArchivedIDs = append(resp.ArchivedIDs, taskid)
+				tl.recordAction(r, qname, taskid, "batch_archive", "archived")
 			}
 		}
 		writeResponseJSON(w, resp)
@@ -719,29 +829,92 @@ func getPageOptions(r *http.Request) (pageSize, pageNum int) {
 	return pageSize, pageNum
 }
 
-func newGetTaskHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatter, rf ResultFormatter) http.HandlerFunc {
+// intQueryParam returns the value of the integer query parameter, or def if the parameter is not set.
+// It returns an error if the value is not an integer in [min, max].
+func intQueryParam(r *http.Request, name string, def, min, max int) (int, error) {
+	s := r.URL.Query().Get(name)
+	if s == "" {
+		return def, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil || v < min || v > max {
+		return 0, fmt.Errorf("invalid value %q for %s: must be an integer between %d and %d", s, name, min, max)
+	}
+	return v, nil
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func newGetTaskHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatter, rf ResultFormatter, tl *taskTimeline, ns *noteStore) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		vars := mux.Vars(r)
 		qname, taskid := vars["qname"], vars["task_id"]
 		if qname == "" {
-			http.Error(w, "queue name cannot be empty", http.StatusBadRequest)
+			writeBadRequest(w, r, "queue name cannot be empty")
 			return
 		}
 		if taskid == "" {
-			http.Error(w, "task_id cannot be empty", http.StatusBadRequest)
+			writeBadRequest(w, r, "task_id cannot be empty")
 			return
 		}
+		withTimeline := false
+		if s := r.URL.Query().Get("timeline"); s != "" {
+			v, err := strconv.ParseBool(s)
+			if err != nil {
+				writeBadRequest(w, r, "invalid value %q for timeline: must be a boolean", s)
+				return
+			}
+			withTimeline = v
+		}
 
+		span := startSpan(r.Context(), "asynq.Inspector/GetTaskInfo")
 		info, err := inspector.GetTaskInfo(qname, taskid)
-		switch {
-		case errors.Is(err, asynq.ErrQueueNotFound), errors.Is(err, asynq.ErrTaskNotFound):
-			http.Error(w, strings.TrimPrefix(err.Error(), "asynq: "), http.StatusNotFound)
-			return
-		case err != nil:
-			http.Error(w, strings.TrimPrefix(err.Error(), "asynq: "), http.StatusInternalServerError)
+		endSpan(span, err)
+		if err != nil {
+			writeErrorResponse(w, r, err)
 			return
 		}
 
-		writeResponseJSON(w, toTaskInfo(info, pf, rf))
+		resp := toTaskInfo(info, pf, rf)
+		keys := []string{taskNotesKey(qname, taskid)}
+		if info.LastErr != "" {
+			resp.ErrorClusterID = errorClusterID(normalizeErrorMessage(info.LastErr))
+			keys = append(keys, errorClusterNotesKey(resp.ErrorClusterID))
+		}
+		notes, err := ns.list(r.Context(), keys...)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		resp.Notes = notes[0]
+		if len(notes) > 1 {
+			resp.ErrorClusterNotes = notes[1]
+		}
+		if withTimeline {
+			recorded, err := tl.events(r.Context(), qname, taskid)
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			enqueued, err := schedulerEnqueueTaskEvent(r.Context(), inspector, info)
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			bulk, err := tl.bulkActions(r.Context(), qname)
+			if err != nil {
+				writeErrorResponse(w, r, err)
+				return
+			}
+			resp.Timeline = buildTaskTimeline(info, recorded, enqueued, lastBulkActionOnTask(info, recorded, bulk))
+		}
+		writeResponseJSON(w, resp)
 	}
 }
@@ -0,0 +1,125 @@
+package asynqmon
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - sampler which periodically lists the tasks of all queues to record
+//     their state changes in the task timeline
+// ****************************************************************************
+
+// Maximum number of tasks listed per state (and per group for aggregating tasks)
+// of each queue in a sample.
+const maxSampledTasksPerState = 100
+
+// taskStateSampler periodically lists the first tasks in each state of all queues
+// and records the tasks whose state changed since the previous sample in the timeline.
+//
+// A task changing state multiple times between two samples is recorded as a single change,
+// and tasks beyond the first maxSampledTasksPerState of a state are not observed in it.
+// Nothing is sampled in read-only mode, which must not write to redis.
+type taskStateSampler struct {
+	inspector *asynq.Inspector
+	timeline  *taskTimeline
+	interval  time.Duration
+	logger    *slog.Logger // may be nil
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newTaskStateSampler(inspector *asynq.Inspector, timeline *taskTimeline, interval time.Duration, logger *slog.Logger) *taskStateSampler {
+	return &taskStateSampler{
+		inspector: inspector,
+		timeline:  timeline,
+		interval:  interval,
+		logger:    logger,
+		done:      make(chan struct{}),
+	}
+}
+
+// start starts sampling in a background goroutine until stop is called.
+func (s *taskStateSampler) start() {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ticker := time.NewTicker(s.interval)
+		defer ticker.Stop()
+		for {
+			s.sample()
+			select {
+			case <-s.done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// stop stops sampling and waits for the background goroutine to exit.
+func (s *taskStateSampler) stop() error {
+	close(s.done)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *taskStateSampler) sample() {
+	if s.timeline.readOnly != nil && s.timeline.readOnly.enabled() {
+		return
+	}
+	qnames, err := s.inspector.Queues()
+	if err != nil {
+		if s.logger != nil {
+			s.logger.Warn("Failed to list queues to sample task states", slog.String("error", err.Error()))
+		}
+		return
+	}
+	for _, qname := range qnames {
+		tasks, err := s.listTasks(qname)
+		if err != nil && s.logger != nil {
+			s.logger.Warn("Failed to sample task states", slog.String("queue", qname), slog.String("error", err.Error()))
+		}
+		// Observe the tasks listed before the error, if any.
+		s.timeline.observe(context.Background(), tasks...)
+	}
+}
+
+// listTasks returns the first tasks in each state of the queue.
+func (s *taskStateSampler) listTasks(qname string) ([]*asynq.TaskInfo, error) {
+	page := asynq.PageSize(maxSampledTasksPerState)
+	lists := []func(string, ...asynq.ListOption) ([]*asynq.TaskInfo, error){
+		s.inspector.ListActiveTasks,
+		s.inspector.ListPendingTasks,
+		s.inspector.ListScheduledTasks,
+		s.inspector.ListRetryTasks,
+		s.inspector.ListArchivedTasks,
+		s.inspector.ListCompletedTasks,
+	}
+	var tasks []*asynq.TaskInfo
+	for _, list := range lists {
+		res, err := list(qname, page)
+		if err != nil {
+			return tasks, err
+		}
+		tasks = append(tasks, res...)
+	}
+	groups, err := s.inspector.Groups(qname)
+	if err != nil {
+		return tasks, err
+	}
+	for _, g := range groups {
+		res, err := s.inspector.ListAggregatingTasks(qname, g.Group, page)
+		if err != nil {
+			return tasks, err
+		}
+		tasks = append(tasks, res...)
+	}
+	return tasks, nil
+}
@@ -0,0 +1,400 @@
+package asynqmon
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/hibiken/asynq"
+	"github.com/redis/go-redis/v9"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - task timeline which records lifecycle events of tasks in redis
+//   - helpers to build the timeline returned by the task endpoint
+// ****************************************************************************
+
+const (
+	// Maximum number of events stored per task.
+	maxTaskTimelineEvents = 100
+
+	// Time to keep the timeline of a task after its last recorded event.
+	taskTimelineTTL = 7 * 24 * time.Hour
+
+	// Maximum number of bulk action events stored per queue.
+	maxBulkActionEvents = 100
+
+	// Maximum number of enqueue events kept per scheduler entry by asynq.
+	maxSchedulerEnqueueEvents = 1000
+)
+
+// Types of task timeline events.
+const (
+	// Operator performed an action (e.g. run, archive) on the task through the API.
+	taskEventAction = "action"
+	// Operator performed an action on all tasks in a state (e.g. run_all), which likely included the task.
+	taskEventBulkAction = "bulk_action"
+	// Scheduler enqueued the task for a periodic task entry.
+	taskEventSchedulerEnqueue = "scheduler_enqueue"
+	// asynqmon observed the task in a different state than the last time it sampled the task.
+	taskEventStateChange = "state_change"
+	// Task failed. Only the last failure is known.
+	taskEventFailed = "failed"
+	// Task was processed successfully.
+	taskEventCompleted = "completed"
+)
+
+// taskEvent is an event in the lifecycle of a task.
+type taskEvent struct {
+	Time time.Time `json:"time"`
+	Type string    `json:"type"`
+	// Action performed by an operator (e.g. "run", "batch_archive").
+	Action string `json:"action,omitempty"`
+	// States before and after the event, if known.
+	FromState string `json:"from_state,omitempty"`
+	ToState   string `json:"to_state,omitempty"`
+	// User and request ID of the operator action.
+	User      string `json:"user,omitempty"`
+	RequestID string `json:"request_id,omitempty"`
+	// Group of the aggregating tasks and number of tasks affected by a bulk action.
+	Group string `json:"group,omitempty"`
+	Count int    `json:"count,omitempty"`
+	// Message describes the event (e.g. the error message of a failure).
+	Message string `json:"message,omitempty"`
+}
+
+// taskTimeline records events of tasks in redis.
+//
+// Events are stored in a list per task, in the same hash slot as the queue
+// so that they live on the same node in cluster mode:
+//
+//	asynqmon:{<qname>}:timeline:<task_id>
+//
+// The last state asynqmon observed for the task (by sampling or as the result
+// of an action) is stored alongside it to detect state changes:
+//
+//	asynqmon:{<qname>}:observed:<task_id>
+//
+// Actions on all tasks in a state (e.g. run_all) are recorded once per queue,
+// since listing the affected tasks could be expensive:
+//
+//	asynqmon:{<qname>}:bulk_actions
+type taskTimeline struct {
+	rc redis.UniversalClient
+	// State changes are not observed in read-only mode, which must not write to redis.
+	readOnly *readOnlyMode // may be nil
+	logger   *slog.Logger  // may be nil
+}
+
+func newTaskTimeline(rc redis.UniversalClient, readOnly *readOnlyMode, logger *slog.Logger) *taskTimeline {
+	return &taskTimeline{rc: rc, readOnly: readOnly, logger: logger}
+}
+
+func taskTimelineKey(qname, taskID string) string {
+	return fmt.Sprintf("asynqmon:{%s}:timeline:%s", qname, taskID)
+}
+
+func observedTaskStateKey(qname, taskID string) string {
+	return fmt.Sprintf("asynqmon:{%s}:observed:%s", qname, taskID)
+}
+
+func bulkActionsKey(qname string) string {
+	return fmt.Sprintf("asynqmon:{%s}:bulk_actions", qname)
+}
+
+// KEYS[1] -> asynqmon:{<qname>}:timeline:<task_id>
+// KEYS[2] -> asynqmon:{<qname>}:observed:<task_id>
+// ARGV[1] -> event encoded in json
+// ARGV[2] -> ttl in seconds
+// ARGV[3] -> max number of events
+// ARGV[4] -> state of the task after the event, or empty string if unknown
+var recordTaskEventCmd = redis.NewScript(`
+redis.call("RPUSH", KEYS[1], ARGV[1])
+redis.call("LTRIM", KEYS[1], -tonumber(ARGV[3]), -1)
+redis.call("EXPIRE", KEYS[1], ARGV[2])
+if ARGV[4] ~= "" then
+	redis.call("SET", KEYS[2], ARGV[4], "EX", ARGV[2])
+end
+return redis.status_reply("OK")
+`)
+
+// KEYS[1] -> asynqmon:{<qname>}:timeline:<task_id>
+// KEYS[2] -> asynqmon:{<qname>}:observed:<task_id>
+// ARGV[1] -> observed state
+// ARGV[2] -> state change event encoded in json, without from_state
+// ARGV[3] -> ttl in seconds
+// ARGV[4] -> max number of events
+//
+// Returns 1 if the state has changed since the last observation, 0 otherwise.
+var observeTaskStateCmd = redis.NewScript(`
+local prev = redis.call("GET", KEYS[2])
+if prev == ARGV[1] then
+	return 0
+end
+local event = cjson.decode(ARGV[2])
+if prev then
+	event["from_state"] = prev
+end
+redis.call("SET", KEYS[2], ARGV[1], "EX", ARGV[3])
+redis.call("RPUSH", KEYS[1], cjson.encode(event))
+redis.call("LTRIM", KEYS[1], -tonumber(ARGV[4]), -1)
+redis.call("EXPIRE", KEYS[1], ARGV[3])
+return 1
+`)
+
+// recordAction records an operator action on the task made by the request r.
+// toState is the state of the task after the action, or empty string if unknown.
+//
+// Recording is best-effort: a failure is logged and does not fail the request.
+func (tl *taskTimeline) recordAction(r *http.Request, qname, taskID, action, toState string) {
+	ev := &taskEvent{
+		Time:      time.Now(),
+		Type:      taskEventAction,
+		Action:    action,
+		FromState: routeTaskState(r),
+		ToState:   toState,
+		User:      requestUser(r),
+		RequestID: requestIDFromContext(r.Context()),
+	}
+	data, err := json.Marshal(ev)
+	if err == nil {
+		keys := []string{taskTimelineKey(qname, taskID), observedTaskStateKey(qname, taskID)}
+		err = recordTaskEventCmd.Run(r.Context(), tl.rc, keys,
+			data, int(taskTimelineTTL.Seconds()), maxTaskTimelineEvents, toState).Err()
+	}
+	if err != nil && tl.logger != nil {
+		tl.logger.Warn("Failed to record action in task timeline", slog.String("request_id", ev.RequestID),
+			slog.String("action", action), slog.String("queue", qname), slog.String("task_id", taskID),
+			slog.String("error", err.Error()))
+	}
+}
+
+// recordBulkAction records an operator action on all tasks in a state of the queue
+// (or of the group if gname is not empty) made by the request r.
+// toState is the state of the tasks after the action, or empty string if they were deleted.
+//
+// Recording is best-effort: a failure is logged and does not fail the request.
+func (tl *taskTimeline) recordBulkAction(r *http.Request, qname, gname, action, toState string, count int) {
+	if count == 0 {
+		return
+	}
+	ev := &taskEvent{
+		Time:      time.Now(),
+		Type:      taskEventBulkAction,
+		Action:    action,
+		FromState: routeTaskState(r),
+		ToState:   toState,
+		User:      requestUser(r),
+		RequestID: requestIDFromContext(r.Context()),
+		Group:     gname,
+		Count:     count,
+	}
+	data, err := json.Marshal(ev)
+	if err == nil {
+		key := bulkActionsKey(qname)
+		_, err = tl.rc.TxPipelined(r.Context(), func(p redis.Pipeliner) error {
+			p.RPush(r.Context(), key, data)
+			p.LTrim(r.Context(), key, -maxBulkActionEvents, -1)
+			p.Expire(r.Context(), key, taskTimelineTTL)
+			return nil
+		})
+	}
+	if err != nil && tl.logger != nil {
+		tl.logger.Warn("Failed to record bulk action in task timeline", slog.String("request_id", ev.RequestID),
+			slog.String("action", action), slog.String("queue", qname), slog.String("error", err.Error()))
+	}
+}
+
+// observe records a state change event for each task whose state differs from
+// the one observed last time. Tasks are observed by the taskStateSampler.
+//
+// Nothing is recorded in read-only mode.
+// Recording is best-effort: a failure is logged.
+func (tl *taskTimeline) observe(ctx context.Context, tasks ...*asynq.TaskInfo) {
+	if len(tasks) == 0 || (tl.readOnly != nil && tl.readOnly.enabled()) {
+		return
+	}
+	now := time.Now()
+	ttl := int(taskTimelineTTL.Seconds())
+	_, err := tl.rc.Pipelined(ctx, func(p redis.Pipeliner) error {
+		for _, t := range tasks {
+			state := t.State.String()
+			data, err := json.Marshal(&taskEvent{Time: now, Type: taskEventStateChange, ToState: state})
+			if err != nil {
+				return err
+			}
+			keys := []string{taskTimelineKey(t.Queue, t.ID), observedTaskStateKey(t.Queue, t.ID)}
+			// Use EVAL since EVALSHA cannot fall back to EVAL in a pipeline.
+			observeTaskStateCmd.Eval(ctx, p, keys, state, data, ttl, maxTaskTimelineEvents)
+		}
+		return nil
+	})
+	if err != nil && tl.logger != nil {
+		tl.logger.Warn("Failed to record task state changes in task timeline", slog.Int("tasks", len(tasks)),
+			slog.String("error", err.Error()))
+	}
+}
+
+// events returns the recorded events of the task in chronological order.
+func (tl *taskTimeline) events(ctx context.Context, qname, taskID string) ([]*taskEvent, error) {
+	return tl.readEvents(ctx, taskTimelineKey(qname, taskID))
+}
+
+// bulkActions returns the recorded bulk actions on the queue in chronological order.
+func (tl *taskTimeline) bulkActions(ctx context.Context, qname string) ([]*taskEvent, error) {
+	return tl.readEvents(ctx, bulkActionsKey(qname))
+}
+
+func (tl *taskTimeline) readEvents(ctx context.Context, key string) ([]*taskEvent, error) {
+	res, err := tl.rc.LRange(ctx, key, 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+	var events []*taskEvent
+	for _, s := range res {
+		var ev taskEvent
+		if err := json.Unmarshal([]byte(s), &ev); err != nil {
+			return nil, fmt.Errorf("could not decode timeline event %q: %v", s, err)
+		}
+		events = append(events, &ev)
+	}
+	return events, nil
+}
+
+// routeTaskState returns the task state in the path template of the route matched by r,
+// or empty string if the route is not for tasks in a specific state.
+func routeTaskState(r *http.Request) string {
+	route := mux.CurrentRoute(r)
+	if route == nil {
+		return ""
+	}
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return ""
+	}
+	return taskStateInRoute(tmpl)
+}
+
+// taskStateInRoute returns the task state in the route template.
+// For example, "/api/queues/{qname}/retry_tasks/{task_id}:run" returns "retry".
+func taskStateInRoute(route string) string {
+	for _, seg := range strings.Split(route, "/") {
+		if i := strings.Index(seg, ":"); i >= 0 {
+			seg = seg[:i]
+		}
+		if strings.HasSuffix(seg, "_tasks") {
+			return strings.TrimSuffix(seg, "_tasks")
+		}
+	}
+	return ""
+}
+
+// schedulerEnqueueTaskEvent returns the event of the scheduler enqueueing the task,
+// or nil if the task was not enqueued by any of the registered scheduler entries.
+func schedulerEnqueueTaskEvent(ctx context.Context, inspector *asynq.Inspector, info *asynq.TaskInfo) (*taskEvent, error) {
+	span := startSpan(ctx, "asynq.Inspector/SchedulerEntries")
+	entries, err := inspector.SchedulerEntries()
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range entries {
+		if e.Task.Type() != info.Type || schedulerEntryQueue(e) != info.Queue {
+			continue
+		}
+		span := startSpan(ctx, "asynq.Inspector/ListSchedulerEnqueueEvents")
+		events, err := inspector.ListSchedulerEnqueueEvents(e.ID, asynq.PageSize(maxSchedulerEnqueueEvents))
+		endSpan(span, err)
+		if err != nil {
+			return nil, err
+		}
+		for _, ev := range events {
+			if ev.TaskID == info.ID {
+				return &taskEvent{
+					Time:    ev.EnqueuedAt,
+					Type:    taskEventSchedulerEnqueue,
+					Message: fmt.Sprintf("Enqueued by scheduler entry %s (%s)", e.ID, e.Spec),
+				}, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+// schedulerEntryQueue returns the name of the queue the entry enqueues tasks to.
+func schedulerEntryQueue(e *asynq.SchedulerEntry) string {
+	qname := "default"
+	for _, opt := range e.Opts {
+		if opt.Type() == asynq.QueueOpt {
+			if s, ok := opt.Value().(string); ok {
+				qname = s
+			}
+		}
+	}
+	return qname
+}
+
+// lastBulkActionOnTask returns the latest of the bulk actions on the queue which likely
+// moved the task to its current state, or nil if there is none.
+//
+// A bulk action is a candidate if it moved tasks to the current state of the task,
+// happened after the last failure of the task, and the state of the task before the
+// action (as known from the recorded events) was the state the action applied to.
+func lastBulkActionOnTask(info *asynq.TaskInfo, recorded, bulk []*taskEvent) *taskEvent {
+	state := info.State.String()
+	for i := len(bulk) - 1; i >= 0; i-- {
+		ev := bulk[i]
+		if ev.ToState != state || ev.Time.Before(info.LastFailedAt) {
+			continue
+		}
+		if ev.Group != "" && info.Group != "" && ev.Group != info.Group {
+			continue
+		}
+		prev := ""
+		for _, r := range recorded {
+			if r.Time.Before(ev.Time) && r.ToState != "" {
+				prev = r.ToState
+			}
+		}
+		if prev != "" && prev != ev.FromState {
+			continue
+		}
+		return ev
+	}
+	return nil
+}
+
+// buildTaskTimeline merges the recorded events with the events derived from the task info
+// and returns them in chronological order.
+// bulk is the bulk action which likely applied to the task, if any.
+func buildTaskTimeline(info *asynq.TaskInfo, recorded []*taskEvent, enqueued, bulk *taskEvent) []*taskEvent {
+	events := append([]*taskEvent{}, recorded...)
+	if enqueued != nil {
+		events = append(events, enqueued)
+	}
+	if bulk != nil {
+		events = append(events, bulk)
+	}
+	if !info.LastFailedAt.IsZero() {
+		events = append(events, &taskEvent{
+			Time:    info.LastFailedAt,
+			Type:    taskEventFailed,
+			Message: info.LastErr,
+		})
+	}
+	if !info.CompletedAt.IsZero() {
+		events = append(events, &taskEvent{
+			Time: info.CompletedAt,
+			Type: taskEventCompleted,
+		})
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Time.Before(events[j].Time) })
+	return events
+}
@@ -0,0 +1,121 @@
+package asynqmon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/hibiken/asynq"
+)
+
+func TestTaskStateInRoute(t *testing.T) {
+	tests := []struct {
+		route string
+		want  string
+	}{
+		{"/api/queues/{qname}/retry_tasks/{task_id}:run", "retry"},
+		{"/api/queues/{qname}/pending_tasks:batch_archive", "pending"},
+		{"/api/queues/{qname}/groups/{gname}/aggregating_tasks/{task_id}", "aggregating"},
+		{"/api/queues/{qname}/tasks/{task_id}", ""},
+		{"/api/queues/{qname}:pause", ""},
+	}
+	for _, tc := range tests {
+		if got := taskStateInRoute(tc.route); got != tc.want {
+			t.Errorf("taskStateInRoute(%q) = %q, want %q", tc.route, got, tc.want)
+		}
+	}
+}
+
+func TestBuildTaskTimeline(t *testing.T) {
+	now := time.Now()
+	info := &asynq.TaskInfo{
+		ID:           "abc",
+		Queue:        "default",
+		State:        asynq.TaskStateCompleted,
+		LastErr:      "connection refused",
+		LastFailedAt: now.Add(-3 * time.Minute),
+		CompletedAt:  now.Add(-1 * time.Minute),
+	}
+	recorded := []*taskEvent{
+		{Time: now.Add(-4 * time.Minute), Type: taskEventStateChange, ToState: "pending"},
+		{Time: now.Add(-2 * time.Minute), Type: taskEventAction, Action: "run", FromState: "retry", ToState: "pending", User: "alice"},
+	}
+	enqueued := &taskEvent{Time: now.Add(-5 * time.Minute), Type: taskEventSchedulerEnqueue}
+
+	want := []*taskEvent{
+		enqueued,
+		recorded[0],
+		{Time: now.Add(-3 * time.Minute), Type: taskEventFailed, Message: "connection refused"},
+		recorded[1],
+		{Time: now.Add(-1 * time.Minute), Type: taskEventCompleted},
+	}
+	if diff := cmp.Diff(want, buildTaskTimeline(info, recorded, enqueued, nil)); diff != "" {
+		t.Errorf("buildTaskTimeline diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestLastBulkActionOnTask(t *testing.T) {
+	now := time.Now()
+	bulk := []*taskEvent{
+		{Time: now.Add(-50 * time.Minute), Type: taskEventBulkAction, Action: "run_all", FromState: "retry", ToState: "pending", Count: 3},
+		{Time: now.Add(-40 * time.Minute), Type: taskEventBulkAction, Action: "archive_all", FromState: "retry", ToState: "archived", Count: 5},
+		{Time: now.Add(-30 * time.Minute), Type: taskEventBulkAction, Action: "run_all", FromState: "archived", ToState: "pending", Count: 2},
+		{Time: now.Add(-20 * time.Minute), Type: taskEventBulkAction, Action: "run_all", FromState: "aggregating", ToState: "pending", Group: "batch", Count: 4},
+	}
+	tests := []struct {
+		desc     string
+		info     *asynq.TaskInfo
+		recorded []*taskEvent
+		want     *taskEvent
+	}{
+		{
+			desc: "latest bulk action to the current state",
+			info: &asynq.TaskInfo{State: asynq.TaskStatePending},
+			want: bulk[3],
+		},
+		{
+			desc:     "state before the action known from recorded events",
+			info:     &asynq.TaskInfo{State: asynq.TaskStatePending, Group: "other"},
+			recorded: []*taskEvent{{Time: now.Add(-45 * time.Minute), Type: taskEventStateChange, ToState: "retry"}},
+			want:     bulk[0],
+		},
+		{
+			desc:     "archived task which was moved to archived by archive_all",
+			info:     &asynq.TaskInfo{State: asynq.TaskStateArchived, LastFailedAt: now.Add(-42 * time.Minute)},
+			recorded: []*taskEvent{{Time: now.Add(-41 * time.Minute), Type: taskEventStateChange, ToState: "retry"}},
+			want:     bulk[1],
+		},
+		{
+			desc: "task failed after the bulk action",
+			info: &asynq.TaskInfo{State: asynq.TaskStateArchived, LastFailedAt: now.Add(-10 * time.Minute)},
+			want: nil,
+		},
+		{
+			desc: "no bulk action to the current state",
+			info: &asynq.TaskInfo{State: asynq.TaskStateScheduled},
+			want: nil,
+		},
+	}
+	for _, tc := range tests {
+		if got := lastBulkActionOnTask(tc.info, tc.recorded, bulk); got != tc.want {
+			t.Errorf("%s: lastBulkActionOnTask returned %+v, want %+v", tc.desc, got, tc.want)
+		}
+	}
+}
+
+func TestTaskTimelineObserveReadOnly(t *testing.T) {
+	readOnly := &readOnlyMode{}
+	readOnly.set(true)
+	// The timeline has no redis client, so observe panics if it writes to redis.
+	tl := newTaskTimeline(nil, readOnly, nil)
+	tl.observe(context.Background(), &asynq.TaskInfo{ID: "abc", Queue: "default", State: asynq.TaskStatePending})
+}
+
+func TestTaskStateSamplerReadOnly(t *testing.T) {
+	readOnly := &readOnlyMode{}
+	readOnly.set(true)
+	// The sampler has no inspector, so sample panics if it lists tasks.
+	s := newTaskStateSampler(nil, newTaskTimeline(nil, readOnly, nil), time.Minute, nil)
+	s.sample()
+}
@@ -0,0 +1,127 @@
+package asynqmon
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - OpenTelemetry trace spans for API requests, inspector calls and
+//     redis commands
+// ****************************************************************************
+
+// Name of the tracer used to create spans.
+const tracerName = "github.com/hibiken/asynqmon"
+
+// requestTracer creates a span for each API request.
+type requestTracer struct {
+	tracer   trace.Tracer
+	rootPath string
+}
+
+func newRequestTracer(tp trace.TracerProvider, rootPath string) *requestTracer {
+	if tp == nil {
+		tp = otel.GetTracerProvider()
+	}
+	return &requestTracer{tracer: tp.Tracer(tracerName), rootPath: rootPath}
+}
+
+// middleware is a middleware function to trace API requests.
+// The trace context in the request headers (e.g. traceparent) is used as the parent of the span.
+func (t *requestTracer) middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+		route := routeTemplate(r, t.rootPath)
+		ctx, span := t.tracer.Start(ctx, fmt.Sprintf("%s %s", r.Method, route),
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", r.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", r.URL.Path),
+				attribute.String("asynqmon.request_id", requestIDFromContext(ctx)),
+			))
+		defer span.End()
+
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r.WithContext(ctx))
+
+		span.SetAttributes(attribute.Int("http.response.status_code", rec.status))
+		if rec.status >= 500 {
+			span.SetStatus(codes.Error, http.StatusText(rec.status))
+		}
+	})
+}
+
+// startSpan starts a span as a child of the span in ctx.
+// If ctx has no span (e.g. tracing is disabled), the returned span is not recorded.
+func startSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) trace.Span {
+	parent := trace.SpanFromContext(ctx)
+	_, span := parent.TracerProvider().Tracer(tracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+	return span
+}
+
+// endSpan records err (if non-nil) and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// redisTracingHook is a redis.Hook to create a span for each redis command.
+//
+// Spans are only created for commands issued with a context that has a span.
+// Commands issued by asynq.Inspector do not have one since the Inspector API
+// does not take a context; they are covered by the span of the inspector call.
+type redisTracingHook struct{}
+
+func (redisTracingHook) DialHook(next redis.DialHook) redis.DialHook {
+	return next
+}
+
+func (redisTracingHook) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			return next(ctx, cmd)
+		}
+		span := startSpan(ctx, "redis "+cmd.Name(),
+			attribute.String("db.system", "redis"),
+			attribute.String("db.operation", cmd.Name()))
+		err := next(ctx, cmd)
+		endSpan(span, redisError(err))
+		return err
+	}
+}
+
+func (redisTracingHook) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		if !trace.SpanContextFromContext(ctx).IsValid() {
+			return next(ctx, cmds)
+		}
+		span := startSpan(ctx, "redis pipeline",
+			attribute.String("db.system", "redis"),
+			attribute.Int("db.redis.num_cmd", len(cmds)))
+		err := next(ctx, cmds)
+		endSpan(span, redisError(err))
+		return err
+	}
+}
+
+// redisError returns err unless it is redis.Nil, which indicates a missing key rather than a failure.
+func redisError(err error) error {
+	if errors.Is(err, redis.Nil) {
+		return nil
+	}
+	return err
+}
@@ -22,6 +22,8 @@ import SettingsIcon from "@material-ui/icons/Settings";
 import ScheduleIcon from "@material-ui/icons/Schedule";
 import FeedbackIcon from "@material-ui/icons/Feedback";
 import TimelineIcon from "@material-ui/icons/Timeline";
+import TrackChangesIcon from "@material-ui/icons/TrackChanges";
+import BookmarksIcon from "@material-ui/icons/Bookmarks";
 import DoubleArrowIcon from "@material-ui/icons/DoubleArrow";
 import CloseIcon from "@material-ui/icons/Close";
 import { AppState } from "./store";
@@ -30,6 +32,7 @@ import { isDarkTheme, useTheme } from "./theme";
 import { closeSnackbar } from "./actions/snackbarActions";
 import { toggleDrawer } from "./actions/settingsActions";
 import ListItemLink from "./components/ListItemLink";
+import DeleteConfirmationDialog from "./components/DeleteConfirmationDialog";
 import SchedulersView from "./views/SchedulersView";
 import DashboardView from "./views/DashboardView";
 import TasksView from "./views/TasksView";
@@ -37,7 +40,13 @@ import TaskDetailsView from "./views/TaskDetailsView";
 import SettingsView from "./views/SettingsView";
 import ServersView from "./views/ServersView";
 import RedisInfoView from "./views/RedisInfoView";
+import RedisDiagnosticsView from "./views/RedisDiagnosticsView";
+import ErrorClustersView from "./views/ErrorClustersView";
+import CompletedStatsView from "./views/CompletedStatsView";
+import SLOsView from "./views/SLOsView";
 import MetricsView from "./views/MetricsView";
+import SavedViewsView from "./views/SavedViewsView";
+import SavedViewRedirectView from "./views/SavedViewRedirectView";
 import PageNotFoundView from "./views/PageNotFoundView";
 import { ReactComponent as Logo } from "./images/logo-color.svg";
 import { ReactComponent as LogoDarkTheme } from "./images/logo-white.svg";
@@ -217,6 +226,7 @@ function App(props: ConnectedProps<typeof connector>) {
                   }
                 />
               </Snackbar>
+              <DeleteConfirmationDialog />
               <div className={classes.appBarSpacer} />
               <div className={classes.sidebarContainer}>
                 <List>
@@ -241,6 +251,16 @@ function App(props: ConnectedProps<typeof connector>) {
                       primary="Redis"
                       icon={<LayersIcon />}
                     />
+                    <ListItemLink
+                      to={paths.SLOS}
+                      primary="SLOs"
+                      icon={<TrackChangesIcon />}
+                    />
+                    <ListItemLink
+                      to={paths.SAVED_VIEWS}
+                      primary="Saved Views"
+                      icon={<BookmarksIcon />}
+                    />
                     {window.PROMETHEUS_SERVER_ADDRESS && (
                       <ListItemLink
                         to={paths.QUEUE_METRICS}
@@ -277,6 +297,12 @@ function App(props: ConnectedProps<typeof connector>) {
                   <Route exact path={paths.TASK_DETAILS}>
                     <TaskDetailsView />
                   </Route>
+                  <Route exact path={paths.ERROR_CLUSTERS}>
+                    <ErrorClustersView />
+                  </Route>
+                  <Route exact path={paths.COMPLETED_STATS}>
+                    <CompletedStatsView />
+                  </Route>
                   <Route exact path={paths.QUEUE_DETAILS}>
                     <TasksView />
                   </Route>
@@ -286,9 +312,15 @@ function App(props: ConnectedProps<typeof connector>) {
                   <Route exact path={paths.SERVERS}>
                     <ServersView />
                   </Route>
+                  <Route exact path={paths.SLOS}>
+                    <SLOsView />
+                  </Route>
                   <Route exact path={paths.REDIS}>
                     <RedisInfoView />
                   </Route>
+                  <Route exact path={paths.REDIS_DIAGNOSTICS}>
+                    <RedisDiagnosticsView />
+                  </Route>
                   <Route exact path={paths.SETTINGS}>
                     <SettingsView />
                   </Route>
@@ -298,6 +330,12 @@ function App(props: ConnectedProps<typeof connector>) {
                   <Route exact path={paths.QUEUE_METRICS}>
                     <MetricsView />
                   </Route>
+                  <Route exact path={paths.SAVED_VIEWS}>
+                    <SavedViewsView />
+                  </Route>
+                  <Route exact path={paths.SAVED_VIEW}>
+                    <SavedViewRedirectView />
+                  </Route>
                   <Route path="*">
                     <PageNotFoundView />
                   </Route>
@@ -0,0 +1,106 @@
+import { Dispatch } from "redux";
+import {
+  Annotation,
+  createAnnotation,
+  CreateAnnotationRequest,
+  deleteAnnotation,
+  listAnnotations,
+  ListAnnotationsResponse,
+} from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of annotations related action types.
+export const LIST_ANNOTATIONS_BEGIN = "LIST_ANNOTATIONS_BEGIN";
+export const LIST_ANNOTATIONS_SUCCESS = "LIST_ANNOTATIONS_SUCCESS";
+export const LIST_ANNOTATIONS_ERROR = "LIST_ANNOTATIONS_ERROR";
+export const CREATE_ANNOTATION_SUCCESS = "CREATE_ANNOTATION_SUCCESS";
+export const DELETE_ANNOTATION_SUCCESS = "DELETE_ANNOTATION_SUCCESS";
+export const ANNOTATION_ACTION_ERROR = "ANNOTATION_ACTION_ERROR";
+
+interface ListAnnotationsBeginAction {
+  type: typeof LIST_ANNOTATIONS_BEGIN;
+}
+
+interface ListAnnotationsSuccessAction {
+  type: typeof LIST_ANNOTATIONS_SUCCESS;
+  payload: ListAnnotationsResponse;
+}
+
+interface ListAnnotationsErrorAction {
+  type: typeof LIST_ANNOTATIONS_ERROR;
+  error: string;
+}
+
+interface CreateAnnotationSuccessAction {
+  type: typeof CREATE_ANNOTATION_SUCCESS;
+  payload: Annotation;
+}
+
+interface DeleteAnnotationSuccessAction {
+  type: typeof DELETE_ANNOTATION_SUCCESS;
+  annotationId: string;
+}
+
+interface AnnotationActionErrorAction {
+  type: typeof ANNOTATION_ACTION_ERROR;
+  error: string;
+}
+
+// Union of all annotations related actions.
+export type AnnotationsActionTypes =
+  | ListAnnotationsBeginAction
+  | ListAnnotationsSuccessAction
+  | ListAnnotationsErrorAction
+  | CreateAnnotationSuccessAction
+  | DeleteAnnotationSuccessAction
+  | AnnotationActionErrorAction;
+
+export function listAnnotationsAsync(
+  endTime: number,
+  duration: number,
+  queues: string[]
+) {
+  return async (dispatch: Dispatch<AnnotationsActionTypes>) => {
+    dispatch({ type: LIST_ANNOTATIONS_BEGIN });
+    try {
+      const response = await listAnnotations(endTime, duration, queues);
+      dispatch({ type: LIST_ANNOTATIONS_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        "listAnnotationsAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({ type: LIST_ANNOTATIONS_ERROR, error: toErrorString(error) });
+    }
+  };
+}
+
+export function createAnnotationAsync(req: CreateAnnotationRequest) {
+  return async (dispatch: Dispatch<AnnotationsActionTypes>) => {
+    try {
+      const response = await createAnnotation(req);
+      dispatch({ type: CREATE_ANNOTATION_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        "createAnnotationAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({ type: ANNOTATION_ACTION_ERROR, error: toErrorString(error) });
+    }
+  };
+}
+
+export function deleteAnnotationAsync(annotationId: string) {
+  return async (dispatch: Dispatch<AnnotationsActionTypes>) => {
+    try {
+      await deleteAnnotation(annotationId);
+      dispatch({ type: DELETE_ANNOTATION_SUCCESS, annotationId });
+    } catch (error) {
+      console.error(
+        "deleteAnnotationAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({ type: ANNOTATION_ACTION_ERROR, error: toErrorString(error) });
+    }
+  };
+}
@@ -0,0 +1,55 @@
+import { Dispatch } from "redux";
+import { CompletedTaskStatsResponse, getCompletedTaskStats } from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of completed-task-stats related action types.
+export const GET_COMPLETED_STATS_BEGIN = "GET_COMPLETED_STATS_BEGIN";
+export const GET_COMPLETED_STATS_SUCCESS = "GET_COMPLETED_STATS_SUCCESS";
+export const GET_COMPLETED_STATS_ERROR = "GET_COMPLETED_STATS_ERROR";
+
+interface GetCompletedStatsBeginAction {
+  type: typeof GET_COMPLETED_STATS_BEGIN;
+  queue: string;
+}
+
+interface GetCompletedStatsSuccessAction {
+  type: typeof GET_COMPLETED_STATS_SUCCESS;
+  queue: string;
+  payload: CompletedTaskStatsResponse;
+}
+
+interface GetCompletedStatsErrorAction {
+  type: typeof GET_COMPLETED_STATS_ERROR;
+  queue: string;
+  error: string;
+}
+
+// Union of all completed-task-stats related actions.
+export type CompletedStatsActionTypes =
+  | GetCompletedStatsBeginAction
+  | GetCompletedStatsSuccessAction
+  | GetCompletedStatsErrorAction;
+
+export function getCompletedStatsAsync(queue: string, durationSeconds: number) {
+  return async (dispatch: Dispatch<CompletedStatsActionTypes>) => {
+    dispatch({ type: GET_COMPLETED_STATS_BEGIN, queue });
+    try {
+      const response = await getCompletedTaskStats(queue, durationSeconds);
+      dispatch({
+        type: GET_COMPLETED_STATS_SUCCESS,
+        queue,
+        payload: response,
+      });
+    } catch (error) {
+      console.error(
+        "getCompletedStatsAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({
+        type: GET_COMPLETED_STATS_ERROR,
+        queue,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
@@ -0,0 +1,163 @@
+import { Dispatch } from "redux";
+import {
+  archiveErrorCluster,
+  BatchArchiveTasksResponse,
+  BatchDeleteTasksResponse,
+  BatchRunTasksResponse,
+  deleteErrorCluster,
+  ErrorClusterTaskState,
+  listErrorClusters,
+  ListErrorClustersResponse,
+  runErrorCluster,
+} from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of error-clusters related action types.
+export const LIST_ERROR_CLUSTERS_BEGIN = "LIST_ERROR_CLUSTERS_BEGIN";
+export const LIST_ERROR_CLUSTERS_SUCCESS = "LIST_ERROR_CLUSTERS_SUCCESS";
+export const LIST_ERROR_CLUSTERS_ERROR = "LIST_ERROR_CLUSTERS_ERROR";
+export const RUN_ERROR_CLUSTER_SUCCESS = "RUN_ERROR_CLUSTER_SUCCESS";
+export const ARCHIVE_ERROR_CLUSTER_SUCCESS = "ARCHIVE_ERROR_CLUSTER_SUCCESS";
+export const DELETE_ERROR_CLUSTER_SUCCESS = "DELETE_ERROR_CLUSTER_SUCCESS";
+export const ERROR_CLUSTER_ACTION_ERROR = "ERROR_CLUSTER_ACTION_ERROR";
+
+interface ListErrorClustersBeginAction {
+  type: typeof LIST_ERROR_CLUSTERS_BEGIN;
+  queue: string;
+  state: ErrorClusterTaskState;
+}
+
+interface ListErrorClustersSuccessAction {
+  type: typeof LIST_ERROR_CLUSTERS_SUCCESS;
+  queue: string;
+  state: ErrorClusterTaskState;
+  payload: ListErrorClustersResponse;
+}
+
+interface ListErrorClustersErrorAction {
+  type: typeof LIST_ERROR_CLUSTERS_ERROR;
+  queue: string;
+  state: ErrorClusterTaskState;
+  error: string;
+}
+
+interface RunErrorClusterSuccessAction {
+  type: typeof RUN_ERROR_CLUSTER_SUCCESS;
+  payload: BatchRunTasksResponse;
+}
+
+interface ArchiveErrorClusterSuccessAction {
+  type: typeof ARCHIVE_ERROR_CLUSTER_SUCCESS;
+  payload: BatchArchiveTasksResponse;
+}
+
+interface DeleteErrorClusterSuccessAction {
+  type: typeof DELETE_ERROR_CLUSTER_SUCCESS;
+  payload: BatchDeleteTasksResponse;
+}
+
+interface ErrorClusterActionErrorAction {
+  type: typeof ERROR_CLUSTER_ACTION_ERROR;
+  error: string;
+}
+
+// Union of all error-clusters related actions.
+export type ErrorClustersActionTypes =
+  | ListErrorClustersBeginAction
+  | ListErrorClustersSuccessAction
+  | ListErrorClustersErrorAction
+  | RunErrorClusterSuccessAction
+  | ArchiveErrorClusterSuccessAction
+  | DeleteErrorClusterSuccessAction
+  | ErrorClusterActionErrorAction;
+
+export function listErrorClustersAsync(
+  queue: string,
+  state: ErrorClusterTaskState
+) {
+  return async (dispatch: Dispatch<ErrorClustersActionTypes>) => {
+    dispatch({ type: LIST_ERROR_CLUSTERS_BEGIN, queue, state });
+    try {
+      const response = await listErrorClusters(queue, state);
+      dispatch({
+        type: LIST_ERROR_CLUSTERS_SUCCESS,
+        queue,
+        state,
+        payload: response,
+      });
+    } catch (error) {
+      console.error(
+        "listErrorClustersAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({
+        type: LIST_ERROR_CLUSTERS_ERROR,
+        queue,
+        state,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
+
+export function runErrorClusterAsync(
+  queue: string,
+  state: ErrorClusterTaskState,
+  clusterId: string
+) {
+  return async (dispatch: Dispatch<ErrorClustersActionTypes>) => {
+    try {
+      const response = await runErrorCluster(queue, state, clusterId);
+      dispatch({ type: RUN_ERROR_CLUSTER_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        "runErrorClusterAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({
+        type: ERROR_CLUSTER_ACTION_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
+
+export function archiveErrorClusterAsync(queue: string, clusterId: string) {
+  return async (dispatch: Dispatch<ErrorClustersActionTypes>) => {
+    try {
+      const response = await archiveErrorCluster(queue, clusterId);
+      dispatch({ type: ARCHIVE_ERROR_CLUSTER_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        "archiveErrorClusterAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({
+        type: ERROR_CLUSTER_ACTION_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
+
+export function deleteErrorClusterAsync(
+  queue: string,
+  state: ErrorClusterTaskState,
+  clusterId: string
+) {
+  return async (dispatch: Dispatch<ErrorClustersActionTypes>) => {
+    try {
+      const response = await deleteErrorCluster(queue, state, clusterId);
+      dispatch({ type: DELETE_ERROR_CLUSTER_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        "deleteErrorClusterAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({
+        type: ERROR_CLUSTER_ACTION_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
@@ -1,11 +1,22 @@
 import { Dispatch } from "redux";
-import { listGroups, ListGroupsResponse } from "../api";
+import {
+  flushGroups,
+  FlushGroupsResponse,
+  listGroupDetails,
+  ListGroupDetailsResponse,
+  listGroups,
+  ListGroupsResponse,
+} from "../api";
 import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
 
 // List of groups related action types.
 export const LIST_GROUPS_BEGIN = "LIST_GROUPS_BEGIN";
 export const LIST_GROUPS_SUCCESS = "LIST_GROUPS_SUCCESS";
 export const LIST_GROUPS_ERROR = "LIST_GROUPS_ERROR";
+export const LIST_GROUP_DETAILS_SUCCESS = "LIST_GROUP_DETAILS_SUCCESS";
+export const LIST_GROUP_DETAILS_ERROR = "LIST_GROUP_DETAILS_ERROR";
+export const FLUSH_GROUPS_SUCCESS = "FLUSH_GROUPS_SUCCESS";
+export const FLUSH_GROUPS_ERROR = "FLUSH_GROUPS_ERROR";
 
 interface ListGroupsBeginAction {
   type: typeof LIST_GROUPS_BEGIN;
@@ -24,11 +35,39 @@ interface ListGroupsErrorAction {
   error: string;
 }
 
+interface ListGroupDetailsSuccessAction {
+  type: typeof LIST_GROUP_DETAILS_SUCCESS;
+  payload: ListGroupDetailsResponse;
+  queue: string;
+}
+
+interface ListGroupDetailsErrorAction {
+  type: typeof LIST_GROUP_DETAILS_ERROR;
+  queue: string;
+  error: string;
+}
+
+interface FlushGroupsSuccessAction {
+  type: typeof FLUSH_GROUPS_SUCCESS;
+  payload: FlushGroupsResponse;
+  queue: string;
+}
+
+interface FlushGroupsErrorAction {
+  type: typeof FLUSH_GROUPS_ERROR;
+  queue: string;
+  error: string;
+}
+
 // Union of all groups related action types.
 export type GroupsActionTypes =
   | ListGroupsBeginAction
   | ListGroupsSuccessAction
-  | ListGroupsErrorAction;
+  | ListGroupsErrorAction
+  | ListGroupDetailsSuccessAction
+  | ListGroupDetailsErrorAction
+  | FlushGroupsSuccessAction
+  | FlushGroupsErrorAction;
 
 export function listGroupsAsync(qname: string) {
   return async (dispatch: Dispatch<GroupsActionTypes>) => {
@@ -50,3 +89,44 @@ export function listGroupsAsync(qname: string) {
     }
   };
 }
+
+export function listGroupDetailsAsync(qname: string) {
+  return async (dispatch: Dispatch<GroupsActionTypes>) => {
+    try {
+      const response = await listGroupDetails(qname);
+      dispatch({
+        type: LIST_GROUP_DETAILS_SUCCESS,
+        payload: response,
+        queue: qname,
+      });
+    } catch (error) {
+      console.error(
+        `listGroupDetailsAsync: ${toErrorStringWithHttpStatus(error)}`
+      );
+      dispatch({
+        type: LIST_GROUP_DETAILS_ERROR,
+        error: toErrorString(error),
+        queue: qname,
+      });
+    }
+  };
+}
+
+export function flushGroupsAsync(
+  qname: string,
+  selector: { groups: string[] } | { older_than_seconds: number }
+) {
+  return async (dispatch: Dispatch<GroupsActionTypes>) => {
+    try {
+      const response = await flushGroups(qname, selector);
+      dispatch({ type: FLUSH_GROUPS_SUCCESS, payload: response, queue: qname });
+    } catch (error) {
+      console.error(`flushGroupsAsync: ${toErrorStringWithHttpStatus(error)}`);
+      dispatch({
+        type: FLUSH_GROUPS_ERROR,
+        error: toErrorString(error),
+        queue: qname,
+      });
+    }
+  };
+}
@@ -0,0 +1,96 @@
+import { Dispatch } from "redux";
+import {
+  addErrorClusterNote,
+  addTaskNote,
+  deleteErrorClusterNote,
+  deleteTaskNote,
+  Note,
+} from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of notes related action types.
+export const ADD_NOTE_SUCCESS = "ADD_NOTE_SUCCESS";
+export const DELETE_NOTE_SUCCESS = "DELETE_NOTE_SUCCESS";
+export const NOTE_ACTION_ERROR = "NOTE_ACTION_ERROR";
+
+interface AddNoteSuccessAction {
+  type: typeof ADD_NOTE_SUCCESS;
+  payload: Note;
+}
+
+interface DeleteNoteSuccessAction {
+  type: typeof DELETE_NOTE_SUCCESS;
+  noteId: string;
+}
+
+interface NoteActionErrorAction {
+  type: typeof NOTE_ACTION_ERROR;
+  error: string;
+}
+
+// Union of all notes related actions.
+export type NotesActionTypes =
+  | AddNoteSuccessAction
+  | DeleteNoteSuccessAction
+  | NoteActionErrorAction;
+
+export function addTaskNoteAsync(qname: string, taskId: string, text: string) {
+  return async (dispatch: Dispatch<NotesActionTypes>) => {
+    try {
+      const response = await addTaskNote(qname, taskId, text);
+      dispatch({ type: ADD_NOTE_SUCCESS, payload: response });
+    } catch (error) {
+      console.error("addTaskNoteAsync: ", toErrorStringWithHttpStatus(error));
+      dispatch({ type: NOTE_ACTION_ERROR, error: toErrorString(error) });
+    }
+  };
+}
+
+export function deleteTaskNoteAsync(
+  qname: string,
+  taskId: string,
+  noteId: string
+) {
+  return async (dispatch: Dispatch<NotesActionTypes>) => {
+    try {
+      await deleteTaskNote(qname, taskId, noteId);
+      dispatch({ type: DELETE_NOTE_SUCCESS, noteId });
+    } catch (error) {
+      console.error(
+        "deleteTaskNoteAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({ type: NOTE_ACTION_ERROR, error: toErrorString(error) });
+    }
+  };
+}
+
+export function addErrorClusterNoteAsync(clusterId: string, text: string) {
+  return async (dispatch: Dispatch<NotesActionTypes>) => {
+    try {
+      const response = await addErrorClusterNote(clusterId, text);
+      dispatch({ type: ADD_NOTE_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        "addErrorClusterNoteAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({ type: NOTE_ACTION_ERROR, error: toErrorString(error) });
+    }
+  };
+}
+
+export function deleteErrorClusterNoteAsync(clusterId: string, noteId: string) {
+  return async (dispatch: Dispatch<NotesActionTypes>) => {
+    try {
+      await deleteErrorClusterNote(clusterId, noteId);
+      dispatch({ type: DELETE_NOTE_SUCCESS, noteId });
+    } catch (error) {
+      console.error(
+        "deleteErrorClusterNoteAsync: ",
+        toErrorStringWithHttpStatus(error)
+      );
+      dispatch({ type: NOTE_ACTION_ERROR, error: toErrorString(error) });
+    }
+  };
+}
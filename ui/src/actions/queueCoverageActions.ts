@@ -0,0 +1,44 @@
+import { Dispatch } from "redux";
+import { getQueueCoverage, QueueCoverageResponse } from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of queue coverage related action types.
+export const GET_QUEUE_COVERAGE_BEGIN = "GET_QUEUE_COVERAGE_BEGIN";
+export const GET_QUEUE_COVERAGE_SUCCESS = "GET_QUEUE_COVERAGE_SUCCESS";
+export const GET_QUEUE_COVERAGE_ERROR = "GET_QUEUE_COVERAGE_ERROR";
+
+interface GetQueueCoverageBeginAction {
+  type: typeof GET_QUEUE_COVERAGE_BEGIN;
+}
+interface GetQueueCoverageSuccessAction {
+  type: typeof GET_QUEUE_COVERAGE_SUCCESS;
+  payload: QueueCoverageResponse;
+}
+interface GetQueueCoverageErrorAction {
+  type: typeof GET_QUEUE_COVERAGE_ERROR;
+  error: string; // error description
+}
+
+// Union of all queue coverage related actions.
+export type QueueCoverageActionTypes =
+  | GetQueueCoverageBeginAction
+  | GetQueueCoverageSuccessAction
+  | GetQueueCoverageErrorAction;
+
+export function getQueueCoverageAsync() {
+  return async (dispatch: Dispatch<QueueCoverageActionTypes>) => {
+    dispatch({ type: GET_QUEUE_COVERAGE_BEGIN });
+    try {
+      const response = await getQueueCoverage();
+      dispatch({ type: GET_QUEUE_COVERAGE_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        `getQueueCoverageAsync: ${toErrorStringWithHttpStatus(error)}`
+      );
+      dispatch({
+        type: GET_QUEUE_COVERAGE_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
@@ -0,0 +1,51 @@
+import { Dispatch } from "redux";
+import {
+  getQueueRanking,
+  QueueRankingResponse,
+  QueueRankingSortBy,
+} from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of queue ranking related action types.
+export const GET_QUEUE_RANKING_BEGIN = "GET_QUEUE_RANKING_BEGIN";
+export const GET_QUEUE_RANKING_SUCCESS = "GET_QUEUE_RANKING_SUCCESS";
+export const GET_QUEUE_RANKING_ERROR = "GET_QUEUE_RANKING_ERROR";
+
+interface GetQueueRankingBeginAction {
+  type: typeof GET_QUEUE_RANKING_BEGIN;
+}
+interface GetQueueRankingSuccessAction {
+  type: typeof GET_QUEUE_RANKING_SUCCESS;
+  payload: QueueRankingResponse;
+}
+interface GetQueueRankingErrorAction {
+  type: typeof GET_QUEUE_RANKING_ERROR;
+  error: string; // error description
+}
+
+// Union of all queue ranking related actions.
+export type QueueRankingActionTypes =
+  | GetQueueRankingBeginAction
+  | GetQueueRankingSuccessAction
+  | GetQueueRankingErrorAction;
+
+export function getQueueRankingAsync(
+  sortBy: QueueRankingSortBy,
+  durationSeconds: number
+) {
+  return async (dispatch: Dispatch<QueueRankingActionTypes>) => {
+    dispatch({ type: GET_QUEUE_RANKING_BEGIN });
+    try {
+      const response = await getQueueRanking(sortBy, durationSeconds);
+      dispatch({ type: GET_QUEUE_RANKING_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        `getQueueRankingAsync: ${toErrorStringWithHttpStatus(error)}`
+      );
+      dispatch({
+        type: GET_QUEUE_RANKING_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
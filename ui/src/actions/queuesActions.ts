@@ -118,14 +118,14 @@ export function listQueuesAsync() {
   };
 }
 
-export function deleteQueueAsync(qname: string) {
+export function deleteQueueAsync(qname: string, confirmationToken?: string) {
   return async (dispatch: Dispatch<QueuesActionTypes>) => {
     dispatch({
       type: DELETE_QUEUE_BEGIN,
       queue: qname,
     });
     try {
-      await deleteQueue(qname);
+      await deleteQueue(qname, confirmationToken);
       // FIXME: this action doesn't get dispatched when server stalls
       dispatch({
         type: DELETE_QUEUE_SUCCESS,
@@ -0,0 +1,68 @@
+import { Dispatch } from "redux";
+import {
+  getRedisClients,
+  getRedisCommandStats,
+  getRedisLatency,
+  getRedisSlowLog,
+  RedisClientsResponse,
+  RedisCommandStatsResponse,
+  RedisLatencyResponse,
+  RedisSlowLogResponse,
+} from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of redis-diagnostics related action types.
+export const GET_REDIS_DIAGNOSTICS_BEGIN = "GET_REDIS_DIAGNOSTICS_BEGIN";
+export const GET_REDIS_DIAGNOSTICS_SUCCESS = "GET_REDIS_DIAGNOSTICS_SUCCESS";
+export const GET_REDIS_DIAGNOSTICS_ERROR = "GET_REDIS_DIAGNOSTICS_ERROR";
+
+interface GetRedisDiagnosticsBeginAction {
+  type: typeof GET_REDIS_DIAGNOSTICS_BEGIN;
+}
+
+interface GetRedisDiagnosticsSuccessAction {
+  type: typeof GET_REDIS_DIAGNOSTICS_SUCCESS;
+  payload: {
+    slowLog: RedisSlowLogResponse;
+    latency: RedisLatencyResponse;
+    clients: RedisClientsResponse;
+    commandStats: RedisCommandStatsResponse;
+  };
+}
+
+interface GetRedisDiagnosticsErrorAction {
+  type: typeof GET_REDIS_DIAGNOSTICS_ERROR;
+  error: string;
+}
+
+// Union of all redis-diagnostics related actions.
+export type RedisDiagnosticsActionTypes =
+  | GetRedisDiagnosticsBeginAction
+  | GetRedisDiagnosticsSuccessAction
+  | GetRedisDiagnosticsErrorAction;
+
+export function getRedisDiagnosticsAsync() {
+  return async (dispatch: Dispatch<RedisDiagnosticsActionTypes>) => {
+    dispatch({ type: GET_REDIS_DIAGNOSTICS_BEGIN });
+    try {
+      const [slowLog, latency, clients, commandStats] = await Promise.all([
+        getRedisSlowLog(),
+        getRedisLatency(),
+        getRedisClients(),
+        getRedisCommandStats(),
+      ]);
+      dispatch({
+        type: GET_REDIS_DIAGNOSTICS_SUCCESS,
+        payload: { slowLog, latency, clients, commandStats },
+      });
+    } catch (error) {
+      console.error(
+        `getRedisDiagnosticsAsync: ${toErrorStringWithHttpStatus(error)}`
+      );
+      dispatch({
+        type: GET_REDIS_DIAGNOSTICS_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
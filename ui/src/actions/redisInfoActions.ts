@@ -1,11 +1,19 @@
 import { Dispatch } from "redux";
-import { getRedisInfo, RedisInfoResponse } from "../api";
+import {
+  getRedisInfo,
+  getRedisInfoHistory,
+  RedisInfoHistoryResponse,
+  RedisInfoResponse,
+} from "../api";
 import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
 
 // List of redis-info related action types.
 export const GET_REDIS_INFO_BEGIN = "GET_REDIS_INFO_BEGIN";
 export const GET_REDIS_INFO_SUCCESS = "GET_REDIS_INFO_SUCCESS";
 export const GET_REDIS_INFO_ERROR = "GET_REDIS_INFO_ERROR";
+export const GET_REDIS_INFO_HISTORY_BEGIN = "GET_REDIS_INFO_HISTORY_BEGIN";
+export const GET_REDIS_INFO_HISTORY_SUCCESS = "GET_REDIS_INFO_HISTORY_SUCCESS";
+export const GET_REDIS_INFO_HISTORY_ERROR = "GET_REDIS_INFO_HISTORY_ERROR";
 
 interface GetRedisInfoBeginAction {
   type: typeof GET_REDIS_INFO_BEGIN;
@@ -21,11 +29,28 @@ interface GetRedisInfoErrorAction {
   error: string;
 }
 
+interface GetRedisInfoHistoryBeginAction {
+  type: typeof GET_REDIS_INFO_HISTORY_BEGIN;
+}
+
+interface GetRedisInfoHistorySuccessAction {
+  type: typeof GET_REDIS_INFO_HISTORY_SUCCESS;
+  payload: RedisInfoHistoryResponse;
+}
+
+interface GetRedisInfoHistoryErrorAction {
+  type: typeof GET_REDIS_INFO_HISTORY_ERROR;
+  error: string;
+}
+
 // Union of all redis-info related actions.
 export type RedisInfoActionTypes =
   | GetRedisInfoBeginAction
   | GetRedisInfoErrorAction
-  | GetRedisInfoSuccessAction;
+  | GetRedisInfoSuccessAction
+  | GetRedisInfoHistoryBeginAction
+  | GetRedisInfoHistorySuccessAction
+  | GetRedisInfoHistoryErrorAction;
 
 export function getRedisInfoAsync() {
   return async (dispatch: Dispatch<RedisInfoActionTypes>) => {
@@ -42,3 +67,21 @@ export function getRedisInfoAsync() {
     }
   };
 }
+
+export function getRedisInfoHistoryAsync() {
+  return async (dispatch: Dispatch<RedisInfoActionTypes>) => {
+    dispatch({ type: GET_REDIS_INFO_HISTORY_BEGIN });
+    try {
+      const response = await getRedisInfoHistory();
+      dispatch({ type: GET_REDIS_INFO_HISTORY_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        `getRedisInfoHistoryAsync: ${toErrorStringWithHttpStatus(error)}`
+      );
+      dispatch({
+        type: GET_REDIS_INFO_HISTORY_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
@@ -0,0 +1,93 @@
+import { Dispatch } from "redux";
+import {
+  createView,
+  deleteView,
+  listViews,
+  ListViewsResponse,
+  SavedView,
+  ViewRequest,
+} from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of saved-views related action types.
+export const LIST_VIEWS_BEGIN = "LIST_VIEWS_BEGIN";
+export const LIST_VIEWS_SUCCESS = "LIST_VIEWS_SUCCESS";
+export const LIST_VIEWS_ERROR = "LIST_VIEWS_ERROR";
+export const CREATE_VIEW_SUCCESS = "CREATE_VIEW_SUCCESS";
+export const DELETE_VIEW_SUCCESS = "DELETE_VIEW_SUCCESS";
+export const VIEW_ACTION_ERROR = "VIEW_ACTION_ERROR";
+
+interface ListViewsBeginAction {
+  type: typeof LIST_VIEWS_BEGIN;
+}
+
+interface ListViewsSuccessAction {
+  type: typeof LIST_VIEWS_SUCCESS;
+  payload: ListViewsResponse;
+}
+
+interface ListViewsErrorAction {
+  type: typeof LIST_VIEWS_ERROR;
+  error: string;
+}
+
+interface CreateViewSuccessAction {
+  type: typeof CREATE_VIEW_SUCCESS;
+  payload: SavedView;
+}
+
+interface DeleteViewSuccessAction {
+  type: typeof DELETE_VIEW_SUCCESS;
+  viewId: string;
+}
+
+interface ViewActionErrorAction {
+  type: typeof VIEW_ACTION_ERROR;
+  error: string;
+}
+
+// Union of all saved-views related actions.
+export type SavedViewsActionTypes =
+  | ListViewsBeginAction
+  | ListViewsSuccessAction
+  | ListViewsErrorAction
+  | CreateViewSuccessAction
+  | DeleteViewSuccessAction
+  | ViewActionErrorAction;
+
+export function listViewsAsync() {
+  return async (dispatch: Dispatch<SavedViewsActionTypes>) => {
+    dispatch({ type: LIST_VIEWS_BEGIN });
+    try {
+      const response = await listViews();
+      dispatch({ type: LIST_VIEWS_SUCCESS, payload: response });
+    } catch (error) {
+      console.error("listViewsAsync: ", toErrorStringWithHttpStatus(error));
+      dispatch({ type: LIST_VIEWS_ERROR, error: toErrorString(error) });
+    }
+  };
+}
+
+export function createViewAsync(req: ViewRequest) {
+  return async (dispatch: Dispatch<SavedViewsActionTypes>) => {
+    try {
+      const response = await createView(req);
+      dispatch({ type: CREATE_VIEW_SUCCESS, payload: response });
+    } catch (error) {
+      console.error("createViewAsync: ", toErrorStringWithHttpStatus(error));
+      dispatch({ type: VIEW_ACTION_ERROR, error: toErrorString(error) });
+    }
+  };
+}
+
+export function deleteViewAsync(viewId: string) {
+  return async (dispatch: Dispatch<SavedViewsActionTypes>) => {
+    try {
+      await deleteView(viewId);
+      dispatch({ type: DELETE_VIEW_SUCCESS, viewId });
+    } catch (error) {
+      console.error("deleteViewAsync: ", toErrorStringWithHttpStatus(error));
+      dispatch({ type: VIEW_ACTION_ERROR, error: toErrorString(error) });
+    }
+  };
+}
@@ -1,11 +1,19 @@
 import { Dispatch } from "redux";
-import { listServers, ListServersResponse } from "../api";
+import {
+  getServerHistory,
+  listServers,
+  ListServersResponse,
+  ServerHistoryResponse,
+} from "../api";
 import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
 
 // List of server related action types.
 export const LIST_SERVERS_BEGIN = "LIST_SERVERS_BEGIN";
 export const LIST_SERVERS_SUCCESS = "LIST_SERVERS_SUCCESS";
 export const LIST_SERVERS_ERROR = "LIST_SERVERS_ERROR";
+export const GET_SERVER_HISTORY_BEGIN = "GET_SERVER_HISTORY_BEGIN";
+export const GET_SERVER_HISTORY_SUCCESS = "GET_SERVER_HISTORY_SUCCESS";
+export const GET_SERVER_HISTORY_ERROR = "GET_SERVER_HISTORY_ERROR";
 
 interface ListServersBeginAction {
   type: typeof LIST_SERVERS_BEGIN;
@@ -19,11 +27,26 @@ interface ListServersErrorAction {
   error: string; // error description
 }
 
+interface GetServerHistoryBeginAction {
+  type: typeof GET_SERVER_HISTORY_BEGIN;
+}
+interface GetServerHistorySuccessAction {
+  type: typeof GET_SERVER_HISTORY_SUCCESS;
+  payload: ServerHistoryResponse;
+}
+interface GetServerHistoryErrorAction {
+  type: typeof GET_SERVER_HISTORY_ERROR;
+  error: string; // error description
+}
+
 // Union of all server related actions.
 export type ServersActionTypes =
   | ListServersBeginAction
   | ListServersSuccessAction
-  | ListServersErrorAction;
+  | ListServersErrorAction
+  | GetServerHistoryBeginAction
+  | GetServerHistorySuccessAction
+  | GetServerHistoryErrorAction;
 
 export function listServersAsync() {
   return async (dispatch: Dispatch<ServersActionTypes>) => {
@@ -43,3 +66,21 @@ export function listServersAsync() {
     }
   };
 }
+
+export function getServerHistoryAsync() {
+  return async (dispatch: Dispatch<ServersActionTypes>) => {
+    dispatch({ type: GET_SERVER_HISTORY_BEGIN });
+    try {
+      const response = await getServerHistory();
+      dispatch({ type: GET_SERVER_HISTORY_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        `getServerHistoryAsync: ${toErrorStringWithHttpStatus(error)}`
+      );
+      dispatch({
+        type: GET_SERVER_HISTORY_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
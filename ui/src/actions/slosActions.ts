@@ -0,0 +1,42 @@
+import { Dispatch } from "redux";
+import { listSLOs, ListSLOsResponse } from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of SLO related action types.
+export const LIST_SLOS_BEGIN = "LIST_SLOS_BEGIN";
+export const LIST_SLOS_SUCCESS = "LIST_SLOS_SUCCESS";
+export const LIST_SLOS_ERROR = "LIST_SLOS_ERROR";
+
+interface ListSLOsBeginAction {
+  type: typeof LIST_SLOS_BEGIN;
+}
+interface ListSLOsSuccessAction {
+  type: typeof LIST_SLOS_SUCCESS;
+  payload: ListSLOsResponse;
+}
+interface ListSLOsErrorAction {
+  type: typeof LIST_SLOS_ERROR;
+  error: string; // error description
+}
+
+// Union of all SLO related actions.
+export type SLOsActionTypes =
+  | ListSLOsBeginAction
+  | ListSLOsSuccessAction
+  | ListSLOsErrorAction;
+
+export function listSLOsAsync() {
+  return async (dispatch: Dispatch<SLOsActionTypes>) => {
+    dispatch({ type: LIST_SLOS_BEGIN });
+    try {
+      const response = await listSLOs();
+      dispatch({ type: LIST_SLOS_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(`listSLOsAsync: ${toErrorStringWithHttpStatus(error)}`);
+      dispatch({
+        type: LIST_SLOS_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
@@ -0,0 +1,73 @@
+import { Dispatch } from "redux";
+import {
+  CancelFlaggedWorkersResponse,
+  cancelFlaggedWorkers,
+  listWorkers,
+  ListWorkersResponse,
+} from "../api";
+import { toErrorString, toErrorStringWithHttpStatus } from "../utils";
+
+// List of worker related action types.
+export const LIST_WORKERS_BEGIN = "LIST_WORKERS_BEGIN";
+export const LIST_WORKERS_SUCCESS = "LIST_WORKERS_SUCCESS";
+export const LIST_WORKERS_ERROR = "LIST_WORKERS_ERROR";
+export const CANCEL_FLAGGED_WORKERS_SUCCESS = "CANCEL_FLAGGED_WORKERS_SUCCESS";
+export const CANCEL_FLAGGED_WORKERS_ERROR = "CANCEL_FLAGGED_WORKERS_ERROR";
+
+interface ListWorkersBeginAction {
+  type: typeof LIST_WORKERS_BEGIN;
+}
+interface ListWorkersSuccessAction {
+  type: typeof LIST_WORKERS_SUCCESS;
+  payload: ListWorkersResponse;
+}
+interface ListWorkersErrorAction {
+  type: typeof LIST_WORKERS_ERROR;
+  error: string; // error description
+}
+interface CancelFlaggedWorkersSuccessAction {
+  type: typeof CANCEL_FLAGGED_WORKERS_SUCCESS;
+  payload: CancelFlaggedWorkersResponse;
+}
+interface CancelFlaggedWorkersErrorAction {
+  type: typeof CANCEL_FLAGGED_WORKERS_ERROR;
+  error: string; // error description
+}
+
+// Union of all worker related actions.
+export type WorkersActionTypes =
+  | ListWorkersBeginAction
+  | ListWorkersSuccessAction
+  | ListWorkersErrorAction
+  | CancelFlaggedWorkersSuccessAction
+  | CancelFlaggedWorkersErrorAction;
+
+export function listWorkersAsync() {
+  return async (dispatch: Dispatch<WorkersActionTypes>) => {
+    dispatch({ type: LIST_WORKERS_BEGIN });
+    try {
+      const response = await listWorkers();
+      dispatch({ type: LIST_WORKERS_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(`listWorkersAsync: ${toErrorStringWithHttpStatus(error)}`);
+      dispatch({ type: LIST_WORKERS_ERROR, error: toErrorString(error) });
+    }
+  };
+}
+
+export function cancelFlaggedWorkersAsync() {
+  return async (dispatch: Dispatch<WorkersActionTypes>) => {
+    try {
+      const response = await cancelFlaggedWorkers();
+      dispatch({ type: CANCEL_FLAGGED_WORKERS_SUCCESS, payload: response });
+    } catch (error) {
+      console.error(
+        `cancelFlaggedWorkersAsync: ${toErrorStringWithHttpStatus(error)}`
+      );
+      dispatch({
+        type: CANCEL_FLAGGED_WORKERS_ERROR,
+        error: toErrorString(error),
+      });
+    }
+  };
+}
@@ -1,5 +1,6 @@
 import axios from "axios";
 import queryString from "query-string";
+import { requestCanceledMessage } from "./utils";
 
 // In production build, API server is on listening on the same port as
 // the static file server.
@@ -28,6 +29,165 @@ export interface ListServersResponse {
   servers: ServerInfo[];
 }
 
+export interface QueueCoverageResponse {
+  queues: QueueCoverage[];
+  total_concurrency: number;
+  orphaned_queues: string[]; // queues no server is listening on
+  unknown_queues: string[]; // queues servers listen on which do not exist
+  warnings: QueueCoverageWarning[];
+}
+
+export interface QueueCoverage {
+  queue: string;
+  paused: boolean;
+  pending: number;
+  server_ids: string[];
+  concurrency: number;
+  // Number of workers the queue is expected to get.
+  weighted_concurrency: number;
+  // Weighted concurrency over the total concurrency of the servers.
+  share: number;
+  strict_priority_servers: number;
+  orphaned: boolean;
+}
+
+export interface QueueCoverageWarning {
+  queue: string;
+  message: string;
+}
+
+export type QueueRankingSortBy =
+  | "growth"
+  | "latency"
+  | "failure_rate"
+  | "memory";
+
+export interface QueueRankingResponse {
+  // Source of the history of the queue stats.
+  source: "prometheus" | "samples" | "snapshot";
+  sort_by: QueueRankingSortBy;
+  duration_seconds: number;
+  total: number; // total number of queues
+  queues: QueueRanking[];
+}
+
+export interface QueueRanking {
+  queue: string;
+  size: number;
+  size_delta: number;
+  latency_seconds: number;
+  latency_delta_seconds: number;
+  memory_usage_bytes: number;
+  memory_delta_bytes: number;
+  processed: number;
+  failed: number;
+  failure_rate: number | null; // null if no task was processed
+  coverage_seconds: number;
+  // Values of the ranking metric over the period.
+  trend: { time: string; value: number }[];
+}
+
+export interface ListSLOsResponse {
+  source: "prometheus" | "samples";
+  slos: SLOStatus[];
+}
+
+// compliance, error_budget_remaining and burn_rate are null without data.
+export interface SLOStatus {
+  name: string;
+  queue: string;
+  kind: "latency" | "failure_ratio";
+  objective: string;
+  target: number;
+  window_seconds: number;
+  coverage_seconds: number;
+  compliance: number | null;
+  met: boolean;
+  error_budget_remaining: number | null;
+  burn_rates: SLOBurnRate[];
+  error?: string;
+}
+
+export interface SLOBurnRate {
+  period_seconds: number;
+  burn_rate: number | null;
+}
+
+export interface ServerHistoryResponse {
+  enabled: boolean; // false if servers are not tracked
+  interval_seconds: number;
+  retention_seconds: number;
+  stuck_stopped_after_seconds: number;
+  servers: ServerHistoryEntry[];
+  events: ServerFleetEvent[];
+  samples: FleetSample[];
+  stuck_stopped: number;
+  stale_heartbeat: number;
+  last_error?: string;
+}
+
+export interface ServerHistoryEntry {
+  id: string;
+  host: string;
+  pid: number;
+  concurrency: number;
+  queue_priorities: { [qname: string]: number };
+  strict_priority_enabled: boolean;
+  start_time: string;
+  status: string;
+  status_since: string;
+  first_seen: string;
+  last_seen: string;
+  heartbeat_expires_at: string;
+  stale_heartbeat: boolean;
+  stuck_stopped: boolean;
+  disappeared_at: string | null; // null if the server is alive
+  disappear_reason?: "shutdown" | "heartbeat_expired" | "unknown";
+}
+
+export interface ServerFleetEvent {
+  time: string;
+  type: "appeared" | "disappeared";
+  server_id: string;
+  host: string;
+  pid: number;
+  reason?: string;
+}
+
+export interface FleetSample {
+  time: string;
+  servers: number;
+  concurrency: number;
+  active_workers: number;
+}
+
+export interface ListWorkersResponse {
+  workers: WorkerEntry[];
+  long_running_threshold_seconds: number;
+  past_deadline: number;
+  long_running: number;
+  orphaned: number;
+}
+
+// WorkerEntry is a worker processing an active task, or an active task
+// without a worker.
+export interface WorkerEntry {
+  task_id: string;
+  queue: string;
+  task_type: string;
+  task_payload: string;
+  // Empty if no server reports a worker for the task.
+  server_id: string;
+  host: string;
+  pid: number;
+  start_time: string;
+  deadline: string;
+  elapsed_seconds: number;
+  past_deadline: boolean;
+  long_running: boolean;
+  is_orphaned: boolean;
+}
+
 export interface ListSchedulerEntriesResponse {
   entries: SchedulerEntry[];
 }
@@ -56,6 +216,132 @@ export interface BatchArchiveTasksResponse {
   error_ids: string[];
 }
 
+// Task states for which error clusters can be listed.
+export type ErrorClusterTaskState = "retry" | "archived";
+
+export interface ListErrorClustersResponse {
+  clusters: ErrorCluster[];
+  total: number;
+  scanned: number;
+}
+
+export interface ErrorCluster {
+  id: string;
+  pattern: string;
+  example_message: string;
+  count: number;
+  example_task_ids: string[];
+  first_failed_at: string;
+  last_failed_at: string;
+  task_types: { type: string; count: number }[];
+  notes: Note[];
+}
+
+export interface Note {
+  id: string;
+  author: string;
+  text: string;
+  created_at: string;
+}
+
+export interface ListNotesResponse {
+  notes: Note[];
+}
+
+export interface Annotation {
+  id: string;
+  author: string;
+  text: string;
+  start_time: string;
+  end_time: string;
+  // Empty if the annotation applies to all queues.
+  queues: string[];
+  created_at: string;
+}
+
+export interface ListAnnotationsResponse {
+  annotations: Annotation[];
+}
+
+export interface CreateAnnotationRequest {
+  text: string;
+  start_time: string;
+  end_time?: string;
+  queues?: string[];
+}
+
+export type ViewVisibility = "private" | "team";
+
+export interface SavedView {
+  id: string;
+  name: string;
+  queue: string;
+  state: string;
+  filters: { [key: string]: string };
+  sort: string;
+  page_size: number;
+  owner: string;
+  visibility: ViewVisibility;
+  created_at: string;
+  updated_at: string;
+}
+
+export interface ListViewsResponse {
+  views: SavedView[];
+  user: string; // authenticated user, empty if unknown
+}
+
+export interface ViewRequest {
+  name: string;
+  queue: string;
+  state: string;
+  filters?: { [key: string]: string };
+  sort?: string;
+  page_size?: number;
+  visibility: ViewVisibility;
+}
+
+export interface CompletedTaskStatsResponse extends CompletedStats {
+  queue: string;
+  window_start: string;
+  bucket_seconds: number;
+  total: number;
+  scanned: number;
+  task_types: CompletedTaskTypeStats[];
+}
+
+export interface CompletedTaskTypeStats extends CompletedStats {
+  task_type: string;
+}
+
+export interface CompletedStats {
+  count: number;
+  completed_in_window: number;
+  throughput_per_minute: number;
+  // Number of tasks completed in each bucket of the window.
+  completions: number[];
+  retention: DurationBucket[];
+  ttl: DurationBucket[];
+  result_size: ResultSizeStats;
+}
+
+// max_seconds is zero if the bucket has no upper bound.
+export interface DurationBucket {
+  min_seconds: number;
+  max_seconds: number;
+  count: number;
+}
+
+export interface ResultSizeStats {
+  min: number;
+  max: number;
+  avg: number;
+  p50: number;
+  p95: number;
+  total: number;
+  empty: number;
+}
+
 export interface DeleteAllTasksResponse {
   deleted: number;
 }
@@ -86,6 +372,78 @@ export interface RedisInfoResponse {
   // following fields are set only when cluster=true
   raw_cluster_nodes: string;
   queue_locations: QueueLocation[] | null;
+  nodes: ClusterNode[] | null;
+  warnings: string[] | null;
+
+  // following field is set only when connected through sentinels
+  sentinel?: SentinelInfo;
+}
+
+export interface RedisInfoHistoryResponse {
+  enabled: boolean; // false if redis INFO is not sampled
+  interval_seconds: number;
+  retention_seconds: number;
+  nodes: string[];
+  samples: RedisInfoSample[];
+  last_error?: string;
+}
+
+// Sample of redis INFO fields (summed over the masters in cluster).
+export interface RedisInfoSample {
+  time: string;
+  used_memory: number;
+  connected_clients: number;
+  ops_per_sec: number;
+  evicted_keys: number;
+  keyspace_hits: number;
+  keyspace_misses: number;
+  keyspace_hit_ratio: number | null;
+}
+
+// Describes a master or replica node in cluster.
+export interface ClusterNode {
+  id: string;
+  address: string;
+  role: string; // "master" or "replica"
+  master_id?: string;
+  flags: string[];
+  connected: boolean;
+  slots: { start: number; end: number }[] | null;
+  slot_count: number;
+  queues: string[] | null;
+  used_memory: number;
+  used_memory_human: string;
+  maxmemory: number;
+  ops_per_sec: number;
+  connected_clients: number;
+  replication_offset: number;
+  replication_lag: number;
+  error?: string; // set if the node could not be queried
+}
+
+// Sentinel's view of the master.
+export interface SentinelInfo {
+  sentinel_address: string;
+  master_name: string;
+  master: SentinelInstance | null;
+  replicas: SentinelInstance[] | null;
+  sentinels: SentinelInstance[] | null;
+  quorum: number;
+  quorum_ok: boolean;
+  quorum_status: string;
+  failover_in_progress: boolean;
+  failover_state: string;
+  error?: string;
+}
+
+export interface SentinelInstance {
+  address: string;
+  run_id: string;
+  flags: string[];
+  down: boolean;
+  last_ok_ping_reply_ms: number;
+  master_link_status?: string;
+  replication_offset?: number;
 }
 
 // Describes location of a queue in cluster.
@@ -95,6 +453,69 @@ export interface QueueLocation {
   nodes: string[]; // node addresses
 }
 
+// Describes a redis server. Role is set only when connected to redis cluster.
+export interface RedisNode {
+  address: string;
+  role?: string; // "master" or "replica"
+  error?: string; // set if the diagnostics command failed on the node
+}
+
+export interface RedisSlowLogResponse {
+  nodes: (RedisNode & { entries: SlowLogEntry[] })[];
+}
+
+export interface SlowLogEntry {
+  id: number;
+  time: string;
+  duration_us: number;
+  args: string[];
+  client_addr: string;
+  client_name: string;
+}
+
+export interface RedisLatencyResponse {
+  nodes: (RedisNode & { events: LatencyEvent[] })[];
+}
+
+export interface LatencyEvent {
+  event: string;
+  time: string;
+  latency_ms: number;
+  max_latency_ms: number;
+  history: { time: string; latency_ms: number }[];
+}
+
+export interface RedisClientsResponse {
+  nodes: (RedisNode & {
+    total_clients: number;
+    by_name: RedisClientGroup[];
+    by_host: RedisClientGroup[];
+  })[];
+}
+
+// Group of connected clients with the same name or host.
+export interface RedisClientGroup {
+  key: string;
+  clients: number;
+  blocked: number;
+  max_age_seconds: number;
+  max_idle_seconds: number;
+  last_commands: { [cmd: string]: number };
+}
+
+export interface RedisCommandStatsResponse {
+  nodes: (RedisNode & { commands: RedisCommandStat[] })[];
+}
+
+export interface RedisCommandStat {
+  command: string;
+  calls: number;
+  usec: number;
+  usec_per_call: number;
+  rejected_calls: number;
+  failed_calls: number;
+}
+
 export interface MetricsResponse {
   queue_size: PrometheusMetricsResponse;
   queue_latency_seconds: PrometheusMetricsResponse;
@@ -274,6 +695,33 @@ export interface GroupInfo {
   size: number;
 }
 
+export interface ListGroupDetailsResponse {
+  groups: GroupDetail[];
+  size_histogram: GroupSizeBucket[];
+}
+
+export interface GroupDetail {
+  group: string;
+  size: number;
+  oldest_task_time: string;
+  newest_task_time: string;
+  age_seconds: number;
+  // Task types of the first `scanned` tasks in the group.
+  task_types: { type: string; count: number }[];
+  scanned: number;
+}
+
+export interface GroupSizeBucket {
+  min: number;
+  max: number; // 0 for the last bucket, which has no upper bound
+  groups: number;
+}
+
+export interface FlushGroupsResponse {
+  results: { group: string; run: number; error?: string }[];
+  run: number;
+}
+
 export interface Queue {
   queue: string;
   paused: boolean;
@@ -296,11 +744,18 @@ export interface Queue {
 
 export interface DailyStat {
   queue: string;
+  // First day of the period when aggregated by week or month.
   date: string;
   processed: number;
   failed: number;
+  // Number of days in the period; set when aggregated by week or month.
+  days?: number;
 }
 
+export type StatsGroupBy = "day" | "week" | "month";
+
+export type StatsExportFormat = "csv" | "json";
+
 export interface TaskInfo {
   id: string;
   queue: string;
@@ -320,6 +775,29 @@ export interface TaskInfo {
   result: string;
   ttl_seconds: number;
   is_orphaned: boolean; // Only applies to task.state == 'active'
+  timeline?: TaskEvent[]; // Only returned by getTaskInfo
+  notes?: Note[]; // Only returned by getTaskInfo
+  error_cluster_id?: string; // Only returned by getTaskInfo
+  error_cluster_notes?: Note[]; // Only returned by getTaskInfo
+}
+
+export interface TaskEvent {
+  time: string;
+  type:
+    | "action"
+    | "bulk_action"
+    | "scheduler_enqueue"
+    | "state_change"
+    | "failed"
+    | "completed";
+  action?: string;
+  from_state?: string;
+  to_state?: string;
+  user?: string;
+  request_id?: string;
+  group?: string; // only for bulk actions on aggregating tasks
+  count?: number; // number of tasks affected by a bulk action
+  message?: string;
 }
 
 export interface ServerInfo {
@@ -372,10 +850,77 @@ export async function listQueues(): Promise<ListQueuesResponse> {
   return resp.data;
 }
 
-export async function deleteQueue(qname: string): Promise<void> {
+// DryRunResponse is the preview of a destructive request.
+export interface DryRunResponse {
+  dry_run: boolean;
+  affected: number;
+  confirmation_token: string;
+  expires_at: string;
+}
+
+// ConfirmDeleteFn asks the user to confirm a destructive request, showing the
+// number of tasks affected by its dry run. It resolves to false if the user
+// cancels the request.
+export type ConfirmDeleteFn = (
+  description: string,
+  preview: DryRunResponse
+) => Promise<boolean>;
+
+let confirmDeleteFn: ConfirmDeleteFn = () => Promise.resolve(true);
+
+// setConfirmDeleteFn sets the function used by confirmedDelete to ask the user
+// for confirmation, or resets it if fn is null.
+export function setConfirmDeleteFn(fn: ConfirmDeleteFn | null) {
+  confirmDeleteFn = fn || (() => Promise.resolve(true));
+}
+
+async function previewDelete(url: string): Promise<DryRunResponse> {
+  const resp = await axios({
+    method: "delete",
+    url,
+    params: { dry_run: true },
+  });
+  return resp.data;
+}
+
+// confirmedDelete sends the dry run of a DELETE request, asks the user to
+// confirm it with the number of affected tasks, and sends the request with
+// the confirmation token of the dry run, so that it succeeds when the server
+// requires confirmation.
+async function confirmedDelete(url: string, description: string) {
+  const preview = await previewDelete(url);
+  if (!(await confirmDeleteFn(description, preview))) {
+    throw new Error(requestCanceledMessage);
+  }
+  return await axios({
+    method: "delete",
+    url,
+    params: { confirmation_token: preview.confirmation_token },
+  });
+}
+
+export async function previewDeleteQueue(
+  qname: string
+): Promise<DryRunResponse> {
+  return await previewDelete(`${getBaseUrl()}/queues/${qname}`);
+}
+
+// deleteQueue deletes the queue with the confirmation token of the dry run
+// returned by previewDeleteQueue, or asks the user to confirm the deletion if
+// the token is not given.
+export async function deleteQueue(
+  qname: string,
+  confirmationToken?: string
+): Promise<void> {
+  const url = `${getBaseUrl()}/queues/${qname}`;
+  if (!confirmationToken) {
+    await confirmedDelete(url, `Delete queue "${qname}"`);
+    return;
+  }
   await axios({
     method: "delete",
-    url: `${getBaseUrl()}/queues/${qname}`,
+    url,
+    params: { confirmation_token: confirmationToken },
   });
 }
 
@@ -393,14 +938,56 @@ export async function resumeQueue(qname: string): Promise<void> {
   });
 }
 
-export async function listQueueStats(): Promise<ListQueueStatsResponse> {
+export async function getQueueCoverage(): Promise<QueueCoverageResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/queue_coverage`,
+  });
+  return resp.data;
+}
+
+export async function getQueueRanking(
+  sortBy: QueueRankingSortBy,
+  durationSeconds: number
+): Promise<QueueRankingResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/queue_ranking`,
+    params: { sort_by: sortBy, duration: durationSeconds },
+  });
+  return resp.data;
+}
+
+export async function listSLOs(): Promise<ListSLOsResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/slos`,
+  });
+  return resp.data;
+}
+
+export async function listQueueStats(
+  days: number,
+  groupBy: StatsGroupBy
+): Promise<ListQueueStatsResponse> {
   const resp = await axios({
     method: "get",
     url: `${getBaseUrl()}/queue_stats`,
+    params: { days, group_by: groupBy },
   });
   return resp.data;
 }
 
+// Returns the URL to download the stats of all queues as a file.
+export function queueStatsExportUrl(
+  days: number,
+  groupBy: StatsGroupBy,
+  format: StatsExportFormat
+): string {
+  const params = { days, group_by: groupBy, format };
+  return `${getBaseUrl()}/queue_stats?${queryString.stringify(params)}`;
+}
+
 export async function listGroups(qname: string): Promise<ListGroupsResponse> {
   const resp = await axios({
     method: "get",
@@ -409,6 +996,30 @@ export async function listGroups(qname: string): Promise<ListGroupsResponse> {
   return resp.data;
 }
 
+export async function listGroupDetails(
+  qname: string
+): Promise<ListGroupDetailsResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/queues/${qname}/group_details`,
+  });
+  return resp.data;
+}
+
+// flushGroups runs all aggregating tasks of the given groups, or of the groups
+// whose oldest task is older than olderThanSeconds.
+export async function flushGroups(
+  qname: string,
+  selector: { groups: string[] } | { older_than_seconds: number }
+): Promise<FlushGroupsResponse> {
+  const resp = await axios({
+    method: "post",
+    url: `${getBaseUrl()}/queues/${qname}/groups:flush`,
+    data: selector,
+  });
+  return resp.data;
+}
+
 export async function getTaskInfo(
   qname: string,
   id: string
@@ -417,6 +1028,179 @@ export async function getTaskInfo(
   const resp = await axios({
     method: "get",
     url,
+    params: { timeline: true },
+  });
+  return resp.data;
+}
+
+export async function addTaskNote(
+  qname: string,
+  taskId: string,
+  text: string
+): Promise<Note> {
+  const resp = await axios({
+    method: "post",
+    url: `${getBaseUrl()}/queues/${qname}/tasks/${taskId}/notes`,
+    data: { text },
+  });
+  return resp.data;
+}
+
+export async function deleteTaskNote(
+  qname: string,
+  taskId: string,
+  noteId: string
+): Promise<void> {
+  await axios({
+    method: "delete",
+    url: `${getBaseUrl()}/queues/${qname}/tasks/${taskId}/notes/${noteId}`,
+  });
+}
+
+export async function addErrorClusterNote(
+  clusterId: string,
+  text: string
+): Promise<Note> {
+  const resp = await axios({
+    method: "post",
+    url: `${getBaseUrl()}/error_clusters/${clusterId}/notes`,
+    data: { text },
+  });
+  return resp.data;
+}
+
+export async function deleteErrorClusterNote(
+  clusterId: string,
+  noteId: string
+): Promise<void> {
+  await axios({
+    method: "delete",
+    url: `${getBaseUrl()}/error_clusters/${clusterId}/notes/${noteId}`,
+  });
+}
+
+export async function listAnnotations(
+  endTime: number,
+  duration: number,
+  queues: string[]
+): Promise<ListAnnotationsResponse> {
+  let params: MetricsEndpointParams = {
+    endtime: endTime,
+    duration: duration,
+  };
+  if (queues && queues.length > 0) {
+    params.queues = queues.join(",");
+  }
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/annotations?${queryString.stringify(params)}`,
+  });
+  return resp.data;
+}
+
+export async function createAnnotation(
+  req: CreateAnnotationRequest
+): Promise<Annotation> {
+  const resp = await axios({
+    method: "post",
+    url: `${getBaseUrl()}/annotations`,
+    data: req,
+  });
+  return resp.data;
+}
+
+export async function deleteAnnotation(id: string): Promise<void> {
+  await axios({
+    method: "delete",
+    url: `${getBaseUrl()}/annotations/${id}`,
+  });
+}
+
+export async function listViews(): Promise<ListViewsResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/views`,
+  });
+  return resp.data;
+}
+
+export async function getView(id: string): Promise<SavedView> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/views/${id}`,
+  });
+  return resp.data;
+}
+
+export async function createView(req: ViewRequest): Promise<SavedView> {
+  const resp = await axios({
+    method: "post",
+    url: `${getBaseUrl()}/views`,
+    data: req,
+  });
+  return resp.data;
+}
+
+export async function deleteView(id: string): Promise<void> {
+  await axios({
+    method: "delete",
+    url: `${getBaseUrl()}/views/${id}`,
+  });
+}
+
+export async function listErrorClusters(
+  qname: string,
+  state: ErrorClusterTaskState
+): Promise<ListErrorClustersResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/queues/${qname}/${state}_tasks/error_clusters`,
+  });
+  return resp.data;
+}
+
+export async function runErrorCluster(
+  qname: string,
+  state: ErrorClusterTaskState,
+  clusterId: string
+): Promise<BatchRunTasksResponse> {
+  const resp = await axios({
+    method: "post",
+    url: `${getBaseUrl()}/queues/${qname}/${state}_tasks/error_clusters/${clusterId}:run`,
+  });
+  return resp.data;
+}
+
+export async function archiveErrorCluster(
+  qname: string,
+  clusterId: string
+): Promise<BatchArchiveTasksResponse> {
+  const resp = await axios({
+    method: "post",
+    url: `${getBaseUrl()}/queues/${qname}/retry_tasks/error_clusters/${clusterId}:archive`,
+  });
+  return resp.data;
+}
+
+export async function deleteErrorCluster(
+  qname: string,
+  state: ErrorClusterTaskState,
+  clusterId: string
+): Promise<BatchDeleteTasksResponse> {
+  const resp = await confirmedDelete(
+    `${getBaseUrl()}/queues/${qname}/${state}_tasks/error_clusters/${clusterId}`,
+    `Delete all ${state} tasks of the error cluster in queue "${qname}"`
+  );
+  return resp.data;
+}
+
+export async function getCompletedTaskStats(
+  qname: string,
+  durationSeconds: number
+): Promise<CompletedTaskStatsResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/queues/${qname}/completed_task_stats?duration=${durationSeconds}`,
   });
   return resp.data;
 }
@@ -616,10 +1400,10 @@ export async function batchDeletePendingTasks(
 export async function deleteAllPendingTasks(
   qname: string
 ): Promise<DeleteAllTasksResponse> {
-  const resp = await axios({
-    method: "delete",
-    url: `${getBaseUrl()}/queues/${qname}/pending_tasks:delete_all`,
-  });
+  const resp = await confirmedDelete(
+    `${getBaseUrl()}/queues/${qname}/pending_tasks:delete_all`,
+    `Delete all pending tasks in queue "${qname}"`
+  );
   return resp.data;
 }
 
@@ -653,10 +1437,10 @@ export async function deleteAllAggregatingTasks(
   qname: string,
   gname: string
 ): Promise<DeleteAllTasksResponse> {
-  const resp = await axios({
-    method: "delete",
-    url: `${getBaseUrl()}/queues/${qname}/groups/${gname}/aggregating_tasks:delete_all`,
-  });
+  const resp = await confirmedDelete(
+    `${getBaseUrl()}/queues/${qname}/groups/${gname}/aggregating_tasks:delete_all`,
+    `Delete all aggregating tasks of group "${gname}" in queue "${qname}"`
+  );
   return resp.data;
 }
 
@@ -781,10 +1565,10 @@ export async function batchDeleteScheduledTasks(
 export async function deleteAllScheduledTasks(
   qname: string
 ): Promise<DeleteAllTasksResponse> {
-  const resp = await axios({
-    method: "delete",
-    url: `${getBaseUrl()}/queues/${qname}/scheduled_tasks:delete_all`,
-  });
+  const resp = await confirmedDelete(
+    `${getBaseUrl()}/queues/${qname}/scheduled_tasks:delete_all`,
+    `Delete all scheduled tasks in queue "${qname}"`
+  );
   return resp.data;
 }
 
@@ -877,10 +1661,10 @@ export async function batchDeleteRetryTasks(
 export async function deleteAllRetryTasks(
   qname: string
 ): Promise<DeleteAllTasksResponse> {
-  const resp = await axios({
-    method: "delete",
-    url: `${getBaseUrl()}/queues/${qname}/retry_tasks:delete_all`,
-  });
+  const resp = await confirmedDelete(
+    `${getBaseUrl()}/queues/${qname}/retry_tasks:delete_all`,
+    `Delete all retry tasks in queue "${qname}"`
+  );
   return resp.data;
 }
 
@@ -963,10 +1747,10 @@ export async function batchDeleteArchivedTasks(
 export async function deleteAllArchivedTasks(
   qname: string
 ): Promise<DeleteAllTasksResponse> {
-  const resp = await axios({
-    method: "delete",
-    url: `${getBaseUrl()}/queues/${qname}/archived_tasks:delete_all`,
-  });
+  const resp = await confirmedDelete(
+    `${getBaseUrl()}/queues/${qname}/archived_tasks:delete_all`,
+    `Delete all archived tasks in queue "${qname}"`
+  );
   return resp.data;
 }
 
@@ -1018,10 +1802,10 @@ export async function batchDeleteCompletedTasks(
 export async function deleteAllCompletedTasks(
   qname: string
 ): Promise<DeleteAllTasksResponse> {
-  const resp = await axios({
-    method: "delete",
-    url: `${getBaseUrl()}/queues/${qname}/completed_tasks:delete_all`,
-  });
+  const resp = await confirmedDelete(
+    `${getBaseUrl()}/queues/${qname}/completed_tasks:delete_all`,
+    `Delete all completed tasks in queue "${qname}"`
+  );
   return resp.data;
 }
 
@@ -1033,6 +1817,34 @@ export async function listServers(): Promise<ListServersResponse> {
   return resp.data;
 }
 
+export async function getServerHistory(): Promise<ServerHistoryResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/server_history`,
+  });
+  return resp.data;
+}
+
+export async function listWorkers(): Promise<ListWorkersResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/workers`,
+  });
+  return resp.data;
+}
+
+export interface CancelFlaggedWorkersResponse extends BatchCancelTasksResponse {
+  orphaned_ids: string[]; // flagged tasks with no worker to cancel
+}
+
+export async function cancelFlaggedWorkers(): Promise<CancelFlaggedWorkersResponse> {
+  const resp = await axios({
+    method: "post",
+    url: `${getBaseUrl()}/workers:cancel_flagged`,
+  });
+  return resp.data;
+}
+
 export async function listSchedulerEntries(): Promise<ListSchedulerEntriesResponse> {
   const resp = await axios({
     method: "get",
@@ -1059,6 +1871,46 @@ export async function getRedisInfo(): Promise<RedisInfoResponse> {
   return resp.data;
 }
 
+export async function getRedisInfoHistory(): Promise<RedisInfoHistoryResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/redis_info_history`,
+  });
+  return resp.data;
+}
+
+export async function getRedisSlowLog(): Promise<RedisSlowLogResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/redis_slowlog`,
+  });
+  return resp.data;
+}
+
+export async function getRedisLatency(): Promise<RedisLatencyResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/redis_latency`,
+  });
+  return resp.data;
+}
+
+export async function getRedisClients(): Promise<RedisClientsResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/redis_clients`,
+  });
+  return resp.data;
+}
+
+export async function getRedisCommandStats(): Promise<RedisCommandStatsResponse> {
+  const resp = await axios({
+    method: "get",
+    url: `${getBaseUrl()}/redis_commandstats`,
+  });
+  return resp.data;
+}
+
 interface MetricsEndpointParams {
   endtime: number;
   duration: number;
@@ -3,11 +3,16 @@ import Alert from "@material-ui/lab/Alert";
 import AlertTitle from "@material-ui/lab/AlertTitle";
 import React, { useCallback, useState } from "react";
 import { connect, ConnectedProps } from "react-redux";
-import { listGroupsAsync } from "../actions/groupsActions";
+import {
+  flushGroupsAsync,
+  listGroupDetailsAsync,
+  listGroupsAsync,
+} from "../actions/groupsActions";
 import { GroupInfo } from "../api";
 import { usePolling } from "../hooks";
 import { AppState } from "../store";
 import AggregatingTasksTable from "./AggregatingTasksTable";
+import GroupDetailsTable from "./GroupDetailsTable";
 import GroupSelect from "./GroupSelect";
 
 const useStyles = makeStyles((theme) => ({
@@ -27,12 +32,17 @@ function mapStateToProps(state: AppState) {
   return {
     groups: state.groups.data,
     groupsError: state.groups.error,
+    detailsQueue: state.groups.detailsQueue,
+    details: state.groups.details,
+    sizeHistogram: state.groups.sizeHistogram,
     pollInterval: state.settings.pollInterval,
   };
 }
 
 const mapDispatchToProps = {
   listGroupsAsync,
+  listGroupDetailsAsync,
+  flushGroupsAsync,
 };
 
 const connector = connect(mapStateToProps, mapDispatchToProps);
@@ -45,15 +55,31 @@ function AggregatingTasksTableContainer(
   props: Props & ConnectedProps<typeof connector>
 ) {
   const [selectedGroup, setSelectedGroup] = useState<GroupInfo | null>(null);
-  const { pollInterval, listGroupsAsync, queue } = props;
+  const {
+    pollInterval,
+    listGroupsAsync,
+    listGroupDetailsAsync,
+    flushGroupsAsync,
+    queue,
+  } = props;
   const classes = useStyles();
 
   const fetchGroups = useCallback(() => {
     listGroupsAsync(queue);
-  }, [listGroupsAsync, queue]);
+    listGroupDetailsAsync(queue);
+  }, [listGroupsAsync, listGroupDetailsAsync, queue]);
 
   usePolling(fetchGroups, pollInterval);
 
+  const handleFlush = async (groups: string[]) => {
+    await flushGroupsAsync(queue, { groups });
+    fetchGroups();
+  };
+  const handleFlushOlderThan = async (seconds: number) => {
+    await flushGroupsAsync(queue, { older_than_seconds: seconds });
+    fetchGroups();
+  };
+
   if (props.groupsError.length > 0) {
     return (
       <Alert severity="error" className={classes.alert}>
@@ -87,6 +113,18 @@ function AggregatingTasksTableContainer(
           totalTaskCount={selectedGroup.size}
           selectedGroup={selectedGroup.group}
         />
+      ) : props.detailsQueue === queue ? (
+        <GroupDetailsTable
+          groups={props.details}
+          sizeHistogram={props.sizeHistogram}
+          onSelect={(g) =>
+            setSelectedGroup(
+              props.groups.find((info) => info.group === g.group) || null
+            )
+          }
+          onFlush={handleFlush}
+          onFlushOlderThan={handleFlushOlderThan}
+        />
       ) : (
         <Alert severity="info" className={classes.alert}>
           <AlertTitle>Info</AlertTitle>
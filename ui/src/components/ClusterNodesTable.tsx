@@ -0,0 +1,86 @@
+import React from "react";
+import { makeStyles } from "@material-ui/core/styles";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import { ClusterNode } from "../api";
+
+const useStyles = makeStyles((theme) => ({
+  table: {
+    minWidth: 650,
+  },
+  disconnected: {
+    color: theme.palette.error.main,
+  },
+}));
+
+interface Props {
+  nodes: ClusterNode[];
+}
+
+export default function ClusterNodesTable(props: Props) {
+  const classes = useStyles();
+
+  return (
+    <TableContainer>
+      <Table className={classes.table} aria-label="cluster nodes table">
+        <TableHead>
+          <TableRow>
+            <TableCell>Address</TableCell>
+            <TableCell>Role</TableCell>
+            <TableCell>Flags</TableCell>
+            <TableCell>Slots</TableCell>
+            <TableCell>Queues</TableCell>
+            <TableCell align="right">Memory</TableCell>
+            <TableCell align="right">Ops/sec</TableCell>
+            <TableCell align="right">Replication Offset</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {props.nodes.map((n) => (
+            <TableRow key={n.id}>
+              <TableCell
+                component="th"
+                scope="row"
+                className={n.connected ? undefined : classes.disconnected}
+              >
+                {n.address}
+              </TableCell>
+              <TableCell>{n.role}</TableCell>
+              <TableCell>{n.flags.join(", ")}</TableCell>
+              <TableCell>
+                {n.slots
+                  ?.map((r) =>
+                    r.start === r.end ? `${r.start}` : `${r.start}-${r.end}`
+                  )
+                  .join(", ")}
+              </TableCell>
+              <TableCell>{n.queues?.join(", ")}</TableCell>
+              {n.error ? (
+                <TableCell
+                  align="right"
+                  colSpan={3}
+                  className={classes.disconnected}
+                >
+                  {n.error}
+                </TableCell>
+              ) : (
+                <>
+                  <TableCell align="right">{n.used_memory_human}</TableCell>
+                  <TableCell align="right">{n.ops_per_sec}</TableCell>
+                  <TableCell align="right">
+                    {n.replication_offset}
+                    {n.role === "replica" && ` (lag: ${n.replication_lag})`}
+                  </TableCell>
+                </>
+              )}
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </TableContainer>
+  );
+}
@@ -0,0 +1,117 @@
+import React, { useState } from "react";
+import { connect, ConnectedProps } from "react-redux";
+import { makeStyles } from "@material-ui/core/styles";
+import Button from "@material-ui/core/Button";
+import Dialog from "@material-ui/core/Dialog";
+import DialogActions from "@material-ui/core/DialogActions";
+import DialogContent from "@material-ui/core/DialogContent";
+import DialogContentText from "@material-ui/core/DialogContentText";
+import DialogTitle from "@material-ui/core/DialogTitle";
+import TextField from "@material-ui/core/TextField";
+import { createAnnotationAsync } from "../actions/annotationsActions";
+
+const useStyles = makeStyles((theme) => ({
+  field: {
+    marginTop: theme.spacing(2),
+  },
+}));
+
+interface Props {
+  open: boolean;
+  onClose: () => void;
+  // Queues the annotation applies to; all queues if empty.
+  queues: string[];
+}
+
+const connector = connect(null, { createAnnotationAsync });
+
+type ReduxProps = ConnectedProps<typeof connector>;
+
+// toDateTimeLocal formats the date as the value of a datetime-local input.
+function toDateTimeLocal(d: Date): string {
+  const offset = d.getTimezoneOffset() * 60 * 1000;
+  return new Date(d.getTime() - offset).toISOString().slice(0, 16);
+}
+
+function CreateAnnotationDialog(props: Props & ReduxProps) {
+  const classes = useStyles();
+  const [text, setText] = useState("");
+  const [start, setStart] = useState(() => toDateTimeLocal(new Date()));
+  const [end, setEnd] = useState("");
+
+  const handleCreateClick = async () => {
+    await props.createAnnotationAsync({
+      text: text.trim(),
+      start_time: new Date(start).toISOString(),
+      end_time: end ? new Date(end).toISOString() : undefined,
+      queues: props.queues,
+    });
+    setText("");
+    setEnd("");
+    props.onClose();
+  };
+
+  const invalid =
+    text.trim() === "" ||
+    start === "" ||
+    (end !== "" && new Date(end) < new Date(start));
+
+  return (
+    <Dialog
+      open={props.open}
+      onClose={props.onClose}
+      aria-labelledby="annotation-dialog-title"
+      fullWidth
+      maxWidth="sm"
+    >
+      <DialogTitle id="annotation-dialog-title">Add Annotation</DialogTitle>
+      <DialogContent>
+        <DialogContentText>
+          {props.queues.length > 0
+            ? `Applies to ${props.queues.join(", ")}.`
+            : "Applies to all queues."}
+        </DialogContentText>
+        <TextField
+          autoFocus
+          fullWidth
+          multiline
+          label="Text"
+          value={text}
+          onChange={(e) => setText(e.target.value)}
+        />
+        <TextField
+          fullWidth
+          className={classes.field}
+          type="datetime-local"
+          label="Start"
+          InputLabelProps={{ shrink: true }}
+          value={start}
+          onChange={(e) => setStart(e.target.value)}
+        />
+        <TextField
+          fullWidth
+          className={classes.field}
+          type="datetime-local"
+          label="End (optional)"
+          InputLabelProps={{ shrink: true }}
+          value={end}
+          onChange={(e) => setEnd(e.target.value)}
+        />
+      </DialogContent>
+      <DialogActions>
+        <Button onClick={props.onClose} color="primary">
+          Cancel
+        </Button>
+        <Button
+          onClick={handleCreateClick}
+          color="primary"
+          disabled={invalid}
+        >
+          Add
+        </Button>
+      </DialogActions>
+    </Dialog>
+  );
+}
+
+export default connector(CreateAnnotationDialog);
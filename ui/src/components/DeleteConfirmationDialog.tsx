@@ -0,0 +1,67 @@
+import React, { useEffect, useState } from "react";
+import Button from "@material-ui/core/Button";
+import Dialog from "@material-ui/core/Dialog";
+import DialogActions from "@material-ui/core/DialogActions";
+import DialogContent from "@material-ui/core/DialogContent";
+import DialogContentText from "@material-ui/core/DialogContentText";
+import DialogTitle from "@material-ui/core/DialogTitle";
+import { DryRunResponse, setConfirmDeleteFn } from "../api";
+
+interface PendingConfirmation {
+  description: string;
+  preview: DryRunResponse;
+  resolve: (confirmed: boolean) => void;
+}
+
+// DeleteConfirmationDialog asks the user to confirm destructive requests sent
+// by the api module, showing the number of tasks affected by their dry run.
+// It is mounted once in the app.
+export default function DeleteConfirmationDialog() {
+  const [pending, setPending] = useState<PendingConfirmation | null>(null);
+  // The pending confirmation is kept while the dialog closes.
+  const [open, setOpen] = useState(false);
+
+  useEffect(() => {
+    setConfirmDeleteFn(
+      (description, preview) =>
+        new Promise<boolean>((resolve) => {
+          setPending({ description, preview, resolve });
+          setOpen(true);
+        })
+    );
+    return () => setConfirmDeleteFn(null);
+  }, []);
+
+  const handleClose = (confirmed: boolean) => {
+    pending?.resolve(confirmed);
+    setOpen(false);
+  };
+
+  const n = pending ? pending.preview.affected : 0;
+  return (
+    <Dialog
+      open={open}
+      onClose={() => handleClose(false)}
+      aria-labelledby="delete-confirmation-dialog-title"
+      aria-describedby="delete-confirmation-dialog-description"
+    >
+      <DialogTitle id="delete-confirmation-dialog-title">
+        {pending?.description}?
+      </DialogTitle>
+      <DialogContent>
+        <DialogContentText id="delete-confirmation-dialog-description">
+          {n} {n === 1 ? "task" : "tasks"} will be deleted. You can't undo this
+          action.
+        </DialogContentText>
+      </DialogContent>
+      <DialogActions>
+        <Button onClick={() => handleClose(false)} color="primary">
+          Cancel
+        </Button>
+        <Button onClick={() => handleClose(true)} color="primary" autoFocus>
+          Delete
+        </Button>
+      </DialogActions>
+    </Dialog>
+  );
+}
@@ -1,4 +1,4 @@
-import React from "react";
+import React, { useEffect, useState } from "react";
 import { connect, ConnectedProps } from "react-redux";
 import Button from "@material-ui/core/Button";
 import Dialog from "@material-ui/core/Dialog";
@@ -6,9 +6,10 @@ import DialogActions from "@material-ui/core/DialogActions";
 import DialogContent from "@material-ui/core/DialogContent";
 import DialogContentText from "@material-ui/core/DialogContentText";
 import DialogTitle from "@material-ui/core/DialogTitle";
-import { Queue } from "../api";
+import { DryRunResponse, previewDeleteQueue, Queue } from "../api";
 import { AppState } from "../store";
 import { deleteQueueAsync } from "../actions/queuesActions";
+import { toErrorString } from "../utils";
 
 interface Props {
   queue: Queue | null; // queue to delete
@@ -33,11 +34,33 @@ const connector = connect(mapStateToProps, { deleteQueueAsync });
 type ReduxProps = ConnectedProps<typeof connector>;
 
 function DeleteQueueConfirmationDialog(props: Props & ReduxProps) {
+  const [preview, setPreview] = useState<DryRunResponse | null>(null);
+  const [previewError, setPreviewError] = useState("");
+  const qname = props.queue?.queue;
+  const isEmpty = props.queue?.size === 0;
+
+  // Preview the deletion to show the number of tasks deleted with the queue
+  // (i.e. completed tasks) before the user confirms it.
+  useEffect(() => {
+    setPreview(null);
+    setPreviewError("");
+    if (!qname || !isEmpty) {
+      return;
+    }
+    let canceled = false;
+    previewDeleteQueue(qname)
+      .then((resp) => !canceled && setPreview(resp))
+      .catch((error) => !canceled && setPreviewError(toErrorString(error)));
+    return () => {
+      canceled = true;
+    };
+  }, [qname, isEmpty]);
+
   const handleDeleteClick = () => {
-    if (!props.queue) {
+    if (!props.queue || !preview) {
       return;
     }
-    props.deleteQueueAsync(props.queue.queue);
+    props.deleteQueueAsync(props.queue.queue, preview.confirmation_token);
     props.onClose();
   };
   return (
@@ -72,7 +95,13 @@ function DeleteQueueConfirmationDialog(props: Props & ReduxProps) {
             </DialogTitle>
             <DialogContent>
               <DialogContentText id="alert-dialog-description">
-                You can't undo this action.
+                {previewError
+                  ? `Could not preview the deletion: ${previewError}`
+                  : preview === null
+                  ? "Counting the tasks of the queue..."
+                  : `${preview.affected} ${
+                      preview.affected === 1 ? "task" : "tasks"
+                    } will be deleted with the queue. You can't undo this action.`}
               </DialogContentText>
             </DialogContent>
             <DialogActions>
@@ -85,7 +114,7 @@ function DeleteQueueConfirmationDialog(props: Props & ReduxProps) {
               </Button>
               <Button
                 onClick={handleDeleteClick}
-                disabled={props.requestPending}
+                disabled={props.requestPending || preview === null}
                 color="primary"
                 autoFocus
               >
@@ -0,0 +1,141 @@
+import React, { useState } from "react";
+import { makeStyles } from "@material-ui/core/styles";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Button from "@material-ui/core/Button";
+import TextField from "@material-ui/core/TextField";
+import Typography from "@material-ui/core/Typography";
+import { GroupDetail, GroupSizeBucket } from "../api";
+import { durationFromSeconds, stringifyDuration, timeAgo } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  toolbar: {
+    display: "flex",
+    alignItems: "center",
+    justifyContent: "space-between",
+    padding: theme.spacing(1, 2),
+  },
+  flushOlder: {
+    display: "flex",
+    alignItems: "center",
+  },
+  minutesInput: {
+    width: 80,
+    margin: theme.spacing(0, 1),
+  },
+  table: {
+    minWidth: 650,
+  },
+}));
+
+interface Props {
+  groups: GroupDetail[];
+  sizeHistogram: GroupSizeBucket[];
+  onSelect: (group: GroupDetail) => void;
+  onFlush: (groups: string[]) => void;
+  onFlushOlderThan: (seconds: number) => void;
+}
+
+function bucketLabel(b: GroupSizeBucket): string {
+  if (b.max === 0) return `${b.min}+`;
+  if (b.min === b.max) return `${b.min}`;
+  return `${b.min}-${b.max}`;
+}
+
+// GroupDetailsTable shows the age, size and task types of the groups,
+// with actions to run the aggregating tasks of the groups immediately.
+export default function GroupDetailsTable(props: Props) {
+  const classes = useStyles();
+  const [minutes, setMinutes] = useState(10);
+
+  return (
+    <div>
+      <div className={classes.toolbar}>
+        <Typography variant="body2" color="textSecondary">
+          Group sizes:{" "}
+          {props.sizeHistogram
+            .filter((b) => b.groups > 0)
+            .map((b) => `${bucketLabel(b)} tasks: ${b.groups}`)
+            .join(", ")}
+        </Typography>
+        {!window.READ_ONLY && (
+          <div className={classes.flushOlder}>
+            <Typography variant="body2">Flush groups older than</Typography>
+            <TextField
+              className={classes.minutesInput}
+              type="number"
+              size="small"
+              value={minutes}
+              onChange={(e) => setMinutes(Number(e.target.value))}
+              inputProps={{ min: 1 }}
+            />
+            <Typography variant="body2">minutes</Typography>
+            <Button
+              size="small"
+              disabled={minutes <= 0}
+              onClick={() => props.onFlushOlderThan(minutes * 60)}
+            >
+              Flush
+            </Button>
+          </div>
+        )}
+      </div>
+      <TableContainer>
+        <Table
+          className={classes.table}
+          size="small"
+          aria-label="group details table"
+        >
+          <TableHead>
+            <TableRow>
+              <TableCell>Group</TableCell>
+              <TableCell align="right">Size</TableCell>
+              <TableCell>Waiting For</TableCell>
+              <TableCell>Newest Task</TableCell>
+              <TableCell>Task Types</TableCell>
+              {!window.READ_ONLY && <TableCell>Actions</TableCell>}
+            </TableRow>
+          </TableHead>
+          <TableBody>
+            {props.groups.map((g) => (
+              <TableRow key={g.group} hover>
+                <TableCell component="th" scope="row">
+                  <Button size="small" onClick={() => props.onSelect(g)}>
+                    {g.group}
+                  </Button>
+                </TableCell>
+                <TableCell align="right">{g.size}</TableCell>
+                <TableCell>
+                  {g.oldest_task_time
+                    ? stringifyDuration(durationFromSeconds(g.age_seconds))
+                    : "-"}
+                </TableCell>
+                <TableCell>
+                  {g.newest_task_time ? timeAgo(g.newest_task_time) : "-"}
+                </TableCell>
+                <TableCell>
+                  {g.task_types.map((t) => `${t.type} (${t.count})`).join(", ")}
+                  {g.scanned < g.size && ` (first ${g.scanned} tasks)`}
+                </TableCell>
+                {!window.READ_ONLY && (
+                  <TableCell>
+                    <Button
+                      size="small"
+                      onClick={() => props.onFlush([g.group])}
+                    >
+                      Flush
+                    </Button>
+                  </TableCell>
+                )}
+              </TableRow>
+            ))}
+          </TableBody>
+        </Table>
+      </TableContainer>
+    </div>
+  );
+}
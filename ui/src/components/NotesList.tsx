@@ -0,0 +1,110 @@
+import React, { useState } from "react";
+import { makeStyles } from "@material-ui/core/styles";
+import Button from "@material-ui/core/Button";
+import IconButton from "@material-ui/core/IconButton";
+import List from "@material-ui/core/List";
+import ListItem from "@material-ui/core/ListItem";
+import ListItemSecondaryAction from "@material-ui/core/ListItemSecondaryAction";
+import ListItemText from "@material-ui/core/ListItemText";
+import TextField from "@material-ui/core/TextField";
+import Typography from "@material-ui/core/Typography";
+import DeleteIcon from "@material-ui/icons/Delete";
+import { Note } from "../api";
+import { timeAgo } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  noteText: {
+    whiteSpace: "pre-wrap",
+  },
+  form: {
+    display: "flex",
+    alignItems: "flex-start",
+    marginTop: theme.spacing(1),
+  },
+  input: {
+    flex: 1,
+    marginRight: theme.spacing(1),
+  },
+}));
+
+interface Props {
+  notes: Note[];
+  onAdd: (text: string) => Promise<void>;
+  onDelete: (noteId: string) => Promise<void>;
+}
+
+// NotesList shows a list of notes, and lets the user add and delete notes
+// unless the UI is read-only.
+export default function NotesList(props: Props) {
+  const classes = useStyles();
+  const [text, setText] = useState("");
+  const [submitting, setSubmitting] = useState(false);
+
+  const handleSubmit = async (e: React.FormEvent) => {
+    e.preventDefault();
+    if (text.trim() === "") {
+      return;
+    }
+    setSubmitting(true);
+    await props.onAdd(text.trim());
+    setSubmitting(false);
+    setText("");
+  };
+
+  return (
+    <div>
+      {props.notes.length === 0 ? (
+        <Typography color="textSecondary" variant="body2">
+          No notes yet.
+        </Typography>
+      ) : (
+        <List dense disablePadding>
+          {props.notes.map((note) => (
+            <ListItem key={note.id} disableGutters>
+              <ListItemText
+                primary={note.text}
+                primaryTypographyProps={{ className: classes.noteText }}
+                secondary={`${note.author || "anonymous"} · ${timeAgo(
+                  note.created_at
+                )}`}
+              />
+              {!window.READ_ONLY && (
+                <ListItemSecondaryAction>
+                  <IconButton
+                    edge="end"
+                    size="small"
+                    aria-label="delete note"
+                    onClick={() => props.onDelete(note.id)}
+                  >
+                    <DeleteIcon fontSize="small" />
+                  </IconButton>
+                </ListItemSecondaryAction>
+              )}
+            </ListItem>
+          ))}
+        </List>
+      )}
+      {!window.READ_ONLY && (
+        <form className={classes.form} onSubmit={handleSubmit}>
+          <TextField
+            className={classes.input}
+            size="small"
+            variant="outlined"
+            placeholder="Add a note"
+            multiline
+            value={text}
+            onChange={(e) => setText(e.target.value)}
+          />
+          <Button
+            type="submit"
+            variant="outlined"
+            color="primary"
+            disabled={submitting || text.trim() === ""}
+          >
+            Add
+          </Button>
+        </form>
+      )}
+    </div>
+  );
+}
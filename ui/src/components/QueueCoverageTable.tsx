@@ -0,0 +1,78 @@
+import React from "react";
+import { Link as RouterLink } from "react-router-dom";
+import { makeStyles } from "@material-ui/core/styles";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Link from "@material-ui/core/Link";
+import { QueueCoverageResponse } from "../api";
+import { queueDetailsPath } from "../paths";
+import { percentage } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  table: {
+    minWidth: 650,
+  },
+  orphaned: {
+    color: theme.palette.error.main,
+  },
+}));
+
+interface Props {
+  coverage: QueueCoverageResponse;
+}
+
+export default function QueueCoverageTable(props: Props) {
+  const classes = useStyles();
+  const { coverage } = props;
+
+  return (
+    <TableContainer>
+      <Table
+        className={classes.table}
+        size="small"
+        aria-label="queue coverage table"
+      >
+        <TableHead>
+          <TableRow>
+            <TableCell>Queue</TableCell>
+            <TableCell align="right">Pending</TableCell>
+            <TableCell align="right">Servers</TableCell>
+            <TableCell align="right">Strict Priority Servers</TableCell>
+            <TableCell align="right">Weighted Concurrency</TableCell>
+            <TableCell align="right">Share of Workers</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {coverage.queues.map((q) => (
+            <TableRow key={q.queue}>
+              <TableCell component="th" scope="row">
+                <Link component={RouterLink} to={queueDetailsPath(q.queue)}>
+                  {q.queue}
+                </Link>
+                {q.paused && " (paused)"}
+              </TableCell>
+              <TableCell align="right">{q.pending}</TableCell>
+              <TableCell
+                align="right"
+                className={q.orphaned ? classes.orphaned : undefined}
+              >
+                {q.orphaned ? "none" : q.server_ids.length}
+              </TableCell>
+              <TableCell align="right">{q.strict_priority_servers}</TableCell>
+              <TableCell align="right">
+                {q.weighted_concurrency.toFixed(1)}
+              </TableCell>
+              <TableCell align="right">
+                {percentage(q.weighted_concurrency, coverage.total_concurrency)}
+              </TableCell>
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </TableContainer>
+  );
+}
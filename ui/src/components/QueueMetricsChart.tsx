@@ -9,8 +9,10 @@ import {
   Tooltip,
   Legend,
   ResponsiveContainer,
+  ReferenceArea,
+  ReferenceLine,
 } from "recharts";
-import { Metrics } from "../api";
+import { Annotation, Metrics } from "../api";
 
 interface Props {
   data: Metrics[];
@@ -21,6 +23,9 @@ interface Props {
 
   // (optional): Tick formatter function for YAxis
   yAxisTickFormatter?: (val: number) => string;
+
+  // (optional): Annotations to overlay on the chart
+  annotations?: Annotation[];
 }
 
 // interface that rechart understands.
@@ -87,6 +92,34 @@ function QueueMetricsChart(props: Props) {
           }}
         />
         <Legend />
+        {(props.annotations || []).map((a) => {
+          const x1 = Date.parse(a.start_time) / 1000;
+          const x2 = Date.parse(a.end_time) / 1000;
+          const label = {
+            value: a.text,
+            position: "insideTopLeft" as const,
+            fill: theme.palette.text.secondary,
+            fontSize: 12,
+          };
+          return x1 === x2 ? (
+            <ReferenceLine
+              key={a.id}
+              x={x1}
+              stroke={theme.palette.warning.main}
+              strokeDasharray="3 3"
+              label={label}
+            />
+          ) : (
+            <ReferenceArea
+              key={a.id}
+              x1={Math.max(x1, props.startTime)}
+              x2={Math.min(x2, props.endTime)}
+              fill={theme.palette.warning.main}
+              fillOpacity={0.15}
+              label={label}
+            />
+          );
+        })}
         {keys.map((key, idx) => (
           <Line
             key={key}
@@ -0,0 +1,118 @@
+import React from "react";
+import { Link as RouterLink } from "react-router-dom";
+import { makeStyles, useTheme, Theme } from "@material-ui/core/styles";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Link from "@material-ui/core/Link";
+import { LineChart, Line, YAxis } from "recharts";
+import prettyBytes from "pretty-bytes";
+import { QueueRanking, QueueRankingSortBy } from "../api";
+import { queueDetailsPath } from "../paths";
+
+const useStyles = makeStyles((theme) => ({
+  table: {
+    minWidth: 650,
+  },
+  increase: {
+    color: theme.palette.error.main,
+  },
+  decrease: {
+    color: theme.palette.success.main,
+  },
+}));
+
+interface Props {
+  queues: QueueRanking[];
+  sortBy: QueueRankingSortBy;
+}
+
+function signed(n: number, format: (n: number) => string): string {
+  return n > 0 ? `+${format(n)}` : n < 0 ? `-${format(-n)}` : "0";
+}
+
+function formatRatio(v: number | null): string {
+  return v === null ? "-" : `${(v * 100).toFixed(2)}%`;
+}
+
+export default function QueueRankingTable(props: Props) {
+  const classes = useStyles();
+  const theme = useTheme<Theme>();
+
+  const deltaClass = (n: number) =>
+    n > 0 ? classes.increase : n < 0 ? classes.decrease : undefined;
+
+  return (
+    <TableContainer>
+      <Table
+        className={classes.table}
+        size="small"
+        aria-label="queue ranking table"
+      >
+        <TableHead>
+          <TableRow>
+            <TableCell>#</TableCell>
+            <TableCell>Queue</TableCell>
+            <TableCell align="right">Size</TableCell>
+            <TableCell align="right">Latency</TableCell>
+            <TableCell align="right">Failure Rate</TableCell>
+            <TableCell align="right">Memory</TableCell>
+            <TableCell>Trend</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {props.queues.map((q, i) => (
+            <TableRow key={q.queue}>
+              <TableCell>{i + 1}</TableCell>
+              <TableCell component="th" scope="row">
+                <Link component={RouterLink} to={queueDetailsPath(q.queue)}>
+                  {q.queue}
+                </Link>
+              </TableCell>
+              <TableCell align="right">
+                {q.size}{" "}
+                <span className={deltaClass(q.size_delta)}>
+                  ({signed(q.size_delta, String)})
+                </span>
+              </TableCell>
+              <TableCell align="right">
+                {q.latency_seconds.toFixed(1)}s{" "}
+                <span className={deltaClass(q.latency_delta_seconds)}>
+                  ({signed(q.latency_delta_seconds, (n) => n.toFixed(1))}s)
+                </span>
+              </TableCell>
+              <TableCell align="right">
+                {formatRatio(q.failure_rate)} ({q.failed}/{q.processed})
+              </TableCell>
+              <TableCell align="right">
+                {prettyBytes(q.memory_usage_bytes)}{" "}
+                <span className={deltaClass(q.memory_delta_bytes)}>
+                  ({signed(q.memory_delta_bytes, prettyBytes)})
+                </span>
+              </TableCell>
+              <TableCell>
+                <LineChart width={120} height={30} data={q.trend}>
+                  <YAxis hide domain={["dataMin", "dataMax"]} />
+                  <Line
+                    type="monotone"
+                    dataKey="value"
+                    dot={false}
+                    isAnimationActive={false}
+                    stroke={
+                      props.sortBy === "failure_rate"
+                        ? theme.palette.error.main
+                        : theme.palette.primary.main
+                    }
+                  />
+                </LineChart>
+              </TableCell>
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </TableContainer>
+  );
+}
@@ -0,0 +1,128 @@
+import React from "react";
+import { useTheme } from "@material-ui/core/styles";
+import Grid from "@material-ui/core/Grid";
+import Typography from "@material-ui/core/Typography";
+import prettyBytes from "pretty-bytes";
+import {
+  LineChart,
+  Line,
+  XAxis,
+  YAxis,
+  CartesianGrid,
+  Tooltip,
+  ResponsiveContainer,
+} from "recharts";
+import { RedisInfoSample } from "../api";
+
+interface Props {
+  samples: RedisInfoSample[];
+}
+
+// interface that rechart understands.
+interface ChartData {
+  timestamp: number;
+  used_memory: number;
+  connected_clients: number;
+  ops_per_sec: number;
+  evicted_keys: number | null; // number of keys evicted since the previous sample
+  hit_ratio: number | null; // in percent
+}
+
+function toChartData(samples: RedisInfoSample[]): ChartData[] {
+  return samples.map((s, idx) => {
+    const prev = idx > 0 ? samples[idx - 1] : null;
+    return {
+      timestamp: Date.parse(s.time) / 1000,
+      used_memory: s.used_memory,
+      connected_clients: s.connected_clients,
+      ops_per_sec: s.ops_per_sec,
+      evicted_keys:
+        prev && s.evicted_keys >= prev.evicted_keys
+          ? s.evicted_keys - prev.evicted_keys
+          : null,
+      hit_ratio:
+        s.keyspace_hit_ratio === null ? null : s.keyspace_hit_ratio * 100,
+    };
+  });
+}
+
+export default function RedisInfoHistoryCharts(props: Props) {
+  const data = toChartData(props.samples);
+  return (
+    <>
+      <HistoryChart
+        title="Used Memory"
+        data={data}
+        dataKey="used_memory"
+        yAxisTickFormatter={(val: number) => prettyBytes(val)}
+      />
+      <HistoryChart
+        title="Connected Clients"
+        data={data}
+        dataKey="connected_clients"
+      />
+      <HistoryChart title="Ops/sec" data={data} dataKey="ops_per_sec" />
+      <HistoryChart title="Evicted Keys" data={data} dataKey="evicted_keys" />
+      <HistoryChart
+        title="Keyspace Hit Ratio"
+        data={data}
+        dataKey="hit_ratio"
+        yAxisTickFormatter={(val: number) => `${val.toFixed(0)}%`}
+      />
+    </>
+  );
+}
+
+interface HistoryChartProps {
+  title: string;
+  data: ChartData[];
+  dataKey: keyof ChartData;
+  yAxisTickFormatter?: (val: number) => string;
+}
+
+function HistoryChart(props: HistoryChartProps) {
+  const theme = useTheme();
+  return (
+    <Grid item xs={12} md={6}>
+      <Typography variant="subtitle1" color="textSecondary">
+        {props.title}
+      </Typography>
+      <ResponsiveContainer height={200}>
+        <LineChart data={props.data}>
+          <CartesianGrid strokeDasharray="3 3" />
+          <XAxis
+            minTickGap={10}
+            dataKey="timestamp"
+            domain={["dataMin", "dataMax"]}
+            tickFormatter={(timestamp: number) =>
+              new Date(timestamp * 1000).toLocaleTimeString()
+            }
+            type="number"
+            scale="time"
+            stroke={theme.palette.text.secondary}
+          />
+          <YAxis
+            tickFormatter={props.yAxisTickFormatter}
+            stroke={theme.palette.text.secondary}
+          />
+          <Tooltip
+            labelFormatter={(timestamp: number) =>
+              new Date(timestamp * 1000).toLocaleString()
+            }
+            formatter={(val: number) =>
+              props.yAxisTickFormatter ? props.yAxisTickFormatter(val) : val
+            }
+          />
+          <Line
+            type="monotone"
+            dataKey={props.dataKey}
+            name={props.title}
+            stroke={theme.palette.primary.main}
+            dot={false}
+            isAnimationActive={false}
+          />
+        </LineChart>
+      </ResponsiveContainer>
+    </Grid>
+  );
+}
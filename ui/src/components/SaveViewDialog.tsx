@@ -0,0 +1,129 @@
+import React, { useEffect, useState } from "react";
+import { connect, ConnectedProps } from "react-redux";
+import { makeStyles } from "@material-ui/core/styles";
+import Button from "@material-ui/core/Button";
+import Dialog from "@material-ui/core/Dialog";
+import DialogActions from "@material-ui/core/DialogActions";
+import DialogContent from "@material-ui/core/DialogContent";
+import DialogContentText from "@material-ui/core/DialogContentText";
+import DialogTitle from "@material-ui/core/DialogTitle";
+import FormControlLabel from "@material-ui/core/FormControlLabel";
+import Radio from "@material-ui/core/Radio";
+import RadioGroup from "@material-ui/core/RadioGroup";
+import TextField from "@material-ui/core/TextField";
+import { createViewAsync, listViewsAsync } from "../actions/savedViewsActions";
+import { ViewVisibility } from "../api";
+import { AppState } from "../store";
+
+const useStyles = makeStyles((theme) => ({
+  visibility: {
+    marginTop: theme.spacing(2),
+  },
+}));
+
+interface Props {
+  open: boolean;
+  onClose: () => void;
+  queue: string;
+  state: string;
+  // Filter parameters of the task list, saved as given.
+  filters: { [key: string]: string };
+}
+
+function mapStateToProps(state: AppState) {
+  return {
+    pageSize: state.settings.taskRowsPerPage,
+    user: state.savedViews.user,
+  };
+}
+
+const connector = connect(mapStateToProps, {
+  createViewAsync,
+  listViewsAsync,
+});
+
+type ReduxProps = ConnectedProps<typeof connector>;
+
+function SaveViewDialog(props: Props & ReduxProps) {
+  const classes = useStyles();
+  const [name, setName] = useState("");
+  const [visibility, setVisibility] = useState<ViewVisibility>("team");
+  const { open, user, listViewsAsync } = props;
+
+  // Fetch the views to know the authenticated user, who can have private views.
+  useEffect(() => {
+    if (open) {
+      listViewsAsync();
+    }
+  }, [open, listViewsAsync]);
+
+  const handleSaveClick = async () => {
+    await props.createViewAsync({
+      name: name.trim(),
+      queue: props.queue,
+      state: props.state,
+      filters: props.filters,
+      page_size: props.pageSize,
+      visibility: user ? visibility : "team",
+    });
+    setName("");
+    props.onClose();
+  };
+
+  return (
+    <Dialog
+      open={props.open}
+      onClose={props.onClose}
+      aria-labelledby="save-view-dialog-title"
+      fullWidth
+      maxWidth="sm"
+    >
+      <DialogTitle id="save-view-dialog-title">Save View</DialogTitle>
+      <DialogContent>
+        <DialogContentText>
+          Save the {props.state} tasks of queue "{props.queue}" as a view with
+          a stable URL.
+        </DialogContentText>
+        <TextField
+          autoFocus
+          fullWidth
+          label="Name"
+          value={name}
+          onChange={(e) => setName(e.target.value)}
+        />
+        <RadioGroup
+          row
+          className={classes.visibility}
+          value={user ? visibility : "team"}
+          onChange={(e) => setVisibility(e.target.value as ViewVisibility)}
+        >
+          <FormControlLabel
+            value="private"
+            control={<Radio color="primary" />}
+            label="Only me"
+            disabled={!user}
+          />
+          <FormControlLabel
+            value="team"
+            control={<Radio color="primary" />}
+            label="Team"
+          />
+        </RadioGroup>
+      </DialogContent>
+      <DialogActions>
+        <Button onClick={props.onClose} color="primary">
+          Cancel
+        </Button>
+        <Button
+          onClick={handleSaveClick}
+          color="primary"
+          disabled={name.trim() === ""}
+        >
+          Save
+        </Button>
+      </DialogActions>
+    </Dialog>
+  );
+}
+
+export default connector(SaveViewDialog);
@@ -0,0 +1,71 @@
+import React from "react";
+import { makeStyles } from "@material-ui/core/styles";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import { SentinelInstance } from "../api";
+
+const useStyles = makeStyles((theme) => ({
+  table: {
+    minWidth: 650,
+  },
+  down: {
+    color: theme.palette.error.main,
+  },
+}));
+
+interface Props {
+  instances: SentinelInstance[];
+  // Show master link status and replication offset columns.
+  replicas?: boolean;
+}
+
+export default function SentinelInstancesTable(props: Props) {
+  const classes = useStyles();
+
+  return (
+    <TableContainer>
+      <Table className={classes.table} aria-label="sentinel instances table">
+        <TableHead>
+          <TableRow>
+            <TableCell>Address</TableCell>
+            <TableCell>Flags</TableCell>
+            <TableCell align="right">Last OK Ping Reply</TableCell>
+            {props.replicas && (
+              <>
+                <TableCell>Master Link</TableCell>
+                <TableCell align="right">Replication Offset</TableCell>
+              </>
+            )}
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {props.instances.map((inst) => (
+            <TableRow key={inst.address}>
+              <TableCell
+                component="th"
+                scope="row"
+                className={inst.down ? classes.down : undefined}
+              >
+                {inst.address}
+              </TableCell>
+              <TableCell>{inst.flags.join(", ")}</TableCell>
+              <TableCell align="right">
+                {inst.last_ok_ping_reply_ms}ms
+              </TableCell>
+              {props.replicas && (
+                <>
+                  <TableCell>{inst.master_link_status}</TableCell>
+                  <TableCell align="right">{inst.replication_offset}</TableCell>
+                </>
+              )}
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </TableContainer>
+  );
+}
@@ -0,0 +1,210 @@
+import React from "react";
+import { makeStyles, useTheme } from "@material-ui/core/styles";
+import Grid from "@material-ui/core/Grid";
+import Typography from "@material-ui/core/Typography";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Chip from "@material-ui/core/Chip";
+import Alert from "@material-ui/lab/Alert";
+import {
+  LineChart,
+  Line,
+  XAxis,
+  YAxis,
+  CartesianGrid,
+  Tooltip,
+  ResponsiveContainer,
+} from "recharts";
+import { FleetSample, ServerHistoryEntry, ServerHistoryResponse } from "../api";
+import { timeAgo } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  table: {
+    minWidth: 650,
+  },
+  flag: {
+    marginRight: theme.spacing(0.5),
+  },
+  gone: {
+    color: theme.palette.text.secondary,
+  },
+}));
+
+interface Props {
+  history: ServerHistoryResponse;
+}
+
+// ServerHistorySection shows the fleet size over time and the servers seen
+// by the server tracker, including the ones which disappeared.
+export default function ServerHistorySection(props: Props) {
+  const classes = useStyles();
+  const { history } = props;
+  const data = history.samples.map((s: FleetSample) => ({
+    timestamp: Date.parse(s.time) / 1000,
+    servers: s.servers,
+    concurrency: s.concurrency,
+    active_workers: s.active_workers,
+  }));
+  return (
+    <Grid container spacing={2}>
+      {history.last_error && (
+        <Grid item xs={12}>
+          <Alert severity="warning">
+            Could not list servers: {history.last_error}
+          </Alert>
+        </Grid>
+      )}
+      {data.length > 1 && (
+        <>
+          <FleetChart title="Servers" data={data} dataKey="servers" />
+          <FleetChart
+            title="Concurrency / Active Workers"
+            data={data}
+            dataKey="concurrency"
+            secondDataKey="active_workers"
+          />
+        </>
+      )}
+      <Grid item xs={12}>
+        <TableContainer>
+          <Table
+            className={classes.table}
+            size="small"
+            aria-label="server history table"
+          >
+            <TableHead>
+              <TableRow>
+                <TableCell>Host:PID</TableCell>
+                <TableCell>Status</TableCell>
+                <TableCell align="right">Concurrency</TableCell>
+                <TableCell>First Seen</TableCell>
+                <TableCell>Last Seen</TableCell>
+                <TableCell>Disappeared</TableCell>
+                <TableCell>Flags</TableCell>
+              </TableRow>
+            </TableHead>
+            <TableBody>
+              {history.servers.map((s: ServerHistoryEntry) => (
+                <TableRow key={s.id}>
+                  <TableCell
+                    component="th"
+                    scope="row"
+                    className={s.disappeared_at ? classes.gone : undefined}
+                    title={s.id}
+                  >
+                    {s.host}:{s.pid}
+                  </TableCell>
+                  <TableCell>
+                    {s.status} (since {timeAgo(s.status_since)})
+                  </TableCell>
+                  <TableCell align="right">{s.concurrency}</TableCell>
+                  <TableCell>{timeAgo(s.first_seen)}</TableCell>
+                  <TableCell>{timeAgo(s.last_seen)}</TableCell>
+                  <TableCell>
+                    {s.disappeared_at
+                      ? `${timeAgo(s.disappeared_at)} (${s.disappear_reason})`
+                      : "-"}
+                  </TableCell>
+                  <TableCell>
+                    {s.stuck_stopped && (
+                      <Chip
+                        className={classes.flag}
+                        size="small"
+                        color="secondary"
+                        label="stuck stopped"
+                      />
+                    )}
+                    {s.stale_heartbeat && (
+                      <Chip
+                        className={classes.flag}
+                        size="small"
+                        color="secondary"
+                        label="stale heartbeat"
+                      />
+                    )}
+                  </TableCell>
+                </TableRow>
+              ))}
+              {history.servers.length === 0 && (
+                <TableRow>
+                  <TableCell colSpan={7}>
+                    <Typography color="textSecondary">
+                      No servers seen yet
+                    </Typography>
+                  </TableCell>
+                </TableRow>
+              )}
+            </TableBody>
+          </Table>
+        </TableContainer>
+      </Grid>
+    </Grid>
+  );
+}
+
+interface ChartData {
+  timestamp: number;
+  servers: number;
+  concurrency: number;
+  active_workers: number;
+}
+
+interface FleetChartProps {
+  title: string;
+  data: ChartData[];
+  dataKey: keyof ChartData;
+  secondDataKey?: keyof ChartData;
+}
+
+function FleetChart(props: FleetChartProps) {
+  const theme = useTheme();
+  return (
+    <Grid item xs={12} md={6}>
+      <Typography variant="subtitle1" color="textSecondary">
+        {props.title}
+      </Typography>
+      <ResponsiveContainer height={200}>
+        <LineChart data={props.data}>
+          <CartesianGrid strokeDasharray="3 3" />
+          <XAxis
+            minTickGap={10}
+            dataKey="timestamp"
+            domain={["dataMin", "dataMax"]}
+            tickFormatter={(timestamp: number) =>
+              new Date(timestamp * 1000).toLocaleTimeString()
+            }
+            type="number"
+            scale="time"
+            stroke={theme.palette.text.secondary}
+          />
+          <YAxis allowDecimals={false} stroke={theme.palette.text.secondary} />
+          <Tooltip
+            labelFormatter={(timestamp: number) =>
+              new Date(timestamp * 1000).toLocaleString()
+            }
+          />
+          <Line
+            type="stepAfter"
+            dataKey={props.dataKey}
+            stroke={theme.palette.primary.main}
+            dot={false}
+            isAnimationActive={false}
+          />
+          {props.secondDataKey && (
+            <Line
+              type="stepAfter"
+              dataKey={props.secondDataKey}
+              stroke={theme.palette.secondary.main}
+              dot={false}
+              isAnimationActive={false}
+            />
+          )}
+        </LineChart>
+      </ResponsiveContainer>
+    </Grid>
+  );
+}
@@ -0,0 +1,108 @@
+import React from "react";
+import { makeStyles } from "@material-ui/core/styles";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Typography from "@material-ui/core/Typography";
+import { TaskEvent } from "../api";
+import { timeAgo } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  failed: {
+    color: theme.palette.error.main,
+  },
+  message: {
+    wordBreak: "break-word",
+  },
+}));
+
+interface Props {
+  events: TaskEvent[];
+}
+
+export default function TaskTimeline(props: Props) {
+  const classes = useStyles();
+
+  if (props.events.length === 0) {
+    return (
+      <Typography color="textSecondary">
+        No events recorded for this task
+      </Typography>
+    );
+  }
+  return (
+    <TableContainer>
+      <Table size="small" aria-label="task timeline table">
+        <TableHead>
+          <TableRow>
+            <TableCell>Time</TableCell>
+            <TableCell>Event</TableCell>
+            <TableCell>Details</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {props.events.map((e, i) => (
+            <TableRow key={i}>
+              <TableCell title={e.time}>{timeAgo(e.time)}</TableCell>
+              <TableCell
+                className={e.type === "failed" ? classes.failed : undefined}
+              >
+                {eventTitle(e)}
+              </TableCell>
+              <TableCell className={classes.message}>
+                {eventDetails(e)}
+              </TableCell>
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </TableContainer>
+  );
+}
+
+function eventTitle(e: TaskEvent): string {
+  switch (e.type) {
+    case "action":
+      return `Operator: ${e.action}`;
+    case "bulk_action":
+      return `Operator: ${e.action} (${e.count} tasks)`;
+    case "scheduler_enqueue":
+      return "Enqueued by scheduler";
+    case "state_change":
+      return e.from_state
+        ? `${e.from_state} → ${e.to_state}`
+        : `Observed as ${e.to_state}`;
+    case "failed":
+      return "Failed";
+    case "completed":
+      return "Completed";
+  }
+}
+
+function eventDetails(e: TaskEvent): string {
+  const details: string[] = [];
+  if (e.type === "action" || e.type === "bulk_action") {
+    if (e.from_state || e.to_state) {
+      details.push(`${e.from_state || "?"} → ${e.to_state || "?"}`);
+    }
+    if (e.user) {
+      details.push(`by ${e.user}`);
+    }
+    if (e.request_id) {
+      details.push(`(request ${e.request_id})`);
+    }
+  }
+  if (e.type === "bulk_action") {
+    details.push(
+      `on all ${e.from_state} tasks${e.group ? ` in group ${e.group}` : ""},`,
+      "which likely included this task"
+    );
+  }
+  if (e.message) {
+    details.push(e.message);
+  }
+  return details.join(" ");
+}
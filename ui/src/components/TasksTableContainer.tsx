@@ -4,6 +4,7 @@ import { makeStyles } from "@material-ui/core/styles";
 import Typography from "@material-ui/core/Typography";
 import Paper from "@material-ui/core/Paper";
 import Chip from "@material-ui/core/Chip";
+import Button from "@material-ui/core/Button";
 import InputBase from "@material-ui/core/InputBase";
 import SearchIcon from "@material-ui/icons/Search";
 import ActiveTasksTable from "./ActiveTasksTable";
@@ -13,8 +14,15 @@ import RetryTasksTable from "./RetryTasksTable";
 import ArchivedTasksTable from "./ArchivedTasksTable";
 import CompletedTasksTable from "./CompletedTasksTable";
 import AggregatingTasksTableContainer from "./AggregatingTasksTableContainer";
+import SaveViewDialog from "./SaveViewDialog";
 import { useHistory } from "react-router-dom";
-import { queueDetailsPath, taskDetailsPath } from "../paths";
+import { useQuery } from "../hooks";
+import {
+  completedStatsPath,
+  errorClustersPath,
+  queueDetailsPath,
+  taskDetailsPath,
+} from "../paths";
 import { QueueInfo } from "../reducers/queuesReducer";
 import { AppState } from "../store";
 import { isDarkTheme } from "../theme";
@@ -136,6 +144,10 @@ const useStyles = makeStyles((theme) => ({
     color: "inherit",
     width: "100%",
   },
+  clustersButton: {
+    marginRight: theme.spacing(2),
+    whiteSpace: "nowrap",
+  },
   inputInput: {
     padding: theme.spacing(1, 1, 1, 0),
     // vertical padding + font size from searchIcon
@@ -164,6 +176,16 @@ function TasksTableContainer(props: Props & ReduxProps) {
   ];
 
   const [searchQuery, setSearchQuery] = useState<string>("");
+  const [saveViewDialogOpen, setSaveViewDialogOpen] = useState(false);
+
+  // Query params other than the task state are saved as the filters of a view.
+  const query = useQuery();
+  const filters: { [key: string]: string } = {};
+  query.forEach((value, key) => {
+    if (key !== "status") {
+      filters[key] = value;
+    }
+  });
 
   return (
     <Paper variant="outlined" className={classes.container}>
@@ -215,6 +237,35 @@ function TasksTableContainer(props: Props & ReduxProps) {
             />
           </div>
         </div>
+        {(props.selected === "retry" || props.selected === "archived") && (
+          <Button
+            size="small"
+            className={classes.clustersButton}
+            onClick={() =>
+              history.push(errorClustersPath(props.queue, props.selected))
+            }
+          >
+            Group by error
+          </Button>
+        )}
+        {props.selected === "completed" && (
+          <Button
+            size="small"
+            className={classes.clustersButton}
+            onClick={() => history.push(completedStatsPath(props.queue))}
+          >
+            Analytics
+          </Button>
+        )}
+        {!window.READ_ONLY && (
+          <Button
+            size="small"
+            className={classes.clustersButton}
+            onClick={() => setSaveViewDialogOpen(true)}
+          >
+            Save view
+          </Button>
+        )}
       </div>
       <TabPanel value="active" selected={props.selected}>
         <ActiveTasksTable
@@ -255,6 +306,13 @@ function TasksTableContainer(props: Props & ReduxProps) {
           totalTaskCount={currentStats.completed}
         />
       </TabPanel>
+      <SaveViewDialog
+        open={saveViewDialogOpen}
+        onClose={() => setSaveViewDialogOpen(false)}
+        queue={props.queue}
+        state={props.selected}
+        filters={filters}
+      />
     </Paper>
   );
 }
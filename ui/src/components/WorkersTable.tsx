@@ -0,0 +1,125 @@
+import React from "react";
+import { Link as RouterLink } from "react-router-dom";
+import { makeStyles } from "@material-ui/core/styles";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Chip from "@material-ui/core/Chip";
+import Link from "@material-ui/core/Link";
+import Typography from "@material-ui/core/Typography";
+import { WorkerEntry } from "../api";
+import { taskDetailsPath } from "../paths";
+import {
+  durationFromSeconds,
+  stringifyDuration,
+  timeAgo,
+  uuidPrefix,
+} from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  table: {
+    minWidth: 650,
+  },
+  flag: {
+    marginRight: theme.spacing(0.5),
+  },
+  flagged: {
+    backgroundColor: theme.palette.action.hover,
+  },
+}));
+
+interface Props {
+  workers: WorkerEntry[];
+}
+
+export default function WorkersTable(props: Props) {
+  const classes = useStyles();
+
+  return (
+    <TableContainer>
+      <Table className={classes.table} size="small" aria-label="workers table">
+        <TableHead>
+          <TableRow>
+            <TableCell>Task ID</TableCell>
+            <TableCell>Type</TableCell>
+            <TableCell>Queue</TableCell>
+            <TableCell>Server</TableCell>
+            <TableCell>Started</TableCell>
+            <TableCell align="right">Elapsed</TableCell>
+            <TableCell>Deadline</TableCell>
+            <TableCell>Flags</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {props.workers.map((w) => {
+            const flagged = w.past_deadline || w.long_running || w.is_orphaned;
+            return (
+              <TableRow
+                key={w.task_id}
+                className={flagged ? classes.flagged : undefined}
+              >
+                <TableCell component="th" scope="row">
+                  <Link
+                    component={RouterLink}
+                    to={taskDetailsPath(w.queue, w.task_id)}
+                  >
+                    {uuidPrefix(w.task_id)}
+                  </Link>
+                </TableCell>
+                <TableCell>{w.task_type}</TableCell>
+                <TableCell>{w.queue}</TableCell>
+                <TableCell>
+                  {w.server_id ? `${w.host}:${w.pid}` : "-"}
+                </TableCell>
+                <TableCell>
+                  {w.start_time ? timeAgo(w.start_time) : "-"}
+                </TableCell>
+                <TableCell align="right">
+                  {w.server_id
+                    ? stringifyDuration(durationFromSeconds(w.elapsed_seconds))
+                    : "-"}
+                </TableCell>
+                <TableCell>{w.deadline || "-"}</TableCell>
+                <TableCell>
+                  {w.is_orphaned && (
+                    <Chip
+                      className={classes.flag}
+                      size="small"
+                      color="secondary"
+                      label="orphaned"
+                    />
+                  )}
+                  {w.past_deadline && (
+                    <Chip
+                      className={classes.flag}
+                      size="small"
+                      color="secondary"
+                      label="past deadline"
+                    />
+                  )}
+                  {w.long_running && (
+                    <Chip
+                      className={classes.flag}
+                      size="small"
+                      label="long-running"
+                    />
+                  )}
+                </TableCell>
+              </TableRow>
+            );
+          })}
+          {props.workers.length === 0 && (
+            <TableRow>
+              <TableCell colSpan={8}>
+                <Typography color="textSecondary">No active workers</Typography>
+              </TableCell>
+            </TableRow>
+          )}
+        </TableBody>
+      </Table>
+    </TableContainer>
+  );
+}
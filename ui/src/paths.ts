@@ -5,8 +5,14 @@ export const paths = () => ({
   SCHEDULERS: `${window.ROOT_PATH}/schedulers`,
   QUEUE_DETAILS: `${window.ROOT_PATH}/queues/:qname`,
   REDIS: `${window.ROOT_PATH}/redis`,
+  SLOS: `${window.ROOT_PATH}/slos`,
+  REDIS_DIAGNOSTICS: `${window.ROOT_PATH}/redis/diagnostics`,
   TASK_DETAILS: `${window.ROOT_PATH}/queues/:qname/tasks/:taskId`,
+  ERROR_CLUSTERS: `${window.ROOT_PATH}/queues/:qname/error_clusters/:state`,
+  COMPLETED_STATS: `${window.ROOT_PATH}/queues/:qname/completed_stats`,
   QUEUE_METRICS: `${window.ROOT_PATH}/q/metrics`,
+  SAVED_VIEWS: `${window.ROOT_PATH}/views`,
+  SAVED_VIEW: `${window.ROOT_PATH}/views/:viewId`,
 });
 
 /**************************************************************
@@ -27,6 +33,20 @@ export function taskDetailsPath(qname: string, taskId: string): string {
     .replace(":taskId", taskId);
 }
 
+export function errorClustersPath(qname: string, state: string): string {
+  return paths()
+    .ERROR_CLUSTERS.replace(":qname", qname)
+    .replace(":state", state);
+}
+
+export function completedStatsPath(qname: string): string {
+  return paths().COMPLETED_STATS.replace(":qname", qname);
+}
+
+export function savedViewPath(viewId: string): string {
+  return paths().SAVED_VIEW.replace(":viewId", viewId);
+}
+
 /**************************************************************
                         URL Params
  **************************************************************/
@@ -39,3 +59,16 @@ export interface TaskDetailsRouteParams {
   qname: string;
   taskId: string;
 }
+
+export interface ErrorClustersRouteParams {
+  qname: string;
+  state: string;
+}
+
+export interface CompletedStatsRouteParams {
+  qname: string;
+}
+
+export interface SavedViewRouteParams {
+  viewId: string;
+}
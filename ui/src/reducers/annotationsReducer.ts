@@ -0,0 +1,67 @@
+import {
+  AnnotationsActionTypes,
+  CREATE_ANNOTATION_SUCCESS,
+  DELETE_ANNOTATION_SUCCESS,
+  LIST_ANNOTATIONS_BEGIN,
+  LIST_ANNOTATIONS_ERROR,
+  LIST_ANNOTATIONS_SUCCESS,
+} from "../actions/annotationsActions";
+import { Annotation } from "../api";
+
+interface AnnotationsState {
+  loading: boolean;
+  error: string;
+  data: Annotation[];
+}
+
+const initialState: AnnotationsState = {
+  loading: false,
+  error: "",
+  data: [],
+};
+
+export default function annotationsReducer(
+  state = initialState,
+  action: AnnotationsActionTypes
+): AnnotationsState {
+  switch (action.type) {
+    case LIST_ANNOTATIONS_BEGIN:
+      return {
+        ...state,
+        loading: true,
+      };
+
+    case LIST_ANNOTATIONS_ERROR:
+      return {
+        ...state,
+        loading: false,
+        error: action.error,
+      };
+
+    case LIST_ANNOTATIONS_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        data: action.payload.annotations,
+      };
+
+    case CREATE_ANNOTATION_SUCCESS:
+      return {
+        ...state,
+        data: state.data
+          .concat(action.payload)
+          .sort(
+            (a, b) => Date.parse(a.start_time) - Date.parse(b.start_time)
+          ),
+      };
+
+    case DELETE_ANNOTATION_SUCCESS:
+      return {
+        ...state,
+        data: state.data.filter((a) => a.id !== action.annotationId),
+      };
+
+    default:
+      return state;
+  }
+}
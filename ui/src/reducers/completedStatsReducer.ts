@@ -0,0 +1,51 @@
+import {
+  CompletedStatsActionTypes,
+  GET_COMPLETED_STATS_BEGIN,
+  GET_COMPLETED_STATS_ERROR,
+  GET_COMPLETED_STATS_SUCCESS,
+} from "../actions/completedStatsActions";
+import { CompletedTaskStatsResponse } from "../api";
+
+interface CompletedStatsState {
+  loading: boolean;
+  error: string;
+  // Queue of the stats.
+  queue: string;
+  data: CompletedTaskStatsResponse | null;
+}
+
+const initialState: CompletedStatsState = {
+  loading: false,
+  error: "",
+  queue: "",
+  data: null,
+};
+
+export default function completedStatsReducer(
+  state = initialState,
+  action: CompletedStatsActionTypes
+): CompletedStatsState {
+  switch (action.type) {
+    case GET_COMPLETED_STATS_BEGIN: {
+      // Clear the stats of another queue.
+      if (action.queue !== state.queue) {
+        return { ...initialState, loading: true, queue: action.queue };
+      }
+      return { ...state, loading: true };
+    }
+
+    case GET_COMPLETED_STATS_ERROR:
+      return { ...state, loading: false, error: action.error };
+
+    case GET_COMPLETED_STATS_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        queue: action.queue,
+        data: action.payload,
+      };
+
+    default:
+      return state;
+  }
+}
@@ -0,0 +1,70 @@
+import {
+  ErrorClustersActionTypes,
+  LIST_ERROR_CLUSTERS_BEGIN,
+  LIST_ERROR_CLUSTERS_ERROR,
+  LIST_ERROR_CLUSTERS_SUCCESS,
+} from "../actions/errorClustersActions";
+import { ErrorCluster } from "../api";
+
+interface ErrorClustersState {
+  loading: boolean;
+  error: string;
+  // Queue and task state of the clusters.
+  queue: string;
+  state: string;
+  clusters: ErrorCluster[];
+  total: number;
+  scanned: number;
+}
+
+const initialState: ErrorClustersState = {
+  loading: false,
+  error: "",
+  queue: "",
+  state: "",
+  clusters: [],
+  total: 0,
+  scanned: 0,
+};
+
+export default function errorClustersReducer(
+  state = initialState,
+  action: ErrorClustersActionTypes
+): ErrorClustersState {
+  switch (action.type) {
+    case LIST_ERROR_CLUSTERS_BEGIN: {
+      // Clear the clusters of another queue or state.
+      if (action.queue !== state.queue || action.state !== state.state) {
+        return {
+          ...initialState,
+          loading: true,
+          queue: action.queue,
+          state: action.state,
+        };
+      }
+      return {
+        ...state,
+        loading: true,
+      };
+    }
+
+    case LIST_ERROR_CLUSTERS_ERROR:
+      return {
+        ...state,
+        loading: false,
+        error: action.error,
+      };
+
+    case LIST_ERROR_CLUSTERS_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        queue: action.queue,
+        state: action.state,
+        ...action.payload,
+      };
+
+    default:
+      return state;
+  }
+}
@@ -1,5 +1,7 @@
 import {
   GroupsActionTypes,
+  LIST_GROUP_DETAILS_ERROR,
+  LIST_GROUP_DETAILS_SUCCESS,
   LIST_GROUPS_BEGIN,
   LIST_GROUPS_ERROR,
   LIST_GROUPS_SUCCESS,
@@ -8,18 +10,27 @@ import {
   LIST_AGGREGATING_TASKS_SUCCESS,
   TasksActionTypes,
 } from "../actions/tasksActions";
-import { GroupInfo } from "../api";
+import { GroupDetail, GroupInfo, GroupSizeBucket } from "../api";
 
 interface GroupsState {
   loading: boolean;
   data: GroupInfo[];
   error: string;
+  // Details of the groups in detailsQueue.
+  detailsQueue: string;
+  details: GroupDetail[];
+  sizeHistogram: GroupSizeBucket[];
+  detailsError: string;
 }
 
 const initialState: GroupsState = {
   data: [],
   loading: false,
   error: "",
+  detailsQueue: "",
+  details: [],
+  sizeHistogram: [],
+  detailsError: "",
 };
 
 function groupsReducer(
@@ -47,6 +58,18 @@ function groupsReducer(
         data: action.payload.groups,
       };
 
+    case LIST_GROUP_DETAILS_SUCCESS:
+      return {
+        ...state,
+        detailsQueue: action.queue,
+        details: action.payload.groups,
+        sizeHistogram: action.payload.size_histogram,
+        detailsError: "",
+      };
+
+    case LIST_GROUP_DETAILS_ERROR:
+      return { ...state, detailsError: action.error };
+
     default:
       return state;
   }
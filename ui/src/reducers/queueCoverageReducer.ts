@@ -0,0 +1,49 @@
+import {
+  GET_QUEUE_COVERAGE_BEGIN,
+  GET_QUEUE_COVERAGE_ERROR,
+  GET_QUEUE_COVERAGE_SUCCESS,
+  QueueCoverageActionTypes,
+} from "../actions/queueCoverageActions";
+import { QueueCoverageResponse } from "../api";
+
+interface QueueCoverageState {
+  loading: boolean;
+  error: string;
+  data: QueueCoverageResponse | null;
+}
+
+const initialState: QueueCoverageState = {
+  loading: false,
+  error: "",
+  data: null,
+};
+
+export default function queueCoverageReducer(
+  state = initialState,
+  action: QueueCoverageActionTypes
+): QueueCoverageState {
+  switch (action.type) {
+    case GET_QUEUE_COVERAGE_BEGIN:
+      return {
+        ...state,
+        loading: true,
+      };
+
+    case GET_QUEUE_COVERAGE_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        data: action.payload,
+      };
+
+    case GET_QUEUE_COVERAGE_ERROR:
+      return {
+        ...state,
+        loading: false,
+        error: action.error,
+      };
+
+    default:
+      return state;
+  }
+}
@@ -0,0 +1,49 @@
+import {
+  GET_QUEUE_RANKING_BEGIN,
+  GET_QUEUE_RANKING_ERROR,
+  GET_QUEUE_RANKING_SUCCESS,
+  QueueRankingActionTypes,
+} from "../actions/queueRankingActions";
+import { QueueRankingResponse } from "../api";
+
+interface QueueRankingState {
+  loading: boolean;
+  error: string;
+  data: QueueRankingResponse | null;
+}
+
+const initialState: QueueRankingState = {
+  loading: false,
+  error: "",
+  data: null,
+};
+
+export default function queueRankingReducer(
+  state = initialState,
+  action: QueueRankingActionTypes
+): QueueRankingState {
+  switch (action.type) {
+    case GET_QUEUE_RANKING_BEGIN:
+      return {
+        ...state,
+        loading: true,
+      };
+
+    case GET_QUEUE_RANKING_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        data: action.payload,
+      };
+
+    case GET_QUEUE_RANKING_ERROR:
+      return {
+        ...state,
+        loading: false,
+        error: action.error,
+      };
+
+    default:
+      return state;
+  }
+}
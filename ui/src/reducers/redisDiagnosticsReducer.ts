@@ -0,0 +1,60 @@
+import {
+  GET_REDIS_DIAGNOSTICS_BEGIN,
+  GET_REDIS_DIAGNOSTICS_ERROR,
+  GET_REDIS_DIAGNOSTICS_SUCCESS,
+  RedisDiagnosticsActionTypes,
+} from "../actions/redisDiagnosticsActions";
+import {
+  RedisClientsResponse,
+  RedisCommandStatsResponse,
+  RedisLatencyResponse,
+  RedisSlowLogResponse,
+} from "../api";
+
+interface RedisDiagnosticsState {
+  loading: boolean;
+  error: string;
+  slowLog: RedisSlowLogResponse | null;
+  latency: RedisLatencyResponse | null;
+  clients: RedisClientsResponse | null;
+  commandStats: RedisCommandStatsResponse | null;
+}
+
+const initialState: RedisDiagnosticsState = {
+  loading: false,
+  error: "",
+  slowLog: null,
+  latency: null,
+  clients: null,
+  commandStats: null,
+};
+
+export default function redisDiagnosticsReducer(
+  state = initialState,
+  action: RedisDiagnosticsActionTypes
+): RedisDiagnosticsState {
+  switch (action.type) {
+    case GET_REDIS_DIAGNOSTICS_BEGIN:
+      return {
+        ...state,
+        loading: true,
+      };
+
+    case GET_REDIS_DIAGNOSTICS_ERROR:
+      return {
+        ...state,
+        loading: false,
+        error: action.error,
+      };
+
+    case GET_REDIS_DIAGNOSTICS_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        ...action.payload,
+      };
+
+    default:
+      return state;
+  }
+}
@@ -2,9 +2,17 @@ import {
   GET_REDIS_INFO_BEGIN,
   GET_REDIS_INFO_ERROR,
   GET_REDIS_INFO_SUCCESS,
+  GET_REDIS_INFO_HISTORY_ERROR,
+  GET_REDIS_INFO_HISTORY_SUCCESS,
   RedisInfoActionTypes,
 } from "../actions/redisInfoActions";
-import { QueueLocation, RedisInfo } from "../api";
+import {
+  ClusterNode,
+  QueueLocation,
+  RedisInfo,
+  RedisInfoHistoryResponse,
+  SentinelInfo,
+} from "../api";
 
 interface RedisInfoState {
   loading: boolean;
@@ -15,6 +23,11 @@ interface RedisInfoState {
   cluster: boolean;
   rawClusterNodes: string | null;
   queueLocations: QueueLocation[] | null;
+  clusterNodes: ClusterNode[] | null;
+  warnings: string[] | null;
+  sentinel: SentinelInfo | null;
+  history: RedisInfoHistoryResponse | null;
+  historyError: string;
 }
 
 const initialState: RedisInfoState = {
@@ -26,6 +39,11 @@ const initialState: RedisInfoState = {
   cluster: false,
   rawClusterNodes: null,
   queueLocations: null,
+  clusterNodes: null,
+  warnings: null,
+  sentinel: null,
+  history: null,
+  historyError: "",
 };
 
 export default function redisInfoReducer(
@@ -48,6 +66,7 @@ export default function redisInfoReducer(
 
     case GET_REDIS_INFO_SUCCESS:
       return {
+        ...state,
         loading: false,
         error: "",
         address: action.payload.address,
@@ -56,6 +75,22 @@ export default function redisInfoReducer(
         cluster: action.payload.cluster,
         rawClusterNodes: action.payload.raw_cluster_nodes,
         queueLocations: action.payload.queue_locations,
+        clusterNodes: action.payload.nodes,
+        warnings: action.payload.warnings,
+        sentinel: action.payload.sentinel || null,
+      };
+
+    case GET_REDIS_INFO_HISTORY_ERROR:
+      return {
+        ...state,
+        historyError: action.error,
+      };
+
+    case GET_REDIS_INFO_HISTORY_SUCCESS:
+      return {
+        ...state,
+        history: action.payload,
+        historyError: "",
       };
 
     default:
@@ -0,0 +1,69 @@
+import {
+  CREATE_VIEW_SUCCESS,
+  DELETE_VIEW_SUCCESS,
+  LIST_VIEWS_BEGIN,
+  LIST_VIEWS_ERROR,
+  LIST_VIEWS_SUCCESS,
+  SavedViewsActionTypes,
+} from "../actions/savedViewsActions";
+import { SavedView } from "../api";
+
+interface SavedViewsState {
+  loading: boolean;
+  error: string;
+  data: SavedView[];
+  // Authenticated user. Only authenticated users can have private views.
+  user: string;
+}
+
+const initialState: SavedViewsState = {
+  loading: false,
+  error: "",
+  data: [],
+  user: "",
+};
+
+export default function savedViewsReducer(
+  state = initialState,
+  action: SavedViewsActionTypes
+): SavedViewsState {
+  switch (action.type) {
+    case LIST_VIEWS_BEGIN:
+      return {
+        ...state,
+        loading: true,
+      };
+
+    case LIST_VIEWS_ERROR:
+      return {
+        ...state,
+        loading: false,
+        error: action.error,
+      };
+
+    case LIST_VIEWS_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        data: action.payload.views,
+        user: action.payload.user,
+      };
+
+    case CREATE_VIEW_SUCCESS:
+      return {
+        ...state,
+        data: state.data
+          .concat(action.payload)
+          .sort((a, b) => a.name.localeCompare(b.name)),
+      };
+
+    case DELETE_VIEW_SUCCESS:
+      return {
+        ...state,
+        data: state.data.filter((v) => v.id !== action.viewId),
+      };
+
+    default:
+      return state;
+  }
+}
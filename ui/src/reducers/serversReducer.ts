@@ -1,21 +1,27 @@
 import {
+  GET_SERVER_HISTORY_ERROR,
+  GET_SERVER_HISTORY_SUCCESS,
   LIST_SERVERS_BEGIN,
   LIST_SERVERS_ERROR,
   LIST_SERVERS_SUCCESS,
   ServersActionTypes,
 } from "../actions/serversActions";
-import { ServerInfo } from "../api";
+import { ServerHistoryResponse, ServerInfo } from "../api";
 
 interface ServersState {
   loading: boolean;
   error: string;
   data: ServerInfo[];
+  history: ServerHistoryResponse | null;
+  historyError: string;
 }
 
 const initialState: ServersState = {
   loading: false,
   error: "",
   data: [],
+  history: null,
+  historyError: "",
 };
 
 export default function serversReducer(
@@ -31,6 +37,7 @@ export default function serversReducer(
 
     case LIST_SERVERS_SUCCESS:
       return {
+        ...state,
         loading: false,
         error: "",
         data: action.payload.servers,
@@ -43,6 +50,19 @@ export default function serversReducer(
         loading: false,
       };
 
+    case GET_SERVER_HISTORY_ERROR:
+      return {
+        ...state,
+        historyError: action.error,
+      };
+
+    case GET_SERVER_HISTORY_SUCCESS:
+      return {
+        ...state,
+        history: action.payload,
+        historyError: "",
+      };
+
     default:
       return state;
   }
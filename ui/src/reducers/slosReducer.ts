@@ -0,0 +1,49 @@
+import {
+  LIST_SLOS_BEGIN,
+  LIST_SLOS_ERROR,
+  LIST_SLOS_SUCCESS,
+  SLOsActionTypes,
+} from "../actions/slosActions";
+import { ListSLOsResponse } from "../api";
+
+interface SLOsState {
+  loading: boolean;
+  error: string;
+  data: ListSLOsResponse | null;
+}
+
+const initialState: SLOsState = {
+  loading: false,
+  error: "",
+  data: null,
+};
+
+export default function slosReducer(
+  state = initialState,
+  action: SLOsActionTypes
+): SLOsState {
+  switch (action.type) {
+    case LIST_SLOS_BEGIN:
+      return {
+        ...state,
+        loading: true,
+      };
+
+    case LIST_SLOS_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        data: action.payload,
+      };
+
+    case LIST_SLOS_ERROR:
+      return {
+        ...state,
+        loading: false,
+        error: action.error,
+      };
+
+    default:
+      return state;
+  }
+}
@@ -49,6 +49,41 @@ import {
   ARCHIVE_ALL_AGGREGATING_TASKS_SUCCESS,
   DELETE_ALL_AGGREGATING_TASKS_SUCCESS,
 } from "../actions/tasksActions";
+import {
+  ARCHIVE_ERROR_CLUSTER_SUCCESS,
+  DELETE_ERROR_CLUSTER_SUCCESS,
+  ERROR_CLUSTER_ACTION_ERROR,
+  ErrorClustersActionTypes,
+  RUN_ERROR_CLUSTER_SUCCESS,
+} from "../actions/errorClustersActions";
+import {
+  FLUSH_GROUPS_ERROR,
+  FLUSH_GROUPS_SUCCESS,
+  GroupsActionTypes,
+} from "../actions/groupsActions";
+import {
+  ADD_NOTE_SUCCESS,
+  DELETE_NOTE_SUCCESS,
+  NOTE_ACTION_ERROR,
+  NotesActionTypes,
+} from "../actions/notesActions";
+import {
+  ANNOTATION_ACTION_ERROR,
+  AnnotationsActionTypes,
+  CREATE_ANNOTATION_SUCCESS,
+  DELETE_ANNOTATION_SUCCESS,
+} from "../actions/annotationsActions";
+import {
+  CREATE_VIEW_SUCCESS,
+  DELETE_VIEW_SUCCESS,
+  SavedViewsActionTypes,
+  VIEW_ACTION_ERROR,
+} from "../actions/savedViewsActions";
+import {
+  CANCEL_FLAGGED_WORKERS_ERROR,
+  CANCEL_FLAGGED_WORKERS_SUCCESS,
+  WorkersActionTypes,
+} from "../actions/workersActions";
 
 interface SnackbarState {
   isOpen: boolean;
@@ -62,7 +97,15 @@ const initialState: SnackbarState = {
 
 function snackbarReducer(
   state = initialState,
-  action: TasksActionTypes | SnackbarActionTypes
+  action:
+    | TasksActionTypes
+    | ErrorClustersActionTypes
+    | WorkersActionTypes
+    | NotesActionTypes
+    | AnnotationsActionTypes
+    | SavedViewsActionTypes
+    | GroupsActionTypes
+    | SnackbarActionTypes
 ): SnackbarState {
   switch (action.type) {
     case CLOSE_SNACKBAR:
@@ -380,6 +423,130 @@ function snackbarReducer(
         message: `${n} completed ${n === 1 ? "task" : "tasks"} deleted`,
       };
 
+    case RUN_ERROR_CLUSTER_SUCCESS: {
+      const n = action.payload.pending_ids.length;
+      return {
+        isOpen: true,
+        message: `${n} ${n === 1 ? "task is" : "tasks are"} now pending`,
+      };
+    }
+
+    case ARCHIVE_ERROR_CLUSTER_SUCCESS: {
+      const n = action.payload.archived_ids.length;
+      return {
+        isOpen: true,
+        message: `${n} ${n === 1 ? "task is" : "tasks are"} now archived`,
+      };
+    }
+
+    case DELETE_ERROR_CLUSTER_SUCCESS: {
+      const n = action.payload.deleted_ids.length;
+      return {
+        isOpen: true,
+        message: `${n} ${n === 1 ? "task" : "tasks"} deleted`,
+      };
+    }
+
+    case ERROR_CLUSTER_ACTION_ERROR:
+      return {
+        isOpen: true,
+        message: `Could not apply the action to the error cluster: ${action.error}`,
+      };
+
+    case ADD_NOTE_SUCCESS:
+      return {
+        isOpen: true,
+        message: "Note added",
+      };
+
+    case DELETE_NOTE_SUCCESS:
+      return {
+        isOpen: true,
+        message: "Note deleted",
+      };
+
+    case NOTE_ACTION_ERROR:
+      return {
+        isOpen: true,
+        message: `Could not update notes: ${action.error}`,
+      };
+
+    case CREATE_ANNOTATION_SUCCESS:
+      return {
+        isOpen: true,
+        message: "Annotation added",
+      };
+
+    case DELETE_ANNOTATION_SUCCESS:
+      return {
+        isOpen: true,
+        message: "Annotation deleted",
+      };
+
+    case ANNOTATION_ACTION_ERROR:
+      return {
+        isOpen: true,
+        message: `Could not update annotations: ${action.error}`,
+      };
+
+    case CREATE_VIEW_SUCCESS:
+      return {
+        isOpen: true,
+        message: `View "${action.payload.name}" saved`,
+      };
+
+    case DELETE_VIEW_SUCCESS:
+      return {
+        isOpen: true,
+        message: "View deleted",
+      };
+
+    case VIEW_ACTION_ERROR:
+      return {
+        isOpen: true,
+        message: `Could not update views: ${action.error}`,
+      };
+
+    case CANCEL_FLAGGED_WORKERS_SUCCESS: {
+      const n = action.payload.canceled_ids.length;
+      const orphaned = action.payload.orphaned_ids.length;
+      return {
+        isOpen: true,
+        message:
+          `Cancelation signal sent to ${n} flagged ${
+            n === 1 ? "task" : "tasks"
+          }` +
+          (orphaned > 0
+            ? `, ${orphaned} orphaned ${
+                orphaned === 1 ? "task has" : "tasks have"
+              } no worker to cancel`
+            : ""),
+      };
+    }
+
+    case CANCEL_FLAGGED_WORKERS_ERROR:
+      return {
+        isOpen: true,
+        message: `Could not cancel flagged tasks: ${action.error}`,
+      };
+
+    case FLUSH_GROUPS_SUCCESS: {
+      const failed = action.payload.results.filter((r) => r.error).length;
+      const n = action.payload.run;
+      return {
+        isOpen: true,
+        message:
+          `${n} ${n === 1 ? "task is" : "tasks are"} now pending` +
+          (failed > 0 ? ` (${failed} groups could not be flushed)` : ""),
+      };
+    }
+
+    case FLUSH_GROUPS_ERROR:
+      return {
+        isOpen: true,
+        message: `Could not flush groups: ${action.error}`,
+      };
+
     default:
       return state;
   }
@@ -0,0 +1,62 @@
+import {
+  LIST_WORKERS_BEGIN,
+  LIST_WORKERS_ERROR,
+  LIST_WORKERS_SUCCESS,
+  WorkersActionTypes,
+} from "../actions/workersActions";
+import { WorkerEntry } from "../api";
+
+interface WorkersState {
+  loading: boolean;
+  error: string;
+  data: WorkerEntry[];
+  longRunningThresholdSeconds: number;
+  pastDeadline: number;
+  longRunning: number;
+  orphaned: number;
+}
+
+const initialState: WorkersState = {
+  loading: false,
+  error: "",
+  data: [],
+  longRunningThresholdSeconds: 0,
+  pastDeadline: 0,
+  longRunning: 0,
+  orphaned: 0,
+};
+
+export default function workersReducer(
+  state = initialState,
+  action: WorkersActionTypes
+): WorkersState {
+  switch (action.type) {
+    case LIST_WORKERS_BEGIN:
+      return {
+        ...state,
+        loading: true,
+      };
+
+    case LIST_WORKERS_SUCCESS:
+      return {
+        loading: false,
+        error: "",
+        data: action.payload.workers,
+        longRunningThresholdSeconds:
+          action.payload.long_running_threshold_seconds,
+        pastDeadline: action.payload.past_deadline,
+        longRunning: action.payload.long_running,
+        orphaned: action.payload.orphaned,
+      };
+
+    case LIST_WORKERS_ERROR:
+      return {
+        ...state,
+        error: action.error,
+        loading: false,
+      };
+
+    default:
+      return state;
+  }
+}
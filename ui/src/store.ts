@@ -4,11 +4,20 @@ import queuesReducer from "./reducers/queuesReducer";
 import tasksReducer from "./reducers/tasksReducer";
 import groupsReducer from "./reducers/groupsReducer";
 import serversReducer from "./reducers/serversReducer";
+import workersReducer from "./reducers/workersReducer";
 import schedulerEntriesReducer from "./reducers/schedulerEntriesReducer";
 import snackbarReducer from "./reducers/snackbarReducer";
 import queueStatsReducer from "./reducers/queueStatsReducer";
+import queueCoverageReducer from "./reducers/queueCoverageReducer";
+import queueRankingReducer from "./reducers/queueRankingReducer";
 import redisInfoReducer from "./reducers/redisInfoReducer";
+import redisDiagnosticsReducer from "./reducers/redisDiagnosticsReducer";
+import errorClustersReducer from "./reducers/errorClustersReducer";
+import completedStatsReducer from "./reducers/completedStatsReducer";
+import slosReducer from "./reducers/slosReducer";
 import metricsReducer from "./reducers/metricsReducer";
+import annotationsReducer from "./reducers/annotationsReducer";
+import savedViewsReducer from "./reducers/savedViewsReducer";
 import { loadState } from "./localStorage";
 
 const rootReducer = combineReducers({
@@ -17,11 +26,20 @@ const rootReducer = combineReducers({
   tasks: tasksReducer,
   groups: groupsReducer,
   servers: serversReducer,
+  workers: workersReducer,
   schedulerEntries: schedulerEntriesReducer,
   snackbar: snackbarReducer,
   queueStats: queueStatsReducer,
+  queueCoverage: queueCoverageReducer,
+  queueRanking: queueRankingReducer,
   redis: redisInfoReducer,
+  redisDiagnostics: redisDiagnosticsReducer,
+  errorClusters: errorClustersReducer,
+  completedStats: completedStatsReducer,
+  slos: slosReducer,
   metrics: metricsReducer,
+  annotations: annotationsReducer,
+  savedViews: savedViewsReducer,
 });
 
 const preloadedState = loadState();
@@ -1,21 +1,56 @@
 import { AxiosError } from "axios";
 
+// Body of an error response returned by the API server.
+interface ErrorResponse {
+  code: string; // machine-readable error code (e.g. "queue_not_found")
+  message: string;
+  queue?: string;
+  task_id?: string;
+}
+
+function isErrorResponse(data: unknown): data is ErrorResponse {
+  return (
+    typeof data === "object" &&
+    data !== null &&
+    typeof (data as ErrorResponse).message === "string"
+  );
+}
+
+// errorMessage returns the error message from the response body.
+// The body is usually a JSON error response, but it may be plain text
+// (e.g. error returned by a proxy server).
+function errorMessage(data: unknown): string {
+  if (isErrorResponse(data)) {
+    return data.message;
+  }
+  return String(data);
+}
+
 // toErrorStringWithHttpStatus returns a string representaion of axios error with HTTP status.
 export function toErrorStringWithHttpStatus(error: AxiosError<string>): string {
   const { response } = error;
   if (!response) {
     return "error: no error response data available";
   }
-  return `${response.status} (${response.statusText}): ${response.data}`;
+  return `${response.status} (${response.statusText}): ${errorMessage(
+    response.data
+  )}`;
 }
 
+// Message of the error thrown when the user cancels a request in a
+// confirmation dialog.
+export const requestCanceledMessage = "Canceled by user";
+
 // toErrorString returns a string representaion of axios error.
 export function toErrorString(error: AxiosError<string>): string {
   const { response } = error;
   if (!response) {
+    if (error.message === requestCanceledMessage) {
+      return requestCanceledMessage;
+    }
     return "Unknown error occurred. See the logs for details.";
   }
-  return response.data;
+  return errorMessage(response.data);
 }
 
 interface Duration {
@@ -52,6 +87,14 @@ export function stringifyDuration(d: Duration): string {
   );
 }
 
+// Formats a number of seconds in the largest unit dividing it (e.g. 7d, 6h).
+export function formatSeconds(secs: number): string {
+  if (secs % 86400 === 0) return `${secs / 86400}d`;
+  if (secs % 3600 === 0) return `${secs / 3600}h`;
+  if (secs % 60 === 0) return `${secs / 60}m`;
+  return `${secs}s`;
+}
+
 export function durationBefore(timestamp: string): string {
   try {
     const duration = durationBetween(Date.parse(timestamp), Date.now());
@@ -0,0 +1,257 @@
+import React, { useMemo, useState } from "react";
+import { connect, ConnectedProps } from "react-redux";
+import { Link as RouterLink, useParams } from "react-router-dom";
+import Container from "@material-ui/core/Container";
+import { makeStyles, useTheme } from "@material-ui/core/styles";
+import Grid from "@material-ui/core/Grid";
+import Typography from "@material-ui/core/Typography";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Paper from "@material-ui/core/Paper";
+import Button from "@material-ui/core/Button";
+import Select from "@material-ui/core/Select";
+import MenuItem from "@material-ui/core/MenuItem";
+import Alert from "@material-ui/lab/Alert";
+import AlertTitle from "@material-ui/lab/AlertTitle";
+import ArrowBackIcon from "@material-ui/icons/ArrowBack";
+import {
+  BarChart,
+  Bar,
+  XAxis,
+  YAxis,
+  CartesianGrid,
+  Tooltip,
+  ResponsiveContainer,
+} from "recharts";
+import { getCompletedStatsAsync } from "../actions/completedStatsActions";
+import { DurationBucket } from "../api";
+import { usePolling } from "../hooks";
+import { CompletedStatsRouteParams, queueDetailsPath } from "../paths";
+import { AppState } from "../store";
+import { formatSeconds } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  container: {
+    paddingTop: theme.spacing(4),
+    paddingBottom: theme.spacing(4),
+  },
+  header: {
+    display: "flex",
+    alignItems: "center",
+    justifyContent: "space-between",
+  },
+  paper: {
+    padding: theme.spacing(2),
+  },
+  table: {
+    minWidth: 650,
+  },
+}));
+
+// Windows of the completion throughput, in seconds.
+const windows = [
+  { label: "Last hour", seconds: 3600 },
+  { label: "Last 6 hours", seconds: 6 * 3600 },
+  { label: "Last 24 hours", seconds: 24 * 3600 },
+  { label: "Last 7 days", seconds: 7 * 24 * 3600 },
+];
+
+function mapStateToProps(state: AppState) {
+  return {
+    loading: state.completedStats.loading,
+    error: state.completedStats.error,
+    stats: state.completedStats.data,
+    pollInterval: state.settings.pollInterval,
+  };
+}
+
+const connector = connect(mapStateToProps, { getCompletedStatsAsync });
+
+type Props = ConnectedProps<typeof connector>;
+
+function bucketLabel(b: DurationBucket): string {
+  if (b.max_seconds === 0) return `>= ${formatSeconds(b.min_seconds)}`;
+  if (b.min_seconds === 0) return `< ${formatSeconds(b.max_seconds)}`;
+  return `${formatSeconds(b.min_seconds)}-${formatSeconds(b.max_seconds)}`;
+}
+
+function formatBuckets(buckets: DurationBucket[]): string {
+  return buckets
+    .filter((b) => b.count > 0)
+    .map((b) => `${bucketLabel(b)}: ${b.count}`)
+    .join(", ");
+}
+
+function CompletedStatsView(props: Props) {
+  const classes = useStyles();
+  const theme = useTheme();
+  const { qname } = useParams<CompletedStatsRouteParams>();
+  const [windowSeconds, setWindowSeconds] = useState(windows[0].seconds);
+  const { getCompletedStatsAsync, pollInterval, stats } = props;
+
+  const fetchStats = useMemo(() => {
+    return () => {
+      getCompletedStatsAsync(qname, windowSeconds);
+    };
+  }, [qname, windowSeconds, getCompletedStatsAsync]);
+
+  usePolling(fetchStats, pollInterval);
+
+  const chartData = stats
+    ? stats.completions.map((count, i) => ({
+        timestamp:
+          Date.parse(stats.window_start) / 1000 + i * stats.bucket_seconds,
+        count,
+      }))
+    : [];
+
+  return (
+    <Container maxWidth="lg" className={classes.container}>
+      <Grid container spacing={3}>
+        <Grid item xs={12}>
+          <Button
+            startIcon={<ArrowBackIcon />}
+            component={RouterLink}
+            to={queueDetailsPath(qname, "completed")}
+          >
+            Back to completed tasks
+          </Button>
+        </Grid>
+        <Grid item xs={12} className={classes.header}>
+          <div>
+            <Typography variant="h5" color="textPrimary">
+              Completed Task Analytics
+            </Typography>
+            {stats && (
+              <Typography color="textSecondary">
+                Completed tasks in queue {qname}. Scanned {stats.scanned}{" "}
+                of {stats.total} tasks; tasks are deleted once their
+                retention period expires.
+              </Typography>
+            )}
+          </div>
+          <Select
+            value={windowSeconds}
+            onChange={(e) => setWindowSeconds(e.target.value as number)}
+          >
+            {windows.map((w) => (
+              <MenuItem key={w.seconds} value={w.seconds}>
+                {w.label}
+              </MenuItem>
+            ))}
+          </Select>
+        </Grid>
+        {props.error !== "" && (
+          <Grid item xs={12}>
+            <Alert severity="error">
+              <AlertTitle>Error</AlertTitle>
+              Could not retrieve completed task stats —{" "}
+              <strong>{props.error}</strong>
+            </Alert>
+          </Grid>
+        )}
+        {stats && (
+          <Grid item xs={12}>
+            <Paper variant="outlined" className={classes.paper}>
+              <Typography variant="subtitle1" color="textSecondary">
+                Completions: {stats.completed_in_window} (
+                {stats.throughput_per_minute.toFixed(2)}/min)
+              </Typography>
+              <ResponsiveContainer height={200}>
+                <BarChart data={chartData}>
+                  <CartesianGrid strokeDasharray="3 3" />
+                  <XAxis
+                    minTickGap={10}
+                    dataKey="timestamp"
+                    tickFormatter={(timestamp: number) =>
+                      windowSeconds > 24 * 3600
+                        ? new Date(timestamp * 1000).toLocaleDateString()
+                        : new Date(timestamp * 1000).toLocaleTimeString()
+                    }
+                    stroke={theme.palette.text.secondary}
+                  />
+                  <YAxis
+                    allowDecimals={false}
+                    stroke={theme.palette.text.secondary}
+                  />
+                  <Tooltip
+                    labelFormatter={(timestamp: number) =>
+                      new Date(timestamp * 1000).toLocaleString()
+                    }
+                  />
+                  <Bar
+                    dataKey="count"
+                    fill={theme.palette.success.main}
+                    isAnimationActive={false}
+                  />
+                </BarChart>
+              </ResponsiveContainer>
+            </Paper>
+          </Grid>
+        )}
+        {stats && (
+          <Grid item xs={12}>
+            <TableContainer component={Paper} variant="outlined">
+              <Table
+                className={classes.table}
+                size="small"
+                aria-label="completed task stats table"
+              >
+                <TableHead>
+                  <TableRow>
+                    <TableCell>Task Type</TableCell>
+                    <TableCell align="right">Tasks</TableCell>
+                    <TableCell align="right">Completed in Window</TableCell>
+                    <TableCell align="right">Per Minute</TableCell>
+                    <TableCell align="right">
+                      Result Size (avg/p95/max)
+                    </TableCell>
+                    <TableCell>Retention</TableCell>
+                    <TableCell>TTL</TableCell>
+                  </TableRow>
+                </TableHead>
+                <TableBody>
+                  {stats.task_types.map((t) => (
+                    <TableRow key={t.task_type}>
+                      <TableCell component="th" scope="row">
+                        {t.task_type}
+                      </TableCell>
+                      <TableCell align="right">{t.count}</TableCell>
+                      <TableCell align="right">
+                        {t.completed_in_window}
+                      </TableCell>
+                      <TableCell align="right">
+                        {t.throughput_per_minute.toFixed(2)}
+                      </TableCell>
+                      <TableCell align="right">
+                        {Math.round(t.result_size.avg)}/{t.result_size.p95}/
+                        {t.result_size.max} B
+                      </TableCell>
+                      <TableCell>{formatBuckets(t.retention)}</TableCell>
+                      <TableCell>{formatBuckets(t.ttl)}</TableCell>
+                    </TableRow>
+                  ))}
+                  {stats.task_types.length === 0 && !props.loading && (
+                    <TableRow>
+                      <TableCell colSpan={7}>
+                        <Typography color="textSecondary">
+                          No completed tasks
+                        </Typography>
+                      </TableCell>
+                    </TableRow>
+                  )}
+                </TableBody>
+              </Table>
+            </TableContainer>
+          </Grid>
+        )}
+      </Grid>
+    </Container>
+  );
+}
+
+export default connector(CompletedStatsView);
@@ -1,11 +1,15 @@
-import React, { useEffect } from "react";
+import React, { useEffect, useMemo, useState } from "react";
 import { connect, ConnectedProps } from "react-redux";
 import Container from "@material-ui/core/Container";
 import { makeStyles } from "@material-ui/core/styles";
 import Grid from "@material-ui/core/Grid";
 import Paper from "@material-ui/core/Paper";
 import Typography from "@material-ui/core/Typography";
+import IconButton from "@material-ui/core/IconButton";
+import Menu from "@material-ui/core/Menu";
+import MenuItem from "@material-ui/core/MenuItem";
 import InfoIcon from "@material-ui/icons/Info";
+import GetAppIcon from "@material-ui/icons/GetApp";
 import Alert from "@material-ui/lab/Alert";
 import AlertTitle from "@material-ui/lab/AlertTitle";
 import {
@@ -15,11 +19,20 @@ import {
   deleteQueueAsync,
 } from "../actions/queuesActions";
 import { listQueueStatsAsync } from "../actions/queueStatsActions";
+import { getQueueCoverageAsync } from "../actions/queueCoverageActions";
+import { getQueueRankingAsync } from "../actions/queueRankingActions";
 import { dailyStatsKeyChange } from "../actions/settingsActions";
 import { AppState } from "../store";
+import {
+  queueStatsExportUrl,
+  QueueRankingSortBy,
+  StatsGroupBy,
+} from "../api";
 import QueueSizeChart from "../components/QueueSizeChart";
 import ProcessedTasksChart from "../components/ProcessedTasksChart";
 import QueuesOverviewTable from "../components/QueuesOverviewTable";
+import QueueCoverageTable from "../components/QueueCoverageTable";
+import QueueRankingTable from "../components/QueueRankingTable";
 import Tooltip from "../components/Tooltip";
 import SplitButton from "../components/SplitButton";
 import { usePolling } from "../hooks";
@@ -46,6 +59,13 @@ const useStyles = makeStyles((theme) => ({
     display: "flex",
     alignItems: "center",
   },
+  chartHeaderActions: {
+    display: "flex",
+    alignItems: "center",
+    "& > *": {
+      marginLeft: theme.spacing(1),
+    },
+  },
   chartContainer: {
     width: "100%",
     height: "300px",
@@ -74,6 +94,8 @@ function mapStateToProps(state: AppState) {
     pollInterval: state.settings.pollInterval,
     queueStats: state.queueStats.data,
     dailyStatsKey: state.settings.dailyStatsChartType,
+    coverage: state.queueCoverage.data,
+    ranking: state.queueRanking.data,
   };
 }
 
@@ -83,6 +105,8 @@ const mapDispatchToProps = {
   resumeQueueAsync,
   deleteQueueAsync,
   listQueueStatsAsync,
+  getQueueCoverageAsync,
+  getQueueRankingAsync,
   dailyStatsKeyChange,
 };
 
@@ -93,6 +117,13 @@ type Props = ConnectedProps<typeof connector>;
 export type DailyStatsKey = "today" | "last-7d" | "last-30d" | "last-90d";
 export const defaultDailyStatsKey = "last-7d";
 
+const numDaysByKey: { [key in DailyStatsKey]: number } = {
+  today: 1,
+  "last-7d": 7,
+  "last-30d": 30,
+  "last-90d": 90,
+};
+
 function DashboardView(props: Props) {
   const {
     pollInterval,
@@ -100,10 +131,29 @@ function DashboardView(props: Props) {
     queues,
     listQueueStatsAsync,
     dailyStatsKey,
+    getQueueCoverageAsync,
+    coverage,
+    getQueueRankingAsync,
+    ranking,
   } = props;
   const classes = useStyles();
+  const [groupBy, setGroupBy] = useState<StatsGroupBy>("day");
+  const [exportMenuAnchor, setExportMenuAnchor] =
+    useState<null | HTMLElement>(null);
+  const numDays = numDaysByKey[dailyStatsKey];
+  const [rankBy, setRankBy] = useState<QueueRankingSortBy>("growth");
+  const [rankWindow, setRankWindow] = useState(3600);
 
   usePolling(listQueuesAsync, pollInterval);
+  usePolling(getQueueCoverageAsync, pollInterval);
+
+  const fetchRanking = useMemo(() => {
+    return () => {
+      getQueueRankingAsync(rankBy, rankWindow);
+    };
+  }, [getQueueRankingAsync, rankBy, rankWindow]);
+
+  usePolling(fetchRanking, pollInterval);
 
   // Refetch queue stats if a queue is added or deleted.
   const qnames = queues
@@ -112,8 +162,8 @@ function DashboardView(props: Props) {
     .join(",");
 
   useEffect(() => {
-    listQueueStatsAsync();
-  }, [listQueueStatsAsync, qnames]);
+    listQueueStatsAsync(numDays, groupBy);
+  }, [listQueueStatsAsync, qnames, numDays, groupBy]);
 
   const processedStats = queues.map((q) => ({
     queue: q.queue,
@@ -133,6 +183,15 @@ function DashboardView(props: Props) {
             </Alert>
           </Grid>
         )}
+        {coverage && coverage.warnings.length > 0 && (
+          <Grid item xs={12}>
+            {coverage.warnings.map((w) => (
+              <Alert key={w.queue} severity="warning">
+                {w.message}
+              </Alert>
+            ))}
+          </Grid>
+        )}
         <Grid item xs={6}>
           <Paper className={classes.paper} variant="outlined">
             <div className={classes.chartHeader}>
@@ -186,7 +245,8 @@ function DashboardView(props: Props) {
                   title={
                     <div>
                       <div className={classes.tooltipSection}>
-                        Total number of tasks processed in a given day (UTC)
+                        Total number of tasks processed in a given day, week
+                        or month (UTC)
                       </div>
                       <div className={classes.tooltipSection}>
                         <strong>Succeeded</strong>: number of tasks successfully
@@ -202,7 +262,18 @@ function DashboardView(props: Props) {
                   <InfoIcon fontSize="small" className={classes.infoIcon} />
                 </Tooltip>
               </div>
-              <div>
+              <div className={classes.chartHeaderActions}>
+                {dailyStatsKey !== "today" && (
+                  <SplitButton
+                    options={[
+                      { label: "Daily", key: "day" },
+                      { label: "Weekly", key: "week" },
+                      { label: "Monthly", key: "month" },
+                    ]}
+                    initialSelectedKey={groupBy}
+                    onSelect={(key) => setGroupBy(key as StatsGroupBy)}
+                  />
+                )}
                 <SplitButton
                   options={[
                     { label: "Today", key: "today" },
@@ -215,6 +286,33 @@ function DashboardView(props: Props) {
                     props.dailyStatsKeyChange(key as DailyStatsKey)
                   }
                 />
+                <Tooltip title="Download stats">
+                  <IconButton
+                    size="small"
+                    aria-label="download stats"
+                    onClick={(e) => setExportMenuAnchor(e.currentTarget)}
+                  >
+                    <GetAppIcon />
+                  </IconButton>
+                </Tooltip>
+                <Menu
+                  anchorEl={exportMenuAnchor}
+                  keepMounted
+                  open={Boolean(exportMenuAnchor)}
+                  onClose={() => setExportMenuAnchor(null)}
+                >
+                  {(["csv", "json"] as const).map((format) => (
+                    <MenuItem
+                      key={format}
+                      component="a"
+                      href={queueStatsExportUrl(numDays, groupBy, format)}
+                      download
+                      onClick={() => setExportMenuAnchor(null)}
+                    >
+                      Download {format.toUpperCase()}
+                    </MenuItem>
+                  ))}
+                </Menu>
               </div>
             </div>
             <div className={classes.chartContainer}>
@@ -245,6 +343,91 @@ function DashboardView(props: Props) {
             />
           </Paper>
         </Grid>
+
+        {ranking && ranking.queues.length > 0 && (
+          <Grid item xs={12} className={classes.tableContainer}>
+            <Paper className={classes.paper} variant="outlined">
+              <div className={classes.chartHeader}>
+                <div className={classes.chartHeaderTitle}>
+                  <Typography variant="h6">Top Queues</Typography>
+                  <Tooltip
+                    title={
+                      <div>
+                        <div className={classes.tooltipSection}>
+                          Queues ranked by growth of the queue size, latency,
+                          ratio of failed tasks or memory usage, with the
+                          change over the period in parentheses
+                        </div>
+                        <div>
+                          {ranking.source === "prometheus"
+                            ? "History is taken from Prometheus"
+                            : ranking.source === "samples"
+                            ? "History is taken from samples kept in memory " +
+                              "since asynqmon started"
+                            : "No history is available; set " +
+                              "--queue-sample-interval to rank by change"}
+                        </div>
+                      </div>
+                    }
+                  >
+                    <InfoIcon fontSize="small" className={classes.infoIcon} />
+                  </Tooltip>
+                </div>
+                <div className={classes.chartHeaderActions}>
+                  <SplitButton
+                    options={[
+                      { label: "Growth", key: "growth" },
+                      { label: "Latency", key: "latency" },
+                      { label: "Failure Rate", key: "failure_rate" },
+                      { label: "Memory", key: "memory" },
+                    ]}
+                    initialSelectedKey={rankBy}
+                    onSelect={(key) => setRankBy(key as QueueRankingSortBy)}
+                  />
+                  <SplitButton
+                    options={[
+                      { label: "Last 15m", key: "900" },
+                      { label: "Last 1h", key: "3600" },
+                      { label: "Last 6h", key: "21600" },
+                      { label: "Last 24h", key: "86400" },
+                    ]}
+                    initialSelectedKey={String(rankWindow)}
+                    onSelect={(key) => setRankWindow(Number(key))}
+                  />
+                </div>
+              </div>
+              <QueueRankingTable queues={ranking.queues} sortBy={rankBy} />
+            </Paper>
+          </Grid>
+        )}
+
+        {coverage && coverage.queues.length > 0 && (
+          <Grid item xs={12} className={classes.tableContainer}>
+            <Paper className={classes.paper} variant="outlined">
+              <div className={classes.chartHeaderTitle}>
+                <Typography variant="h6">Consumer Coverage</Typography>
+                <Tooltip
+                  title={
+                    <div>
+                      <div className={classes.tooltipSection}>
+                        Servers listening on each queue, and the number of
+                        workers the queue is expected to get given the queue
+                        priorities of the servers
+                      </div>
+                      <div>
+                        With strict priority, the queues of the highest
+                        priority with pending tasks get all the workers
+                      </div>
+                    </div>
+                  }
+                >
+                  <InfoIcon fontSize="small" className={classes.infoIcon} />
+                </Tooltip>
+              </div>
+              <QueueCoverageTable coverage={coverage} />
+            </Paper>
+          </Grid>
+        )}
       </Grid>
     </Container>
   );
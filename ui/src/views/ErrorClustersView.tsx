@@ -0,0 +1,236 @@
+import React, { useMemo } from "react";
+import { connect, ConnectedProps } from "react-redux";
+import { Link as RouterLink, useParams } from "react-router-dom";
+import Container from "@material-ui/core/Container";
+import { makeStyles } from "@material-ui/core/styles";
+import Grid from "@material-ui/core/Grid";
+import Typography from "@material-ui/core/Typography";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Paper from "@material-ui/core/Paper";
+import Button from "@material-ui/core/Button";
+import Link from "@material-ui/core/Link";
+import Alert from "@material-ui/lab/Alert";
+import AlertTitle from "@material-ui/lab/AlertTitle";
+import ArrowBackIcon from "@material-ui/icons/ArrowBack";
+import {
+  archiveErrorClusterAsync,
+  deleteErrorClusterAsync,
+  listErrorClustersAsync,
+  runErrorClusterAsync,
+} from "../actions/errorClustersActions";
+import { ErrorClusterTaskState } from "../api";
+import { usePolling } from "../hooks";
+import {
+  ErrorClustersRouteParams,
+  queueDetailsPath,
+  taskDetailsPath,
+} from "../paths";
+import { AppState } from "../store";
+import { timeAgo } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  container: {
+    paddingTop: theme.spacing(4),
+    paddingBottom: theme.spacing(4),
+  },
+  table: {
+    minWidth: 650,
+  },
+  pattern: {
+    fontFamily: "monospace",
+    wordBreak: "break-word",
+  },
+  example: {
+    display: "block",
+    fontFamily: "monospace",
+    fontSize: "0.8rem",
+  },
+}));
+
+function mapStateToProps(state: AppState) {
+  return {
+    loading: state.errorClusters.loading,
+    error: state.errorClusters.error,
+    clusters: state.errorClusters.clusters,
+    total: state.errorClusters.total,
+    scanned: state.errorClusters.scanned,
+    pollInterval: state.settings.pollInterval,
+  };
+}
+
+const connector = connect(mapStateToProps, {
+  listErrorClustersAsync,
+  runErrorClusterAsync,
+  archiveErrorClusterAsync,
+  deleteErrorClusterAsync,
+});
+
+type Props = ConnectedProps<typeof connector>;
+
+function ErrorClustersView(props: Props) {
+  const classes = useStyles();
+  const { qname, state: stateParam } = useParams<ErrorClustersRouteParams>();
+  const state: ErrorClusterTaskState =
+    stateParam === "archived" ? "archived" : "retry";
+  const { listErrorClustersAsync, pollInterval } = props;
+
+  const fetchClusters = useMemo(() => {
+    return () => {
+      listErrorClustersAsync(qname, state);
+    };
+  }, [qname, state, listErrorClustersAsync]);
+
+  usePolling(fetchClusters, pollInterval);
+
+  const handleRun = async (id: string) => {
+    await props.runErrorClusterAsync(qname, state, id);
+    fetchClusters();
+  };
+  const handleArchive = async (id: string) => {
+    await props.archiveErrorClusterAsync(qname, id);
+    fetchClusters();
+  };
+  const handleDelete = async (id: string) => {
+    await props.deleteErrorClusterAsync(qname, state, id);
+    fetchClusters();
+  };
+
+  return (
+    <Container maxWidth="lg" className={classes.container}>
+      <Grid container spacing={3}>
+        <Grid item xs={12}>
+          <Button
+            startIcon={<ArrowBackIcon />}
+            component={RouterLink}
+            to={queueDetailsPath(qname, state)}
+          >
+            Back to {state} tasks
+          </Button>
+        </Grid>
+        <Grid item xs={12}>
+          <Typography variant="h5" color="textPrimary">
+            Error Clusters
+          </Typography>
+          <Typography color="textSecondary">
+            {state === "retry" ? "Retry" : "Archived"} tasks in queue{" "}
+            {qname} grouped by error message, with numbers, UUIDs and quoted
+            values masked. Scanned {props.scanned} of {props.total} tasks.
+          </Typography>
+        </Grid>
+        {props.error !== "" && (
+          <Grid item xs={12}>
+            <Alert severity="error">
+              <AlertTitle>Error</AlertTitle>
+              Could not retrieve error clusters —{" "}
+              <strong>{props.error}</strong>
+            </Alert>
+          </Grid>
+        )}
+        <Grid item xs={12}>
+          <TableContainer component={Paper} variant="outlined">
+            <Table
+              className={classes.table}
+              size="small"
+              aria-label="error clusters table"
+            >
+              <TableHead>
+                <TableRow>
+                  <TableCell>Error</TableCell>
+                  <TableCell align="right">Tasks</TableCell>
+                  <TableCell>Task Types</TableCell>
+                  <TableCell>First Failed</TableCell>
+                  <TableCell>Last Failed</TableCell>
+                  <TableCell>Examples</TableCell>
+                  <TableCell>Notes</TableCell>
+                  {!window.READ_ONLY && <TableCell>Actions</TableCell>}
+                </TableRow>
+              </TableHead>
+              <TableBody>
+                {props.clusters.map((c) => (
+                  <TableRow key={c.id}>
+                    <TableCell
+                      className={classes.pattern}
+                      title={c.example_message}
+                    >
+                      {c.pattern || "(empty error message)"}
+                    </TableCell>
+                    <TableCell align="right">{c.count}</TableCell>
+                    <TableCell>
+                      {c.task_types
+                        .map((t) => `${t.type} (${t.count})`)
+                        .join(", ")}
+                    </TableCell>
+                    <TableCell>
+                      {c.first_failed_at ? timeAgo(c.first_failed_at) : "-"}
+                    </TableCell>
+                    <TableCell>
+                      {c.last_failed_at ? timeAgo(c.last_failed_at) : "-"}
+                    </TableCell>
+                    <TableCell>
+                      {c.example_task_ids.map((id) => (
+                        <Link
+                          key={id}
+                          className={classes.example}
+                          component={RouterLink}
+                          to={taskDetailsPath(qname, id)}
+                        >
+                          {id}
+                        </Link>
+                      ))}
+                    </TableCell>
+                    <TableCell
+                      title={c.notes
+                        .map((n) => `${n.author || "anonymous"}: ${n.text}`)
+                        .join("\n")}
+                    >
+                      {c.notes.length > 0
+                        ? c.notes[c.notes.length - 1].text
+                        : "-"}
+                    </TableCell>
+                    {!window.READ_ONLY && (
+                      <TableCell>
+                        <Button size="small" onClick={() => handleRun(c.id)}>
+                          Run
+                        </Button>
+                        {state === "retry" && (
+                          <Button
+                            size="small"
+                            onClick={() => handleArchive(c.id)}
+                          >
+                            Archive
+                          </Button>
+                        )}
+                        <Button
+                          size="small"
+                          onClick={() => handleDelete(c.id)}
+                        >
+                          Delete
+                        </Button>
+                      </TableCell>
+                    )}
+                  </TableRow>
+                ))}
+                {props.clusters.length === 0 && !props.loading && (
+                  <TableRow>
+                    <TableCell colSpan={8}>
+                      <Typography color="textSecondary">
+                        No {state} tasks
+                      </Typography>
+                    </TableCell>
+                  </TableRow>
+                )}
+              </TableBody>
+            </Table>
+          </TableContainer>
+        </Grid>
+      </Grid>
+    </Container>
+  );
+}
+
+export default connector(ErrorClustersView);
@@ -6,18 +6,30 @@ import { makeStyles } from "@material-ui/core/styles";
 import Container from "@material-ui/core/Container";
 import Grid from "@material-ui/core/Grid";
 import Typography from "@material-ui/core/Typography";
+import Button from "@material-ui/core/Button";
+import IconButton from "@material-ui/core/IconButton";
+import List from "@material-ui/core/List";
+import ListItem from "@material-ui/core/ListItem";
+import ListItemSecondaryAction from "@material-ui/core/ListItemSecondaryAction";
+import ListItemText from "@material-ui/core/ListItemText";
+import DeleteIcon from "@material-ui/icons/Delete";
 import WarningIcon from "@material-ui/icons/Warning";
 import InfoIcon from "@material-ui/icons/Info";
 import prettyBytes from "pretty-bytes";
 import { getMetricsAsync } from "../actions/metricsActions";
 import { listQueuesAsync } from "../actions/queuesActions";
+import {
+  deleteAnnotationAsync,
+  listAnnotationsAsync,
+} from "../actions/annotationsActions";
 import { AppState } from "../store";
 import QueueMetricsChart from "../components/QueueMetricsChart";
+import CreateAnnotationDialog from "../components/CreateAnnotationDialog";
 import Tooltip from "../components/Tooltip";
 import { currentUnixtime } from "../utils";
 import MetricsFetchControls from "../components/MetricsFetchControls";
 import { useQuery } from "../hooks";
-import { PrometheusMetricsResponse } from "../api";
+import { Annotation, PrometheusMetricsResponse } from "../api";
 
 const useStyles = makeStyles((theme) => ({
   container: {
@@ -55,6 +67,11 @@ const useStyles = makeStyles((theme) => ({
     color: "#ff6700",
     marginRight: 6,
   },
+  annotationsHeader: {
+    display: "flex",
+    alignItems: "center",
+    justifyContent: "space-between",
+  },
 }));
 
 function mapStateToProps(state: AppState) {
@@ -64,12 +81,15 @@ function mapStateToProps(state: AppState) {
     data: state.metrics.data,
     pollInterval: state.settings.pollInterval,
     queues: state.queues.data.map((q) => q.name),
+    annotations: state.annotations.data,
   };
 }
 
 const connector = connect(mapStateToProps, {
   getMetricsAsync,
   listQueuesAsync,
+  listAnnotationsAsync,
+  deleteAnnotationAsync,
 });
 type Props = ConnectedProps<typeof connector>;
 
@@ -87,11 +107,19 @@ function MetricsView(props: Props) {
   const durationStr = query.get(DURATION_URL_PARAM_KEY);
   const duration = durationStr ? parseFloat(durationStr) : 60 * 60; // default to 1h
 
-  const { pollInterval, getMetricsAsync, listQueuesAsync, data } = props;
+  const {
+    pollInterval,
+    getMetricsAsync,
+    listQueuesAsync,
+    listAnnotationsAsync,
+    data,
+    annotations,
+  } = props;
 
   const [endTimeSec, setEndTimeSec] = React.useState(endTime);
   const [durationSec, setDurationSec] = React.useState(duration);
   const [selectedQueues, setSelectedQueues] = React.useState<string[]>([]);
+  const [annotationDialogOpen, setAnnotationDialogOpen] = React.useState(false);
 
   const handleEndTimeChange = (endTime: number, isEndTimeFixed: boolean) => {
     const urlQuery = isEndTimeFixed
@@ -153,6 +181,10 @@ function MetricsView(props: Props) {
     getMetricsAsync(endTimeSec, durationSec, selectedQueues);
   }, [pollInterval, getMetricsAsync, durationSec, endTimeSec, selectedQueues]);
 
+  React.useEffect(() => {
+    listAnnotationsAsync(endTimeSec, durationSec, selectedQueues);
+  }, [listAnnotationsAsync, durationSec, endTimeSec, selectedQueues]);
+
   return (
     <Container maxWidth="lg" className={classes.container}>
       <div className={classes.controlsContainer}>
@@ -179,6 +211,7 @@ function MetricsView(props: Props) {
               metrics={data.tasks_processed_per_second}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
             />
           </Grid>
         )}
@@ -190,6 +223,7 @@ function MetricsView(props: Props) {
               metrics={data.tasks_failed_per_second}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
             />
           </Grid>
         )}
@@ -201,6 +235,7 @@ function MetricsView(props: Props) {
               metrics={data.error_rate}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
             />
           </Grid>
         )}
@@ -212,6 +247,7 @@ function MetricsView(props: Props) {
               metrics={data.queue_size}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
             />
           </Grid>
         )}
@@ -223,6 +259,7 @@ function MetricsView(props: Props) {
               metrics={data.queue_latency_seconds}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
               yAxisTickFormatter={(val: number) => val + "s"}
             />
           </Grid>
@@ -235,6 +272,7 @@ function MetricsView(props: Props) {
               metrics={data.queue_memory_usage_approx_bytes}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
               yAxisTickFormatter={(val: number) => {
                 try {
                   return prettyBytes(val);
@@ -253,6 +291,7 @@ function MetricsView(props: Props) {
               metrics={data.pending_tasks_by_queue}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
             />
           </Grid>
         )}
@@ -264,6 +303,7 @@ function MetricsView(props: Props) {
               metrics={data.retry_tasks_by_queue}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
             />
           </Grid>
         )}
@@ -275,16 +315,72 @@ function MetricsView(props: Props) {
               metrics={data.archived_tasks_by_queue}
               endTime={endTimeSec}
               startTime={endTimeSec - durationSec}
+              annotations={annotations}
             />
           </Grid>
         )}
+        <Grid item xs={12}>
+          <div className={classes.annotationsHeader}>
+            <Typography color="textPrimary">Annotations</Typography>
+            {!window.READ_ONLY && (
+              <Button
+                size="small"
+                color="primary"
+                onClick={() => setAnnotationDialogOpen(true)}
+              >
+                Add Annotation
+              </Button>
+            )}
+          </div>
+          {annotations.length === 0 ? (
+            <Typography color="textSecondary" variant="body2">
+              No annotations in this time range.
+            </Typography>
+          ) : (
+            <List dense>
+              {annotations.map((a) => (
+                <ListItem key={a.id} disableGutters>
+                  <ListItemText
+                    primary={a.text}
+                    secondary={annotationSummary(a)}
+                  />
+                  {!window.READ_ONLY && (
+                    <ListItemSecondaryAction>
+                      <IconButton
+                        edge="end"
+                        size="small"
+                        aria-label="delete annotation"
+                        onClick={() => props.deleteAnnotationAsync(a.id)}
+                      >
+                        <DeleteIcon fontSize="small" />
+                      </IconButton>
+                    </ListItemSecondaryAction>
+                  )}
+                </ListItem>
+              ))}
+            </List>
+          )}
+        </Grid>
       </Grid>
+      <CreateAnnotationDialog
+        open={annotationDialogOpen}
+        onClose={() => setAnnotationDialogOpen(false)}
+        queues={selectedQueues}
+      />
     </Container>
   );
 }
 
 export default connector(MetricsView);
 
+function annotationSummary(a: Annotation): string {
+  const start = new Date(a.start_time).toLocaleString();
+  const end = new Date(a.end_time).toLocaleString();
+  const period = start === end ? start : `${start} - ${end}`;
+  const queues = a.queues.length > 0 ? a.queues.join(", ") : "all queues";
+  return `${period} · ${queues} · ${a.author || "anonymous"}`;
+}
+
 /******** Helper components ********/
 
 interface ChartRowProps {
@@ -294,6 +390,7 @@ interface ChartRowProps {
   endTime: number;
   startTime: number;
   yAxisTickFormatter?: (val: number) => string;
+  annotations?: Annotation[];
 }
 
 function ChartRow(props: ChartRowProps) {
@@ -323,6 +420,7 @@ function ChartRow(props: ChartRowProps) {
         endTime={props.endTime}
         startTime={props.startTime}
         yAxisTickFormatter={props.yAxisTickFormatter}
+        annotations={props.annotations}
       />
     </>
   );
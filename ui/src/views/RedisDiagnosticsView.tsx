@@ -0,0 +1,339 @@
+import React from "react";
+import { connect, ConnectedProps } from "react-redux";
+import Container from "@material-ui/core/Container";
+import { makeStyles } from "@material-ui/core/styles";
+import Grid from "@material-ui/core/Grid";
+import Typography from "@material-ui/core/Typography";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Link from "@material-ui/core/Link";
+import Alert from "@material-ui/lab/Alert";
+import AlertTitle from "@material-ui/lab/AlertTitle";
+import { getRedisDiagnosticsAsync } from "../actions/redisDiagnosticsActions";
+import { usePolling } from "../hooks";
+import { AppState } from "../store";
+import { RedisClientGroup, RedisNode } from "../api";
+import { durationFromSeconds, stringifyDuration, timeAgo } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  container: {
+    paddingTop: theme.spacing(4),
+    paddingBottom: theme.spacing(4),
+  },
+  table: {
+    minWidth: 650,
+  },
+  args: {
+    fontFamily: "monospace",
+    wordBreak: "break-all",
+  },
+}));
+
+function mapStateToProps(state: AppState) {
+  return {
+    loading: state.redisDiagnostics.loading,
+    error: state.redisDiagnostics.error,
+    slowLog: state.redisDiagnostics.slowLog,
+    latency: state.redisDiagnostics.latency,
+    clients: state.redisDiagnostics.clients,
+    commandStats: state.redisDiagnostics.commandStats,
+    pollInterval: state.settings.pollInterval,
+  };
+}
+
+const connector = connect(mapStateToProps, { getRedisDiagnosticsAsync });
+type Props = ConnectedProps<typeof connector>;
+
+// Maximum number of commands to show per node in the command stats table.
+const maxCommandStats = 20;
+
+function RedisDiagnosticsView(props: Props) {
+  const classes = useStyles();
+  const {
+    pollInterval,
+    getRedisDiagnosticsAsync,
+    slowLog,
+    latency,
+    clients,
+    commandStats,
+  } = props;
+  usePolling(getRedisDiagnosticsAsync, pollInterval);
+
+  if (props.error !== "") {
+    return (
+      <Container maxWidth="lg" className={classes.container}>
+        <Alert severity="error">
+          <AlertTitle>Error</AlertTitle>
+          Could not retrieve redis diagnostics —{" "}
+          <strong>{props.error}</strong>
+        </Alert>
+      </Container>
+    );
+  }
+
+  return (
+    <Container maxWidth="lg" className={classes.container}>
+      <Grid container spacing={3}>
+        <Grid item xs={12}>
+          <Typography variant="h5" color="textPrimary">
+            Redis Diagnostics
+          </Typography>
+        </Grid>
+        <SectionTitle
+          href="https://redis.io/commands/slowlog-get"
+          title="SLOWLOG"
+        />
+        {slowLog?.nodes.map((node) => (
+          <Grid item xs={12} key={node.address}>
+            <NodeTitle node={node} />
+            {node.error ? null : node.entries.length === 0 ? (
+              <Typography color="textSecondary">No slow log entries</Typography>
+            ) : (
+              <TableContainer>
+                <Table
+                  className={classes.table}
+                  size="small"
+                  aria-label="slow log table"
+                >
+                  <TableHead>
+                    <TableRow>
+                      <TableCell>Time</TableCell>
+                      <TableCell align="right">Duration</TableCell>
+                      <TableCell>Command</TableCell>
+                      <TableCell>Client</TableCell>
+                    </TableRow>
+                  </TableHead>
+                  <TableBody>
+                    {node.entries.map((e) => (
+                      <TableRow key={e.id}>
+                        <TableCell>{timeAgo(e.time)}</TableCell>
+                        <TableCell align="right">
+                          {formatMicros(e.duration_us)}
+                        </TableCell>
+                        <TableCell className={classes.args}>
+                          {e.args.join(" ")}
+                        </TableCell>
+                        <TableCell>
+                          {e.client_name
+                            ? `${e.client_name} (${e.client_addr})`
+                            : e.client_addr}
+                        </TableCell>
+                      </TableRow>
+                    ))}
+                  </TableBody>
+                </Table>
+              </TableContainer>
+            )}
+          </Grid>
+        ))}
+        <SectionTitle
+          href="https://redis.io/commands/latency-latest"
+          title="LATENCY"
+        />
+        {latency?.nodes.map((node) => (
+          <Grid item xs={12} key={node.address}>
+            <NodeTitle node={node} />
+            {node.error ? null : node.events.length === 0 ? (
+              <Typography color="textSecondary">
+                No latency events (latency monitor is disabled if
+                latency-monitor-threshold is 0)
+              </Typography>
+            ) : (
+              <TableContainer>
+                <Table
+                  className={classes.table}
+                  size="small"
+                  aria-label="latency table"
+                >
+                  <TableHead>
+                    <TableRow>
+                      <TableCell>Event</TableCell>
+                      <TableCell>Latest Spike</TableCell>
+                      <TableCell align="right">Latest (ms)</TableCell>
+                      <TableCell align="right">Max (ms)</TableCell>
+                      <TableCell>History (ms)</TableCell>
+                    </TableRow>
+                  </TableHead>
+                  <TableBody>
+                    {node.events.map((e) => (
+                      <TableRow key={e.event}>
+                        <TableCell component="th" scope="row">
+                          {e.event}
+                        </TableCell>
+                        <TableCell>{timeAgo(e.time)}</TableCell>
+                        <TableCell align="right">{e.latency_ms}</TableCell>
+                        <TableCell align="right">{e.max_latency_ms}</TableCell>
+                        <TableCell className={classes.args}>
+                          {e.history.map((s) => s.latency_ms).join(" ")}
+                        </TableCell>
+                      </TableRow>
+                    ))}
+                  </TableBody>
+                </Table>
+              </TableContainer>
+            )}
+          </Grid>
+        ))}
+        <SectionTitle
+          href="https://redis.io/commands/client-list"
+          title="CLIENT LIST"
+        />
+        {clients?.nodes.map((node) => (
+          <React.Fragment key={node.address}>
+            <Grid item xs={12}>
+              <NodeTitle node={node} />
+              {!node.error && (
+                <Typography color="textSecondary">
+                  Total clients: {node.total_clients}
+                </Typography>
+              )}
+            </Grid>
+            {!node.error && (
+              <>
+                <Grid item xs={12} md={6}>
+                  <ClientGroupTable title="Host" groups={node.by_host} />
+                </Grid>
+                <Grid item xs={12} md={6}>
+                  <ClientGroupTable title="Name" groups={node.by_name} />
+                </Grid>
+              </>
+            )}
+          </React.Fragment>
+        ))}
+        <SectionTitle
+          href="https://redis.io/commands/info"
+          title="INFO commandstats"
+        />
+        {commandStats?.nodes.map((node) => (
+          <Grid item xs={12} key={node.address}>
+            <NodeTitle node={node} />
+            {!node.error && (
+              <TableContainer>
+                <Table
+                  className={classes.table}
+                  size="small"
+                  aria-label="command stats table"
+                >
+                  <TableHead>
+                    <TableRow>
+                      <TableCell>Command</TableCell>
+                      <TableCell align="right">Calls</TableCell>
+                      <TableCell align="right">Total Time</TableCell>
+                      <TableCell align="right">Time per Call</TableCell>
+                      <TableCell align="right">Rejected</TableCell>
+                      <TableCell align="right">Failed</TableCell>
+                    </TableRow>
+                  </TableHead>
+                  <TableBody>
+                    {node.commands.slice(0, maxCommandStats).map((c) => (
+                      <TableRow key={c.command}>
+                        <TableCell component="th" scope="row">
+                          {c.command}
+                        </TableCell>
+                        <TableCell align="right">{c.calls}</TableCell>
+                        <TableCell align="right">
+                          {formatMicros(c.usec)}
+                        </TableCell>
+                        <TableCell align="right">
+                          {formatMicros(c.usec_per_call)}
+                        </TableCell>
+                        <TableCell align="right">{c.rejected_calls}</TableCell>
+                        <TableCell align="right">{c.failed_calls}</TableCell>
+                      </TableRow>
+                    ))}
+                  </TableBody>
+                </Table>
+              </TableContainer>
+            )}
+          </Grid>
+        ))}
+      </Grid>
+    </Container>
+  );
+}
+
+function SectionTitle(props: { href: string; title: string }) {
+  return (
+    <Grid item xs={12}>
+      <Typography variant="h6" color="textSecondary">
+        <Link href={props.href} target="_">
+          {props.title}
+        </Link>
+      </Typography>
+    </Grid>
+  );
+}
+
+function NodeTitle(props: { node: RedisNode }) {
+  const { node } = props;
+  return (
+    <>
+      <Typography variant="subtitle1" color="textPrimary">
+        {node.role ? `${node.address} (${node.role})` : node.address}
+      </Typography>
+      {node.error && (
+        <Typography color="error">
+          Failed to query node: {node.error}
+        </Typography>
+      )}
+    </>
+  );
+}
+
+function ClientGroupTable(props: {
+  title: string;
+  groups: RedisClientGroup[];
+}) {
+  const classes = useStyles();
+  return (
+    <TableContainer>
+      <Table size="small" aria-label="client group table">
+        <TableHead>
+          <TableRow>
+            <TableCell>{props.title}</TableCell>
+            <TableCell align="right">Clients</TableCell>
+            <TableCell align="right">Blocked</TableCell>
+            <TableCell align="right">Max Idle</TableCell>
+            <TableCell>Last Commands</TableCell>
+          </TableRow>
+        </TableHead>
+        <TableBody>
+          {props.groups.map((g) => (
+            <TableRow key={g.key}>
+              <TableCell component="th" scope="row">
+                {g.key || "(no name)"}
+              </TableCell>
+              <TableCell align="right">{g.clients}</TableCell>
+              <TableCell align="right">{g.blocked}</TableCell>
+              <TableCell align="right">
+                {stringifyDuration(durationFromSeconds(g.max_idle_seconds))}
+              </TableCell>
+              <TableCell className={classes.args}>
+                {Object.entries(g.last_commands)
+                  .sort((a, b) => b[1] - a[1])
+                  .map(([cmd, n]) => `${cmd}(${n})`)
+                  .join(" ")}
+              </TableCell>
+            </TableRow>
+          ))}
+        </TableBody>
+      </Table>
+    </TableContainer>
+  );
+}
+
+function formatMicros(usec: number): string {
+  if (usec >= 1000000) {
+    return `${(usec / 1000000).toFixed(2)}s`;
+  }
+  if (usec >= 1000) {
+    return `${(usec / 1000).toFixed(2)}ms`;
+  }
+  return `${usec.toFixed(usec % 1 === 0 ? 0 : 2)}µs`;
+}
+
+export default connector(RedisDiagnosticsView);
@@ -1,5 +1,6 @@
 import React from "react";
 import { connect, ConnectedProps } from "react-redux";
+import { Link as RouterLink } from "react-router-dom";
 import Container from "@material-ui/core/Container";
 import { makeStyles } from "@material-ui/core/styles";
 import Grid from "@material-ui/core/Grid";
@@ -9,13 +10,20 @@ import CardContent from "@material-ui/core/CardContent";
 import Alert from "@material-ui/lab/Alert";
 import AlertTitle from "@material-ui/lab/AlertTitle";
 import SyntaxHighlighter from "../components/SyntaxHighlighter";
-import { getRedisInfoAsync } from "../actions/redisInfoActions";
+import {
+  getRedisInfoAsync,
+  getRedisInfoHistoryAsync,
+} from "../actions/redisInfoActions";
 import { usePolling } from "../hooks";
 import { AppState } from "../store";
 import { timeAgoUnix } from "../utils";
-import { RedisInfo } from "../api";
+import { RedisInfo, SentinelInfo } from "../api";
 import QueueLocationTable from "../components/QueueLocationTable";
+import ClusterNodesTable from "../components/ClusterNodesTable";
+import SentinelInstancesTable from "../components/SentinelInstancesTable";
+import RedisInfoHistoryCharts from "../components/RedisInfoHistoryCharts";
 import Link from "@material-ui/core/Link";
+import { paths } from "../paths";
 
 const useStyles = makeStyles((theme) => ({
   container: {
@@ -34,12 +42,19 @@ function mapStateToProps(state: AppState) {
     redisClusterEnabled: state.redis.cluster,
     redisClusterNodesRaw: state.redis.rawClusterNodes,
     queueLocations: state.redis.queueLocations,
+    clusterNodes: state.redis.clusterNodes,
+    warnings: state.redis.warnings,
+    sentinel: state.redis.sentinel,
+    history: state.redis.history,
     pollInterval: state.settings.pollInterval,
     themePreference: state.settings.themePreference,
   };
 }
 
-const connector = connect(mapStateToProps, { getRedisInfoAsync });
+const connector = connect(mapStateToProps, {
+  getRedisInfoAsync,
+  getRedisInfoHistoryAsync,
+});
 type Props = ConnectedProps<typeof connector>;
 
 function RedisInfoView(props: Props) {
@@ -52,8 +67,14 @@ function RedisInfoView(props: Props) {
     redisClusterEnabled,
     redisClusterNodesRaw,
     queueLocations,
+    clusterNodes,
+    warnings,
+    sentinel,
+    history,
+    getRedisInfoHistoryAsync,
   } = props;
   usePolling(getRedisInfoAsync, pollInterval);
+  usePolling(getRedisInfoHistoryAsync, pollInterval);
 
   // Metrics to show
   // - Used Memory
@@ -77,7 +98,43 @@ function RedisInfoView(props: Props) {
                   Connected to: {props.redisAddress}
                 </Typography>
               )}
+              <Link component={RouterLink} to={paths().REDIS_DIAGNOSTICS}>
+                Slow log, latency, clients and command stats
+              </Link>
             </Grid>
+            {warnings && warnings.length > 0 && (
+              <Grid item xs={12}>
+                {warnings.map((w) => (
+                  <Alert severity="warning" key={w}>
+                    {w}
+                  </Alert>
+                ))}
+              </Grid>
+            )}
+            {clusterNodes && clusterNodes.length > 0 && (
+              <Grid item xs={12}>
+                <Typography variant="h6" color="textSecondary">
+                  Cluster Nodes
+                </Typography>
+                <ClusterNodesTable nodes={clusterNodes} />
+              </Grid>
+            )}
+            {sentinel && <SentinelSection sentinel={sentinel} />}
+            {history && history.enabled && history.samples.length > 1 && (
+              <>
+                <Grid item xs={12}>
+                  <Typography variant="h6" color="textSecondary">
+                    History
+                  </Typography>
+                  {history.last_error && (
+                    <Alert severity="warning">
+                      Could not sample redis INFO: {history.last_error}
+                    </Alert>
+                  )}
+                </Grid>
+                <RedisInfoHistoryCharts samples={history.samples} />
+              </>
+            )}
             {queueLocations && queueLocations.length > 0 && (
               <Grid item xs={12}>
                 <Typography variant="h6" color="textSecondary">
@@ -146,6 +203,87 @@ function RedisInfoView(props: Props) {
   );
 }
 
+function SentinelSection(props: { sentinel: SentinelInfo }) {
+  const { sentinel } = props;
+  if (sentinel.error) {
+    return (
+      <Grid item xs={12}>
+        <Alert severity="error">
+          <AlertTitle>Could not query sentinels</AlertTitle>
+          {sentinel.error}
+        </Alert>
+      </Grid>
+    );
+  }
+  return (
+    <>
+      <Grid item xs={12}>
+        <Typography variant="h6" color="textSecondary">
+          Sentinel
+        </Typography>
+        <Typography variant="subtitle1" color="textSecondary">
+          Master "{sentinel.master_name}" as seen by sentinel{" "}
+          {sentinel.sentinel_address}
+        </Typography>
+      </Grid>
+      {sentinel.failover_in_progress && (
+        <Grid item xs={12}>
+          <Alert severity="warning">
+            Failover in progress ({sentinel.failover_state})
+          </Alert>
+        </Grid>
+      )}
+      {!sentinel.quorum_ok && (
+        <Grid item xs={12}>
+          <Alert severity="error">
+            Sentinels cannot authorize a failover: {sentinel.quorum_status}
+          </Alert>
+        </Grid>
+      )}
+      <Grid item xs={3}>
+        <MetricCard title="Quorum" content={`${sentinel.quorum}`} />
+      </Grid>
+      <Grid item xs={3}>
+        <MetricCard
+          title="Replicas"
+          content={`${sentinel.replicas?.length || 0}`}
+        />
+      </Grid>
+      <Grid item xs={3}>
+        <MetricCard
+          title="Sentinels"
+          content={`${(sentinel.sentinels?.length || 0) + 1}`}
+        />
+      </Grid>
+      <Grid item xs={3} />
+      {sentinel.master && (
+        <Grid item xs={12}>
+          <Typography variant="subtitle1" color="textPrimary">
+            Master
+          </Typography>
+          <SentinelInstancesTable instances={[sentinel.master]} />
+        </Grid>
+      )}
+      {sentinel.replicas && sentinel.replicas.length > 0 && (
+        <Grid item xs={12}>
+          <Typography variant="subtitle1" color="textPrimary">
+            Replicas
+          </Typography>
+          <SentinelInstancesTable instances={sentinel.replicas} replicas />
+        </Grid>
+      )}
+      {sentinel.sentinels && sentinel.sentinels.length > 0 && (
+        <Grid item xs={12}>
+          <Typography variant="subtitle1" color="textPrimary">
+            Other Sentinels
+          </Typography>
+          <SentinelInstancesTable instances={sentinel.sentinels} />
+        </Grid>
+      )}
+    </>
+  );
+}
+
 function RedisMetricCards(props: { redisInfo: RedisInfo }) {
   const { redisInfo } = props;
   return (
@@ -0,0 +1,199 @@
+import React from "react";
+import { connect, ConnectedProps } from "react-redux";
+import Container from "@material-ui/core/Container";
+import { makeStyles } from "@material-ui/core/styles";
+import Grid from "@material-ui/core/Grid";
+import Paper from "@material-ui/core/Paper";
+import Typography from "@material-ui/core/Typography";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Chip from "@material-ui/core/Chip";
+import Alert from "@material-ui/lab/Alert";
+import AlertTitle from "@material-ui/lab/AlertTitle";
+import { listSLOsAsync } from "../actions/slosActions";
+import { SLOBurnRate } from "../api";
+import { usePolling } from "../hooks";
+import { AppState } from "../store";
+import {
+  durationFromSeconds,
+  formatSeconds,
+  stringifyDuration,
+} from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  container: {
+    paddingTop: theme.spacing(4),
+    paddingBottom: theme.spacing(4),
+  },
+  paper: {
+    padding: theme.spacing(2),
+    display: "flex",
+    overflow: "auto",
+    flexDirection: "column",
+  },
+  heading: {
+    paddingLeft: theme.spacing(2),
+    marginBottom: theme.spacing(1),
+  },
+  table: {
+    minWidth: 650,
+  },
+  burning: {
+    color: theme.palette.error.main,
+  },
+}));
+
+function mapStateToProps(state: AppState) {
+  return {
+    loading: state.slos.loading,
+    error: state.slos.error,
+    data: state.slos.data,
+    pollInterval: state.settings.pollInterval,
+  };
+}
+
+const connector = connect(mapStateToProps, { listSLOsAsync });
+
+type Props = ConnectedProps<typeof connector>;
+
+function formatRatio(v: number | null): string {
+  return v === null ? "-" : `${(v * 100).toFixed(2)}%`;
+}
+
+function formatBurnRate(b: SLOBurnRate): string {
+  const rate = b.burn_rate === null ? "-" : b.burn_rate.toFixed(2);
+  return `${formatSeconds(b.period_seconds)}: ${rate}`;
+}
+
+function SLOsView(props: Props) {
+  const { pollInterval, listSLOsAsync, data } = props;
+  const classes = useStyles();
+
+  usePolling(listSLOsAsync, pollInterval);
+
+  return (
+    <Container maxWidth="lg" className={classes.container}>
+      <Grid container spacing={3}>
+        {props.error !== "" && (
+          <Grid item xs={12}>
+            <Alert severity="error">
+              <AlertTitle>Error</AlertTitle>
+              Could not retrieve SLOs — <strong>{props.error}</strong>
+            </Alert>
+          </Grid>
+        )}
+        {data && data.slos.length === 0 && (
+          <Grid item xs={12}>
+            <Alert severity="info">
+              <AlertTitle>Info</AlertTitle>
+              No SLOs are configured. Use the --slos flag (e.g.
+              critical:latency&lt;30s@95%/7d) to define queue SLOs.
+            </Alert>
+          </Grid>
+        )}
+        {data && data.slos.length > 0 && (
+          <Grid item xs={12}>
+            <Paper className={classes.paper} variant="outlined">
+              <Typography variant="h6" className={classes.heading}>
+                Service Level Objectives
+              </Typography>
+              <Typography
+                variant="body2"
+                color="textSecondary"
+                className={classes.heading}
+              >
+                Evaluated from{" "}
+                {data.source === "prometheus"
+                  ? "Prometheus"
+                  : "samples of the queue stats kept in memory"}
+                . A burn rate above 1 exhausts the error budget before the end
+                of the window.
+              </Typography>
+              <TableContainer>
+                <Table
+                  className={classes.table}
+                  size="small"
+                  aria-label="slos table"
+                >
+                  <TableHead>
+                    <TableRow>
+                      <TableCell>Name</TableCell>
+                      <TableCell>Objective</TableCell>
+                      <TableCell align="right">Compliance</TableCell>
+                      <TableCell align="right">Error Budget Left</TableCell>
+                      <TableCell>Burn Rate</TableCell>
+                      <TableCell>Status</TableCell>
+                    </TableRow>
+                  </TableHead>
+                  <TableBody>
+                    {data.slos.map((slo) => (
+                      <TableRow key={slo.name}>
+                        <TableCell component="th" scope="row">
+                          {slo.name}
+                        </TableCell>
+                        <TableCell>
+                          {slo.objective}
+                          {slo.coverage_seconds < slo.window_seconds && (
+                            <Typography variant="caption" display="block">
+                              Data covers the last{" "}
+                              {stringifyDuration(
+                                durationFromSeconds(
+                                  Math.floor(slo.coverage_seconds)
+                                )
+                              )}
+                            </Typography>
+                          )}
+                        </TableCell>
+                        <TableCell align="right">
+                          {formatRatio(slo.compliance)}
+                        </TableCell>
+                        <TableCell align="right">
+                          {formatRatio(slo.error_budget_remaining)}
+                        </TableCell>
+                        <TableCell>
+                          {slo.burn_rates.map((b) => (
+                            <div
+                              key={b.period_seconds}
+                              className={
+                                b.burn_rate !== null && b.burn_rate > 1
+                                  ? classes.burning
+                                  : undefined
+                              }
+                            >
+                              {formatBurnRate(b)}
+                            </div>
+                          ))}
+                        </TableCell>
+                        <TableCell>
+                          {slo.error ? (
+                            <Typography variant="body2" color="error">
+                              {slo.error}
+                            </Typography>
+                          ) : slo.compliance === null ? (
+                            <Chip size="small" label="no data" />
+                          ) : (
+                            <Chip
+                              size="small"
+                              color={slo.met ? "primary" : "secondary"}
+                              label={slo.met ? "met" : "violated"}
+                            />
+                          )}
+                        </TableCell>
+                      </TableRow>
+                    ))}
+                  </TableBody>
+                </Table>
+              </TableContainer>
+            </Paper>
+          </Grid>
+        )}
+      </Grid>
+    </Container>
+  );
+}
+
+export default connector(SLOsView);
@@ -0,0 +1,63 @@
+import React, { useEffect, useState } from "react";
+import { connect, ConnectedProps } from "react-redux";
+import { useHistory, useParams } from "react-router-dom";
+import queryString from "query-string";
+import Container from "@material-ui/core/Container";
+import { makeStyles } from "@material-ui/core/styles";
+import Alert from "@material-ui/lab/Alert";
+import AlertTitle from "@material-ui/lab/AlertTitle";
+import { getView } from "../api";
+import { taskRowsPerPageChange } from "../actions/settingsActions";
+import { queueDetailsPath, SavedViewRouteParams } from "../paths";
+import { toErrorString } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  container: {
+    paddingTop: theme.spacing(4),
+    paddingBottom: theme.spacing(4),
+  },
+}));
+
+const connector = connect(null, { taskRowsPerPageChange });
+
+type Props = ConnectedProps<typeof connector>;
+
+// SavedViewRedirectView resolves the stable URL of a saved view to the task
+// list of the view's queue and state, with the view's filters and sort as
+// query params.
+function SavedViewRedirectView(props: Props) {
+  const classes = useStyles();
+  const history = useHistory();
+  const { viewId } = useParams<SavedViewRouteParams>();
+  const { taskRowsPerPageChange } = props;
+  const [error, setError] = useState("");
+
+  useEffect(() => {
+    getView(viewId)
+      .then((view) => {
+        if (view.page_size > 0) {
+          taskRowsPerPageChange(view.page_size);
+        }
+        const query = queryString.stringify({
+          ...view.filters,
+          sort: view.sort || undefined,
+          status: view.state,
+        });
+        history.replace(`${queueDetailsPath(view.queue)}?${query}`);
+      })
+      .catch((error) => setError(toErrorString(error)));
+  }, [viewId, history, taskRowsPerPageChange]);
+
+  return (
+    <Container maxWidth="lg" className={classes.container}>
+      {error && (
+        <Alert severity="error">
+          <AlertTitle>Error</AlertTitle>
+          Could not open saved view — <strong>{error}</strong>
+        </Alert>
+      )}
+    </Container>
+  );
+}
+
+export default connector(SavedViewRedirectView);
@@ -0,0 +1,160 @@
+import React, { useEffect } from "react";
+import { connect, ConnectedProps } from "react-redux";
+import { Link as RouterLink } from "react-router-dom";
+import Container from "@material-ui/core/Container";
+import { makeStyles } from "@material-ui/core/styles";
+import Grid from "@material-ui/core/Grid";
+import Paper from "@material-ui/core/Paper";
+import Typography from "@material-ui/core/Typography";
+import Table from "@material-ui/core/Table";
+import TableBody from "@material-ui/core/TableBody";
+import TableCell from "@material-ui/core/TableCell";
+import TableContainer from "@material-ui/core/TableContainer";
+import TableHead from "@material-ui/core/TableHead";
+import TableRow from "@material-ui/core/TableRow";
+import Button from "@material-ui/core/Button";
+import Link from "@material-ui/core/Link";
+import Alert from "@material-ui/lab/Alert";
+import AlertTitle from "@material-ui/lab/AlertTitle";
+import { deleteViewAsync, listViewsAsync } from "../actions/savedViewsActions";
+import { SavedView } from "../api";
+import { savedViewPath } from "../paths";
+import { AppState } from "../store";
+import { timeAgo } from "../utils";
+
+const useStyles = makeStyles((theme) => ({
+  container: {
+    paddingTop: theme.spacing(4),
+    paddingBottom: theme.spacing(4),
+  },
+  paper: {
+    padding: theme.spacing(2),
+    display: "flex",
+    overflow: "auto",
+    flexDirection: "column",
+  },
+  heading: {
+    paddingLeft: theme.spacing(2),
+    marginBottom: theme.spacing(1),
+  },
+  table: {
+    minWidth: 650,
+  },
+}));
+
+function mapStateToProps(state: AppState) {
+  return {
+    loading: state.savedViews.loading,
+    error: state.savedViews.error,
+    views: state.savedViews.data,
+  };
+}
+
+const connector = connect(mapStateToProps, {
+  listViewsAsync,
+  deleteViewAsync,
+});
+
+type Props = ConnectedProps<typeof connector>;
+
+function formatFilters(v: SavedView): string {
+  const filters = Object.entries(v.filters).map(([k, val]) => `${k}=${val}`);
+  if (v.sort) {
+    filters.push(`sort=${v.sort}`);
+  }
+  return filters.length > 0 ? filters.join(", ") : "-";
+}
+
+function SavedViewsView(props: Props) {
+  const { listViewsAsync } = props;
+  const classes = useStyles();
+
+  useEffect(() => {
+    listViewsAsync();
+  }, [listViewsAsync]);
+
+  return (
+    <Container maxWidth="lg" className={classes.container}>
+      <Grid container spacing={3}>
+        {props.error !== "" && (
+          <Grid item xs={12}>
+            <Alert severity="error">
+              <AlertTitle>Error</AlertTitle>
+              Could not retrieve saved views — <strong>{props.error}</strong>
+            </Alert>
+          </Grid>
+        )}
+        <Grid item xs={12}>
+          <Paper className={classes.paper} variant="outlined">
+            <Typography variant="h6" className={classes.heading}>
+              Saved Views
+            </Typography>
+            <TableContainer>
+              <Table
+                className={classes.table}
+                size="small"
+                aria-label="saved views table"
+              >
+                <TableHead>
+                  <TableRow>
+                    <TableCell>Name</TableCell>
+                    <TableCell>Queue</TableCell>
+                    <TableCell>State</TableCell>
+                    <TableCell>Filters</TableCell>
+                    <TableCell align="right">Page Size</TableCell>
+                    <TableCell>Owner</TableCell>
+                    <TableCell>Visibility</TableCell>
+                    <TableCell>Updated</TableCell>
+                    {!window.READ_ONLY && <TableCell>Actions</TableCell>}
+                  </TableRow>
+                </TableHead>
+                <TableBody>
+                  {props.views.map((v) => (
+                    <TableRow key={v.id}>
+                      <TableCell component="th" scope="row">
+                        <Link component={RouterLink} to={savedViewPath(v.id)}>
+                          {v.name}
+                        </Link>
+                      </TableCell>
+                      <TableCell>{v.queue}</TableCell>
+                      <TableCell>{v.state}</TableCell>
+                      <TableCell>{formatFilters(v)}</TableCell>
+                      <TableCell align="right">
+                        {v.page_size > 0 ? v.page_size : "-"}
+                      </TableCell>
+                      <TableCell>{v.owner || "-"}</TableCell>
+                      <TableCell>{v.visibility}</TableCell>
+                      <TableCell>{timeAgo(v.updated_at)}</TableCell>
+                      {!window.READ_ONLY && (
+                        <TableCell>
+                          <Button
+                            size="small"
+                            onClick={() => props.deleteViewAsync(v.id)}
+                          >
+                            Delete
+                          </Button>
+                        </TableCell>
+                      )}
+                    </TableRow>
+                  ))}
+                  {props.views.length === 0 && !props.loading && (
+                    <TableRow>
+                      <TableCell colSpan={9}>
+                        <Typography color="textSecondary">
+                          No saved views. Save the task list of a queue from
+                          the queue page.
+                        </Typography>
+                      </TableCell>
+                    </TableRow>
+                  )}
+                </TableBody>
+              </Table>
+            </TableContainer>
+          </Paper>
+        </Grid>
+      </Grid>
+    </Container>
+  );
+}
+
+export default connector(SavedViewsView);
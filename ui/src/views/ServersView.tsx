@@ -5,10 +5,20 @@ import { makeStyles } from "@material-ui/core/styles";
 import Grid from "@material-ui/core/Grid";
 import Paper from "@material-ui/core/Paper";
 import Typography from "@material-ui/core/Typography";
+import Button from "@material-ui/core/Button";
 import Alert from "@material-ui/lab/Alert";
 import AlertTitle from "@material-ui/lab/AlertTitle";
 import ServersTable from "../components/ServersTable";
-import { listServersAsync } from "../actions/serversActions";
+import WorkersTable from "../components/WorkersTable";
+import ServerHistorySection from "../components/ServerHistorySection";
+import {
+  getServerHistoryAsync,
+  listServersAsync,
+} from "../actions/serversActions";
+import {
+  cancelFlaggedWorkersAsync,
+  listWorkersAsync,
+} from "../actions/workersActions";
 import { AppState } from "../store";
 import { usePolling } from "../hooks";
 
@@ -27,6 +37,12 @@ const useStyles = makeStyles((theme) => ({
     paddingLeft: theme.spacing(2),
     marginBottom: theme.spacing(1),
   },
+  workersHeader: {
+    display: "flex",
+    justifyContent: "space-between",
+    alignItems: "center",
+    paddingRight: theme.spacing(2),
+  },
 }));
 
 function mapStateToProps(state: AppState) {
@@ -34,19 +50,48 @@ function mapStateToProps(state: AppState) {
     loading: state.servers.loading,
     error: state.servers.error,
     servers: state.servers.data,
+    history: state.servers.history,
+    workersError: state.workers.error,
+    workers: state.workers.data,
+    longRunningThresholdSeconds: state.workers.longRunningThresholdSeconds,
+    pastDeadline: state.workers.pastDeadline,
+    longRunning: state.workers.longRunning,
+    orphaned: state.workers.orphaned,
     pollInterval: state.settings.pollInterval,
   };
 }
 
-const connector = connect(mapStateToProps, { listServersAsync });
+const connector = connect(mapStateToProps, {
+  listServersAsync,
+  getServerHistoryAsync,
+  listWorkersAsync,
+  cancelFlaggedWorkersAsync,
+});
 
 type Props = ConnectedProps<typeof connector>;
 
 function ServersView(props: Props) {
-  const { pollInterval, listServersAsync } = props;
+  const {
+    pollInterval,
+    listServersAsync,
+    getServerHistoryAsync,
+    listWorkersAsync,
+    history,
+  } = props;
   const classes = useStyles();
 
   usePolling(listServersAsync, pollInterval);
+  usePolling(getServerHistoryAsync, pollInterval);
+  usePolling(listWorkersAsync, pollInterval);
+
+  // Long-running workers are canceled only if requested explicitly.
+  const flagged = props.workers.filter(
+    (w) => w.past_deadline || w.is_orphaned
+  ).length;
+  const handleCancelFlagged = async () => {
+    await props.cancelFlaggedWorkersAsync();
+    listWorkersAsync();
+  };
 
   return (
     <Container maxWidth="lg" className={classes.container}>
@@ -69,6 +114,67 @@ function ServersView(props: Props) {
             </Alert>
           </Grid>
         )}
+        {props.workersError === "" ? (
+          <Grid item xs={12}>
+            <Paper className={classes.paper} variant="outlined">
+              <div className={classes.workersHeader}>
+                <div>
+                  <Typography variant="h6" className={classes.heading}>
+                    Workers
+                  </Typography>
+                  <Typography
+                    variant="body2"
+                    color="textSecondary"
+                    className={classes.heading}
+                  >
+                    {props.pastDeadline} past deadline, {props.longRunning}{" "}
+                    running longer than{" "}
+                    {Math.round(props.longRunningThresholdSeconds / 60)}m,{" "}
+                    {props.orphaned} orphaned
+                  </Typography>
+                </div>
+                {!window.READ_ONLY && (
+                  <Button
+                    variant="outlined"
+                    color="secondary"
+                    size="small"
+                    disabled={flagged === 0}
+                    onClick={handleCancelFlagged}
+                  >
+                    Cancel flagged ({flagged})
+                  </Button>
+                )}
+              </div>
+              <WorkersTable workers={props.workers} />
+            </Paper>
+          </Grid>
+        ) : (
+          <Grid item xs={12}>
+            <Alert severity="error">
+              <AlertTitle>Error</AlertTitle>
+              Could not retrieve workers live data —{" "}
+              <strong>See the logs for details</strong>
+            </Alert>
+          </Grid>
+        )}
+        {history && history.enabled && (
+          <Grid item xs={12}>
+            <Paper className={classes.paper} variant="outlined">
+              <Typography variant="h6" className={classes.heading}>
+                Fleet History
+              </Typography>
+              <Typography
+                variant="body2"
+                color="textSecondary"
+                className={classes.heading}
+              >
+                {history.stuck_stopped} stuck in stopped status,{" "}
+                {history.stale_heartbeat} with stale heartbeat
+              </Typography>
+              <ServerHistorySection history={history} />
+            </Paper>
+          </Grid>
+        )}
       </Grid>
     </Container>
   );
@@ -18,6 +18,14 @@ import { TaskDetailsRouteParams } from "../paths";
 import { usePolling } from "../hooks";
 import { listQueuesAsync } from "../actions/queuesActions";
 import SyntaxHighlighter from "../components/SyntaxHighlighter";
+import TaskTimeline from "../components/TaskTimeline";
+import NotesList from "../components/NotesList";
+import {
+  addErrorClusterNoteAsync,
+  addTaskNoteAsync,
+  deleteErrorClusterNoteAsync,
+  deleteTaskNoteAsync,
+} from "../actions/notesActions";
 import { durationFromSeconds, stringifyDuration, timeAgo, prettifyPayload } from "../utils";
 
 function mapStateToProps(state: AppState) {
@@ -33,6 +41,10 @@ function mapStateToProps(state: AppState) {
 const connector = connect(mapStateToProps, {
   getTaskInfoAsync,
   listQueuesAsync,
+  addTaskNoteAsync,
+  deleteTaskNoteAsync,
+  addErrorClusterNoteAsync,
+  deleteErrorClusterNoteAsync,
 });
 
 const useStyles = makeStyles((theme) => ({
@@ -65,6 +77,14 @@ const useStyles = makeStyles((theme) => ({
     paddingTop: theme.spacing(3),
     paddingBottom: theme.spacing(3),
   },
+  notesSubtitle: {
+    marginTop: theme.spacing(2),
+  },
+  timeline: {
+    [theme.breakpoints.up("md")]: {
+      paddingLeft: theme.spacing(2),
+    },
+  },
 }));
 
 type Props = ConnectedProps<typeof connector>;
@@ -297,6 +317,55 @@ function TaskDetailsView(props: Props) {
             </Button>
           </div>
         </Grid>
+        {!props.error && taskInfo?.timeline && (
+          <Grid item xs={12} md={6} className={classes.timeline}>
+            <Paper className={classes.paper} variant="outlined">
+              <Typography variant="h6">Timeline</Typography>
+              <TaskTimeline events={taskInfo.timeline} />
+            </Paper>
+            <Paper className={classes.paper} variant="outlined">
+              <Typography variant="h6">Notes</Typography>
+              <NotesList
+                notes={taskInfo.notes || []}
+                onAdd={async (text) => {
+                  await props.addTaskNoteAsync(qname, taskId, text);
+                  fetchTaskInfo();
+                }}
+                onDelete={async (noteId) => {
+                  await props.deleteTaskNoteAsync(qname, taskId, noteId);
+                  fetchTaskInfo();
+                }}
+              />
+              {taskInfo.error_cluster_id && (
+                <>
+                  <Typography
+                    variant="subtitle2"
+                    className={classes.notesSubtitle}
+                  >
+                    Error Cluster Notes
+                  </Typography>
+                  <NotesList
+                    notes={taskInfo.error_cluster_notes || []}
+                    onAdd={async (text) => {
+                      await props.addErrorClusterNoteAsync(
+                        taskInfo.error_cluster_id!,
+                        text
+                      );
+                      fetchTaskInfo();
+                    }}
+                    onDelete={async (noteId) => {
+                      await props.deleteErrorClusterNoteAsync(
+                        taskInfo.error_cluster_id!,
+                        noteId
+                      );
+                      fetchTaskInfo();
+                    }}
+                  />
+                </>
+              )}
+            </Paper>
+          </Grid>
+        )}
       </Grid>
     </Container>
   );
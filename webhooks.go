@@ -0,0 +1,521 @@
+package asynqmon
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - webhooks which receive operator actions and queue events
+//   - dispatcher which signs and delivers the events, and keeps a delivery log
+//   - http.Handler(s) for webhook related endpoints
+// ****************************************************************************
+
+// Types of the events published to webhooks.
+const (
+	// WebhookEventAction is published when an operator changes something through the API
+	// (e.g. deletes all archived tasks of a queue or pauses a queue).
+	WebhookEventAction = "action"
+
+	// WebhookEventQueuePaused and WebhookEventQueueResumed are published when a queue
+	// is seen paused or resumed, whether from asynqmon or another tool.
+	WebhookEventQueuePaused  = "queue.paused"
+	WebhookEventQueueResumed = "queue.resumed"
+
+	// WebhookEventQueueCountChanged is published when the number of tasks in a state
+	// of a queue increases by at least the threshold of the webhook between two samples.
+	WebhookEventQueueCountChanged = "queue.count_changed"
+)
+
+var webhookEventTypes = []string{WebhookEventAction, WebhookEventQueuePaused, WebhookEventQueueResumed, WebhookEventQueueCountChanged}
+
+// Task states counted by the queue samples.
+var queueCountStates = []string{"active", "pending", "aggregating", "scheduled", "retry", "archived", "completed"}
+
+// Webhook is an endpoint events are published to.
+//
+// Events are sent as JSON in POST requests. If Secret is set, the request body is signed
+// with HMAC-SHA256 and the hex encoded signature is sent in the X-Asynqmon-Signature header
+// as "sha256=<signature>".
+type Webhook struct {
+	// URL to send the events to.
+	//
+	// This field is required.
+	URL string
+
+	// Secret used to sign the events.
+	//
+	// This field is optional. If this field is not set, the events are not signed.
+	Secret string
+
+	// Events specifies the types of the events to send (e.g. WebhookEventAction).
+	//
+	// This field is optional. Default is all events.
+	Events []string
+
+	// CountThresholds specifies the increases of task counts which trigger a
+	// WebhookEventQueueCountChanged event.
+	//
+	// This field is optional. If this field is not set, count changes are not sent.
+	CountThresholds []QueueCountThreshold
+}
+
+// QueueCountThreshold is the minimum increase of the number of tasks in a state of a queue
+// between two samples of the queue stats to publish a WebhookEventQueueCountChanged event.
+type QueueCountThreshold struct {
+	// Queue the threshold applies to.
+	//
+	// This field is optional. Default is all queues.
+	Queue string
+
+	// State of the tasks: active, pending, aggregating, scheduled, retry, archived or completed.
+	//
+	// This field is required.
+	State string
+
+	// MinIncrease is the minimum increase of the number of tasks in the state.
+	//
+	// This field is required.
+	MinIncrease int
+}
+
+// validate validates the webhook.
+func (wh *Webhook) validate() error {
+	u, err := url.Parse(wh.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("URL must be an absolute http or https URL")
+	}
+	for _, e := range wh.Events {
+		if !containsString(webhookEventTypes, e) {
+			return fmt.Errorf("unknown event %q", e)
+		}
+	}
+	for _, t := range wh.CountThresholds {
+		if !containsString(queueCountStates, t.State) {
+			return fmt.Errorf("unknown task state %q in count threshold", t.State)
+		}
+		if t.MinIncrease <= 0 {
+			return fmt.Errorf("min increase of count threshold must be positive, got %d", t.MinIncrease)
+		}
+	}
+	return nil
+}
+
+// subscribed reports whether the webhook receives events of the type.
+func (wh *Webhook) subscribed(eventType string) bool {
+	return len(wh.Events) == 0 || containsString(wh.Events, eventType)
+}
+
+// countThresholdExceeded reports whether one of the count changes of the queue exceeds a threshold of the webhook.
+func (wh *Webhook) countThresholdExceeded(qname string, changes []*queueCountChange) bool {
+	for _, t := range wh.CountThresholds {
+		if t.Queue != "" && t.Queue != qname {
+			continue
+		}
+		for _, c := range changes {
+			if c.State == t.State && c.Delta >= t.MinIncrease {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// webhookEvent is the payload sent to webhooks.
+type webhookEvent struct {
+	ID    string    `json:"id"`
+	Type  string    `json:"type"`
+	Time  time.Time `json:"time"`
+	Queue string    `json:"queue,omitempty"`
+	// Set for action events.
+	Action *actionEvent `json:"action,omitempty"`
+	// Set for count change events.
+	Changes []*queueCountChange `json:"changes,omitempty"`
+}
+
+// actionEvent describes an API request which changed something.
+type actionEvent struct {
+	Method string `json:"method"`
+	// Route template (e.g. "/api/queues/{qname}/archived_tasks:delete_all") and actual path.
+	Route     string            `json:"route"`
+	Path      string            `json:"path"`
+	Vars      map[string]string `json:"vars,omitempty"`
+	Status    int               `json:"status"`
+	User      string            `json:"user,omitempty"`
+	RequestID string            `json:"request_id,omitempty"`
+}
+
+// queueCountChange is the change of the number of tasks in a state between two samples.
+type queueCountChange struct {
+	State string `json:"state"`
+	From  int    `json:"from"`
+	To    int    `json:"to"`
+	Delta int    `json:"delta"`
+}
+
+// Statuses of deliveries.
+const (
+	deliveryPending   = "pending"
+	deliveryDelivered = "delivered"
+	deliveryFailed    = "failed"
+	// The event was dropped because too many events were waiting to be delivered.
+	deliveryDropped = "dropped"
+)
+
+const (
+	// Maximum number of attempts to deliver an event.
+	maxWebhookAttempts = 5
+
+	// Timeout of each attempt.
+	webhookTimeout = 10 * time.Second
+
+	// Maximum number of deliveries kept in the delivery log.
+	maxWebhookDeliveries = 500
+
+	// Maximum number of events waiting to be delivered to each webhook.
+	webhookQueueSize = 100
+)
+
+// webhookDelivery is the delivery of an event to a webhook.
+type webhookDelivery struct {
+	ID       string            `json:"id"`
+	URL      string            `json:"url"`
+	Event    *webhookEvent     `json:"event"`
+	Status   string            `json:"status"`
+	Attempts []*webhookAttempt `json:"attempts"`
+}
+
+type webhookAttempt struct {
+	Time time.Time `json:"time"`
+	// HTTP status code of the response. Zero if no response was received.
+	StatusCode int     `json:"status_code,omitempty"`
+	Error      string  `json:"error,omitempty"`
+	DurationMs float64 `json:"duration_ms"`
+}
+
+// webhookBackoff returns the delay before retrying a failed attempt: 1s, 2s, 4s, ...
+func webhookBackoff(attempt int) time.Duration {
+	return time.Second << (attempt - 1)
+}
+
+// webhookDispatcher publishes events to the webhooks. Each webhook has its own goroutine
+// delivering the events in order, so that a slow endpoint doesn't delay the others.
+type webhookDispatcher struct {
+	webhooks []*Webhook
+	client   *http.Client
+	logger   *slog.Logger // may be nil
+	rootPath string
+	backoff  func(attempt int) time.Duration
+
+	queues []chan *webhookDelivery // by webhook
+
+	mu         sync.Mutex
+	deliveries []*webhookDelivery // in chronological order
+
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+func newWebhookDispatcher(webhooks []*Webhook, rootPath string, logger *slog.Logger) *webhookDispatcher {
+	ctx, cancel := context.WithCancel(context.Background())
+	d := &webhookDispatcher{
+		webhooks: webhooks,
+		client:   &http.Client{Timeout: webhookTimeout},
+		logger:   logger,
+		rootPath: rootPath,
+		backoff:  webhookBackoff,
+		ctx:      ctx,
+		cancel:   cancel,
+	}
+	for range webhooks {
+		d.queues = append(d.queues, make(chan *webhookDelivery, webhookQueueSize))
+	}
+	return d
+}
+
+// start starts delivering events in background goroutines until stop is called.
+func (d *webhookDispatcher) start() {
+	for i := range d.webhooks {
+		d.wg.Add(1)
+		go func(wh *Webhook, queue chan *webhookDelivery) {
+			defer d.wg.Done()
+			for {
+				select {
+				case <-d.ctx.Done():
+					return
+				case delivery := <-queue:
+					d.deliver(wh, delivery)
+				}
+			}
+		}(d.webhooks[i], d.queues[i])
+	}
+}
+
+// stop stops delivering events and waits for the background goroutines to exit.
+// Events not delivered yet are abandoned.
+func (d *webhookDispatcher) stop() error {
+	d.cancel()
+	d.wg.Wait()
+	return nil
+}
+
+// publish sends the event to the webhooks for which accept returns true.
+func (d *webhookDispatcher) publish(event *webhookEvent, accept func(wh *Webhook) bool) {
+	event.ID = newRequestID()
+	for i, wh := range d.webhooks {
+		if !wh.subscribed(event.Type) || (accept != nil && !accept(wh)) {
+			continue
+		}
+		delivery := &webhookDelivery{
+			ID:       newRequestID(),
+			URL:      wh.URL,
+			Event:    event,
+			Status:   deliveryPending,
+			Attempts: make([]*webhookAttempt, 0),
+		}
+		d.mu.Lock()
+		d.deliveries = append(d.deliveries, delivery)
+		if len(d.deliveries) > maxWebhookDeliveries {
+			d.deliveries = d.deliveries[len(d.deliveries)-maxWebhookDeliveries:]
+		}
+		d.mu.Unlock()
+		select {
+		case d.queues[i] <- delivery:
+		default:
+			d.setStatus(delivery, deliveryDropped)
+			if d.logger != nil {
+				d.logger.Warn("Dropped webhook event", slog.String("url", wh.URL), slog.String("event", event.Type))
+			}
+		}
+	}
+}
+
+// deliver sends the event to the webhook, and retries with exponential backoff
+// on network errors and on 429 and 5xx responses.
+func (d *webhookDispatcher) deliver(wh *Webhook, delivery *webhookDelivery) {
+	body, err := json.Marshal(delivery.Event)
+	if err != nil {
+		d.setStatus(delivery, deliveryFailed)
+		return
+	}
+	for attempt := 1; attempt <= maxWebhookAttempts; attempt++ {
+		status, err := d.send(wh, delivery, body)
+		retryable := err != nil || status == http.StatusTooManyRequests || status >= 500
+		switch {
+		case err == nil && status >= 200 && status < 300:
+			d.setStatus(delivery, deliveryDelivered)
+			return
+		case !retryable || attempt == maxWebhookAttempts:
+			d.setStatus(delivery, deliveryFailed)
+			if d.logger != nil {
+				d.logger.Warn("Failed to deliver webhook event", slog.String("url", wh.URL),
+					slog.String("event", delivery.Event.Type), slog.String("delivery_id", delivery.ID), slog.Int("attempts", attempt))
+			}
+			return
+		}
+		select {
+		case <-d.ctx.Done():
+			return
+		case <-time.After(d.backoff(attempt)):
+		}
+	}
+}
+
+// send makes one attempt to deliver the event and records it in the delivery.
+func (d *webhookDispatcher) send(wh *Webhook, delivery *webhookDelivery, body []byte) (int, error) {
+	start := time.Now()
+	status, err := func() (int, error) {
+		req, err := http.NewRequestWithContext(d.ctx, "POST", wh.URL, bytes.NewReader(body))
+		if err != nil {
+			return 0, err
+		}
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("User-Agent", "asynqmon")
+		req.Header.Set("X-Asynqmon-Event", delivery.Event.Type)
+		req.Header.Set("X-Asynqmon-Delivery", delivery.ID)
+		if wh.Secret != "" {
+			req.Header.Set("X-Asynqmon-Signature", signWebhookPayload(wh.Secret, body))
+		}
+		resp, err := d.client.Do(req)
+		if err != nil {
+			return 0, err
+		}
+		resp.Body.Close()
+		return resp.StatusCode, nil
+	}()
+	a := &webhookAttempt{Time: start, StatusCode: status, DurationMs: float64(time.Since(start).Microseconds()) / 1000}
+	if err != nil {
+		a.Error = err.Error()
+	} else if status < 200 || status >= 300 {
+		a.Error = http.StatusText(status)
+	}
+	d.mu.Lock()
+	delivery.Attempts = append(delivery.Attempts, a)
+	d.mu.Unlock()
+	return status, err
+}
+
+func (d *webhookDispatcher) setStatus(delivery *webhookDelivery, status string) {
+	d.mu.Lock()
+	delivery.Status = status
+	d.mu.Unlock()
+}
+
+// signWebhookPayload returns the value of the X-Asynqmon-Signature header for the body.
+func signWebhookPayload(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// observeQueue publishes the queue events seen between two samples of the queue stats.
+func (d *webhookDispatcher) observeQueue(qname string, prev, cur *queueSample) {
+	if prev.paused != cur.paused {
+		typ := WebhookEventQueueResumed
+		if cur.paused {
+			typ = WebhookEventQueuePaused
+		}
+		d.publish(&webhookEvent{Type: typ, Time: cur.time, Queue: qname}, nil)
+	}
+	var changes []*queueCountChange
+	for _, state := range queueCountStates {
+		if from, to := prev.counts[state], cur.counts[state]; from != to {
+			changes = append(changes, &queueCountChange{State: state, From: from, To: to, Delta: to - from})
+		}
+	}
+	if len(changes) == 0 {
+		return
+	}
+	d.publish(&webhookEvent{Type: WebhookEventQueueCountChanged, Time: cur.time, Queue: qname, Changes: changes},
+		func(wh *Webhook) bool { return wh.countThresholdExceeded(qname, changes) })
+}
+
+// middleware is a middleware function to publish the successful API requests other than GET as action events.
+func (d *webhookDispatcher) middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Dry runs of destructive requests don't change anything.
+		if r.Method == "GET" || r.Method == "HEAD" || r.Method == "OPTIONS" || isDryRun(r) {
+			h.ServeHTTP(w, r)
+			return
+		}
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		h.ServeHTTP(rec, r)
+		if rec.status < 200 || rec.status >= 300 {
+			return
+		}
+		vars := mux.Vars(r)
+		d.publish(&webhookEvent{
+			Type:  WebhookEventAction,
+			Time:  time.Now(),
+			Queue: vars["qname"],
+			Action: &actionEvent{
+				Method:    r.Method,
+				Route:     routeTemplate(r, d.rootPath),
+				Path:      r.URL.Path,
+				Vars:      vars,
+				Status:    rec.status,
+				User:      requestUser(r),
+				RequestID: requestIDFromContext(r.Context()),
+			},
+		}, nil)
+	})
+}
+
+// list returns the deliveries with the status (all if empty), most recent first.
+func (d *webhookDispatcher) list(status string, limit int) (total int, res []*webhookDelivery) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	res = make([]*webhookDelivery, 0)
+	for i := len(d.deliveries) - 1; i >= 0; i-- {
+		delivery := d.deliveries[i]
+		if status != "" && delivery.Status != status {
+			continue
+		}
+		total++
+		if len(res) < limit {
+			// Copy the delivery since it is updated while being delivered.
+			c := *delivery
+			c.Attempts = append([]*webhookAttempt(nil), delivery.Attempts...)
+			res = append(res, &c)
+		}
+	}
+	return total, res
+}
+
+type webhookInfo struct {
+	URL             string                `json:"url"`
+	Signed          bool                  `json:"signed"`
+	Events          []string              `json:"events"`
+	CountThresholds []*countThresholdInfo `json:"count_thresholds"`
+}
+
+type countThresholdInfo struct {
+	Queue       string `json:"queue,omitempty"`
+	State       string `json:"state"`
+	MinIncrease int    `json:"min_increase"`
+}
+
+type listWebhooksResponse struct {
+	Webhooks []*webhookInfo `json:"webhooks"`
+}
+
+// newListWebhooksHandlerFunc returns the configured webhooks, without their secrets.
+func newListWebhooksHandlerFunc(d *webhookDispatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		resp := listWebhooksResponse{Webhooks: make([]*webhookInfo, 0)}
+		if d != nil {
+			for _, wh := range d.webhooks {
+				info := &webhookInfo{URL: wh.URL, Signed: wh.Secret != "", Events: wh.Events, CountThresholds: make([]*countThresholdInfo, 0)}
+				if len(info.Events) == 0 {
+					info.Events = webhookEventTypes
+				}
+				for _, t := range wh.CountThresholds {
+					info.CountThresholds = append(info.CountThresholds, &countThresholdInfo{Queue: t.Queue, State: t.State, MinIncrease: t.MinIncrease})
+				}
+				resp.Webhooks = append(resp.Webhooks, info)
+			}
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+type listWebhookDeliveriesResponse struct {
+	Total      int                `json:"total"`
+	Deliveries []*webhookDelivery `json:"deliveries"`
+}
+
+// newListWebhookDeliveriesHandlerFunc returns the delivery log, most recent first,
+// optionally filtered by status.
+func newListWebhookDeliveriesHandlerFunc(d *webhookDispatcher) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		status := r.URL.Query().Get("status")
+		if status != "" && !containsString([]string{deliveryPending, deliveryDelivered, deliveryFailed, deliveryDropped}, status) {
+			writeBadRequest(w, r, "invalid value %q for status: must be one of pending, delivered, failed or dropped", status)
+			return
+		}
+		limit, err := intQueryParam(r, "limit", 100, 1, maxWebhookDeliveries)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		resp := listWebhookDeliveriesResponse{Deliveries: make([]*webhookDelivery, 0)}
+		if d != nil {
+			resp.Total, resp.Deliveries = d.list(status, limit)
+		}
+		writeResponseJSON(w, resp)
+	}
+}
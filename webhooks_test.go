@@ -0,0 +1,179 @@
+package asynqmon
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/gorilla/mux"
+)
+
+// webhookReceiver records the requests made to a webhook and responds with the given status codes in turn.
+type webhookReceiver struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rcv *webhookReceiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+	rcv.mu.Lock()
+	status := http.StatusOK
+	if n := len(rcv.requests); n < len(rcv.statuses) {
+		status = rcv.statuses[n]
+	}
+	rcv.requests = append(rcv.requests, r)
+	rcv.bodies = append(rcv.bodies, body)
+	rcv.mu.Unlock()
+	w.WriteHeader(status)
+}
+
+func newTestDispatcher(t *testing.T, webhooks ...*Webhook) *webhookDispatcher {
+	d := newWebhookDispatcher(webhooks, "", nil)
+	d.backoff = func(int) time.Duration { return 0 }
+	d.start()
+	t.Cleanup(func() { d.stop() })
+	return d
+}
+
+// waitDelivery waits until the delivery of the most recent event is done.
+func waitDelivery(t *testing.T, d *webhookDispatcher) *webhookDelivery {
+	t.Helper()
+	for start := time.Now(); time.Since(start) < 5*time.Second; time.Sleep(10 * time.Millisecond) {
+		if _, list := d.list("", 1); len(list) == 1 && list[0].Status != deliveryPending {
+			return list[0]
+		}
+	}
+	t.Fatal("timed out waiting for the delivery")
+	return nil
+}
+
+func TestWebhookDeliveryRetriesAndSigns(t *testing.T) {
+	rcv := &webhookReceiver{statuses: []int{500, 429, 200}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	d := newTestDispatcher(t, &Webhook{URL: srv.URL, Secret: "s3cret"})
+
+	d.publish(&webhookEvent{Type: WebhookEventQueuePaused, Time: time.Now(), Queue: "default"}, nil)
+	delivery := waitDelivery(t, d)
+
+	if delivery.Status != deliveryDelivered || len(delivery.Attempts) != 3 {
+		t.Fatalf("delivery status = %q with %d attempts, want %q with 3 attempts", delivery.Status, len(delivery.Attempts), deliveryDelivered)
+	}
+	if got := delivery.Attempts[0].StatusCode; got != 500 {
+		t.Errorf("status code of first attempt = %d, want 500", got)
+	}
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	req, body := rcv.requests[2], rcv.bodies[2]
+	if got, want := req.Header.Get("X-Asynqmon-Signature"), signWebhookPayload("s3cret", body); got != want {
+		t.Errorf("X-Asynqmon-Signature = %q, want %q", got, want)
+	}
+	if got := req.Header.Get("X-Asynqmon-Event"); got != WebhookEventQueuePaused {
+		t.Errorf("X-Asynqmon-Event = %q, want %q", got, WebhookEventQueuePaused)
+	}
+	var event webhookEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		t.Fatal(err)
+	}
+	if event.Queue != "default" || event.ID == "" {
+		t.Errorf("event = %+v, want an event with an ID for queue default", event)
+	}
+}
+
+func TestWebhookDeliveryFailsOnClientError(t *testing.T) {
+	rcv := &webhookReceiver{statuses: []int{404}}
+	srv := httptest.NewServer(rcv)
+	defer srv.Close()
+	d := newTestDispatcher(t, &Webhook{URL: srv.URL})
+
+	d.publish(&webhookEvent{Type: WebhookEventAction, Time: time.Now()}, nil)
+	delivery := waitDelivery(t, d)
+	if delivery.Status != deliveryFailed || len(delivery.Attempts) != 1 {
+		t.Errorf("delivery status = %q with %d attempts, want %q with 1 attempt", delivery.Status, len(delivery.Attempts), deliveryFailed)
+	}
+	rcv.mu.Lock()
+	defer rcv.mu.Unlock()
+	if got := rcv.requests[0].Header.Get("X-Asynqmon-Signature"); got != "" {
+		t.Errorf("X-Asynqmon-Signature = %q, want no signature without secret", got)
+	}
+}
+
+func TestWebhookObserveQueue(t *testing.T) {
+	d := newWebhookDispatcher([]*Webhook{
+		{URL: "http://a", CountThresholds: []QueueCountThreshold{{Queue: "critical", State: "archived", MinIncrease: 10}}},
+		{URL: "http://b", Events: []string{WebhookEventQueuePaused}},
+	}, "", nil)
+	now := time.Now()
+	prev := &queueSample{time: now, counts: map[string]int{"archived": 5, "pending": 100}}
+
+	// Below the threshold.
+	d.observeQueue("critical", prev, &queueSample{time: now, counts: map[string]int{"archived": 14, "pending": 100}})
+	// Other queue.
+	d.observeQueue("default", prev, &queueSample{time: now, counts: map[string]int{"archived": 50, "pending": 100}})
+	// Above the threshold, and paused.
+	d.observeQueue("critical", prev, &queueSample{time: now, paused: true, counts: map[string]int{"archived": 15, "pending": 90}})
+
+	_, list := d.list("", 10)
+	var got []string
+	for _, delivery := range list {
+		got = append(got, delivery.URL+" "+delivery.Event.Type)
+	}
+	want := []string{"http://a " + WebhookEventQueueCountChanged, "http://b " + WebhookEventQueuePaused, "http://a " + WebhookEventQueuePaused}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("deliveries mismatch (-want,+got)\n%s", diff)
+	}
+	wantChanges := []*queueCountChange{
+		{State: "pending", From: 100, To: 90, Delta: -10},
+		{State: "archived", From: 5, To: 15, Delta: 10},
+	}
+	if diff := cmp.Diff(wantChanges, list[0].Event.Changes); diff != "" {
+		t.Errorf("changes mismatch (-want,+got)\n%s", diff)
+	}
+}
+
+func TestWebhookMiddleware(t *testing.T) {
+	d := newWebhookDispatcher([]*Webhook{{URL: "http://a"}}, "/monitoring", nil)
+	router := mux.NewRouter().PathPrefix("/monitoring").Subrouter()
+	api := router.PathPrefix("/api").Subrouter()
+	api.HandleFunc("/queues/{qname}/archived_tasks:delete_all", func(w http.ResponseWriter, r *http.Request) {}).Methods("DELETE")
+	api.HandleFunc("/queues/{qname}:pause", func(w http.ResponseWriter, r *http.Request) {
+		writeError(w, r, http.StatusNotFound, errCodeQueueNotFound, "queue not found")
+	}).Methods("POST")
+	api.HandleFunc("/queues", func(w http.ResponseWriter, r *http.Request) {}).Methods("GET")
+	api.Use(d.middleware)
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest("DELETE", "/monitoring/api/queues/critical/archived_tasks:delete_all", nil),
+		httptest.NewRequest("POST", "/monitoring/api/queues/missing:pause", nil),
+		httptest.NewRequest("GET", "/monitoring/api/queues", nil),
+	} {
+		req.SetBasicAuth("alice", "")
+		router.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	total, list := d.list("", 10)
+	if total != 1 {
+		t.Fatalf("got %d deliveries, want 1 for the successful DELETE request", total)
+	}
+	want := &actionEvent{
+		Method: "DELETE",
+		Route:  "/api/queues/{qname}/archived_tasks:delete_all",
+		Path:   "/monitoring/api/queues/critical/archived_tasks:delete_all",
+		Vars:   map[string]string{"qname": "critical"},
+		Status: http.StatusOK,
+		User:   "alice",
+	}
+	if diff := cmp.Diff(want, list[0].Event.Action); diff != "" {
+		t.Errorf("action mismatch (-want,+got)\n%s", diff)
+	}
+	if list[0].Event.Queue != "critical" {
+		t.Errorf("event queue = %q, want %q", list[0].Event.Queue, "critical")
+	}
+}
@@ -0,0 +1,280 @@
+package asynqmon
+
+import (
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/hibiken/asynq"
+)
+
+// ****************************************************************************
+// This file defines:
+//   - join of server workers and active tasks
+//   - http.Handler(s) for worker related endpoints
+// ****************************************************************************
+
+// Default threshold of elapsed time above which a worker is flagged as long-running.
+const defaultLongRunningThreshold = 10 * time.Minute
+
+// Number of active tasks listed per page while listing workers.
+const workerTasksPageSize = 1000
+
+// workerEntry is a worker processing an active task, or an active task without a worker.
+type workerEntry struct {
+	TaskID      string `json:"task_id"`
+	Queue       string `json:"queue"`
+	TaskType    string `json:"task_type"`
+	TaskPayload string `json:"task_payload"`
+
+	// Server running the worker. Empty if no server reports a worker for the task.
+	ServerID string `json:"server_id"`
+	Host     string `json:"host"`
+	PID      int    `json:"pid"`
+
+	// Started and Deadline are in RFC3339 format, or empty if no worker is found.
+	Started  string `json:"start_time"`
+	Deadline string `json:"deadline"`
+	// ElapsedSeconds is the time elapsed since the worker started processing the task.
+	ElapsedSeconds float64 `json:"elapsed_seconds"`
+
+	// PastDeadline indicates the worker is still processing the task after its deadline.
+	PastDeadline bool `json:"past_deadline"`
+	// LongRunning indicates the elapsed time exceeds the long-running threshold.
+	LongRunning bool `json:"long_running"`
+	// IsOrphaned indicates the task is left in active state with no worker processing it.
+	IsOrphaned bool `json:"is_orphaned"`
+}
+
+// flagged reports whether the worker is past its deadline or orphaned,
+// or long-running if includeLongRunning is true.
+func (e *workerEntry) flagged(includeLongRunning bool) bool {
+	return e.PastDeadline || e.IsOrphaned || (includeLongRunning && e.LongRunning)
+}
+
+// joinWorkers joins the workers of the servers with the active tasks.
+// Active tasks without a worker are included, as well as workers whose task
+// was not listed (e.g. completed in the meantime) if the worker's queue is in qnames.
+//
+// Entries are sorted with orphaned tasks first, then by elapsed time in descending order.
+func joinWorkers(servers []*asynq.ServerInfo, tasks []*asynq.TaskInfo, qnames []string, now time.Time, longRunning time.Duration, pf PayloadFormatter) []*workerEntry {
+	entries := make(map[string]*workerEntry)
+	var order []string
+	for _, t := range tasks {
+		entries[t.ID] = &workerEntry{
+			TaskID:      t.ID,
+			Queue:       t.Queue,
+			TaskType:    t.Type,
+			TaskPayload: pf.FormatPayload(t.Type, t.Payload),
+			IsOrphaned:  t.IsOrphaned,
+		}
+		order = append(order, t.ID)
+	}
+	for _, srv := range servers {
+		for _, w := range srv.ActiveWorkers {
+			e, ok := entries[w.TaskID]
+			if !ok {
+				if !containsString(qnames, w.Queue) {
+					continue
+				}
+				e = &workerEntry{
+					TaskID:      w.TaskID,
+					Queue:       w.Queue,
+					TaskType:    w.TaskType,
+					TaskPayload: pf.FormatPayload(w.TaskType, w.TaskPayload),
+				}
+				entries[w.TaskID] = e
+				order = append(order, w.TaskID)
+			}
+			e.ServerID = srv.ID
+			e.Host = srv.Host
+			e.PID = srv.PID
+			e.Started = w.Started.Format(time.RFC3339)
+			e.Deadline = formatTimeInRFC3339(w.Deadline)
+			elapsed := now.Sub(w.Started)
+			e.ElapsedSeconds = elapsed.Seconds()
+			e.PastDeadline = !w.Deadline.IsZero() && now.After(w.Deadline)
+			e.LongRunning = elapsed > longRunning
+		}
+	}
+	res := make([]*workerEntry, 0, len(order))
+	for _, id := range order {
+		res = append(res, entries[id])
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		if res[i].IsOrphaned != res[j].IsOrphaned {
+			return res[i].IsOrphaned
+		}
+		return res[i].ElapsedSeconds > res[j].ElapsedSeconds
+	})
+	return res
+}
+
+// listWorkers returns the workers of the queue, or of all queues if qname is empty.
+func listWorkers(r *http.Request, inspector *asynq.Inspector, qname string, longRunning time.Duration, pf PayloadFormatter) ([]*workerEntry, error) {
+	qnames := []string{qname}
+	if qname == "" {
+		span := startSpan(r.Context(), "asynq.Inspector/Queues")
+		queues, err := inspector.Queues()
+		endSpan(span, err)
+		if err != nil {
+			return nil, err
+		}
+		qnames = queues
+	}
+	var tasks []*asynq.TaskInfo
+	for _, q := range qnames {
+		for page := 1; ; page++ {
+			span := startSpan(r.Context(), "asynq.Inspector/ListActiveTasks")
+			list, err := inspector.ListActiveTasks(q, asynq.PageSize(workerTasksPageSize), asynq.Page(page))
+			endSpan(span, err)
+			if err != nil {
+				return nil, err
+			}
+			tasks = append(tasks, list...)
+			if len(list) < workerTasksPageSize {
+				break
+			}
+		}
+	}
+	span := startSpan(r.Context(), "asynq.Inspector/Servers")
+	servers, err := inspector.Servers()
+	endSpan(span, err)
+	if err != nil {
+		return nil, err
+	}
+	return joinWorkers(servers, tasks, qnames, time.Now(), longRunning, pf), nil
+}
+
+// workersQuery returns the queue name and the long-running threshold in the query parameters.
+func workersQuery(r *http.Request) (qname string, longRunning time.Duration, err error) {
+	secs, err := intQueryParam(r, "long_running", int(defaultLongRunningThreshold.Seconds()), 1, int((7 * 24 * time.Hour).Seconds()))
+	if err != nil {
+		return "", 0, err
+	}
+	return r.URL.Query().Get("queue"), time.Duration(secs) * time.Second, nil
+}
+
+type listWorkersResponse struct {
+	Workers []*workerEntry `json:"workers"`
+	// Threshold of elapsed time above which a worker is flagged as long-running.
+	LongRunningThresholdSeconds float64 `json:"long_running_threshold_seconds"`
+	// Number of flagged entries.
+	PastDeadline int `json:"past_deadline"`
+	LongRunning  int `json:"long_running"`
+	Orphaned     int `json:"orphaned"`
+}
+
+func newListWorkersHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatter) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qname, longRunning, err := workersQuery(r)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		workers, err := listWorkers(r, inspector, qname, longRunning, pf)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		resp := listWorkersResponse{
+			Workers:                     workers,
+			LongRunningThresholdSeconds: longRunning.Seconds(),
+		}
+		for _, e := range workers {
+			if e.PastDeadline {
+				resp.PastDeadline++
+			}
+			if e.LongRunning {
+				resp.LongRunning++
+			}
+			if e.IsOrphaned {
+				resp.Orphaned++
+			}
+		}
+		writeResponseJSON(w, resp)
+	}
+}
+
+type cancelFlaggedWorkersResponse struct {
+	batchCancelTasksResponse
+	// Flagged tasks which are orphaned, i.e. no worker is processing them,
+	// so the cancelation signal is not sent to them.
+	OrphanedIDs []string `json:"orphaned_ids"`
+}
+
+// selectFlaggedWorkers returns the flagged workers to send the cancelation signal to,
+// and the IDs of the flagged tasks which are orphaned.
+// Long-running workers which are not past their deadline are selected only if
+// includeLongRunning is true. If taskIDs is not empty, only the tasks with the
+// given IDs are selected.
+func selectFlaggedWorkers(workers []*workerEntry, taskIDs []string, includeLongRunning bool) (toCancel []*workerEntry, orphanedIDs []string) {
+	for _, e := range workers {
+		if !e.flagged(includeLongRunning) || (len(taskIDs) > 0 && !containsString(taskIDs, e.TaskID)) {
+			continue
+		}
+		if e.IsOrphaned {
+			orphanedIDs = append(orphanedIDs, e.TaskID)
+			continue
+		}
+		toCancel = append(toCancel, e)
+	}
+	return toCancel, orphanedIDs
+}
+
+// newCancelFlaggedWorkersHandlerFunc returns a handler which sends the cancelation signal to
+// the tasks of the workers past their deadline, and of the long-running workers only if
+// the long_running query param is given explicitly.
+// Orphaned tasks have no worker to receive the signal and are reported separately.
+// If the request body has task_ids, only the flagged tasks with the given IDs are canceled.
+func newCancelFlaggedWorkersHandlerFunc(inspector *asynq.Inspector, pf PayloadFormatter, tl *taskTimeline, logger *slog.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		qname, longRunning, err := workersQuery(r)
+		if err != nil {
+			writeBadRequest(w, r, "%v", err)
+			return
+		}
+		var req batchCancelTasksRequest
+		if r.ContentLength != 0 {
+			r.Body = http.MaxBytesReader(w, r.Body, maxRequestBodySize)
+			dec := json.NewDecoder(r.Body)
+			dec.DisallowUnknownFields()
+			if err := dec.Decode(&req); err != nil {
+				writeBadRequest(w, r, "invalid request body: %v", err)
+				return
+			}
+		}
+		workers, err := listWorkers(r, inspector, qname, longRunning, pf)
+		if err != nil {
+			writeErrorResponse(w, r, err)
+			return
+		}
+		toCancel, orphanedIDs := selectFlaggedWorkers(workers, req.TaskIDs, r.URL.Query().Has("long_running"))
+		resp := cancelFlaggedWorkersResponse{
+			// avoid null in the json response
+			batchCancelTasksResponse: batchCancelTasksResponse{
+				CanceledIDs: make([]string, 0),
+				ErrorIDs:    make([]string, 0),
+			},
+			OrphanedIDs: append(make([]string, 0), orphanedIDs...),
+		}
+		for _, e := range toCancel {
+			span := startSpan(r.Context(), "asynq.Inspector/CancelProcessing")
+			err := inspector.CancelProcessing(e.TaskID)
+			endSpan(span, err)
+			if err != nil {
+				if logger != nil {
+					logger.Warn("Failed to send cancelation signal to flagged task", slog.String("request_id", requestIDFromContext(r.Context())),
+						slog.String("queue", e.Queue), slog.String("task_id", e.TaskID), slog.String("error", err.Error()))
+				}
+				resp.ErrorIDs = append(resp.ErrorIDs, e.TaskID)
+			} else {
+				resp.CanceledIDs = append(resp.CanceledIDs, e.TaskID)
+				tl.recordAction(r, e.Queue, e.TaskID, "cancel_flagged", "")
+			}
+		}
+		writeResponseJSON(w, resp)
+	}
+}
@@ -0,0 +1,85 @@
+package asynqmon
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/hibiken/asynq"
+)
+
+func TestJoinWorkers(t *testing.T) {
+	now := time.Now().UTC().Truncate(time.Second)
+	servers := []*asynq.ServerInfo{
+		{
+			ID:   "srv1",
+			Host: "worker-1",
+			PID:  42,
+			ActiveWorkers: []*asynq.WorkerInfo{
+				{TaskID: "t1", TaskType: "email", Queue: "default", Started: now.Add(-30 * time.Second), Deadline: now.Add(30 * time.Minute)},
+				{TaskID: "t2", TaskType: "report", Queue: "default", Started: now.Add(-20 * time.Minute), Deadline: now.Add(-5 * time.Minute)},
+				// Task of another queue.
+				{TaskID: "t5", TaskType: "email", Queue: "low", Started: now.Add(-time.Hour), Deadline: now.Add(time.Hour)},
+				// Task completed after active tasks were listed.
+				{TaskID: "t4", TaskType: "email", Queue: "default", Started: now.Add(-time.Minute), Deadline: now.Add(time.Hour)},
+			},
+		},
+	}
+	tasks := []*asynq.TaskInfo{
+		{ID: "t1", Type: "email", Queue: "default"},
+		{ID: "t2", Type: "report", Queue: "default"},
+		{ID: "t3", Type: "email", Queue: "default", IsOrphaned: true},
+	}
+	got := joinWorkers(servers, tasks, []string{"default"}, now, 10*time.Minute, DefaultPayloadFormatter)
+	want := []*workerEntry{
+		{TaskID: "t3", Queue: "default", TaskType: "email", IsOrphaned: true},
+		{TaskID: "t2", Queue: "default", TaskType: "report", ServerID: "srv1", Host: "worker-1", PID: 42,
+			Started: now.Add(-20 * time.Minute).Format(time.RFC3339), Deadline: now.Add(-5 * time.Minute).Format(time.RFC3339),
+			ElapsedSeconds: 1200, PastDeadline: true, LongRunning: true},
+		{TaskID: "t4", Queue: "default", TaskType: "email", ServerID: "srv1", Host: "worker-1", PID: 42,
+			Started: now.Add(-time.Minute).Format(time.RFC3339), Deadline: now.Add(time.Hour).Format(time.RFC3339),
+			ElapsedSeconds: 60},
+		{TaskID: "t1", Queue: "default", TaskType: "email", ServerID: "srv1", Host: "worker-1", PID: 42,
+			Started: now.Add(-30 * time.Second).Format(time.RFC3339), Deadline: now.Add(30 * time.Minute).Format(time.RFC3339),
+			ElapsedSeconds: 30},
+	}
+	if diff := cmp.Diff(want, got, cmpopts.IgnoreFields(workerEntry{}, "TaskPayload")); diff != "" {
+		t.Errorf("joinWorkers diff (-want, +got):\n%s", diff)
+	}
+}
+
+func TestSelectFlaggedWorkers(t *testing.T) {
+	workers := []*workerEntry{
+		{TaskID: "t1", IsOrphaned: true},
+		{TaskID: "t2", PastDeadline: true, LongRunning: true},
+		{TaskID: "t3", LongRunning: true},
+		{TaskID: "t4"},
+	}
+	tests := []struct {
+		taskIDs      []string
+		longRunning  bool
+		wantCancel   []string
+		wantOrphaned []string
+	}{
+		{nil, false, []string{"t2"}, []string{"t1"}},
+		{nil, true, []string{"t2", "t3"}, []string{"t1"}},
+		// Task which is only long-running is not canceled by default.
+		{[]string{"t3"}, false, nil, nil},
+		{[]string{"t1", "t3", "t4"}, true, []string{"t3"}, []string{"t1"}},
+		{[]string{"t2"}, false, []string{"t2"}, nil},
+	}
+	for _, tc := range tests {
+		toCancel, orphaned := selectFlaggedWorkers(workers, tc.taskIDs, tc.longRunning)
+		var gotCancel []string
+		for _, e := range toCancel {
+			gotCancel = append(gotCancel, e.TaskID)
+		}
+		if diff := cmp.Diff(tc.wantCancel, gotCancel); diff != "" {
+			t.Errorf("selectFlaggedWorkers(%v, %t) workers to cancel diff (-want, +got):\n%s", tc.taskIDs, tc.longRunning, diff)
+		}
+		if diff := cmp.Diff(tc.wantOrphaned, orphaned); diff != "" {
+			t.Errorf("selectFlaggedWorkers(%v, %t) orphaned IDs diff (-want, +got):\n%s", tc.taskIDs, tc.longRunning, diff)
+		}
+	}
+}